@@ -10,6 +10,8 @@ var cli struct {
 
 	Track airtrack.TrackCmd `cmd:"" help:"Track aircraft"`
 
+	Replay airtrack.ReplayCmd `cmd:"" help:"Replay a BEAST capture through the tracker"`
+
 	Migrate struct {
 		Up    airtrack.MigrateUpCmd    `cmd:"" help:"Migrate to latest database migration"`
 		Down  airtrack.MigrateDownCmd  `cmd:"" help:"Rollback all migrations"`
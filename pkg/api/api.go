@@ -0,0 +1,623 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/afk11/airtrack/pkg/coverage"
+	"github.com/afk11/airtrack/pkg/db"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultPageSize - number of records returned if
+	// the limit parameter is not set
+	DefaultPageSize = 100
+	// MaxPageSize - maximum value for the limit parameter
+	MaxPageSize = 1000
+
+	// KmlContentType - content type for KML responses
+	KmlContentType = "application/vnd.google-earth.kml+xml"
+)
+
+type (
+	// Server provides a read-only HTTP API for the
+	// projects, sessions and sightings in the database.
+	Server struct {
+		database db.Database
+		coverage *coverage.Coverage
+	}
+
+	// Project - JSON structure for a project
+	Project struct {
+		ID        uint64    `json:"id"`
+		Name      string    `json:"name"`
+		Label     *string   `json:"label,omitempty"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+	// Session - JSON structure for a session
+	Session struct {
+		ID                    uint64     `json:"id"`
+		Identifier            string     `json:"identifier"`
+		CreatedAt             time.Time  `json:"created_at"`
+		ClosedAt              *time.Time `json:"closed_at"`
+		WithSquawks           bool       `json:"with_squawks"`
+		WithTransmissionTypes bool       `json:"with_transmission_types"`
+		WithCallSigns         bool       `json:"with_callsigns"`
+	}
+	// Sighting - JSON structure for a sighting
+	Sighting struct {
+		ID                uint64     `json:"id"`
+		ProjectID         uint64     `json:"project_id"`
+		SessionID         uint64     `json:"session_id"`
+		Icao              string     `json:"icao"`
+		CallSign          *string    `json:"callsign"`
+		Squawk            *string    `json:"squawk"`
+		TransmissionTypes uint8      `json:"transmission_types"`
+		CreatedAt         time.Time  `json:"created_at"`
+		UpdatedAt         time.Time  `json:"updated_at"`
+		ClosedAt          *time.Time `json:"closed_at"`
+		Origin            *Airport   `json:"origin"`
+		Destination       *Airport   `json:"destination"`
+	}
+	// Airport - JSON structure for a sighting's origin or destination
+	Airport struct {
+		Code      string  `json:"code"`
+		Name      string  `json:"name"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+		Distance  float64 `json:"distance"`
+	}
+	// Location - JSON structure for a sighting location
+	Location struct {
+		ID        uint64    `json:"id"`
+		Time      time.Time `json:"time"`
+		Altitude  int64     `json:"altitude"`
+		Latitude  float64   `json:"latitude"`
+		Longitude float64   `json:"longitude"`
+		// Provenance - how the position was obtained: adsb, mlat,
+		// tisb or adsr. Omitted if unknown.
+		Provenance *string `json:"provenance,omitempty"`
+	}
+	// CallSign - JSON structure for an entry in the callsign log
+	CallSign struct {
+		CallSign   string    `json:"callsign"`
+		ObservedAt time.Time `json:"observed_at"`
+	}
+	// Squawk - JSON structure for an entry in the squawk log
+	Squawk struct {
+		Squawk     string    `json:"squawk"`
+		ObservedAt time.Time `json:"observed_at"`
+	}
+	// Phase - JSON structure for an entry in the flight phase log
+	Phase struct {
+		Phase      string    `json:"phase"`
+		ObservedAt time.Time `json:"observed_at"`
+	}
+	// Receiver - JSON structure for a receiver which heard the aircraft.
+	// BestRssi is omitted if the signal strength is unknown.
+	Receiver struct {
+		Receiver  string    `json:"receiver"`
+		FirstSeen time.Time `json:"first_seen"`
+		LastSeen  time.Time `json:"last_seen"`
+		BestRssi  *float64  `json:"best_rssi,omitempty"`
+	}
+
+	// ProjectsResponse - response for /projects
+	ProjectsResponse struct {
+		Projects []Project `json:"projects"`
+	}
+	// SessionsResponse - response for /projects/{project}/sessions
+	SessionsResponse struct {
+		Sessions []Session `json:"sessions"`
+	}
+	// SightingsResponse - response for /projects/{project}/sightings.
+	// If NextAfter is set, it should be passed as the `after` parameter
+	// to fetch the next page.
+	SightingsResponse struct {
+		Sightings []Sighting `json:"sightings"`
+		NextAfter *uint64    `json:"next_after,omitempty"`
+	}
+	// LocationsResponse - response for /sightings/{id}/locations.
+	// If NextAfter is set, it should be passed as the `after` parameter
+	// to fetch the next page.
+	LocationsResponse struct {
+		Locations []Location `json:"locations"`
+		NextAfter *uint64    `json:"next_after,omitempty"`
+	}
+	// CallSignsResponse - response for /sightings/{id}/callsigns
+	CallSignsResponse struct {
+		CallSigns []CallSign `json:"callsigns"`
+	}
+	// SquawksResponse - response for /sightings/{id}/squawks
+	SquawksResponse struct {
+		Squawks []Squawk `json:"squawks"`
+	}
+	// PhasesResponse - response for /sightings/{id}/phases
+	PhasesResponse struct {
+		Phases []Phase `json:"phases"`
+	}
+	// ReceiversResponse - response for /sightings/{id}/receivers
+	ReceiversResponse struct {
+		Receivers []Receiver `json:"receivers"`
+	}
+	// CoverageResponse - response for /coverage
+	CoverageResponse struct {
+		Receivers []*coverage.Outline `json:"receivers"`
+	}
+	// ErrorResponse - returned with non-200 status codes
+	ErrorResponse struct {
+		Error string `json:"error"`
+	}
+
+	// httpError is an error with a status code
+	httpError struct {
+		code int
+		msg  string
+	}
+)
+
+// Error - implements error
+func (e *httpError) Error() string {
+	return e.msg
+}
+
+// newHTTPError creates an error which is returned to the client
+// with the provided status code
+func newHTTPError(code int, format string, args ...interface{}) error {
+	return &httpError{code: code, msg: fmt.Sprintf(format, args...)}
+}
+
+// NewServer returns a new Server
+func NewServer(database db.Database) *Server {
+	return &Server{database: database}
+}
+
+// SetCoverage enables the coverage endpoints, returning the range
+// of receivers in c. It must be called before RegisterRoutes.
+func (s *Server) SetCoverage(c *coverage.Coverage) {
+	s.coverage = c
+}
+
+// RegisterRoutes registers handler functions for the API on r.
+func (s *Server) RegisterRoutes(r *mux.Router) error {
+	r.HandleFunc("/projects", s.handler(s.ProjectsHandler)).Methods("GET")
+	r.HandleFunc("/projects/{project}/sessions", s.handler(s.SessionsHandler)).Methods("GET")
+	r.HandleFunc("/projects/{project}/sightings", s.handler(s.SightingsHandler)).Methods("GET")
+	r.HandleFunc("/sightings/{id:[0-9]+}", s.handler(s.SightingHandler)).Methods("GET")
+	r.HandleFunc("/sightings/{id:[0-9]+}/locations", s.handler(s.LocationsHandler)).Methods("GET")
+	r.HandleFunc("/sightings/{id:[0-9]+}/callsigns", s.handler(s.CallSignsHandler)).Methods("GET")
+	r.HandleFunc("/sightings/{id:[0-9]+}/squawks", s.handler(s.SquawksHandler)).Methods("GET")
+	r.HandleFunc("/sightings/{id:[0-9]+}/phases", s.handler(s.PhasesHandler)).Methods("GET")
+	r.HandleFunc("/sightings/{id:[0-9]+}/receivers", s.handler(s.ReceiversHandler)).Methods("GET")
+	r.HandleFunc("/sightings/{id:[0-9]+}/kml", s.KmlHandler).Methods("GET")
+	if s.coverage != nil {
+		r.HandleFunc("/coverage", s.handler(s.CoverageHandler)).Methods("GET")
+		r.HandleFunc("/coverage/{receiver}", s.handler(s.ReceiverCoverageHandler)).Methods("GET")
+	}
+	return nil
+}
+
+// handler wraps f, writing its result as JSON. If f returns an
+// error, an ErrorResponse is written instead.
+func (s *Server) handler(f func(r *http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := f(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// ProjectsHandler returns all projects.
+func (s *Server) ProjectsHandler(r *http.Request) (interface{}, error) {
+	projects, err := s.database.GetProjects()
+	if err != nil {
+		return nil, errors.Wrap(err, "loading projects")
+	}
+	res := ProjectsResponse{Projects: make([]Project, 0, len(projects))}
+	for i := range projects {
+		res.Projects = append(res.Projects, Project{
+			ID:        projects[i].ID,
+			Name:      projects[i].Identifier,
+			Label:     projects[i].Label,
+			CreatedAt: projects[i].CreatedAt,
+			UpdatedAt: projects[i].UpdatedAt,
+		})
+	}
+	return res, nil
+}
+
+// SessionsHandler returns the sessions for a project.
+func (s *Server) SessionsHandler(r *http.Request) (interface{}, error) {
+	project, err := s.loadProject(r)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.database.GetProjectSessions(project)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading sessions")
+	}
+	res := SessionsResponse{Sessions: make([]Session, 0, len(sessions))}
+	for i := range sessions {
+		res.Sessions = append(res.Sessions, Session{
+			ID:                    sessions[i].ID,
+			Identifier:            sessions[i].Identifier,
+			CreatedAt:             sessions[i].CreatedAt,
+			ClosedAt:              sessions[i].ClosedAt,
+			WithSquawks:           sessions[i].WithSquawks,
+			WithTransmissionTypes: sessions[i].WithTransmissionTypes,
+			WithCallSigns:         sessions[i].WithCallSigns,
+		})
+	}
+	return res, nil
+}
+
+// SightingsHandler returns a page of sightings for a project. Results
+// can be filtered with the since, icao, and callsign query parameters.
+func (s *Server) SightingsHandler(r *http.Request) (interface{}, error) {
+	project, err := s.loadProject(r)
+	if err != nil {
+		return nil, err
+	}
+	q := r.URL.Query()
+	query := db.SightingQuery{
+		Icao:        q.Get("icao"),
+		CallSign:    q.Get("callsign"),
+		Origin:      q.Get("origin"),
+		Destination: q.Get("destination"),
+	}
+	if since := q.Get("since"); since != "" {
+		t, err := parseTime(since)
+		if err != nil {
+			return nil, newHTTPError(http.StatusBadRequest, "invalid value for since: %s", since)
+		}
+		query.Since = &t
+	}
+	query.AfterID, query.Limit, err = parsePagination(r)
+	if err != nil {
+		return nil, err
+	}
+	sightings, err := s.database.SearchSightings(project, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "searching sightings")
+	}
+	res := SightingsResponse{Sightings: make([]Sighting, 0, len(sightings))}
+	for i := range sightings {
+		res.Sightings = append(res.Sightings, newSighting(&sightings[i].Sighting, sightings[i].Icao))
+	}
+	if uint(len(sightings)) == query.Limit {
+		res.NextAfter = &sightings[len(sightings)-1].ID
+	}
+	return res, nil
+}
+
+// SightingHandler returns a single sighting.
+func (s *Server) SightingHandler(r *http.Request) (interface{}, error) {
+	sighting, err := s.loadSighting(r)
+	if err != nil {
+		return nil, err
+	}
+	ac, err := s.database.GetAircraftByID(sighting.AircraftID)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading aircraft")
+	}
+	return newSighting(sighting, ac.Icao), nil
+}
+
+// LocationsHandler returns a page of locations for a sighting.
+func (s *Server) LocationsHandler(r *http.Request) (interface{}, error) {
+	sighting, err := s.loadSighting(r)
+	if err != nil {
+		return nil, err
+	}
+	after, limit, err := parsePagination(r)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.database.LoadLocationHistory(sighting, int64(after), int64(limit))
+	if err != nil {
+		return nil, errors.Wrap(err, "loading locations")
+	}
+	defer rows.Close()
+	res := LocationsResponse{Locations: make([]Location, 0)}
+	for rows.Next() {
+		location := db.SightingLocation{}
+		err = rows.StructScan(&location)
+		if err != nil {
+			return nil, errors.Wrap(err, "scanning location record")
+		}
+		res.Locations = append(res.Locations, Location{
+			ID:         location.ID,
+			Time:       location.TimeStamp,
+			Altitude:   location.Altitude,
+			Latitude:   location.Latitude,
+			Longitude:  location.Longitude,
+			Provenance: location.Provenance,
+		})
+	}
+	if uint(len(res.Locations)) == limit {
+		res.NextAfter = &res.Locations[len(res.Locations)-1].ID
+	}
+	return res, nil
+}
+
+// CallSignsHandler returns the callsign log for a sighting.
+func (s *Server) CallSignsHandler(r *http.Request) (interface{}, error) {
+	sighting, err := s.loadSighting(r)
+	if err != nil {
+		return nil, err
+	}
+	callsigns, err := s.database.GetSightingCallSigns(sighting)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading callsigns")
+	}
+	res := CallSignsResponse{CallSigns: make([]CallSign, 0, len(callsigns))}
+	for i := range callsigns {
+		res.CallSigns = append(res.CallSigns, CallSign{
+			CallSign:   callsigns[i].CallSign,
+			ObservedAt: callsigns[i].ObservedAt,
+		})
+	}
+	return res, nil
+}
+
+// SquawksHandler returns the squawk log for a sighting.
+func (s *Server) SquawksHandler(r *http.Request) (interface{}, error) {
+	sighting, err := s.loadSighting(r)
+	if err != nil {
+		return nil, err
+	}
+	squawks, err := s.database.GetSightingSquawks(sighting)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading squawks")
+	}
+	res := SquawksResponse{Squawks: make([]Squawk, 0, len(squawks))}
+	for i := range squawks {
+		res.Squawks = append(res.Squawks, Squawk{
+			Squawk:     squawks[i].Squawk,
+			ObservedAt: squawks[i].ObservedAt,
+		})
+	}
+	return res, nil
+}
+
+// PhasesHandler returns the flight phase log for a sighting.
+func (s *Server) PhasesHandler(r *http.Request) (interface{}, error) {
+	sighting, err := s.loadSighting(r)
+	if err != nil {
+		return nil, err
+	}
+	phases, err := s.database.GetSightingPhases(sighting)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading phases")
+	}
+	res := PhasesResponse{Phases: make([]Phase, 0, len(phases))}
+	for i := range phases {
+		res.Phases = append(res.Phases, Phase{
+			Phase:      phases[i].Phase,
+			ObservedAt: phases[i].ObservedAt,
+		})
+	}
+	return res, nil
+}
+
+// ReceiversHandler returns the receivers which heard the aircraft
+// during a sighting.
+func (s *Server) ReceiversHandler(r *http.Request) (interface{}, error) {
+	sighting, err := s.loadSighting(r)
+	if err != nil {
+		return nil, err
+	}
+	receivers, err := s.database.GetSightingReceivers(sighting)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading receivers")
+	}
+	res := ReceiversResponse{Receivers: make([]Receiver, 0, len(receivers))}
+	for i := range receivers {
+		res.Receivers = append(res.Receivers, Receiver{
+			Receiver:  receivers[i].Receiver,
+			FirstSeen: receivers[i].FirstSeen,
+			LastSeen:  receivers[i].LastSeen,
+			BestRssi:  receivers[i].BestRssi,
+		})
+	}
+	return res, nil
+}
+
+// CoverageHandler returns the coverage of all receivers
+// with a known location.
+func (s *Server) CoverageHandler(r *http.Request) (interface{}, error) {
+	names := s.coverage.Receivers()
+	res := CoverageResponse{Receivers: make([]*coverage.Outline, 0, len(names))}
+	for _, name := range names {
+		// the receiver may have been removed since
+		if outline, ok := s.coverage.Outline(name); ok {
+			res.Receivers = append(res.Receivers, outline)
+		}
+	}
+	return res, nil
+}
+
+// ReceiverCoverageHandler returns the coverage of a receiver.
+func (s *Server) ReceiverCoverageHandler(r *http.Request) (interface{}, error) {
+	name := mux.Vars(r)["receiver"]
+	outline, ok := s.coverage.Outline(name)
+	if !ok {
+		return nil, newHTTPError(http.StatusNotFound, "unknown receiver: %s", name)
+	}
+	return outline, nil
+}
+
+// KmlHandler responds with the KML file for a sighting.
+func (s *Server) KmlHandler(w http.ResponseWriter, r *http.Request) {
+	sighting, err := s.loadSighting(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	sightingKml, err := s.database.GetSightingKml(sighting)
+	if err == sql.ErrNoRows {
+		writeError(w, newHTTPError(http.StatusNotFound, "no kml for sighting"))
+		return
+	} else if err != nil {
+		writeError(w, errors.Wrap(err, "loading kml"))
+		return
+	}
+	data, err := sightingKml.DecodedKml()
+	if err != nil {
+		writeError(w, errors.Wrap(err, "decoding kml"))
+		return
+	}
+	w.Header().Set("Content-Type", KmlContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"sighting-%d.kml\"", sighting.ID))
+	_, err = w.Write(data)
+	if err != nil {
+		log.Infof("error writing response: %s", err.Error())
+	}
+}
+
+// loadProject returns the project named in the request.
+func (s *Server) loadProject(r *http.Request) (*db.Project, error) {
+	name := mux.Vars(r)["project"]
+	project, err := s.database.GetProject(name)
+	if err == sql.ErrNoRows {
+		return nil, newHTTPError(http.StatusNotFound, "unknown project: %s", name)
+	} else if err != nil {
+		return nil, errors.Wrap(err, "loading project")
+	}
+	return project, nil
+}
+
+// loadSighting returns the sighting identified in the request.
+func (s *Server) loadSighting(r *http.Request) (*db.Sighting, error) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		return nil, newHTTPError(http.StatusBadRequest, "invalid sighting id")
+	}
+	sighting, err := s.database.GetSightingByID(id)
+	if err == sql.ErrNoRows {
+		return nil, newHTTPError(http.StatusNotFound, "unknown sighting: %d", id)
+	} else if err != nil {
+		return nil, errors.Wrap(err, "loading sighting")
+	}
+	return sighting, nil
+}
+
+// newSighting converts a db.Sighting into a Sighting
+func newSighting(sighting *db.Sighting, icao string) Sighting {
+	res := Sighting{
+		ID:                sighting.ID,
+		ProjectID:         sighting.ProjectID,
+		SessionID:         sighting.SessionID,
+		Icao:              icao,
+		CallSign:          sighting.CallSign,
+		Squawk:            sighting.Squawk,
+		TransmissionTypes: sighting.TransmissionTypes,
+		CreatedAt:         sighting.CreatedAt,
+		UpdatedAt:         sighting.UpdatedAt,
+		ClosedAt:          sighting.ClosedAt,
+	}
+	if sighting.OriginCode != nil {
+		res.Origin = &Airport{
+			Code:      *sighting.OriginCode,
+			Name:      stringValue(sighting.OriginName),
+			Latitude:  float64Value(sighting.OriginLatitude),
+			Longitude: float64Value(sighting.OriginLongitude),
+			Distance:  float64Value(sighting.OriginDistance),
+		}
+	}
+	if sighting.DestinationCode != nil {
+		res.Destination = &Airport{
+			Code:      *sighting.DestinationCode,
+			Name:      stringValue(sighting.DestinationName),
+			Latitude:  float64Value(sighting.DestinationLatitude),
+			Longitude: float64Value(sighting.DestinationLongitude),
+			Distance:  float64Value(sighting.DestinationDistance),
+		}
+	}
+	return res
+}
+
+// stringValue returns the value of s, or an empty
+// string if s is nil (a NULL column)
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// float64Value returns the value of f, or zero if
+// f is nil (a NULL column)
+func float64Value(f *float64) float64 {
+	if f == nil {
+		return 0
+	}
+	return *f
+}
+
+// parsePagination parses the after and limit query parameters.
+func parsePagination(r *http.Request) (uint64, uint, error) {
+	q := r.URL.Query()
+	var after uint64
+	limit := uint(DefaultPageSize)
+	if v := q.Get("after"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return 0, 0, newHTTPError(http.StatusBadRequest, "invalid value for after: %s", v)
+		}
+		after = n
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || n == 0 || n > MaxPageSize {
+			return 0, 0, newHTTPError(http.StatusBadRequest, "limit must be between 1 and %d", MaxPageSize)
+		}
+		limit = uint(n)
+	}
+	return after, limit, nil
+}
+
+// parseTime accepts an RFC3339 timestamp or a unix timestamp
+func parseTime(v string) (time.Time, error) {
+	if unix, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// writeJSON writes v as JSON with the provided status code
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Warnf("api: failed to encode response: %s", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, err = w.Write(data)
+	if err != nil {
+		log.Infof("error writing response: %s", err.Error())
+	}
+}
+
+// writeError writes an ErrorResponse. Errors not created by
+// newHTTPError are logged and hidden from the client.
+func writeError(w http.ResponseWriter, err error) {
+	if he, ok := err.(*httpError); ok {
+		writeJSON(w, he.code, ErrorResponse{Error: he.msg})
+		return
+	}
+	log.Warnf("api: %s", err.Error())
+	writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/afk11/airtrack/pkg/coverage"
+	"github.com/afk11/airtrack/pkg/db"
+	"github.com/afk11/airtrack/pkg/test"
+	"github.com/gorilla/mux"
+	"github.com/jmoiron/sqlx"
+	assert "github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func startServer(t *testing.T, database db.Database) *httptest.Server {
+	r := mux.NewRouter()
+	err := NewServer(database).RegisterRoutes(r.PathPrefix("/api").Subrouter())
+	assert.NoError(t, err)
+	return httptest.NewServer(r)
+}
+
+func get(t *testing.T, url string, expectCode int, v interface{}) []byte {
+	res, err := http.Get(url)
+	assert.NoError(t, err)
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, expectCode, res.StatusCode, string(body))
+	if v != nil {
+		assert.NoError(t, json.Unmarshal(body, v))
+	}
+	return body
+}
+
+func TestServer(t *testing.T) {
+	loc := test.MustLoadTestTimeZone()
+	dbConn, dialect, _, closer := test.InitDBUp()
+	defer closer()
+	database := db.NewDatabase(dbConn, dialect)
+
+	now := time.Now().In(loc)
+	_, err := database.CreateProject("proj", now)
+	assert.NoError(t, err)
+	p, err := database.GetProject("proj")
+	assert.NoError(t, err)
+	_, err = database.CreateSession(p, "sess", true, true, true)
+	assert.NoError(t, err)
+	sess, err := database.GetSessionByIdentifier(p, "sess")
+	assert.NoError(t, err)
+	_, err = database.CreateAircraft("ABCDEF", now)
+	assert.NoError(t, err)
+	ac, err := database.GetAircraftByIcao("ABCDEF")
+	assert.NoError(t, err)
+	_, err = database.CreateSighting(sess, ac, now)
+	assert.NoError(t, err)
+	sighting, err := database.GetLastSighting(sess, ac)
+	assert.NoError(t, err)
+	mlat := db.ProvenanceMLAT
+	for i := 0; i < 3; i++ {
+		// the last position was calculated by MLAT
+		var provenance *string
+		if i == 2 {
+			provenance = &mlat
+		}
+		_, err = database.CreateSightingLocation(sighting.ID, now.Add(time.Duration(i)*time.Second), int64(1000*i), 1.0+float64(i), 2.0, provenance)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, database.Transaction(func(tx *sqlx.Tx) error {
+		_, err := database.UpdateSightingOriginTx(tx, sighting, db.SightingAirport{Code: "EIDW", Name: "Dublin"})
+		return err
+	}))
+
+	srv := startServer(t, database)
+	defer srv.Close()
+
+	t.Run("projects", func(t *testing.T) {
+		res := ProjectsResponse{}
+		get(t, srv.URL+"/api/projects", http.StatusOK, &res)
+		assert.Equal(t, 1, len(res.Projects))
+		assert.Equal(t, "proj", res.Projects[0].Name)
+	})
+	t.Run("sessions", func(t *testing.T) {
+		res := SessionsResponse{}
+		get(t, srv.URL+"/api/projects/proj/sessions", http.StatusOK, &res)
+		assert.Equal(t, 1, len(res.Sessions))
+		assert.Equal(t, "sess", res.Sessions[0].Identifier)
+
+		errRes := ErrorResponse{}
+		get(t, srv.URL+"/api/projects/unknown/sessions", http.StatusNotFound, &errRes)
+		assert.Equal(t, "unknown project: unknown", errRes.Error)
+	})
+	t.Run("sightings", func(t *testing.T) {
+		res := SightingsResponse{}
+		get(t, srv.URL+"/api/projects/proj/sightings", http.StatusOK, &res)
+		assert.Equal(t, 1, len(res.Sightings))
+		assert.Equal(t, sighting.ID, res.Sightings[0].ID)
+		assert.Equal(t, "ABCDEF", res.Sightings[0].Icao)
+		assert.Nil(t, res.NextAfter)
+
+		assert.NotNil(t, res.Sightings[0].Origin)
+		assert.Equal(t, "EIDW", res.Sightings[0].Origin.Code)
+		assert.Nil(t, res.Sightings[0].Destination)
+
+		res = SightingsResponse{}
+		get(t, srv.URL+"/api/projects/proj/sightings?icao=000000", http.StatusOK, &res)
+		assert.Equal(t, 0, len(res.Sightings))
+
+		res = SightingsResponse{}
+		get(t, srv.URL+"/api/projects/proj/sightings?origin=EIDW", http.StatusOK, &res)
+		assert.Equal(t, 1, len(res.Sightings))
+
+		res = SightingsResponse{}
+		get(t, srv.URL+"/api/projects/proj/sightings?destination=EIDW", http.StatusOK, &res)
+		assert.Equal(t, 0, len(res.Sightings))
+
+		res = SightingsResponse{}
+		get(t, fmt.Sprintf("%s/api/projects/proj/sightings?since=%d", srv.URL, now.Add(time.Hour).Unix()), http.StatusOK, &res)
+		assert.Equal(t, 0, len(res.Sightings))
+
+		errRes := ErrorResponse{}
+		get(t, srv.URL+"/api/projects/proj/sightings?since=yesterday", http.StatusBadRequest, &errRes)
+		assert.Equal(t, "invalid value for since: yesterday", errRes.Error)
+		get(t, srv.URL+"/api/projects/proj/sightings?limit=0", http.StatusBadRequest, nil)
+	})
+	t.Run("sighting", func(t *testing.T) {
+		res := Sighting{}
+		get(t, fmt.Sprintf("%s/api/sightings/%d", srv.URL, sighting.ID), http.StatusOK, &res)
+		assert.Equal(t, sighting.ID, res.ID)
+		assert.Equal(t, "ABCDEF", res.Icao)
+		get(t, srv.URL+"/api/sightings/9999", http.StatusNotFound, nil)
+	})
+	t.Run("locations", func(t *testing.T) {
+		res := LocationsResponse{}
+		get(t, fmt.Sprintf("%s/api/sightings/%d/locations?limit=2", srv.URL, sighting.ID), http.StatusOK, &res)
+		assert.Equal(t, 2, len(res.Locations))
+		assert.Equal(t, 1.0, res.Locations[0].Latitude)
+		assert.Equal(t, 2.0, res.Locations[1].Latitude)
+		assert.Nil(t, res.Locations[0].Provenance)
+		assert.NotNil(t, res.NextAfter)
+		assert.Equal(t, res.Locations[1].ID, *res.NextAfter)
+
+		next := LocationsResponse{}
+		get(t, fmt.Sprintf("%s/api/sightings/%d/locations?limit=2&after=%d", srv.URL, sighting.ID, *res.NextAfter), http.StatusOK, &next)
+		assert.Equal(t, 1, len(next.Locations))
+		assert.Equal(t, 3.0, next.Locations[0].Latitude)
+		assert.Equal(t, int64(2000), next.Locations[0].Altitude)
+		assert.NotNil(t, next.Locations[0].Provenance)
+		assert.Equal(t, db.ProvenanceMLAT, *next.Locations[0].Provenance)
+		assert.Nil(t, next.NextAfter)
+	})
+	t.Run("phases", func(t *testing.T) {
+		res := PhasesResponse{}
+		get(t, fmt.Sprintf("%s/api/sightings/%d/phases", srv.URL, sighting.ID), http.StatusOK, &res)
+		assert.NotNil(t, res.Phases)
+		assert.Equal(t, 0, len(res.Phases))
+
+		assert.NoError(t, database.Transaction(func(tx *sqlx.Tx) error {
+			_, err := database.CreateNewSightingPhaseTx(tx, sighting, "takeoff", now)
+			return err
+		}))
+		get(t, fmt.Sprintf("%s/api/sightings/%d/phases", srv.URL, sighting.ID), http.StatusOK, &res)
+		assert.Equal(t, 1, len(res.Phases))
+		assert.Equal(t, "takeoff", res.Phases[0].Phase)
+	})
+	t.Run("receivers", func(t *testing.T) {
+		res := ReceiversResponse{}
+		get(t, fmt.Sprintf("%s/api/sightings/%d/receivers", srv.URL, sighting.ID), http.StatusOK, &res)
+		assert.NotNil(t, res.Receivers)
+		assert.Equal(t, 0, len(res.Receivers))
+
+		rssi := -20.5
+		assert.NoError(t, database.Transaction(func(tx *sqlx.Tx) error {
+			_, err := database.CreateSightingReceiverTx(tx, sighting, "home", now, now, &rssi)
+			return err
+		}))
+		get(t, fmt.Sprintf("%s/api/sightings/%d/receivers", srv.URL, sighting.ID), http.StatusOK, &res)
+		assert.Equal(t, 1, len(res.Receivers))
+		assert.Equal(t, "home", res.Receivers[0].Receiver)
+		assert.NotNil(t, res.Receivers[0].BestRssi)
+		assert.Equal(t, rssi, *res.Receivers[0].BestRssi)
+	})
+	t.Run("kml", func(t *testing.T) {
+		url := fmt.Sprintf("%s/api/sightings/%d/kml", srv.URL, sighting.ID)
+		get(t, url, http.StatusNotFound, nil)
+
+		_, err := database.CreateSightingKmlContent(sighting, []byte("<kml></kml>"))
+		assert.NoError(t, err)
+		res, err := http.Get(url)
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		body, err := ioutil.ReadAll(res.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, KmlContentType, res.Header.Get("Content-Type"))
+		assert.Equal(t, "<kml></kml>", string(body))
+	})
+}
+
+func TestServer_Coverage(t *testing.T) {
+	dbConn, dialect, _, closer := test.InitDBUp()
+	defer closer()
+	database := db.NewDatabase(dbConn, dialect)
+
+	// disabled unless coverage is set
+	srv := startServer(t, database)
+	get(t, srv.URL+"/api/coverage", http.StatusNotFound, nil)
+	srv.Close()
+
+	c := coverage.NewCoverage(coverage.DefaultMaxRange)
+	assert.NoError(t, c.AddReceiver("home", 53.35, -6.26, nil))
+	_, _, ok := c.Record("home", 54.35, -6.26, nil, time.Now())
+	assert.True(t, ok)
+	s := NewServer(database)
+	s.SetCoverage(c)
+	r := mux.NewRouter()
+	assert.NoError(t, s.RegisterRoutes(r.PathPrefix("/api").Subrouter()))
+	srv = httptest.NewServer(r)
+	defer srv.Close()
+
+	res := CoverageResponse{}
+	get(t, srv.URL+"/api/coverage", http.StatusOK, &res)
+	assert.Equal(t, 1, len(res.Receivers))
+	assert.Equal(t, "home", res.Receivers[0].Receiver)
+	assert.Equal(t, 1, len(res.Receivers[0].Points))
+
+	outline := coverage.Outline{}
+	get(t, srv.URL+"/api/coverage/home", http.StatusOK, &outline)
+	assert.Equal(t, 53.35, outline.Latitude)
+	assert.Equal(t, 0, outline.Points[0].Bearing)
+	assert.Equal(t, 54.35, outline.Points[0].Latitude)
+	get(t, srv.URL+"/api/coverage/other", http.StatusNotFound, nil)
+}
+
+func TestNewSighting_PartialAirport(t *testing.T) {
+	code := "EIDW"
+	lat := 53.42
+	res := newSighting(&db.Sighting{
+		ID:              1,
+		OriginCode:      &code,
+		OriginLatitude:  &lat,
+		DestinationCode: &code,
+	}, "ABCDEF")
+	assert.NotNil(t, res.Origin)
+	assert.Equal(t, "EIDW", res.Origin.Code)
+	assert.Equal(t, "", res.Origin.Name)
+	assert.Equal(t, 53.42, res.Origin.Latitude)
+	assert.Equal(t, 0.0, res.Origin.Longitude)
+	assert.NotNil(t, res.Destination)
+	assert.Equal(t, 0.0, res.Destination.Distance)
+}
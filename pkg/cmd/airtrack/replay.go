@@ -0,0 +1,66 @@
+package airtrack
+
+import (
+	"github.com/afk11/airtrack/pkg/tracker"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ReplayCmd - replays a BEAST capture through the tracker
+type ReplayCmd struct {
+	// Config - aircraft configuration file path
+	Config string `help:"Configuration file path"`
+	// Projects - List of project configuration files
+	Projects []string `help:"Projects configuration file (may be repeated, and in addition to main configuration file)"`
+	// Verbosity - log level to use
+	Verbosity string `help:"Log level panic, fatal, error, warn, info, debug, trace)" default:"warn"`
+	// File - capture file written by a beast source with the record option
+	File string `help:"Capture file recorded by a beast source" required:""`
+	// Speed - multiple of the recorded speed, or 0 for as fast as possible
+	Speed float64 `help:"Replay speed as a multiple of real time (0 replays as fast as possible)" default:"1"`
+}
+
+// Run - the command line entry point for ReplayCmd
+func (c *ReplayCmd) Run() error {
+	if c.Speed < 0 {
+		return errors.New("speed must not be negative")
+	} else if c.Speed == tracker.ReplaySpeedUnlimited {
+		log.Infof("replaying %s as fast as possible", c.File)
+	} else {
+		log.Infof("replaying %s at %.1fx speed", c.File, c.Speed)
+	}
+	var stopSignal = make(chan os.Signal, 1)
+	signal.Notify(stopSignal, syscall.SIGTERM)
+	signal.Notify(stopSignal, syscall.SIGINT)
+
+	loader := Loader{
+		replayFile:  c.File,
+		replaySpeed: c.Speed,
+	}
+	err := loader.Load(&TrackCmd{
+		Config:    c.Config,
+		Projects:  c.Projects,
+		Verbosity: c.Verbosity,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "during initialization")
+	}
+	err = loader.Start()
+	if err != nil {
+		return errors.Wrapf(err, "during startup")
+	}
+	select {
+	case sig := <-stopSignal:
+		log.Infof("stop signal received - %s", sig.String())
+	case <-loader.replay.Done():
+		log.Infof("replay finished")
+	}
+	err = loader.Stop()
+	if err != nil {
+		return err
+	}
+	return loader.replay.Err()
+}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"github.com/afk11/airtrack/pkg/aircraft/ccode"
 	"github.com/afk11/airtrack/pkg/airports"
+	"github.com/afk11/airtrack/pkg/api"
 	asset "github.com/afk11/airtrack/pkg/assets"
 	"github.com/afk11/airtrack/pkg/config"
+	"github.com/afk11/airtrack/pkg/coverage"
 	"github.com/afk11/airtrack/pkg/db"
 	dump1090 "github.com/afk11/airtrack/pkg/dump1090/acmap"
 	"github.com/afk11/airtrack/pkg/fs"
@@ -21,6 +23,8 @@ import (
 	"github.com/afk11/airtrack/pkg/readsb/aircraftdb"
 	"github.com/afk11/airtrack/pkg/tar1090"
 	"github.com/afk11/airtrack/pkg/tracker"
+	"github.com/afk11/airtrack/pkg/watchlist"
+	"github.com/afk11/airtrack/pkg/webhook"
 	smtp "github.com/afk11/mail"
 	"github.com/doug-martin/goqu/v9"
 	// include necessary drivers for db
@@ -28,6 +32,8 @@ import (
 	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
 	_ "github.com/doug-martin/goqu/v9/dialect/sqlite3"
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -36,6 +42,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"reflect"
 	"runtime/pprof"
 	"syscall"
 	"time"
@@ -66,42 +73,54 @@ func (c *TrackCmd) Run() error {
 	signal.Notify(stopSignal, syscall.SIGINT)
 	signal.Notify(reloadSignal, syscall.SIGHUP)
 
+	loader := Loader{}
+	err := loader.Load(c)
+	if err != nil {
+		return errors.Wrapf(err, "during initialization")
+	}
+	err = loader.Start()
+	if err != nil {
+		return errors.Wrapf(err, "during startup")
+	}
 	for {
-		loader := Loader{}
-		err := loader.Load(c)
-		if err != nil {
-			return errors.Wrapf(err, "during initialization")
-		}
-		err = loader.Start()
-		if err != nil {
-			return errors.Wrapf(err, "during startup")
-		}
 		select {
 		case sig := <-stopSignal:
 			log.Infof("stop signal received - %s", sig.String())
 			return loader.Stop()
 		case <-reloadSignal:
 			log.Infof("reload signal received")
-			err = loader.Stop()
+			err = loader.Reload(c)
 			if err != nil {
-				return errors.Wrapf(err, "error stopping during reload")
+				log.Errorf("reload failed, continuing with previous configuration: %s", err)
 			}
 		}
 	}
 }
 
+// beastSource is a running BeastProducer and the configuration it was created with
+type beastSource struct {
+	cfg      config.BeastConfig
+	producer *tracker.BeastProducer
+	record   *os.File
+}
+
 // Loader takes care of initializing dependencies for airtrack
 // and processing the configuration. It is not intended to be reused
-// for multiple runs.
+// for multiple runs, but Reload can apply changes to projects and
+// beast servers while running.
 type Loader struct {
 	cfg                  *config.Config
 	location             *time.Location
 	dbConn               *sqlx.DB
 	options              *tracker.Options
 	mailSender           *mailer.Mailer
+	webhookSender        *webhook.Dispatcher
+	watchlists           *watchlist.Watchlists
 	producers            []tracker.Producer
 	mapServer            *tracker.AircraftMap
+	aircraftStream       *tracker.AircraftStream
 	metricsServer        *http.Server
+	apiServer            *http.Server
 	t                    *tracker.Tracker
 	usingBeast           bool
 	readsbInitialized    bool
@@ -109,6 +128,15 @@ type Loader struct {
 	heapProfileFileName  string
 	heapProfileCanceller func()
 	msgs                 chan *pb.Message
+	captureFiles         []*os.File
+	replayFile           string
+	replaySpeed          float64
+	replay               *tracker.BeastReplayProducer
+	output               *tracker.Output
+	coverage             *coverage.Coverage
+	beastSources         map[string]*beastSource
+	projects             map[string]*tracker.Project
+	outputServers        []*tracker.OutputServer
 
 	icaoFilterExpirationCanceller func()
 }
@@ -130,9 +158,35 @@ func (l *Loader) loadCleanup() error {
 			log.Warnf("failed to close cpu profile file: %s", err)
 		}
 	}
+	l.closeCaptureFiles()
 	return nil
 }
 
+// closeCaptureFile closes f and removes it from l.captureFiles
+func (l *Loader) closeCaptureFile(f *os.File) {
+	for i := range l.captureFiles {
+		if l.captureFiles[i] == f {
+			l.captureFiles = append(l.captureFiles[:i], l.captureFiles[i+1:]...)
+			break
+		}
+	}
+	err := f.Close()
+	if err != nil {
+		log.Warnf("failed to close capture file: %s", err)
+	}
+}
+
+// closeCaptureFiles closes files opened for recording or replay
+func (l *Loader) closeCaptureFiles() {
+	for _, f := range l.captureFiles {
+		err := f.Close()
+		if err != nil {
+			log.Warnf("failed to close capture file: %s", err)
+		}
+	}
+	l.captureFiles = nil
+}
+
 // ExtractOpenAipFile takes an .aip files data and registers the airports
 func ExtractOpenAipFile(nearestAirports *geo.NearestAirportGeocoder, d []byte) (int, error) {
 	openaipFile, err := openaip.Parse(d)
@@ -150,6 +204,44 @@ func ExtractOpenAipFile(nearestAirports *geo.NearestAirportGeocoder, d []byte) (
 	return len(acRecords), nil
 }
 
+// LoadZones creates geo.Zones from the zone configuration,
+// reading any GeoJSON or KML files
+func LoadZones(cfgs []config.Zone) (*geo.Zones, error) {
+	var zones []geo.Zone
+	for i := range cfgs {
+		cfg := &cfgs[i]
+		if cfg.File != "" {
+			fileZones, err := geo.ReadZonesFile(cfg.File, cfg.Name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading zone file: %s", cfg.File)
+			}
+			zones = append(zones, fileZones...)
+		} else if len(cfg.Polygon) > 0 {
+			polygon := make([]geo.Point, len(cfg.Polygon))
+			for j := range cfg.Polygon {
+				polygon[j] = geo.Point{Latitude: cfg.Polygon[j][0], Longitude: cfg.Polygon[j][1]}
+			}
+			zones = append(zones, geo.NewPolygonZone(cfg.Name, polygon))
+		} else {
+			zones = append(zones, geo.NewCircleZone(cfg.Name, cfg.Latitude, cfg.Longitude, cfg.Radius))
+		}
+	}
+	return geo.NewZones(zones)
+}
+
+// LoadWatchlists creates watchlist.Watchlists from the
+// watchlist configuration, reading their files
+func LoadWatchlists(cfgs []config.Watchlist) (*watchlist.Watchlists, error) {
+	w := watchlist.NewWatchlists()
+	for i := range cfgs {
+		err := w.Add(cfgs[i].Name, cfgs[i].File, cfgs[i].Label)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
 // Load loads and processes the configuration sets everything up
 func (l *Loader) Load(c *TrackCmd) error {
 	var err error
@@ -204,13 +296,24 @@ func (l *Loader) Load(c *TrackCmd) error {
 		OnGroundUpdateThreshold:   tracker.DefaultOnGroundUpdateThreshold,
 		NearestAirportMaxDistance: tracker.DefaultNearestAirportMaxDistance,
 		NearestAirportMaxAltitude: tracker.DefaultNearestAirportMaxAltitude,
+		MaxPositionSpeed:          tracker.DefaultMaxPositionSpeed,
+		DedupWindow:               tracker.DefaultDedupWindow,
+		TimeZone:                  l.location,
 	}
+	l.coverage = coverage.NewCoverage(coverage.DefaultMaxRange)
+	opt.Coverage = l.coverage
 	if l.cfg.Sighting.Timeout != nil {
 		opt.SightingTimeout = time.Second * time.Duration(*l.cfg.Sighting.Timeout)
 	}
 	if l.cfg.Sighting.LocationUpdateInterval != nil {
 		opt.LocationUpdateInterval = time.Second * time.Duration(*l.cfg.Sighting.LocationUpdateInterval)
 	}
+	if l.cfg.Sighting.MaxPositionSpeed != nil {
+		opt.MaxPositionSpeed = *l.cfg.Sighting.MaxPositionSpeed
+	}
+	if l.cfg.Sighting.DedupWindowMs != nil {
+		opt.DedupWindow = time.Millisecond * time.Duration(*l.cfg.Sighting.DedupWindowMs)
+	}
 
 	if l.cfg.EmailSettings != nil {
 		switch l.cfg.EmailSettings.Driver {
@@ -245,6 +348,9 @@ func (l *Loader) Load(c *TrackCmd) error {
 		log.Info("no mailer configured")
 	}
 
+	l.webhookSender = webhook.NewDispatcher(database, nil)
+	opt.Webhooks = l.webhookSender
+
 	nearestAirports := geo.NewNearestAirportGeocoder(tracker.DefaultGeoHashLength)
 
 	var airportFiles int
@@ -318,6 +424,23 @@ func (l *Loader) Load(c *TrackCmd) error {
 
 	opt.AirportGeocoder = nearestAirports
 
+	if len(l.cfg.Zones) > 0 {
+		opt.Zones, err = LoadZones(l.cfg.Zones)
+		if err != nil {
+			return errors.Wrapf(err, "loading zones")
+		}
+		log.Infof("loaded %d zones", opt.Zones.Len())
+	}
+
+	if len(l.cfg.Watchlists) > 0 {
+		l.watchlists, err = LoadWatchlists(l.cfg.Watchlists)
+		if err != nil {
+			return errors.Wrapf(err, "loading watchlists")
+		}
+		opt.Watchlists = l.watchlists
+		log.Infof("loaded %d watchlists", l.watchlists.Len())
+	}
+
 	countryCodesData, err := asset.Asset("assets/iso3166_country_codes.txt")
 	if err != nil {
 		return errors.Wrapf(err, "loading country codes file")
@@ -373,34 +496,60 @@ func (l *Loader) Load(c *TrackCmd) error {
 		}
 	}
 
-	l.msgs = make(chan *pb.Message)
-	if l.cfg.AdsbxConfig != nil {
-		var adsbxEndpoint = tracker.DefaultAdsbxEndpoint
-		var adsbxAPIKey string
-		if l.cfg.AdsbxConfig.APIURL != "" {
-			adsbxEndpoint = l.cfg.AdsbxConfig.APIURL
+	if l.cfg.API != nil && l.cfg.API.Enabled {
+		var apiPort = 8081
+		var apiIface = "0.0.0.0"
+		if l.cfg.API.Port != 0 {
+			apiPort = l.cfg.API.Port
 		}
-		if l.cfg.AdsbxConfig.APIKey != "" {
-			adsbxAPIKey = l.cfg.AdsbxConfig.APIKey
+		if l.cfg.API.Interface != "" {
+			apiIface = l.cfg.API.Interface
+		}
+		r := mux.NewRouter()
+		apiServer := api.NewServer(database)
+		apiServer.SetCoverage(l.coverage)
+		err = apiServer.RegisterRoutes(r.PathPrefix("/api").Subrouter())
+		if err != nil {
+			return errors.Wrapf(err, "registering api routes")
+		}
+		l.apiServer = &http.Server{
+			Addr:         fmt.Sprintf("%s:%d", apiIface, apiPort),
+			Handler:      handlers.CORS()(r),
+			WriteTimeout: 15 * time.Second,
+			ReadTimeout:  15 * time.Second,
 		}
-		p := tracker.NewAdsbxProducer(l.msgs, adsbxEndpoint, adsbxAPIKey)
-		v, ok := os.LookupEnv("AIRTRACK_ADSBX_PANIC_IF_STUCK")
-		p.PanicIfStuck(!ok || (v == "true" || v == "1" || v == "y" || v == "Y"))
-		l.producers = append(l.producers, p)
 	}
-	if len(l.cfg.Beast) > 0 {
-		l.usingBeast = true
-		for i, bcfg := range l.cfg.Beast {
-			if bcfg.Name == "" {
-				return errors.Errorf("beast server %d is missing name field", i)
-			} else if bcfg.Host == "" {
-				return errors.Errorf("beast server '%s' is missing host field", bcfg.Name)
+
+	l.msgs = make(chan *pb.Message)
+	if len(l.cfg.Output) > 0 {
+		l.output = tracker.NewOutput(tracker.DefaultOutputDedupWindow)
+		opt.Output = l.output
+	}
+	if l.replayFile != "" {
+		// sources in the configuration are ignored when replaying
+		err = l.loadReplay()
+		if err != nil {
+			return err
+		}
+		opt.UseMessageTime = true
+		// a single worker processes messages in the recorded order
+		opt.Workers = 1
+	} else {
+		err = l.loadProducers()
+		if err != nil {
+			return err
+		}
+		// replays always start from scratch
+		if l.cfg.Checkpoint != nil && l.cfg.Checkpoint.File != "" {
+			opt.CheckpointFile = l.cfg.Checkpoint.File
+			opt.CheckpointInterval = tracker.DefaultCheckpointInterval
+			if l.cfg.Checkpoint.Interval != nil {
+				opt.CheckpointInterval = time.Second * time.Duration(*l.cfg.Checkpoint.Interval)
 			}
-			var port uint16 = 30005
-			if bcfg.Port != nil {
-				port = *bcfg.Port
+			opt.CheckpointMaxAge = opt.SightingTimeout
+			if l.cfg.Checkpoint.MaxAge != nil {
+				opt.CheckpointMaxAge = time.Second * time.Duration(*l.cfg.Checkpoint.MaxAge)
 			}
-			l.producers = append(l.producers, tracker.NewBeastProducer(l.msgs, bcfg.Host, port, bcfg.Name))
 		}
 	}
 
@@ -432,7 +581,15 @@ func (l *Loader) Load(c *TrackCmd) error {
 			case tracker.Dump1090MapService:
 				err = l.mapServer.RegisterMapService(dump1090.NewDump1090Map(l.mapServer))
 			case tracker.Tar1090MapService:
-				err = l.mapServer.RegisterMapService(tar1090.NewTar1090Map(l.mapServer, historyFiles))
+				tar1090Map := tar1090.NewTar1090Map(l.mapServer, historyFiles)
+				tar1090Map.SetCoverage(l.coverage)
+				err = l.mapServer.RegisterMapService(tar1090Map)
+			case tracker.StreamMapService:
+				l.aircraftStream = tracker.NewAircraftStream(l.mapServer)
+				err = l.mapServer.RegisterMapService(l.aircraftStream)
+				if err == nil {
+					err = l.t.RegisterProjectAircraftUpdateListener(l.aircraftStream)
+				}
 			default:
 				return errors.New("unsupported map service: " + mapService)
 			}
@@ -451,6 +608,7 @@ func (l *Loader) Load(c *TrackCmd) error {
 	}
 
 	var ignored int32
+	l.projects = make(map[string]*tracker.Project)
 	for _, proj := range l.cfg.Projects {
 		if proj.Disabled {
 			ignored++
@@ -464,11 +622,429 @@ func (l *Loader) Load(c *TrackCmd) error {
 		if err != nil {
 			return errors.Wrap(err, "failed to add project to tracker")
 		}
+		l.projects[p.Name] = p
 		log.Debugf("init project %s", p.Name)
 	}
 	if ignored > 0 {
 		log.Debugf("skipping %d disabled projects", ignored)
 	}
+	err = l.t.Restore()
+	if err != nil {
+		return errors.Wrap(err, "failed to restore checkpoint")
+	}
+	err = l.loadOutputs()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// loadOutputs initializes output servers
+func (l *Loader) loadOutputs() error {
+	for i, ocfg := range l.cfg.Output {
+		if ocfg.Port == 0 {
+			return errors.Errorf("output %d is missing port field", i)
+		}
+		iface := "0.0.0.0"
+		if ocfg.Interface != "" {
+			iface = ocfg.Interface
+		}
+		var filter tracker.OutputFilter
+		if ocfg.Project != "" {
+			if _, ok := l.projects[ocfg.Project]; !ok {
+				return errors.Errorf("output %d uses unknown or disabled project '%s'", i, ocfg.Project)
+			}
+			// look up the project each time, it may be replaced by a reload
+			project := ocfg.Project
+			filter = func(icao string) bool {
+				return l.t.IsObserving(project, icao)
+			}
+		}
+		s, err := tracker.NewOutputServer(ocfg.Format, fmt.Sprintf("%s:%d", iface, ocfg.Port), filter)
+		if err != nil {
+			return errors.Wrapf(err, "output %d", i)
+		}
+		l.output.AddServer(s)
+		l.outputServers = append(l.outputServers, s)
+	}
+	return nil
+}
+
+// loadProducers initializes producers for the sources in the configuration
+func (l *Loader) loadProducers() error {
+	if l.cfg.AdsbxConfig != nil {
+		var adsbxAPIKey string
+		if l.cfg.AdsbxConfig.APIKey != "" {
+			adsbxAPIKey = l.cfg.AdsbxConfig.APIKey
+		}
+		var p *tracker.AdsbxProducer
+		switch l.cfg.AdsbxConfig.Version {
+		case "", "v1":
+			if l.cfg.AdsbxConfig.Query != nil {
+				return errors.New("adsbx query requires version v2")
+			}
+			var adsbxEndpoint = tracker.DefaultAdsbxEndpoint
+			if l.cfg.AdsbxConfig.APIURL != "" {
+				adsbxEndpoint = l.cfg.AdsbxConfig.APIURL
+			}
+			p = tracker.NewAdsbxProducer(l.msgs, adsbxEndpoint, adsbxAPIKey)
+		case "v2":
+			var adsbxEndpoint = tracker.DefaultAdsbxV2Endpoint
+			if l.cfg.AdsbxConfig.APIURL != "" {
+				adsbxEndpoint = l.cfg.AdsbxConfig.APIURL
+			}
+			query := &tracker.AdsbxQuery{Mode: tracker.AdsbxQueryAll}
+			if q := l.cfg.AdsbxConfig.Query; q != nil && q.Mode != "" {
+				query = &tracker.AdsbxQuery{
+					Mode:      q.Mode,
+					Latitude:  q.Latitude,
+					Longitude: q.Longitude,
+					Distance:  q.Distance,
+					Value:     q.Value,
+				}
+			}
+			var err error
+			p, err = tracker.NewAdsbxV2Producer(l.msgs, adsbxEndpoint, adsbxAPIKey, query)
+			if err != nil {
+				return errors.Wrap(err, "invalid adsbx query")
+			}
+		default:
+			return errors.Errorf("unknown adsbx version '%s'", l.cfg.AdsbxConfig.Version)
+		}
+		if l.cfg.AdsbxConfig.Interval < 0 {
+			return errors.New("adsbx interval cannot be negative")
+		} else if l.cfg.AdsbxConfig.Interval > 0 {
+			p.SetInterval(time.Duration(l.cfg.AdsbxConfig.Interval) * time.Second)
+		}
+		v, ok := os.LookupEnv("AIRTRACK_ADSBX_PANIC_IF_STUCK")
+		p.PanicIfStuck(!ok || (v == "true" || v == "1" || v == "y" || v == "Y"))
+		l.producers = append(l.producers, p)
+	}
+	err := validateBeastConfig(l.cfg.Beast)
+	if err != nil {
+		return err
+	}
+	l.beastSources = make(map[string]*beastSource)
+	if len(l.cfg.Beast) > 0 {
+		l.usingBeast = true
+		for _, bcfg := range l.cfg.Beast {
+			src, err := l.newBeastSource(bcfg)
+			if err != nil {
+				return err
+			}
+			l.producers = append(l.producers, src.producer)
+		}
+	}
+	for i, scfg := range l.cfg.Sbs {
+		if scfg.Name == "" {
+			return errors.Errorf("sbs server %d is missing name field", i)
+		} else if scfg.Host == "" {
+			return errors.Errorf("sbs server '%s' is missing host field", scfg.Name)
+		}
+		var port uint16 = 30003
+		if scfg.Port != nil {
+			port = *scfg.Port
+		}
+		l.producers = append(l.producers, tracker.NewSbsProducer(l.msgs, scfg.Host, port, scfg.Name))
+	}
+	if len(l.cfg.Avr) > 0 {
+		// AVR frames are decoded by readsb, same as BEAST
+		l.usingBeast = true
+		for i, acfg := range l.cfg.Avr {
+			if acfg.Name == "" {
+				return errors.Errorf("avr server %d is missing name field", i)
+			} else if acfg.Host == "" {
+				return errors.Errorf("avr server '%s' is missing host field", acfg.Name)
+			}
+			var port uint16 = 30002
+			if acfg.Port != nil {
+				port = *acfg.Port
+			}
+			p := tracker.NewAvrProducer(l.msgs, acfg.Host, port, acfg.Name)
+			if l.output != nil {
+				p.SetOutput(l.output)
+			}
+			l.producers = append(l.producers, p)
+		}
+	}
+	for i, ucfg := range l.cfg.Uat {
+		if ucfg.Name == "" {
+			return errors.Errorf("uat server %d is missing name field", i)
+		} else if ucfg.Host == "" {
+			return errors.Errorf("uat server '%s' is missing host field", ucfg.Name)
+		}
+		format := tracker.UatFormatJSON
+		if ucfg.Format != "" {
+			format = ucfg.Format
+		}
+		var port uint16 = 30979
+		if format == tracker.UatFormatRaw {
+			port = 30978
+		}
+		if ucfg.Port != nil {
+			port = *ucfg.Port
+		}
+		p, err := tracker.NewUatProducer(l.msgs, ucfg.Host, port, ucfg.Name, format)
+		if err != nil {
+			return errors.Wrapf(err, "uat server '%s'", ucfg.Name)
+		}
+		l.producers = append(l.producers, p)
+	}
+	for i, jcfg := range l.cfg.AircraftJSON {
+		if jcfg.Name == "" {
+			return errors.Errorf("aircraft_json source %d is missing name field", i)
+		} else if (jcfg.URL == "") == (jcfg.File == "") {
+			return errors.Errorf("aircraft_json source '%s' requires either url or file", jcfg.Name)
+		}
+		location := jcfg.URL
+		if jcfg.File != "" {
+			location = jcfg.File
+		}
+		interval := tracker.DefaultAircraftJSONInterval
+		if jcfg.Interval != 0 {
+			interval = time.Duration(jcfg.Interval) * time.Second
+		}
+		l.producers = append(l.producers, tracker.NewAircraftJSONProducer(l.msgs, location, interval, jcfg.Name))
+	}
+	return nil
+}
+
+// validateBeastConfig checks the beast server configuration
+func validateBeastConfig(beast []config.BeastConfig) error {
+	names := make(map[string]struct{}, len(beast))
+	for i, bcfg := range beast {
+		if bcfg.Name == "" {
+			return errors.Errorf("beast server %d is missing name field", i)
+		} else if bcfg.Host == "" {
+			return errors.Errorf("beast server '%s' is missing host field", bcfg.Name)
+		} else if _, ok := names[bcfg.Name]; ok {
+			return errors.Errorf("duplicated beast server name '%s'", bcfg.Name)
+		} else if (bcfg.Latitude == nil) != (bcfg.Longitude == nil) {
+			return errors.Errorf("beast server '%s' must set both latitude and longitude", bcfg.Name)
+		}
+		names[bcfg.Name] = struct{}{}
+	}
+	return nil
+}
+
+// newBeastSource initializes a BeastProducer for bcfg, and adds it to l.beastSources.
+// The producer is not started.
+func (l *Loader) newBeastSource(bcfg config.BeastConfig) (*beastSource, error) {
+	var port uint16 = 30005
+	if bcfg.Port != nil {
+		port = *bcfg.Port
+	}
+	src := &beastSource{
+		cfg:      bcfg,
+		producer: tracker.NewBeastProducer(l.msgs, bcfg.Host, port, bcfg.Name),
+	}
+	if l.output != nil {
+		src.producer.SetOutput(l.output)
+	}
+	if bcfg.Latitude != nil {
+		err := l.coverage.AddReceiver(bcfg.Name, *bcfg.Latitude, *bcfg.Longitude, bcfg.Altitude)
+		if err != nil {
+			return nil, err
+		}
+		src.producer.SetReceiverLocation(*bcfg.Latitude, *bcfg.Longitude)
+	}
+	if bcfg.Record != "" {
+		f, err := os.OpenFile(bcfg.Record, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, errors.Wrapf(err, "opening record file for beast server '%s'", bcfg.Name)
+		}
+		l.captureFiles = append(l.captureFiles, f)
+		src.record = f
+		src.producer.Record(tracker.NewBeastCaptureWriter(f))
+	}
+	l.beastSources[bcfg.Name] = src
+	return src, nil
+}
+
+// Reload reads the configuration again, and applies changes to projects
+// and beast servers without stopping the tracker. Aircraft in view remain
+// tracked by projects which weren't removed. Changes to other settings
+// require a restart.
+func (l *Loader) Reload(c *TrackCmd) error {
+	cfg, err := config.ReadConfigs(c.Config, c.Projects)
+	if err != nil {
+		return errors.Wrapf(err, "reading configuration")
+	}
+	err = validateBeastConfig(cfg.Beast)
+	if err != nil {
+		return err
+	}
+	err = l.reloadProjects(cfg.Projects)
+	if err != nil {
+		return errors.Wrapf(err, "reloading projects")
+	}
+	if l.replayFile == "" {
+		err = l.reloadBeast(cfg.Beast)
+		if err != nil {
+			return errors.Wrapf(err, "reloading beast servers")
+		}
+		l.cfg.Beast = cfg.Beast
+	}
+
+	// check for changes we can't apply
+	cfg.Projects, cfg.Beast = l.cfg.Projects, l.cfg.Beast
+	if !reflect.DeepEqual(cfg, l.cfg) {
+		log.Warn("configuration changes other than projects and beast servers require a restart")
+	}
+	log.Info("reload complete")
+	return nil
+}
+
+// reloadProjects compares projects with the active projects, adding,
+// updating or removing projects from the tracker as necessary. All
+// projects are initialized before making changes, so the tracker is
+// unchanged if a project is invalid. l.cfg.Projects is updated as each
+// change is applied, so if the tracker returns an error, the next reload
+// only retries the changes which weren't applied.
+func (l *Loader) reloadProjects(projects []config.Project) error {
+	current := make(map[string]config.Project)
+	for _, proj := range l.cfg.Projects {
+		if !proj.Disabled {
+			current[proj.Name] = proj
+		}
+	}
+	next := make(map[string]config.Project)
+	initialized := make(map[string]*tracker.Project)
+	for _, proj := range projects {
+		if proj.Disabled {
+			continue
+		} else if _, ok := next[proj.Name]; ok {
+			return errors.Errorf("duplicated project name %s", proj.Name)
+		}
+		next[proj.Name] = proj
+		if old, ok := current[proj.Name]; ok && reflect.DeepEqual(old, proj) {
+			continue
+		}
+		p, err := tracker.InitProject(proj)
+		if err != nil {
+			return errors.Wrapf(err, "failed to init project %s", proj.Name)
+		}
+		initialized[proj.Name] = p
+	}
+
+	for name := range current {
+		if _, ok := next[name]; ok {
+			continue
+		}
+		err := l.t.RemoveProject(name)
+		if err != nil {
+			return err
+		}
+		l.setProjectConfig(name, nil)
+		delete(l.projects, name)
+		for _, ocfg := range l.cfg.Output {
+			if ocfg.Project == name {
+				log.Warnf("project %s was removed, %s output on port %d won't send anything", name, ocfg.Format, ocfg.Port)
+			}
+		}
+	}
+	for name, p := range initialized {
+		if _, ok := current[name]; ok {
+			err := l.t.UpdateProject(p)
+			if err != nil {
+				return err
+			}
+			cfg := next[name]
+			l.setProjectConfig(name, &cfg)
+			log.Infof("updated project %s", name)
+			continue
+		}
+		err := l.t.AddProject(p)
+		if err != nil {
+			return errors.Wrap(err, "failed to add project to tracker")
+		}
+		cfg := next[name]
+		l.setProjectConfig(name, &cfg)
+		l.projects[name] = p
+		log.Infof("added project %s", name)
+	}
+	l.cfg.Projects = projects
+	return nil
+}
+
+// setProjectConfig replaces the configuration of the named project in
+// l.cfg.Projects with proj, or removes it if proj is nil.
+func (l *Loader) setProjectConfig(name string, proj *config.Project) {
+	projects := make([]config.Project, 0, len(l.cfg.Projects)+1)
+	for _, other := range l.cfg.Projects {
+		if other.Name != name {
+			projects = append(projects, other)
+		}
+	}
+	if proj != nil {
+		projects = append(projects, *proj)
+	}
+	l.cfg.Projects = projects
+}
+
+// reloadBeast compares beast with the running beast producers. Producers
+// which were removed or changed are stopped, and new ones are started.
+func (l *Loader) reloadBeast(beast []config.BeastConfig) error {
+	next := make(map[string]config.BeastConfig, len(beast))
+	for _, bcfg := range beast {
+		next[bcfg.Name] = bcfg
+	}
+	for name, src := range l.beastSources {
+		if bcfg, ok := next[name]; ok && reflect.DeepEqual(bcfg, src.cfg) {
+			continue
+		}
+		log.Infof("stopping %s producer..", name)
+		src.producer.Stop()
+		l.removeProducer(src.producer)
+		// the coverage is kept unless the location was removed
+		if bcfg, ok := next[name]; !ok || bcfg.Latitude == nil {
+			l.coverage.RemoveReceiver(name)
+		}
+		if src.record != nil {
+			l.closeCaptureFile(src.record)
+		}
+		delete(l.beastSources, name)
+	}
+	for _, bcfg := range beast {
+		if _, ok := l.beastSources[bcfg.Name]; ok {
+			continue
+		}
+		if !l.usingBeast {
+			l.usingBeast = true
+			l.initBeast()
+		}
+		src, err := l.newBeastSource(bcfg)
+		if err != nil {
+			return err
+		}
+		l.producers = append(l.producers, src.producer)
+		log.Infof("starting %s producer..", bcfg.Name)
+		src.producer.Start()
+	}
+	return nil
+}
+
+// removeProducer removes p from l.producers
+func (l *Loader) removeProducer(p tracker.Producer) {
+	for i := range l.producers {
+		if l.producers[i] == p {
+			l.producers = append(l.producers[:i], l.producers[i+1:]...)
+			return
+		}
+	}
+}
+
+// loadReplay initializes a BeastReplayProducer for l.replayFile
+func (l *Loader) loadReplay() error {
+	f, err := os.Open(l.replayFile)
+	if err != nil {
+		return errors.Wrapf(err, "opening replay file")
+	}
+	l.captureFiles = append(l.captureFiles, f)
+	// replays are decoded by readsb, same as BEAST
+	l.usingBeast = true
+	l.replay = tracker.NewBeastReplayProducer(l.msgs, f, l.replaySpeed, "replay")
+	l.producers = append(l.producers, l.replay)
 	return nil
 }
 
@@ -480,10 +1056,23 @@ func (l *Loader) Start() error {
 	if l.mapServer != nil {
 		l.mapServer.Serve()
 	}
+	for _, s := range l.outputServers {
+		err := s.Start()
+		if err != nil {
+			return errors.Wrapf(err, "starting %s output", s.Format())
+		}
+		log.Infof("%s output listening on %s", s.Format(), s.Addr())
+	}
 	l.t.Start(l.msgs)
 	if l.mailSender != nil {
 		l.mailSender.Start()
 	}
+	if l.webhookSender != nil {
+		l.webhookSender.Start()
+	}
+	if l.watchlists != nil {
+		l.watchlists.Start(watchlist.DefaultReloadInterval)
+	}
 	if l.cfg.Metrics != nil && l.cfg.Metrics.Enabled {
 		go func() {
 			err := l.metricsServer.ListenAndServe()
@@ -492,6 +1081,14 @@ func (l *Loader) Start() error {
 			}
 		}()
 	}
+	if l.apiServer != nil {
+		go func() {
+			err := l.apiServer.ListenAndServe()
+			if err != nil && err != http.ErrServerClosed {
+				panic(err)
+			}
+		}()
+	}
 
 	if l.cpuProfileFile != nil {
 		err := pprof.StartCPUProfile(l.cpuProfileFile)
@@ -571,12 +1168,21 @@ func (l *Loader) Stop() error {
 		producer.Stop()
 	}
 	close(l.msgs)
+	l.closeCaptureFiles()
 	log.Debugf("stopping tracker")
 	err := l.t.Stop()
 	if err != nil {
 		return err
 	}
 
+	if l.output != nil {
+		log.Debugf("stopping output servers")
+		l.output.Stop()
+	}
+	if l.aircraftStream != nil {
+		log.Debugf("closing aircraft stream clients")
+		l.aircraftStream.Stop()
+	}
 	if l.mapServer != nil {
 		log.Debugf("stopping map server")
 		err = l.mapServer.Stop()
@@ -595,10 +1201,29 @@ func (l *Loader) Stop() error {
 			return errors.Wrapf(err, "in metrics server shutdown")
 		}
 	}
+	if l.apiServer != nil {
+		log.Debugf("stopping api server")
+		ctxShutDown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer func() {
+			cancel()
+		}()
+		err := l.apiServer.Shutdown(ctxShutDown)
+		if err != nil && err != http.ErrServerClosed {
+			return errors.Wrapf(err, "in api server shutdown")
+		}
+	}
 	if l.mailSender != nil {
 		log.Debugf("stopping mailer")
 		l.mailSender.Stop()
 	}
+	if l.webhookSender != nil {
+		log.Debugf("stopping webhook dispatcher")
+		l.webhookSender.Stop()
+	}
+	if l.watchlists != nil {
+		log.Debugf("stopping watchlist reloader")
+		l.watchlists.Stop()
+	}
 	if l.usingBeast {
 		log.Debugf("stopping readsb icao filter expiration routine")
 		l.icaoFilterExpirationCanceller()
@@ -0,0 +1,47 @@
+package airtrack
+
+import (
+	"github.com/afk11/airtrack/pkg/config"
+	"github.com/afk11/airtrack/pkg/db"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/afk11/airtrack/pkg/readsb/aircraftdb"
+	"github.com/afk11/airtrack/pkg/test"
+	"github.com/afk11/airtrack/pkg/tracker"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLoader_ReloadProjects(t *testing.T) {
+	dbConn, dialect, _, closer := test.InitDBUp()
+	defer closer()
+	tr, err := tracker.New(db.NewDatabase(dbConn, dialect), tracker.Options{
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		AircraftDb:              aircraftdb.New(),
+	})
+	assert.NoError(t, err)
+	tr.Start(make(chan *pb.Message))
+	defer tr.Stop()
+
+	l := &Loader{
+		cfg:      &config.Config{},
+		t:        tr,
+		projects: make(map[string]*tracker.Project),
+	}
+	a := config.Project{Name: "a"}
+	assert.NoError(t, l.reloadProjects([]config.Project{a}))
+	assert.Equal(t, []config.Project{a}, l.cfg.Projects)
+
+	// a is removed, but b can't be added without a geocoder
+	b := config.Project{Name: "b", Features: []string{string(tracker.GeocodeEndpoints)}}
+	assert.Error(t, l.reloadProjects([]config.Project{b}))
+	assert.Equal(t, 0, len(l.cfg.Projects))
+	_, ok := l.projects["a"]
+	assert.False(t, ok)
+
+	// the removal isn't repeated
+	c := config.Project{Name: "c"}
+	assert.NoError(t, l.reloadProjects([]config.Project{c}))
+	assert.Equal(t, []config.Project{c}, l.cfg.Projects)
+}
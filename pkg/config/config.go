@@ -89,6 +89,34 @@ type (
 		Email string `yaml:"email"`
 		// Enabled - list of subscribed email events
 		Enabled []string `yaml:"events"`
+		// Webhooks - list of HTTP endpoints which receive events
+		Webhooks []Webhook `yaml:"webhooks"`
+	}
+	// Webhook - configuration of a HTTP endpoint which receives
+	// events as JSON
+	Webhook struct {
+		// URL - the endpoint events are POSTed to
+		URL string `yaml:"url"`
+		// Secret - optional key used to sign the request body
+		Secret string `yaml:"secret"`
+		// Enabled - list of subscribed events. If empty, the
+		// events in Notifications.Enabled are used.
+		Enabled []string `yaml:"events"`
+	}
+
+	// Rule - a custom project event, triggered when a CEL
+	// expression evaluates to true
+	Rule struct {
+		// Name - the event name, used in notification event lists
+		Name string `yaml:"name"`
+		// Condition - a CEL expression, with the same variables as the project filter
+		Condition string `yaml:"condition"`
+		// Trigger - either once (trigger once per sighting) or cooldown
+		// (trigger again once Cooldown has passed). Default: once
+		Trigger string `yaml:"trigger"`
+		// Cooldown - number of seconds before a cooldown rule
+		// can trigger again
+		Cooldown int64 `yaml:"cooldown"`
 	}
 
 	// ProjectMapSettings contains project level configuration
@@ -114,6 +142,8 @@ type (
 		Notifications *Notifications `yaml:"notifications"`
 		// Features - per project extra features
 		Features []string
+		// Rules - custom events for the project
+		Rules []Rule `yaml:"rules"`
 		// ReopenSightings - whether to reopen a previously closed sighting
 		// if a new sighting is within a certain timeframe
 		ReopenSightings bool `yaml:"reopen_sightings"`
@@ -155,6 +185,28 @@ type (
 		Port int `yaml:"port"`
 	}
 
+	// API contains configuration for the read-only HTTP API
+	API struct {
+		// Enabled - control whether to enable the API. Default to off.
+		Enabled bool `yaml:"enabled"`
+		// Interface - interface to bind on. If empty, default is "0.0.0.0"
+		Interface string `yaml:"interface"`
+		// Port - port to listen for API HTTP server. If empty, default is 8081.
+		Port int `yaml:"port"`
+	}
+
+	// Checkpoint contains configuration for saving the tracker
+	// state, so sightings continue after a restart
+	Checkpoint struct {
+		// File - path of the checkpoint file
+		File string `yaml:"file"`
+		// Interval - seconds between checkpoints. Defaults to 30.
+		Interval *int64 `yaml:"interval"`
+		// MaxAge - checkpoints older than this (in seconds) are not
+		// restored. Defaults to the sighting timeout.
+		MaxAge *int64 `yaml:"max_age"`
+	}
+
 	// AdsbxConfig contains configuration for the ADSB Exchange data source
 	AdsbxConfig struct {
 		// Custom ADSB Exchange URL (not required, but useful if
@@ -162,6 +214,27 @@ type (
 		APIURL string `yaml:"url"`
 		// ADSB Exchange API key
 		APIKey string `yaml:"apikey"`
+		// Version - API version, either v1 or v2 (Optional, defaults to v1)
+		Version string `yaml:"version"`
+		// Query - aircraft requested from the v2 API (Optional, defaults to all)
+		Query *AdsbxQueryConfig `yaml:"query"`
+		// Interval - minimum number of seconds between requests (Optional, defaults to 2)
+		Interval int64 `yaml:"interval"`
+	}
+
+	// AdsbxQueryConfig selects the aircraft requested from
+	// the ADSB Exchange v2 API
+	AdsbxQueryConfig struct {
+		// Mode - one of all, radius, hex, callsign or registration
+		Mode string `yaml:"mode"`
+		// Latitude - centre of a radius query
+		Latitude float64 `yaml:"latitude"`
+		// Longitude - centre of a radius query
+		Longitude float64 `yaml:"longitude"`
+		// Distance - radius of a radius query, in nautical miles
+		Distance int64 `yaml:"distance"`
+		// Value - the hex, callsign or registration to request
+		Value string `yaml:"value"`
 	}
 
 	// BeastConfig contains configuration for a single BEAST server
@@ -172,6 +245,106 @@ type (
 		Host string `yaml:"host"`
 		// Port for beast services (Optional, defaults to 30005)
 		Port *uint16 `yaml:"port"`
+		// Record - file path to append the received BEAST stream to,
+		// for use with `airtrack replay` (Optional)
+		Record string `yaml:"record"`
+		// Latitude - decimal latitude of the receiver (Optional)
+		Latitude *float64 `yaml:"latitude"`
+		// Longitude - decimal longitude of the receiver (Optional)
+		Longitude *float64 `yaml:"longitude"`
+		// Altitude - altitude of the receiver's antenna in meters (Optional)
+		Altitude *float64 `yaml:"altitude"`
+	}
+
+	// SbsConfig contains configuration for a single SBS (BaseStation) server
+	SbsConfig struct {
+		// Name for this SBS server
+		Name string `yaml:"name"`
+		// IP or hostname for SBS server
+		Host string `yaml:"host"`
+		// Port for SBS services (Optional, defaults to 30003)
+		Port *uint16 `yaml:"port"`
+	}
+
+	// AvrConfig contains configuration for a single AVR (raw hex) server
+	AvrConfig struct {
+		// Name for this AVR server
+		Name string `yaml:"name"`
+		// IP or hostname for AVR server
+		Host string `yaml:"host"`
+		// Port for AVR services (Optional, defaults to 30002)
+		Port *uint16 `yaml:"port"`
+	}
+
+	// UatConfig contains configuration for a single dump978 server,
+	// which decodes UAT (978MHz) messages
+	UatConfig struct {
+		// Name for this UAT server
+		Name string `yaml:"name"`
+		// IP or hostname for UAT server
+		Host string `yaml:"host"`
+		// Port for UAT services (Optional, defaults to 30979 for
+		// json, or 30978 for raw)
+		Port *uint16 `yaml:"port"`
+		// Format - either json or raw (Optional, defaults to json)
+		Format string `yaml:"format"`
+	}
+
+	// AircraftJSONConfig contains configuration for polling an
+	// aircraft.json file written by readsb or dump1090. One of
+	// URL or File must be set.
+	AircraftJSONConfig struct {
+		// Name for this source
+		Name string `yaml:"name"`
+		// URL - http(s) URL for aircraft.json
+		URL string `yaml:"url"`
+		// File - path to aircraft.json
+		File string `yaml:"file"`
+		// Interval - number of seconds between polls (Optional, defaults to 1)
+		Interval int64 `yaml:"interval"`
+	}
+
+	// OutputConfig contains configuration for a server which re-shares
+	// the merged stream of messages received from all sources
+	OutputConfig struct {
+		// Format - either beast or sbs
+		Format string `yaml:"format"`
+		// Interface to listen on (Optional, defaults to 0.0.0.0)
+		Interface string `yaml:"interface"`
+		// Port to listen on
+		Port uint16 `yaml:"port"`
+		// Project - only share aircraft observed by this project (Optional)
+		Project string `yaml:"project"`
+	}
+
+	// Zone contains configuration for a named geographic area. A zone
+	// is either a circle (latitude, longitude and radius), a polygon,
+	// or loaded from a GeoJSON or KML file.
+	Zone struct {
+		// Name of the zone. Required unless File is set, in which case
+		// it overrides the names in the file.
+		Name string `yaml:"name"`
+		// Latitude - decimal latitude of a circle's center
+		Latitude float64 `yaml:"latitude"`
+		// Longitude - decimal longitude of a circle's center
+		Longitude float64 `yaml:"longitude"`
+		// Radius - radius of a circle in meters
+		Radius float64 `yaml:"radius"`
+		// Polygon - list of [latitude, longitude] vertices
+		Polygon [][2]float64 `yaml:"polygon"`
+		// File - path to a GeoJSON (.geojson, .json) or KML (.kml) file
+		File string `yaml:"file"`
+	}
+
+	// Watchlist contains configuration for a named list of
+	// aircraft loaded from a CSV or JSON file.
+	Watchlist struct {
+		// Name of the watchlist, used by the watchlist() filter function
+		Name string `yaml:"name"`
+		// File - path to a CSV (.csv) or JSON (.json) file
+		File string `yaml:"file"`
+		// Label - label for entries without one. Defaults to Name.
+		Label string `yaml:"label"`
 	}
 
 	// Config - represents the yaml block in the main config file.
@@ -182,8 +355,22 @@ type (
 		AdsbxConfig *AdsbxConfig `yaml:"adsbx"`
 		// Beast - list of beast server configs
 		Beast []BeastConfig `yaml:"beast"`
+		// Sbs - list of SBS server configs
+		Sbs []SbsConfig `yaml:"sbs"`
+		// Avr - list of AVR server configs
+		Avr []AvrConfig `yaml:"avr"`
+		// Uat - list of dump978 server configs
+		Uat []UatConfig `yaml:"uat"`
+		// AircraftJSON - list of aircraft.json configs
+		AircraftJSON []AircraftJSONConfig `yaml:"aircraft_json"`
+		// Output - list of servers re-sharing received messages
+		Output []OutputConfig `yaml:"output"`
 		// Airports - where directories of airport location files are configured
 		Airports *Airports `yaml:"airports"`
+		// Zones - list of named geographic areas
+		Zones []Zone `yaml:"zones"`
+		// Watchlists - list of named lists of aircraft
+		Watchlists []Watchlist `yaml:"watchlists"`
 		// EmailSettings - configuration of email driver.
 		EmailSettings *EmailSettings `yaml:"email"`
 		// Database - configuration of the database driver.
@@ -192,12 +379,23 @@ type (
 		Metrics *Metrics `yaml:"metrics"`
 		// MapSettings - configuration of the HTTP map server
 		MapSettings *MapSettings `yaml:"map"`
+		// API - configuration of the read-only HTTP API
+		API *API `yaml:"api"`
+		// Checkpoint - configuration of tracker state checkpoints
+		Checkpoint *Checkpoint `yaml:"checkpoint"`
 		// Sighting - some global defaults for sighting configuration
 		Sighting struct {
 			Timeout *int64 `yaml:"timeout"`
 			// LocationUpdateInterval sets a default LocationUpdateInterval
 			// to be used by projects which don't specify
 			LocationUpdateInterval *int64 `yaml:"location_update_interval"`
+			// MaxPositionSpeed - positions implying a faster speed (in knots)
+			// since the last position are rejected. Zero disables the check.
+			MaxPositionSpeed *float64 `yaml:"max_position_speed"`
+			// DedupWindowMs - frames received again by another receiver within
+			// this number of milliseconds are only processed once. Zero disables
+			// de-duplication.
+			DedupWindowMs *int64 `yaml:"dedup_window_ms"`
 		} `yaml:"sighting"`
 		// Projects - list of project configurations
 		Projects []Project `yaml:"projects"`
@@ -23,10 +23,23 @@ projects:
       events:
         - map_produced
         - spotted_in_flight
+      webhooks:
+        - url: https://hooks.domain.local/airtrack
+          secret: abcd
+        - url: https://other.domain.local/airtrack
+          events:
+            - takeoff_complete
     features:
       - track_callsigns
       - track_squawks
       - track_takeoff
+    rules:
+      - name: low_rch
+        condition: state.CallSign.startsWith("RCH") && state.AltitudeBarometric < 5000
+      - name: fast
+        condition: state.GroundSpeed > 600
+        trigger: cooldown
+        cooldown: 600
 `)
 		cfg, err := ReadProjectsConfig(buf)
 		assert.NoError(t, err)
@@ -49,10 +62,23 @@ projects:
 		assert.Equal(t, 2, len(cfg.Projects[1].Notifications.Enabled))
 		assert.Equal(t, "map_produced", cfg.Projects[1].Notifications.Enabled[0])
 		assert.Equal(t, "spotted_in_flight", cfg.Projects[1].Notifications.Enabled[1])
+		assert.Equal(t, 2, len(cfg.Projects[1].Notifications.Webhooks))
+		assert.Equal(t, "https://hooks.domain.local/airtrack", cfg.Projects[1].Notifications.Webhooks[0].URL)
+		assert.Equal(t, "abcd", cfg.Projects[1].Notifications.Webhooks[0].Secret)
+		assert.Nil(t, cfg.Projects[1].Notifications.Webhooks[0].Enabled)
+		assert.Equal(t, "https://other.domain.local/airtrack", cfg.Projects[1].Notifications.Webhooks[1].URL)
+		assert.Equal(t, []string{"takeoff_complete"}, cfg.Projects[1].Notifications.Webhooks[1].Enabled)
 		assert.Equal(t, 3, len(cfg.Projects[1].Features))
 		assert.Equal(t, "track_callsigns", cfg.Projects[1].Features[0])
 		assert.Equal(t, "track_squawks", cfg.Projects[1].Features[1])
 		assert.Equal(t, "track_takeoff", cfg.Projects[1].Features[2])
+		assert.Equal(t, 2, len(cfg.Projects[1].Rules))
+		assert.Equal(t, "low_rch", cfg.Projects[1].Rules[0].Name)
+		assert.Equal(t, `state.CallSign.startsWith("RCH") && state.AltitudeBarometric < 5000`, cfg.Projects[1].Rules[0].Condition)
+		assert.Equal(t, "", cfg.Projects[1].Rules[0].Trigger)
+		assert.Equal(t, "fast", cfg.Projects[1].Rules[1].Name)
+		assert.Equal(t, "cooldown", cfg.Projects[1].Rules[1].Trigger)
+		assert.Equal(t, int64(600), cfg.Projects[1].Rules[1].Cooldown)
 	})
 }
 
@@ -95,6 +121,246 @@ metrics:
 		assert.Equal(t, 9999, cfg.Metrics.Port)
 	})
 
+	t.Run("api", func(t *testing.T) {
+		buf := bytes.NewBufferString(`
+timezone: UTC
+api:
+  enabled: true
+  interface: 127.0.0.1
+  port: 9998
+`)
+		cfg, err := ReadConfig(buf)
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.NotNil(t, cfg.API)
+		assert.Equal(t, true, cfg.API.Enabled)
+		assert.Equal(t, "127.0.0.1", cfg.API.Interface)
+		assert.Equal(t, 9998, cfg.API.Port)
+	})
+
+	t.Run("sbs", func(t *testing.T) {
+		buf := bytes.NewBufferString(`
+timezone: UTC
+sbs:
+  - name: remote
+    host: 10.10.10.93
+  - name: other
+    host: 10.10.10.94
+    port: 40003
+`)
+		cfg, err := ReadConfig(buf)
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.Equal(t, 2, len(cfg.Sbs))
+		assert.Equal(t, "remote", cfg.Sbs[0].Name)
+		assert.Equal(t, "10.10.10.93", cfg.Sbs[0].Host)
+		assert.Nil(t, cfg.Sbs[0].Port)
+		assert.Equal(t, "other", cfg.Sbs[1].Name)
+		assert.Equal(t, uint16(40003), *cfg.Sbs[1].Port)
+	})
+
+	t.Run("beast", func(t *testing.T) {
+		buf := bytes.NewBufferString(`
+timezone: UTC
+beast:
+  - name: home
+    host: 10.10.10.92
+    latitude: 53.35
+    longitude: -6.26
+    altitude: 60
+  - name: other
+    host: 10.10.10.93
+`)
+		cfg, err := ReadConfig(buf)
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.Equal(t, 2, len(cfg.Beast))
+		assert.Equal(t, "home", cfg.Beast[0].Name)
+		assert.Equal(t, 53.35, *cfg.Beast[0].Latitude)
+		assert.Equal(t, -6.26, *cfg.Beast[0].Longitude)
+		assert.Equal(t, 60.0, *cfg.Beast[0].Altitude)
+		assert.Nil(t, cfg.Beast[1].Latitude)
+		assert.Nil(t, cfg.Beast[1].Longitude)
+		assert.Nil(t, cfg.Beast[1].Altitude)
+	})
+
+	t.Run("avr", func(t *testing.T) {
+		buf := bytes.NewBufferString(`
+timezone: UTC
+avr:
+  - name: sdr
+    host: 10.10.10.94
+    port: 30002
+`)
+		cfg, err := ReadConfig(buf)
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.Equal(t, 1, len(cfg.Avr))
+		assert.Equal(t, "sdr", cfg.Avr[0].Name)
+		assert.Equal(t, "10.10.10.94", cfg.Avr[0].Host)
+		assert.Equal(t, uint16(30002), *cfg.Avr[0].Port)
+	})
+
+	t.Run("uat", func(t *testing.T) {
+		buf := bytes.NewBufferString(`
+timezone: UTC
+uat:
+  - name: uat
+    host: 10.10.10.95
+  - name: uatraw
+    host: 10.10.10.95
+    port: 30978
+    format: raw
+`)
+		cfg, err := ReadConfig(buf)
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.Equal(t, 2, len(cfg.Uat))
+		assert.Equal(t, "uat", cfg.Uat[0].Name)
+		assert.Equal(t, "10.10.10.95", cfg.Uat[0].Host)
+		assert.Nil(t, cfg.Uat[0].Port)
+		assert.Equal(t, "", cfg.Uat[0].Format)
+		assert.Equal(t, uint16(30978), *cfg.Uat[1].Port)
+		assert.Equal(t, "raw", cfg.Uat[1].Format)
+	})
+
+	t.Run("adsbx v2", func(t *testing.T) {
+		buf := bytes.NewBufferString(`
+timezone: UTC
+adsbx:
+  apikey: abcd
+  version: v2
+  interval: 5
+  query:
+    mode: radius
+    latitude: 53.35
+    longitude: -6.26
+    distance: 50
+`)
+		cfg, err := ReadConfig(buf)
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.NotNil(t, cfg.AdsbxConfig)
+		assert.Equal(t, "abcd", cfg.AdsbxConfig.APIKey)
+		assert.Equal(t, "v2", cfg.AdsbxConfig.Version)
+		assert.Equal(t, int64(5), cfg.AdsbxConfig.Interval)
+		assert.NotNil(t, cfg.AdsbxConfig.Query)
+		assert.Equal(t, "radius", cfg.AdsbxConfig.Query.Mode)
+		assert.Equal(t, 53.35, cfg.AdsbxConfig.Query.Latitude)
+		assert.Equal(t, -6.26, cfg.AdsbxConfig.Query.Longitude)
+		assert.Equal(t, int64(50), cfg.AdsbxConfig.Query.Distance)
+	})
+
+	t.Run("output", func(t *testing.T) {
+		buf := bytes.NewBufferString(`
+timezone: UTC
+output:
+  - format: beast
+    port: 30105
+  - format: sbs
+    interface: 127.0.0.1
+    port: 30103
+    project: global
+`)
+		cfg, err := ReadConfig(buf)
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.Equal(t, 2, len(cfg.Output))
+		assert.Equal(t, "beast", cfg.Output[0].Format)
+		assert.Equal(t, uint16(30105), cfg.Output[0].Port)
+		assert.Equal(t, "", cfg.Output[0].Project)
+		assert.Equal(t, "sbs", cfg.Output[1].Format)
+		assert.Equal(t, "127.0.0.1", cfg.Output[1].Interface)
+		assert.Equal(t, "global", cfg.Output[1].Project)
+	})
+
+	t.Run("zones", func(t *testing.T) {
+		buf := bytes.NewBufferString(`
+timezone: UTC
+zones:
+  - name: home
+    latitude: 53.421333
+    longitude: -6.270075
+    radius: 5000
+  - name: box
+    polygon:
+      - [53, -7]
+      - [54, -7]
+      - [54, -6]
+  - file: /etc/airtrack/zones.geojson
+`)
+		cfg, err := ReadConfig(buf)
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.Equal(t, 3, len(cfg.Zones))
+		assert.Equal(t, "home", cfg.Zones[0].Name)
+		assert.Equal(t, 53.421333, cfg.Zones[0].Latitude)
+		assert.Equal(t, -6.270075, cfg.Zones[0].Longitude)
+		assert.Equal(t, 5000.0, cfg.Zones[0].Radius)
+		assert.Equal(t, "box", cfg.Zones[1].Name)
+		assert.Equal(t, [][2]float64{{53, -7}, {54, -7}, {54, -6}}, cfg.Zones[1].Polygon)
+		assert.Equal(t, "/etc/airtrack/zones.geojson", cfg.Zones[2].File)
+	})
+
+	t.Run("watchlists", func(t *testing.T) {
+		buf := bytes.NewBufferString(`
+timezone: UTC
+watchlists:
+  - name: gov
+    file: /etc/airtrack/gov.csv
+  - name: medical
+    file: /etc/airtrack/medical.json
+    label: air ambulance
+`)
+		cfg, err := ReadConfig(buf)
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.Equal(t, 2, len(cfg.Watchlists))
+		assert.Equal(t, "gov", cfg.Watchlists[0].Name)
+		assert.Equal(t, "/etc/airtrack/gov.csv", cfg.Watchlists[0].File)
+		assert.Equal(t, "", cfg.Watchlists[0].Label)
+		assert.Equal(t, "medical", cfg.Watchlists[1].Name)
+		assert.Equal(t, "air ambulance", cfg.Watchlists[1].Label)
+	})
+
+	t.Run("checkpoint", func(t *testing.T) {
+		buf := bytes.NewBufferString(`
+timezone: UTC
+checkpoint:
+  file: /var/lib/airtrack/checkpoint.json
+  max_age: 300
+`)
+		cfg, err := ReadConfig(buf)
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.NotNil(t, cfg.Checkpoint)
+		assert.Equal(t, "/var/lib/airtrack/checkpoint.json", cfg.Checkpoint.File)
+		assert.Nil(t, cfg.Checkpoint.Interval)
+		assert.NotNil(t, cfg.Checkpoint.MaxAge)
+		assert.Equal(t, int64(300), *cfg.Checkpoint.MaxAge)
+	})
+
+	t.Run("aircraft_json", func(t *testing.T) {
+		buf := bytes.NewBufferString(`
+timezone: UTC
+aircraft_json:
+  - name: local
+    file: /run/readsb/aircraft.json
+  - name: remote
+    url: http://10.10.10.95/tar1090/data/aircraft.json
+    interval: 5
+`)
+		cfg, err := ReadConfig(buf)
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.Equal(t, 2, len(cfg.AircraftJSON))
+		assert.Equal(t, "local", cfg.AircraftJSON[0].Name)
+		assert.Equal(t, "/run/readsb/aircraft.json", cfg.AircraftJSON[0].File)
+		assert.Equal(t, int64(0), cfg.AircraftJSON[0].Interval)
+		assert.Equal(t, "http://10.10.10.95/tar1090/data/aircraft.json", cfg.AircraftJSON[1].URL)
+		assert.Equal(t, int64(5), cfg.AircraftJSON[1].Interval)
+	})
+
 	t.Run("sighting", func(t *testing.T) {
 		buf := bytes.NewBufferString(`
 timezone: UTC
@@ -102,6 +368,7 @@ encryption:
   key: G7ZgLnbGr9YVI+w+rHEhs2MDtVxLI68AqMWv+9dl0zk=
 sighting:
   timeout: 60
+  dedup_window_ms: 250
 `)
 		cfg, err := ReadConfig(buf)
 		assert.NoError(t, err)
@@ -109,6 +376,7 @@ sighting:
 		assert.Equal(t, "UTC", *cfg.TimeZone, "parsed timezone should match")
 		assert.NotNil(t, cfg.Sighting)
 		assert.Equal(t, int64(60), *cfg.Sighting.Timeout)
+		assert.Equal(t, int64(250), *cfg.Sighting.DedupWindowMs)
 	})
 
 	t.Run("database", func(t *testing.T) {
@@ -0,0 +1,219 @@
+// Package coverage measures the range of receivers with a known location.
+// Each receiver's coverage is a polar outline containing the furthest
+// position heard in each direction.
+package coverage
+
+import (
+	"github.com/afk11/airtrack/pkg/geo"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// Sectors - the number of sectors the outline is divided
+	// into. Each sector covers one degree of bearing.
+	Sectors = 360
+	// DefaultMaxRange - positions further than this distance in
+	// meters from the receiver are ignored, as they can't have
+	// been received directly
+	DefaultMaxRange float64 = 600000
+)
+
+var (
+	maxRangeVec = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "airtrack",
+			Name:      "receiver_max_range_meters",
+			Help:      "The furthest distance a position was received from, per receiver",
+		},
+		[]string{"receiver"},
+	)
+)
+
+type (
+	// Point is the furthest position received in a sector
+	Point struct {
+		// Bearing - the start of the sector, in degrees
+		Bearing int `json:"bearing"`
+		// Distance - distance from the receiver in meters
+		Distance  float64 `json:"distance"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+		// Altitude - barometric altitude in feet, if known
+		Altitude *int64    `json:"altitude,omitempty"`
+		Time     time.Time `json:"time"`
+	}
+	// Outline contains the location and coverage of a receiver.
+	// Points are ordered by bearing, and sectors where nothing
+	// was received are omitted.
+	Outline struct {
+		Receiver  string   `json:"receiver"`
+		Latitude  float64  `json:"latitude"`
+		Longitude float64  `json:"longitude"`
+		Altitude  *float64 `json:"altitude,omitempty"`
+		// MaxRange - the furthest distance in meters
+		MaxRange float64 `json:"max_range"`
+		Points   []Point `json:"points"`
+	}
+	// receiver contains the location of a receiver, and the
+	// furthest point in each sector.
+	receiver struct {
+		name      string
+		latitude  float64
+		longitude float64
+		altitude  *float64
+		maxRange  float64
+		sectors   [Sectors]*Point
+	}
+	// Coverage contains the receivers in the order they were
+	// added. It's safe for concurrent use.
+	Coverage struct {
+		maxRange  float64
+		receivers []*receiver
+		byName    map[string]*receiver
+		mu        sync.RWMutex
+	}
+)
+
+// NewCoverage creates an empty Coverage. Positions further
+// than maxRange meters from the receiver are ignored.
+func NewCoverage(maxRange float64) *Coverage {
+	return &Coverage{
+		maxRange: maxRange,
+		byName:   make(map[string]*receiver),
+	}
+}
+
+// AddReceiver adds the location of the named receiver. altitude
+// is the height of the antenna in meters, and can be nil. If
+// the receiver exists, its coverage is reset if it moved.
+func (c *Coverage) AddReceiver(name string, latitude, longitude float64, altitude *float64) error {
+	if latitude < -90 || latitude > 90 {
+		return errors.Errorf("invalid latitude for receiver '%s'", name)
+	} else if longitude < -180 || longitude > 180 {
+		return errors.Errorf("invalid longitude for receiver '%s'", name)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if r, ok := c.byName[name]; ok {
+		if r.latitude != latitude || r.longitude != longitude {
+			r.latitude, r.longitude = latitude, longitude
+			r.maxRange = 0
+			r.sectors = [Sectors]*Point{}
+			maxRangeVec.WithLabelValues(name).Set(0)
+		}
+		r.altitude = altitude
+		return nil
+	}
+	r := &receiver{
+		name:      name,
+		latitude:  latitude,
+		longitude: longitude,
+		altitude:  altitude,
+	}
+	c.receivers = append(c.receivers, r)
+	c.byName[name] = r
+	maxRangeVec.WithLabelValues(name).Set(0)
+	return nil
+}
+
+// RemoveReceiver removes the named receiver and its coverage
+func (c *Coverage) RemoveReceiver(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.byName[name]; !ok {
+		return
+	}
+	delete(c.byName, name)
+	for i := range c.receivers {
+		if c.receivers[i].name == name {
+			c.receivers = append(c.receivers[:i], c.receivers[i+1:]...)
+			break
+		}
+	}
+	maxRangeVec.DeleteLabelValues(name)
+}
+
+// HasReceiver returns true if the receiver's location is known
+func (c *Coverage) HasReceiver(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.byName[name]
+	return ok
+}
+
+// Record measures the distance and bearing of a position heard by
+// the named receiver, and updates its outline if it's the furthest
+// in its sector. altitude can be nil if unknown. The distance and
+// bearing are returned, and ok is false if the receiver's location
+// isn't known or the position is beyond the maximum range.
+func (c *Coverage) Record(name string, latitude, longitude float64, altitude *int64, now time.Time) (distance float64, bearing float64, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, known := c.byName[name]
+	if !known {
+		return 0, 0, false
+	}
+	distance = geo.Distance(r.latitude, r.longitude, latitude, longitude)
+	if distance > c.maxRange {
+		return distance, 0, false
+	}
+	bearing = geo.Bearing(r.latitude, r.longitude, latitude, longitude)
+	sector := int(math.Floor(bearing)) % Sectors
+	if p := r.sectors[sector]; p == nil || distance > p.Distance {
+		r.sectors[sector] = &Point{
+			Bearing:   sector,
+			Distance:  distance,
+			Latitude:  latitude,
+			Longitude: longitude,
+			Altitude:  altitude,
+			Time:      now,
+		}
+	}
+	if distance > r.maxRange {
+		r.maxRange = distance
+		maxRangeVec.WithLabelValues(name).Set(distance)
+	}
+	return distance, bearing, true
+}
+
+// Receivers returns the names of the receivers, in
+// the order they were added.
+func (c *Coverage) Receivers() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := make([]string, 0, len(c.receivers))
+	for _, r := range c.receivers {
+		names = append(names, r.name)
+	}
+	return names
+}
+
+// Outline returns the coverage of the named receiver. False
+// is returned if the receiver's location isn't known.
+func (c *Coverage) Outline(name string) (*Outline, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	r, ok := c.byName[name]
+	if !ok {
+		return nil, false
+	}
+	o := &Outline{
+		Receiver:  r.name,
+		Latitude:  r.latitude,
+		Longitude: r.longitude,
+		Altitude:  r.altitude,
+		MaxRange:  r.maxRange,
+		Points:    make([]Point, 0, Sectors),
+	}
+	for _, p := range r.sectors {
+		if p != nil {
+			o.Points = append(o.Points, *p)
+		}
+	}
+	return o, true
+}
@@ -0,0 +1,65 @@
+package coverage
+
+import (
+	assert "github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCoverage(t *testing.T) {
+	now := time.Now()
+	c := NewCoverage(DefaultMaxRange)
+	assert.Error(t, c.AddReceiver("home", 91, 0, nil))
+	assert.Error(t, c.AddReceiver("home", 0, 181, nil))
+	alt := 60.0
+	assert.NoError(t, c.AddReceiver("home", 53.35, -6.26, &alt))
+	assert.True(t, c.HasReceiver("home"))
+
+	// unknown receivers are ignored
+	_, _, ok := c.Record("other", 53.5, -6.26, nil, now)
+	assert.False(t, ok)
+
+	// north
+	fl := int64(35000)
+	distance, bearing, ok := c.Record("home", 54.35, -6.26, &fl, now)
+	assert.True(t, ok)
+	assert.InDelta(t, 111000, distance, 1000)
+	assert.InDelta(t, 0, bearing, 0.001)
+	// closer in the same sector
+	_, _, ok = c.Record("home", 53.85, -6.26, nil, now)
+	assert.True(t, ok)
+	// east
+	_, bearing, ok = c.Record("home", 53.35, -5.26, nil, now)
+	assert.True(t, ok)
+	assert.InDelta(t, 90, bearing, 0.5)
+	// too far away
+	_, _, ok = c.Record("home", 40.0, -6.26, nil, now)
+	assert.False(t, ok)
+
+	o, ok := c.Outline("home")
+	assert.True(t, ok)
+	assert.Equal(t, "home", o.Receiver)
+	assert.Equal(t, &alt, o.Altitude)
+	assert.Equal(t, 2, len(o.Points))
+	assert.Equal(t, 0, o.Points[0].Bearing)
+	assert.Equal(t, 54.35, o.Points[0].Latitude)
+	assert.Equal(t, &fl, o.Points[0].Altitude)
+	assert.Equal(t, 89, o.Points[1].Bearing)
+	assert.Equal(t, o.Points[0].Distance, o.MaxRange)
+
+	// the coverage is reset if the receiver moves
+	assert.NoError(t, c.AddReceiver("home", 53.35, -6.26, nil))
+	o, _ = c.Outline("home")
+	assert.Equal(t, 2, len(o.Points))
+	assert.NoError(t, c.AddReceiver("home", 53.0, -6.0, nil))
+	o, _ = c.Outline("home")
+	assert.Equal(t, 0, len(o.Points))
+	assert.Equal(t, 0.0, o.MaxRange)
+
+	assert.NoError(t, c.AddReceiver("other", 51.47, -0.45, nil))
+	assert.Equal(t, []string{"home", "other"}, c.Receivers())
+	c.RemoveReceiver("home")
+	assert.Equal(t, []string{"other"}, c.Receivers())
+	_, ok = c.Outline("home")
+	assert.False(t, ok)
+}
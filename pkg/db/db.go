@@ -20,6 +20,11 @@ const (
 	// EmailFailed - status of a failed email
 	EmailFailed = 0
 
+	// WebhookPending - status of a pending webhook
+	WebhookPending = 1
+	// WebhookFailed - status of a failed webhook
+	WebhookFailed = 0
+
 	// KmlPlainTextContentType - content type used when
 	// sighting_kml kml record is encoded in plain text KML
 	KmlPlainTextContentType = 0
@@ -27,6 +32,16 @@ const (
 	// sighting_kml kml record is gzipped KML
 	KmlGzipContentType = 1
 
+	// ProvenanceADSB - sighting_location provenance of ADS-B positions
+	ProvenanceADSB = "adsb"
+	// ProvenanceMLAT - sighting_location provenance of positions
+	// calculated by multilateration
+	ProvenanceMLAT = "mlat"
+	// ProvenanceTISB - sighting_location provenance of TIS-B positions
+	ProvenanceTISB = "tisb"
+	// ProvenanceADSR - sighting_location provenance of ADS-R positions
+	ProvenanceADSR = "adsr"
+
 	projectTable          = "project"
 	sessionTable          = "session"
 	aircraftTable         = "aircraft"
@@ -34,8 +49,11 @@ const (
 	sightingLocationTable = "sighting_location"
 	sightingCallsignTable = "sighting_callsign"
 	sightingSquawkTable   = "sighting_squawk"
+	sightingPhaseTable    = "sighting_phase"
+	sightingReceiverTable = "sighting_receiver"
 	sightingKmlTable      = "sighting_kml"
 	emailTable            = "email"
+	webhookTable          = "webhook"
 	schemaMigrationsTable = "schema_migrations"
 )
 
@@ -87,6 +105,33 @@ type (
 
 		TransmissionTypes uint8   `db:"transmission_types"`
 		Squawk            *string `db:"squawk"`
+
+		OriginCode      *string  `db:"origin_code"`
+		OriginName      *string  `db:"origin_name"`
+		OriginLatitude  *float64 `db:"origin_latitude"`
+		OriginLongitude *float64 `db:"origin_longitude"`
+		OriginDistance  *float64 `db:"origin_distance"`
+
+		DestinationCode      *string  `db:"destination_code"`
+		DestinationName      *string  `db:"destination_name"`
+		DestinationLatitude  *float64 `db:"destination_latitude"`
+		DestinationLongitude *float64 `db:"destination_longitude"`
+		DestinationDistance  *float64 `db:"destination_distance"`
+	}
+	// SightingAirport contains the airport a sighting departed from
+	// or arrived at, as determined by the geocode_endpoints feature.
+	SightingAirport struct {
+		// Code - ICAO airport code, eg, EIDW
+		Code string
+		// Name - airport name, eg, Dublin
+		Name string
+		// Latitude - airport latitude
+		Latitude float64
+		// Longitude - airport longitude
+		Longitude float64
+		// Distance - distance in meters between the airport and
+		// the aircraft's position
+		Distance float64
 	}
 	// SightingCallSign database record. Created for the first callsign
 	// and for newly adopted callsign.
@@ -113,6 +158,9 @@ type (
 		Altitude   int64     `db:"altitude"`
 		Latitude   float64   `db:"latitude"`
 		Longitude  float64   `db:"longitude"`
+		// Provenance - how the position was obtained, one of the Provenance
+		// constants. Nil if unknown.
+		Provenance *string `db:"provenance"`
 	}
 	// SightingSquawk database record. Created for the first squawk, and for
 	// newly adopted squawks.
@@ -122,6 +170,25 @@ type (
 		Squawk     string    `db:"squawk"`
 		ObservedAt time.Time `db:"observed_at"`
 	}
+	// SightingPhase database record. Created each time the
+	// aircraft enters a new flight phase.
+	SightingPhase struct {
+		ID         uint64    `db:"id"`
+		SightingID uint64    `db:"sighting_id"`
+		Phase      string    `db:"phase"`
+		ObservedAt time.Time `db:"observed_at"`
+	}
+	// SightingReceiver database record. Created for each receiver
+	// which heard the aircraft during the sighting.
+	SightingReceiver struct {
+		ID         uint64    `db:"id"`
+		SightingID uint64    `db:"sighting_id"`
+		Receiver   string    `db:"receiver"`
+		FirstSeen  time.Time `db:"first_seen"`
+		LastSeen   time.Time `db:"last_seen"`
+		// BestRssi is the strongest signal received, if known
+		BestRssi *float64 `db:"best_rssi"`
+	}
 	// Email database record. Contains the encoded job, as well as information
 	// relating to it's pending status. Will be deleted if successfully processed,
 	// otherwise will be left in the failed state.
@@ -134,6 +201,42 @@ type (
 		UpdatedAt  time.Time  `db:"updated_at"`
 		Job        []byte
 	}
+	// Webhook database record. Contains the encoded job, as well as information
+	// relating to it's pending status. Will be deleted if successfully delivered,
+	// otherwise will be left in the failed state.
+	Webhook struct {
+		ID         uint64     `db:"id"`
+		Status     int32      `db:"status"`
+		Retries    int32      `db:"retries"`
+		RetryAfter *time.Time `db:"retry_after"`
+		CreatedAt  time.Time  `db:"created_at"`
+		UpdatedAt  time.Time  `db:"updated_at"`
+		Job        []byte
+	}
+	// AircraftSighting is a Sighting record along with
+	// the aircraft's ICAO.
+	AircraftSighting struct {
+		Sighting
+		Icao string `db:"icao"`
+	}
+	// SightingQuery contains search parameters for SearchSightings.
+	// Zero values are ignored.
+	SightingQuery struct {
+		// Since - only return sightings created at or after this time
+		Since *time.Time
+		// Icao - only return sightings of this aircraft
+		Icao string
+		// CallSign - only return sightings with this callsign
+		CallSign string
+		// Origin - only return sightings which departed from this airport code
+		Origin string
+		// Destination - only return sightings which arrived at this airport code
+		Destination string
+		// AfterID - only return sightings with an ID greater than this value
+		AfterID uint64
+		// Limit - maximum number of sightings to return
+		Limit uint
+	}
 	// SchemaMigrations database record. Contains information
 	// about state of database migrations.
 	SchemaMigrations struct {
@@ -212,15 +315,24 @@ type Database interface {
 	// GetProject searches for a Project by its name. If the project exists
 	// it will be returned. Otherwise an error will be returned.
 	GetProject(projectName string) (*Project, error)
+	// GetProjects returns all Project records, ordered by ID. An error is
+	// returned if the query fails.
+	GetProjects() ([]Project, error)
 
 	// CreateSession creates a new Session for a particular project.
 	CreateSession(project *Project, identifier string, withSquawks bool, withTxTypes bool, withCallSigns bool) (sql.Result, error)
 	// GetSessionByIdentifier searches for a Session belonging to the provided project.
 	// If the Session exists it will be returned. Otherwise an error is returned.
 	GetSessionByIdentifier(project *Project, identifier string) (*Session, error)
+	// GetProjectSessions returns the Session records belonging to the provided project,
+	// ordered by ID. An error is returned if the query fails.
+	GetProjectSessions(project *Project) ([]Session, error)
 	// CloseSession marks the Session as closed. The sql.Result is returned
 	// if the query was successful, otherwise an error is returned.
 	CloseSession(session *Session, closedAt time.Time) (sql.Result, error)
+	// UpdateSessionFeatures updates the features recorded by the Session. The
+	// sql.Result is returned if the query was successful, otherwise an error is returned.
+	UpdateSessionFeatures(session *Session, withSquawks bool, withTxTypes bool, withCallSigns bool) (sql.Result, error)
 
 	// GetAircraftByIcao searches for an Aircraft using it's hex ICAO. If the
 	// Aircraft exists it will be returned. Otherwise an error is returned.
@@ -241,6 +353,21 @@ type Database interface {
 	// GetSightingById searches for a Sighting with the provided ID. The Sighting is returned
 	// if one was found. Otherwise an error is returned.
 	GetSightingByID(sightingID uint64) (*Sighting, error)
+	// SearchSightings returns the project's sightings which match q, ordered
+	// by ID. An error is returned if the query fails.
+	SearchSightings(project *Project, q SightingQuery) ([]AircraftSighting, error)
+	// GetSightingCallSigns returns the SightingCallSign records for the sighting,
+	// ordered by ID. An error is returned if the query fails.
+	GetSightingCallSigns(sighting *Sighting) ([]SightingCallSign, error)
+	// GetSightingSquawks returns the SightingSquawk records for the sighting,
+	// ordered by ID. An error is returned if the query fails.
+	GetSightingSquawks(sighting *Sighting) ([]SightingSquawk, error)
+	// GetSightingPhases returns the SightingPhase records for the sighting,
+	// ordered by ID. An error is returned if the query fails.
+	GetSightingPhases(sighting *Sighting) ([]SightingPhase, error)
+	// GetSightingReceivers returns the SightingReceiver records for the sighting,
+	// ordered by ID. An error is returned if the query fails.
+	GetSightingReceivers(sighting *Sighting) ([]SightingReceiver, error)
 	// GetSightingById searches for a Sighting with the provided ID, executing the query
 	// with the provided transaction. The Sighting is returned if one was found. Otherwise
 	// an error is returned.
@@ -268,6 +395,12 @@ type Database interface {
 	// UpdateSightingSquawkTx updates the Sighting.Squawk to the provided squawk.
 	// A sql.Result is returned if the query is successful. Otherwise an error is returned.
 	UpdateSightingSquawkTx(tx *sqlx.Tx, sighting *Sighting, squawk string) (sql.Result, error)
+	// UpdateSightingOriginTx sets the Sighting's origin airport to the provided airport.
+	// A sql.Result is returned if the query is successful. Otherwise an error is returned.
+	UpdateSightingOriginTx(tx *sqlx.Tx, sighting *Sighting, airport SightingAirport) (sql.Result, error)
+	// UpdateSightingDestinationTx sets the Sighting's destination airport to the provided airport.
+	// A sql.Result is returned if the query is successful. Otherwise an error is returned.
+	UpdateSightingDestinationTx(tx *sqlx.Tx, sighting *Sighting, airport SightingAirport) (sql.Result, error)
 
 	// CreateNewSightingCallSignTx inserts a new SightingCallSign for a sighting, executing
 	// the query on the provided tx. A sql.Result is returned if the query was successful.
@@ -277,14 +410,27 @@ type Database interface {
 	// the query on the provided tx. A sql.Result is returned if the query was successful.
 	// Otherwise an error is returned.
 	CreateNewSightingSquawkTx(tx *sqlx.Tx, sighting *Sighting, squawk string, observedAt time.Time) (sql.Result, error)
-
-	// CreateSightingLocation inserts a new SightingCallSign for a sighting. A sql.Result is
-	// returned if the query was successful. Otherwise an error is returned.
-	CreateSightingLocation(sightingID uint64, t time.Time, altitude int64, lat float64, long float64) (sql.Result, error)
-	// CreateSightingLocationTx inserts a new SightingLocation for a sighting, executing
+	// CreateNewSightingPhaseTx inserts a new SightingPhase for a sighting, executing
 	// the query on the provided tx. A sql.Result is returned if the query was successful.
 	// Otherwise an error is returned.
-	CreateSightingLocationTx(tx *sqlx.Tx, sightingID uint64, t time.Time, altitude int64, lat float64, long float64) (sql.Result, error)
+	CreateNewSightingPhaseTx(tx *sqlx.Tx, sighting *Sighting, phase string, observedAt time.Time) (sql.Result, error)
+	// CreateSightingReceiverTx inserts a new SightingReceiver for a sighting, executing
+	// the query on the provided tx. bestRssi can be nil if the signal strength is unknown.
+	// A sql.Result is returned if the query was successful. Otherwise an error is returned.
+	CreateSightingReceiverTx(tx *sqlx.Tx, sighting *Sighting, receiver string, firstSeen time.Time, lastSeen time.Time, bestRssi *float64) (sql.Result, error)
+	// UpdateSightingReceiverTx updates the last seen time and best signal strength of
+	// the sighting's receiver, executing the query on the provided tx. A sql.Result is
+	// returned if the query was successful. Otherwise an error is returned.
+	UpdateSightingReceiverTx(tx *sqlx.Tx, sighting *Sighting, receiver string, lastSeen time.Time, bestRssi *float64) (sql.Result, error)
+
+	// CreateSightingLocation inserts a new SightingCallSign for a sighting. provenance can
+	// be nil if it's unknown. A sql.Result is returned if the query was successful.
+	// Otherwise an error is returned.
+	CreateSightingLocation(sightingID uint64, t time.Time, altitude int64, lat float64, long float64, provenance *string) (sql.Result, error)
+	// CreateSightingLocationTx inserts a new SightingLocation for a sighting, executing
+	// the query on the provided tx. provenance can be nil if it's unknown. A sql.Result
+	// is returned if the query was successful. Otherwise an error is returned.
+	CreateSightingLocationTx(tx *sqlx.Tx, sightingID uint64, t time.Time, altitude int64, lat float64, long float64, provenance *string) (sql.Result, error)
 	// LoadLocationHistory searches for SightingLocation records for the provided Sighting.
 	// lastID should initially be zero, and in subsequent calls the ID of the last processed
 	// row should be used instead. At most batchSize results will be returned. If the query
@@ -326,6 +472,23 @@ type Database interface {
 	// RetryEmailAfterTx updates the job records retryAfter to the provided retryAfter value.
 	// A sql.Result is returned if the query was successful, otherwise an error is returned.
 	RetryEmailAfterTx(tx *sqlx.Tx, job *Email, retryAfter time.Time) (sql.Result, error)
+
+	// CreateWebhookJobTx inserts a new Webhook record, executing the query on the provided tx. A
+	// sql.Result is returned if the query was successful, otherwise an error is returned.
+	CreateWebhookJobTx(tx *sqlx.Tx, createdAt time.Time, content []byte) (sql.Result, error)
+	// GetPendingWebhookJobs searches for non-failed webhooks with a retryTime less than or equal to
+	// currentTime. A list of Webhook records is returned if successful, otherwise an error is
+	// returned.
+	GetPendingWebhookJobs(currentTime time.Time) ([]Webhook, error)
+	// DeleteCompletedWebhookTx deletes the specified job, executing the query on the provided tx.
+	// A sql.Result is returned if the query was successful, otherwise an error is returned.
+	DeleteCompletedWebhookTx(tx *sqlx.Tx, job Webhook) (sql.Result, error)
+	// MarkWebhookFailedTx sets job's status to failed, executing the query on the provided tx.
+	// A sql.Result is returned if the query was successful, otherwise an error is returned.
+	MarkWebhookFailedTx(tx *sqlx.Tx, job *Webhook) (sql.Result, error)
+	// RetryWebhookAfterTx updates the job records retryAfter to the provided retryAfter value.
+	// A sql.Result is returned if the query was successful, otherwise an error is returned.
+	RetryWebhookAfterTx(tx *sqlx.Tx, job *Webhook, retryAfter time.Time) (sql.Result, error)
 }
 
 // DatabaseImpl - Implements Database.
@@ -377,6 +540,25 @@ func (d *DatabaseImpl) GetProject(name string) (*Project, error) {
 	return &project, err
 }
 
+// GetProjects - see Database.GetProjects
+// Does not return sql.ErrNoRows
+func (d *DatabaseImpl) GetProjects() ([]Project, error) {
+	s, p, err := d.dialect.
+		From(projectTable).
+		Prepared(true).
+		Order(goqu.C("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	var projects []Project
+	err = d.db.Select(&projects, s, p...)
+	if err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
 // CreateSession - see Database.CreateSession
 func (d *DatabaseImpl) CreateSession(project *Project, identifier string, withSquawks bool, withTxTypes bool, withCallSigns bool) (sql.Result, error) {
 	now := time.Now()
@@ -419,6 +601,26 @@ func (d *DatabaseImpl) GetSessionByIdentifier(project *Project, identifier strin
 	return session, nil
 }
 
+// GetProjectSessions - see Database.GetProjectSessions
+// Does not return sql.ErrNoRows
+func (d *DatabaseImpl) GetProjectSessions(project *Project) ([]Session, error) {
+	s, p, err := d.dialect.
+		From(sessionTable).
+		Prepared(true).
+		Where(goqu.C("project_id").Eq(project.ID)).
+		Order(goqu.C("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	var sessions []Session
+	err = d.db.Select(&sessions, s, p...)
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
 // CloseSession - see Database.CloseSession
 func (d *DatabaseImpl) CloseSession(session *Session, closedAt time.Time) (sql.Result, error) {
 	s, p, err := d.dialect.
@@ -440,6 +642,34 @@ func (d *DatabaseImpl) CloseSession(session *Session, closedAt time.Time) (sql.R
 	return res, nil
 }
 
+// UpdateSessionFeatures - see Database.UpdateSessionFeatures
+func (d *DatabaseImpl) UpdateSessionFeatures(session *Session, withSquawks bool, withTxTypes bool, withCallSigns bool) (sql.Result, error) {
+	now := time.Now()
+	s, p, err := d.dialect.
+		Update(sessionTable).
+		Prepared(true).
+		Set(goqu.Ex{
+			"with_squawks":            withSquawks,
+			"with_transmission_types": withTxTypes,
+			"with_callsigns":          withCallSigns,
+			"updated_at":              now,
+		}).
+		Where(goqu.C("id").Eq(session.ID)).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	res, err := d.db.Exec(s, p...)
+	if err != nil {
+		return nil, err
+	}
+	session.WithSquawks = withSquawks
+	session.WithTransmissionTypes = withTxTypes
+	session.WithCallSigns = withCallSigns
+	session.UpdatedAt = now
+	return res, nil
+}
+
 // GetAircraftByIcao - see Database.GetAircraftByIcao
 func (d *DatabaseImpl) GetAircraftByIcao(icao string) (*Aircraft, error) {
 	s, p, err := d.dialect.
@@ -652,6 +882,64 @@ func (d *DatabaseImpl) UpdateSightingSquawkTx(tx *sqlx.Tx, sighting *Sighting, s
 	return res, nil
 }
 
+// UpdateSightingOriginTx - see Database.UpdateSightingOriginTx
+func (d *DatabaseImpl) UpdateSightingOriginTx(tx *sqlx.Tx, sighting *Sighting, airport SightingAirport) (sql.Result, error) {
+	s, p, err := d.dialect.
+		Update(sightingTable).
+		Prepared(true).
+		Set(goqu.Ex{
+			"origin_code":      airport.Code,
+			"origin_name":      airport.Name,
+			"origin_latitude":  airport.Latitude,
+			"origin_longitude": airport.Longitude,
+			"origin_distance":  airport.Distance,
+		}).
+		Where(goqu.C("id").Eq(sighting.ID)).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	res, err := tx.Exec(s, p...)
+	if err != nil {
+		return nil, err
+	}
+	sighting.OriginCode = &airport.Code
+	sighting.OriginName = &airport.Name
+	sighting.OriginLatitude = &airport.Latitude
+	sighting.OriginLongitude = &airport.Longitude
+	sighting.OriginDistance = &airport.Distance
+	return res, nil
+}
+
+// UpdateSightingDestinationTx - see Database.UpdateSightingDestinationTx
+func (d *DatabaseImpl) UpdateSightingDestinationTx(tx *sqlx.Tx, sighting *Sighting, airport SightingAirport) (sql.Result, error) {
+	s, p, err := d.dialect.
+		Update(sightingTable).
+		Prepared(true).
+		Set(goqu.Ex{
+			"destination_code":      airport.Code,
+			"destination_name":      airport.Name,
+			"destination_latitude":  airport.Latitude,
+			"destination_longitude": airport.Longitude,
+			"destination_distance":  airport.Distance,
+		}).
+		Where(goqu.C("id").Eq(sighting.ID)).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	res, err := tx.Exec(s, p...)
+	if err != nil {
+		return nil, err
+	}
+	sighting.DestinationCode = &airport.Code
+	sighting.DestinationName = &airport.Name
+	sighting.DestinationLatitude = &airport.Latitude
+	sighting.DestinationLongitude = &airport.Longitude
+	sighting.DestinationDistance = &airport.Distance
+	return res, nil
+}
+
 // CloseSightingBatch - see Database.CloseSightingBatch
 func (d *DatabaseImpl) CloseSightingBatch(sightings []*Sighting, closedAt time.Time) error {
 	if len(sightings) == 0 {
@@ -729,6 +1017,51 @@ func (d *DatabaseImpl) GetSightingByID(sightingID uint64) (*Sighting, error) {
 	return sighting, nil
 }
 
+// SearchSightings - see Database.SearchSightings
+// Does not return sql.ErrNoRows
+func (d *DatabaseImpl) SearchSightings(project *Project, q SightingQuery) ([]AircraftSighting, error) {
+	ds := d.dialect.
+		From(sightingTable).
+		Prepared(true).
+		Join(goqu.T(aircraftTable), goqu.On(goqu.Ex{
+			sightingTable + ".aircraft_id": goqu.I(aircraftTable + ".id"),
+		})).
+		Select(goqu.T(sightingTable).All(), goqu.T(aircraftTable).Col("icao")).
+		Where(goqu.T(sightingTable).Col("project_id").Eq(project.ID)).
+		Order(goqu.T(sightingTable).Col("id").Asc())
+	if q.Since != nil {
+		ds = ds.Where(goqu.T(sightingTable).Col("created_at").Gte(*q.Since))
+	}
+	if q.Icao != "" {
+		ds = ds.Where(goqu.T(aircraftTable).Col("icao").Eq(q.Icao))
+	}
+	if q.CallSign != "" {
+		ds = ds.Where(goqu.T(sightingTable).Col("callsign").Eq(q.CallSign))
+	}
+	if q.Origin != "" {
+		ds = ds.Where(goqu.T(sightingTable).Col("origin_code").Eq(q.Origin))
+	}
+	if q.Destination != "" {
+		ds = ds.Where(goqu.T(sightingTable).Col("destination_code").Eq(q.Destination))
+	}
+	if q.AfterID != 0 {
+		ds = ds.Where(goqu.T(sightingTable).Col("id").Gt(q.AfterID))
+	}
+	if q.Limit != 0 {
+		ds = ds.Limit(q.Limit)
+	}
+	s, p, err := ds.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	var sightings []AircraftSighting
+	err = d.db.Select(&sightings, s, p...)
+	if err != nil {
+		return nil, err
+	}
+	return sightings, nil
+}
+
 // GetSightingByIDTx - see Database.GetSightingByIDTx
 func (d *DatabaseImpl) GetSightingByIDTx(tx *sqlx.Tx, sightingID uint64) (*Sighting, error) {
 	s, p, err := d.dialect.
@@ -785,13 +1118,33 @@ func (d *DatabaseImpl) GetLastSightingCallSignTx(tx *sqlx.Tx, sighting *Sighting
 	return &callsign, nil
 }
 
+// GetSightingCallSigns - see Database.GetSightingCallSigns
+// Does not return sql.ErrNoRows
+func (d *DatabaseImpl) GetSightingCallSigns(sighting *Sighting) ([]SightingCallSign, error) {
+	s, p, err := d.dialect.
+		From(sightingCallsignTable).
+		Prepared(true).
+		Where(goqu.C("sighting_id").Eq(sighting.ID)).
+		Order(goqu.C("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	var callsigns []SightingCallSign
+	err = d.db.Select(&callsigns, s, p...)
+	if err != nil {
+		return nil, err
+	}
+	return callsigns, nil
+}
+
 // CreateSightingLocation - see Database.CreateSightingLocation
-func (d *DatabaseImpl) CreateSightingLocation(sightingID uint64, t time.Time, altitude int64, lat float64, long float64) (sql.Result, error) {
+func (d *DatabaseImpl) CreateSightingLocation(sightingID uint64, t time.Time, altitude int64, lat float64, long float64, provenance *string) (sql.Result, error) {
 	s, p, err := d.dialect.
 		Insert(sightingLocationTable).
 		Prepared(true).
-		Cols("sighting_id", "timestamp", "altitude", "latitude", "longitude").
-		Vals(goqu.Vals{sightingID, t, altitude, lat, long}).
+		Cols("sighting_id", "timestamp", "altitude", "latitude", "longitude", "provenance").
+		Vals(goqu.Vals{sightingID, t, altitude, lat, long, provenance}).
 		ToSQL()
 	if err != nil {
 		return nil, err
@@ -801,12 +1154,12 @@ func (d *DatabaseImpl) CreateSightingLocation(sightingID uint64, t time.Time, al
 }
 
 // CreateSightingLocationTx - see Database.CreateSightingLocationTx
-func (d *DatabaseImpl) CreateSightingLocationTx(tx *sqlx.Tx, sightingID uint64, t time.Time, altitude int64, lat float64, long float64) (sql.Result, error) {
+func (d *DatabaseImpl) CreateSightingLocationTx(tx *sqlx.Tx, sightingID uint64, t time.Time, altitude int64, lat float64, long float64, provenance *string) (sql.Result, error) {
 	s, p, err := d.dialect.
 		Insert(sightingLocationTable).
 		Prepared(true).
-		Cols("sighting_id", "timestamp", "altitude", "latitude", "longitude").
-		Vals(goqu.Vals{sightingID, t, altitude, lat, long}).
+		Cols("sighting_id", "timestamp", "altitude", "latitude", "longitude", "provenance").
+		Vals(goqu.Vals{sightingID, t, altitude, lat, long, provenance}).
 		ToSQL()
 	if err != nil {
 		return nil, err
@@ -824,6 +1177,7 @@ func (d *DatabaseImpl) LoadLocationHistory(sighting *Sighting, lastID int64, bat
 			"sighting_id": sighting.ID,
 			"id":          goqu.Op{"gt": lastID},
 		}).
+		Order(goqu.C("id").Asc()).
 		Limit(uint(batchSize)).
 		ToSQL()
 	if err != nil {
@@ -915,6 +1269,114 @@ func (d *DatabaseImpl) GetLastSightingSquawkTx(tx *sqlx.Tx, sighting *Sighting)
 	return &squawk, nil
 }
 
+// GetSightingSquawks - see Database.GetSightingSquawks
+// Does not return sql.ErrNoRows
+func (d *DatabaseImpl) GetSightingSquawks(sighting *Sighting) ([]SightingSquawk, error) {
+	s, p, err := d.dialect.
+		From(sightingSquawkTable).
+		Prepared(true).
+		Where(goqu.C("sighting_id").Eq(sighting.ID)).
+		Order(goqu.C("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	var squawks []SightingSquawk
+	err = d.db.Select(&squawks, s, p...)
+	if err != nil {
+		return nil, err
+	}
+	return squawks, nil
+}
+
+// CreateNewSightingPhaseTx - see Database.CreateNewSightingPhaseTx
+func (d *DatabaseImpl) CreateNewSightingPhaseTx(tx *sqlx.Tx, sighting *Sighting, phase string, observedAt time.Time) (sql.Result, error) {
+	s, p, err := d.dialect.
+		Insert(sightingPhaseTable).
+		Prepared(true).
+		Cols("sighting_id", "phase", "observed_at").
+		Vals(goqu.Vals{sighting.ID, phase, observedAt}).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	return tx.Exec(s, p...)
+}
+
+// GetSightingPhases - see Database.GetSightingPhases
+// Does not return sql.ErrNoRows
+func (d *DatabaseImpl) GetSightingPhases(sighting *Sighting) ([]SightingPhase, error) {
+	s, p, err := d.dialect.
+		From(sightingPhaseTable).
+		Prepared(true).
+		Where(goqu.C("sighting_id").Eq(sighting.ID)).
+		Order(goqu.C("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	var phases []SightingPhase
+	err = d.db.Select(&phases, s, p...)
+	if err != nil {
+		return nil, err
+	}
+	return phases, nil
+}
+
+// CreateSightingReceiverTx - see Database.CreateSightingReceiverTx
+func (d *DatabaseImpl) CreateSightingReceiverTx(tx *sqlx.Tx, sighting *Sighting, receiver string, firstSeen time.Time, lastSeen time.Time, bestRssi *float64) (sql.Result, error) {
+	s, p, err := d.dialect.
+		Insert(sightingReceiverTable).
+		Prepared(true).
+		Cols("sighting_id", "receiver", "first_seen", "last_seen", "best_rssi").
+		Vals(goqu.Vals{sighting.ID, receiver, firstSeen, lastSeen, bestRssi}).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	return tx.Exec(s, p...)
+}
+
+// UpdateSightingReceiverTx - see Database.UpdateSightingReceiverTx
+func (d *DatabaseImpl) UpdateSightingReceiverTx(tx *sqlx.Tx, sighting *Sighting, receiver string, lastSeen time.Time, bestRssi *float64) (sql.Result, error) {
+	s, p, err := d.dialect.
+		Update(sightingReceiverTable).
+		Prepared(true).
+		Set(goqu.Ex{
+			"last_seen": lastSeen,
+			"best_rssi": bestRssi,
+		}).
+		Where(goqu.Ex{
+			"sighting_id": sighting.ID,
+			"receiver":    receiver,
+		}).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	return tx.Exec(s, p...)
+}
+
+// GetSightingReceivers - see Database.GetSightingReceivers
+// Does not return sql.ErrNoRows
+func (d *DatabaseImpl) GetSightingReceivers(sighting *Sighting) ([]SightingReceiver, error) {
+	s, p, err := d.dialect.
+		From(sightingReceiverTable).
+		Prepared(true).
+		Where(goqu.C("sighting_id").Eq(sighting.ID)).
+		Order(goqu.C("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	var receivers []SightingReceiver
+	err = d.db.Select(&receivers, s, p...)
+	if err != nil {
+		return nil, err
+	}
+	return receivers, nil
+}
+
 // GetSightingKml - see Database.GetSightingKml
 func (d *DatabaseImpl) GetSightingKml(sighting *Sighting) (*SightingKml, error) {
 	s, p, err := d.dialect.
@@ -1089,3 +1551,107 @@ func (d *DatabaseImpl) RetryEmailAfterTx(tx *sqlx.Tx, job *Email, retryAfter tim
 	job.Retries = job.Retries + 1
 	return res, nil
 }
+
+// CreateWebhookJobTx - see Database.CreateWebhookJobTx
+func (d *DatabaseImpl) CreateWebhookJobTx(tx *sqlx.Tx, createdAt time.Time, content []byte) (sql.Result, error) {
+	s, p, err := d.dialect.
+		Insert(webhookTable).
+		Prepared(true).
+		Cols("status", "retries", "created_at", "updated_at", "retry_after", "job").
+		Vals(goqu.Vals{WebhookPending, 0, createdAt, createdAt, nil, content}).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	return tx.Exec(s, p...)
+}
+
+// GetPendingWebhookJobs - see Database.GetPendingWebhookJobs
+// Does not return sql.ErrNoRows
+func (d *DatabaseImpl) GetPendingWebhookJobs(now time.Time) ([]Webhook, error) {
+	s, p, err := d.dialect.
+		From(webhookTable).
+		Prepared(true).
+		Where(goqu.C("status").Eq(WebhookPending)).
+		Where(goqu.Or(
+			goqu.C("retry_after").Eq(nil),
+			goqu.C("retry_after").Lte(now))).
+		Order(goqu.C("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []Webhook
+	rows, err := d.db.Queryx(s, p...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		job := Webhook{}
+		err := rows.StructScan(&job)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// DeleteCompletedWebhookTx - see Database.DeleteCompletedWebhookTx
+func (d *DatabaseImpl) DeleteCompletedWebhookTx(tx *sqlx.Tx, job Webhook) (sql.Result, error) {
+	s, p, err := d.dialect.
+		Delete(webhookTable).
+		Prepared(true).
+		Where(goqu.C("id").Eq(job.ID)).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	return tx.Exec(s, p...)
+}
+
+// MarkWebhookFailedTx - see Database.MarkWebhookFailedTx
+func (d *DatabaseImpl) MarkWebhookFailedTx(tx *sqlx.Tx, job *Webhook) (sql.Result, error) {
+	s, p, err := d.dialect.
+		Update(webhookTable).
+		Prepared(true).
+		Set(goqu.Ex{
+			"status": WebhookFailed,
+		}).
+		Where(goqu.C("id").Eq(job.ID)).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	res, err := tx.Exec(s, p...)
+	if err != nil {
+		return nil, err
+	}
+	job.Status = WebhookFailed
+	return res, nil
+}
+
+// RetryWebhookAfterTx - see Database.RetryWebhookAfterTx
+func (d *DatabaseImpl) RetryWebhookAfterTx(tx *sqlx.Tx, job *Webhook, retryAfter time.Time) (sql.Result, error) {
+	s, p, err := d.dialect.
+		Update(webhookTable).
+		Prepared(true).
+		Set(goqu.Ex{
+			"retry_after": retryAfter,
+			"retries":     job.Retries + 1,
+		}).
+		Where(goqu.C("id").Eq(job.ID)).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	res, err := tx.Exec(s, p...)
+	if err != nil {
+		return nil, err
+	}
+	job.RetryAfter = &retryAfter
+	job.Retries = job.Retries + 1
+	return res, nil
+}
@@ -116,6 +116,14 @@ func TestSession(t *testing.T) {
 	assert.False(t, sess3.WithTransmissionTypes)
 	assert.True(t, sess3.WithCallSigns)
 
+	_, err = database.UpdateSessionFeatures(sess3, true, false, false)
+	assert.NoError(t, err)
+	sess3, err = database.GetSessionByIdentifier(p, ident+"3")
+	assert.NoError(t, err)
+	assert.True(t, sess3.WithSquawks)
+	assert.False(t, sess3.WithTransmissionTypes)
+	assert.False(t, sess3.WithCallSigns)
+
 	closeTime := createdAt.Add(time.Second * 6)
 	_, err = database.CloseSession(sess, closeTime)
 	assert.NoError(t, err)
@@ -302,6 +310,32 @@ func TestSighting(t *testing.T) {
 		return nil
 	}))
 
+	assert.Nil(t, sighting.OriginCode)
+	assert.Nil(t, sighting.DestinationCode)
+	origin := SightingAirport{Code: "EIDW", Name: "Dublin", Latitude: 53.421333, Longitude: -6.270075, Distance: 850.5}
+	destination := SightingAirport{Code: "EGLL", Name: "London Heathrow", Latitude: 51.4775, Longitude: -0.461389, Distance: 1200}
+	assert.NoError(t, database.Transaction(func(tx *sqlx.Tx) error {
+		_, err := database.UpdateSightingOriginTx(tx, sighting, origin)
+		assert.NoError(t, err)
+		_, err = database.UpdateSightingDestinationTx(tx, sighting, destination)
+		assert.NoError(t, err)
+		return nil
+	}))
+	assert.Equal(t, origin.Code, *sighting.OriginCode)
+	assert.Equal(t, destination.Code, *sighting.DestinationCode)
+	sightingByID, err = database.GetSightingByID(sighting.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, origin.Code, *sightingByID.OriginCode)
+	assert.Equal(t, origin.Name, *sightingByID.OriginName)
+	assert.InDelta(t, origin.Latitude, *sightingByID.OriginLatitude, 0.000001)
+	assert.InDelta(t, origin.Longitude, *sightingByID.OriginLongitude, 0.000001)
+	assert.InDelta(t, origin.Distance, *sightingByID.OriginDistance, 0.000001)
+	assert.Equal(t, destination.Code, *sightingByID.DestinationCode)
+	assert.Equal(t, destination.Name, *sightingByID.DestinationName)
+	assert.InDelta(t, destination.Latitude, *sightingByID.DestinationLatitude, 0.000001)
+	assert.InDelta(t, destination.Longitude, *sightingByID.DestinationLongitude, 0.000001)
+	assert.InDelta(t, destination.Distance, *sightingByID.DestinationDistance, 0.000001)
+
 	// close and reopen
 	err = database.CloseSightingBatch([]*Sighting{sighting}, now.Add(time.Second*1))
 	assert.NoError(t, err)
@@ -373,14 +407,15 @@ func TestSightingLocation(t *testing.T) {
 	lat1 := 1.9876523
 	lon1 := 1.234789
 	alt1 := int64(10000)
-	_, err = database.CreateSightingLocation(sighting.ID, now, alt1, lat1, lon1)
+	_, err = database.CreateSightingLocation(sighting.ID, now, alt1, lat1, lon1, nil)
 	assert.NoError(t, err)
 
 	lat2 := 1.9899523
 	lon2 := 1.238889
 	alt2 := int64(10015)
 	assert.NoError(t, database.Transaction(func(tx *sqlx.Tx) error {
-		_, err = database.CreateSightingLocationTx(tx, sighting.ID, now, alt2, lat2, lon2)
+		mlat := ProvenanceMLAT
+		_, err = database.CreateSightingLocationTx(tx, sighting.ID, now, alt2, lat2, lon2, &mlat)
 		assert.NoError(t, err)
 		return nil
 	}))
@@ -392,9 +427,12 @@ func TestSightingLocation(t *testing.T) {
 	assert.InDelta(t, lat1, history[0].Latitude, 0.0000001)
 	assert.InDelta(t, lon1, history[0].Longitude, 0.0000001)
 	assert.Equal(t, alt1, history[0].Altitude)
+	assert.Nil(t, history[0].Provenance)
 	assert.InDelta(t, lat2, history[1].Latitude, 0.0000001)
 	assert.InDelta(t, lon2, history[1].Longitude, 0.0000001)
 	assert.Equal(t, alt2, history[1].Altitude)
+	assert.NotNil(t, history[1].Provenance)
+	assert.Equal(t, ProvenanceMLAT, *history[1].Provenance)
 
 	sightingKml, err := database.GetSightingKml(sighting)
 	assert.Error(t, err)
@@ -495,3 +533,224 @@ func TestEmail(t *testing.T) {
 		return nil
 	}))
 }
+func TestWebhook(t *testing.T) {
+	dbConn, dialect, _, closer := test.InitDBUp()
+	defer closer()
+	database := NewDatabase(dbConn, dialect)
+
+	now := time.Now()
+	rows, err := database.GetPendingWebhookJobs(now)
+	assert.Nil(t, err)
+	assert.Nil(t, rows)
+
+	encoded := []byte(`{"url":"http://127.0.0.1/hook","event":"spotted_in_flight","body":{"event":"spotted_in_flight","project":"unittest","icao":"424242"}}`)
+	assert.NoError(t, database.Transaction(func(tx *sqlx.Tx) error {
+		_, err = database.CreateWebhookJobTx(tx, now, encoded)
+		assert.NoError(t, err)
+		return nil
+	}))
+
+	rows, err = database.GetPendingWebhookJobs(now)
+	assert.Nil(t, err)
+	assert.NotNil(t, rows)
+	assert.Equal(t, 1, len(rows))
+	assert.True(t, bytes.Equal(encoded, rows[0].Job))
+
+	assert.NoError(t, database.Transaction(func(tx *sqlx.Tx) error {
+		_, err := database.DeleteCompletedWebhookTx(tx, rows[0])
+		assert.NoError(t, err)
+		return nil
+	}))
+
+	rows, err = database.GetPendingWebhookJobs(now)
+	assert.Nil(t, err)
+	assert.Nil(t, rows)
+
+	assert.NoError(t, database.Transaction(func(tx *sqlx.Tx) error {
+		_, err = database.CreateWebhookJobTx(tx, now, encoded)
+		assert.NoError(t, err)
+		return nil
+	}))
+	rows, err = database.GetPendingWebhookJobs(now)
+	assert.Nil(t, err)
+	assert.NotNil(t, rows)
+	later := time.Now().Add(time.Second * 10)
+	assert.NoError(t, database.Transaction(func(tx *sqlx.Tx) error {
+		_, err = database.RetryWebhookAfterTx(tx, &rows[0], later)
+		assert.NoError(t, err)
+		assert.Equal(t, later, *rows[0].RetryAfter)
+		assert.Equal(t, int32(1), rows[0].Retries)
+		return nil
+	}))
+
+	// not due until later
+	pending, err := database.GetPendingWebhookJobs(now)
+	assert.Nil(t, err)
+	assert.Nil(t, pending)
+	pending, err = database.GetPendingWebhookJobs(later)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(pending))
+
+	assert.NoError(t, database.Transaction(func(tx *sqlx.Tx) error {
+		_, err = database.MarkWebhookFailedTx(tx, &rows[0])
+		assert.NoError(t, err)
+		assert.Equal(t, int32(WebhookFailed), rows[0].Status)
+		return nil
+	}))
+	pending, err = database.GetPendingWebhookJobs(later)
+	assert.Nil(t, err)
+	assert.Nil(t, pending)
+}
+func TestSearchSightings(t *testing.T) {
+	loc := test.MustLoadTestTimeZone()
+	dbConn, dialect, _, closer := test.InitDBUp()
+	defer closer()
+	database := NewDatabase(dbConn, dialect)
+
+	createdAt := time.Now().In(loc).Add(-time.Hour)
+	_, err := database.CreateProject("testProj", createdAt)
+	assert.NoError(t, err)
+	_, err = database.CreateProject("testProj2", createdAt)
+	assert.NoError(t, err)
+	projects, err := database.GetProjects()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(projects))
+	assert.Equal(t, "testProj", projects[0].Identifier)
+	assert.Equal(t, "testProj2", projects[1].Identifier)
+	p := &projects[0]
+
+	sessions, err := database.GetProjectSessions(p)
+	assert.NoError(t, err)
+	assert.Nil(t, sessions)
+	_, err = database.CreateSession(p, "session1", true, true, true)
+	assert.NoError(t, err)
+	sessions, err = database.GetProjectSessions(p)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(sessions))
+	assert.Equal(t, "session1", sessions[0].Identifier)
+	sess := &sessions[0]
+
+	var sightings []*Sighting
+	for i, icao := range []string{"AAAAAA", "BBBBBB", "AAAAAA"} {
+		ac, err := database.GetAircraftByIcao(icao)
+		if err == sql.ErrNoRows {
+			_, err = database.CreateAircraft(icao, createdAt)
+			assert.NoError(t, err)
+			ac, err = database.GetAircraftByIcao(icao)
+		}
+		assert.NoError(t, err)
+		_, err = database.CreateSighting(sess, ac, createdAt.Add(time.Duration(i)*time.Minute))
+		assert.NoError(t, err)
+		sighting, err := database.GetLastSighting(sess, ac)
+		assert.NoError(t, err)
+		sightings = append(sightings, sighting)
+	}
+	assert.NoError(t, database.Transaction(func(tx *sqlx.Tx) error {
+		_, err := database.UpdateSightingCallsignTx(tx, sightings[1], "CALL1")
+		assert.NoError(t, err)
+		_, err = database.CreateNewSightingCallSignTx(tx, sightings[1], "CALL1", createdAt)
+		assert.NoError(t, err)
+		_, err = database.CreateNewSightingSquawkTx(tx, sightings[1], "7000", createdAt)
+		assert.NoError(t, err)
+		_, err = database.CreateNewSightingPhaseTx(tx, sightings[1], "climb", createdAt)
+		assert.NoError(t, err)
+		_, err = database.CreateNewSightingPhaseTx(tx, sightings[1], "cruise", createdAt.Add(time.Minute))
+		assert.NoError(t, err)
+		rssi := -12.5
+		_, err = database.CreateSightingReceiverTx(tx, sightings[1], "home", createdAt, createdAt, nil)
+		assert.NoError(t, err)
+		_, err = database.CreateSightingReceiverTx(tx, sightings[1], "adsbx", createdAt, createdAt, nil)
+		assert.NoError(t, err)
+		_, err = database.UpdateSightingReceiverTx(tx, sightings[1], "home", createdAt.Add(time.Minute), &rssi)
+		assert.NoError(t, err)
+		_, err = database.UpdateSightingOriginTx(tx, sightings[0], SightingAirport{Code: "EIDW", Name: "Dublin"})
+		assert.NoError(t, err)
+		_, err = database.UpdateSightingDestinationTx(tx, sightings[0], SightingAirport{Code: "EGLL", Name: "London Heathrow"})
+		assert.NoError(t, err)
+		_, err = database.UpdateSightingOriginTx(tx, sightings[2], SightingAirport{Code: "EGLL", Name: "London Heathrow"})
+		assert.NoError(t, err)
+		return nil
+	}))
+
+	found, err := database.SearchSightings(p, SightingQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(found))
+	for i := range found {
+		assert.Equal(t, sightings[i].ID, found[i].ID)
+		assert.Equal(t, sightings[i].AircraftID, found[i].AircraftID)
+	}
+	assert.Equal(t, "AAAAAA", found[0].Icao)
+	assert.Equal(t, "BBBBBB", found[1].Icao)
+
+	found, err = database.SearchSightings(p, SightingQuery{Icao: "AAAAAA"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(found))
+	assert.Equal(t, sightings[0].ID, found[0].ID)
+	assert.Equal(t, sightings[2].ID, found[1].ID)
+
+	found, err = database.SearchSightings(p, SightingQuery{CallSign: "CALL1"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(found))
+	assert.Equal(t, sightings[1].ID, found[0].ID)
+	assert.Equal(t, "CALL1", *found[0].CallSign)
+
+	found, err = database.SearchSightings(p, SightingQuery{Origin: "EGLL"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(found))
+	assert.Equal(t, sightings[2].ID, found[0].ID)
+	assert.Equal(t, "London Heathrow", *found[0].OriginName)
+	assert.Nil(t, found[0].DestinationCode)
+
+	found, err = database.SearchSightings(p, SightingQuery{Destination: "EGLL"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(found))
+	assert.Equal(t, sightings[0].ID, found[0].ID)
+
+	since := createdAt.Add(time.Minute)
+	found, err = database.SearchSightings(p, SightingQuery{Since: &since})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(found))
+	assert.Equal(t, sightings[1].ID, found[0].ID)
+
+	found, err = database.SearchSightings(p, SightingQuery{AfterID: sightings[0].ID, Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(found))
+	assert.Equal(t, sightings[1].ID, found[0].ID)
+
+	found, err = database.SearchSightings(&projects[1], SightingQuery{})
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+
+	callsigns, err := database.GetSightingCallSigns(sightings[1])
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(callsigns))
+	assert.Equal(t, "CALL1", callsigns[0].CallSign)
+	squawks, err := database.GetSightingSquawks(sightings[1])
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(squawks))
+	assert.Equal(t, "7000", squawks[0].Squawk)
+	squawks, err = database.GetSightingSquawks(sightings[0])
+	assert.NoError(t, err)
+	assert.Nil(t, squawks)
+	phases, err := database.GetSightingPhases(sightings[1])
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(phases))
+	assert.Equal(t, "climb", phases[0].Phase)
+	assert.Equal(t, "cruise", phases[1].Phase)
+	assert.Equal(t, sightings[1].ID, phases[1].SightingID)
+	phases, err = database.GetSightingPhases(sightings[0])
+	assert.NoError(t, err)
+	assert.Nil(t, phases)
+	receivers, err := database.GetSightingReceivers(sightings[1])
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(receivers))
+	assert.Equal(t, "home", receivers[0].Receiver)
+	assert.Equal(t, createdAt.Add(time.Minute).Unix(), receivers[0].LastSeen.Unix())
+	assert.NotNil(t, receivers[0].BestRssi)
+	assert.Equal(t, -12.5, *receivers[0].BestRssi)
+	assert.Equal(t, "adsbx", receivers[1].Receiver)
+	assert.Nil(t, receivers[1].BestRssi)
+	receivers, err = database.GetSightingReceivers(sightings[0])
+	assert.NoError(t, err)
+	assert.Nil(t, receivers)
+}
@@ -36,6 +36,7 @@ type (
 		Project       string
 		Icao          string
 		CallSign      string
+		Watchlist     string
 		StartTime     time.Time
 		StartTimeFmt  string
 		StartLocation Location
@@ -47,6 +48,7 @@ type (
 		Project       string
 		Icao          string
 		CallSign      string
+		Watchlist     string
 		StartTime     time.Time
 		EndTime       time.Time
 		DurationFmt   string
@@ -63,6 +65,7 @@ type (
 		Project       string
 		Icao          string
 		CallSign      string
+		Watchlist     string
 		AirportName   string
 		StartTimeFmt  string
 		StartLocation Location
@@ -74,6 +77,7 @@ type (
 		Project       string
 		Icao          string
 		CallSign      string
+		Watchlist     string
 		AirportName   string
 		StartTimeFmt  string
 		StartLocation Location
@@ -85,10 +89,104 @@ type (
 		Project       string
 		Icao          string
 		CallSign      string
+		Watchlist     string
 		StartTimeFmt  string
 		StartLocation Location
 	}
 
+	// LandingParams contains parameters for the
+	// LandingAtAirport template.
+	LandingParams struct {
+		Project         string
+		Icao            string
+		CallSign        string
+		Watchlist       string
+		AirportName     string
+		LandingTimeFmt  string
+		LandingLocation Location
+	}
+
+	// LandingUnknownAirportParams contains parameters for the
+	// LandingUnknownAirport template.
+	LandingUnknownAirportParams struct {
+		Project         string
+		Icao            string
+		CallSign        string
+		Watchlist       string
+		LandingTimeFmt  string
+		LandingLocation Location
+	}
+
+	// EmergencyParams contains parameters for the
+	// Emergency template.
+	EmergencyParams struct {
+		Project     string
+		Icao        string
+		CallSign    string
+		Watchlist   string
+		Operator    string
+		Squawk      string
+		Emergency   string
+		Description string
+		TimeFmt     string
+		Location    Location
+		HaveTrack   bool
+	}
+
+	// ZoneParams contains parameters for the
+	// ZoneEntered and ZoneExited templates.
+	ZoneParams struct {
+		Project   string
+		Icao      string
+		CallSign  string
+		Watchlist string
+		Zone      string
+		TimeFmt   string
+		Location  Location
+	}
+
+	// OrbitingParams contains parameters for the
+	// Orbiting template.
+	OrbitingParams struct {
+		Project   string
+		Icao      string
+		CallSign  string
+		Watchlist string
+		TimeFmt   string
+		// Centre - the centre of the orbit
+		Centre Location
+		// Radius - the radius of the orbit in meters
+		Radius int64
+	}
+
+	// PositionAnomalyParams contains parameters for the
+	// PositionAnomaly template.
+	PositionAnomalyParams struct {
+		Project      string
+		Icao         string
+		CallSign     string
+		Watchlist    string
+		Description  string
+		TimeFmt      string
+		HaveLocation bool
+		// Location - the last accepted position
+		Location Location
+	}
+
+	// RuleParams contains parameters for the
+	// Rule template.
+	RuleParams struct {
+		Project      string
+		Icao         string
+		CallSign     string
+		Watchlist    string
+		Rule         string
+		Condition    string
+		TimeFmt      string
+		HaveLocation bool
+		Location     Location
+	}
+
 	// MailTemplates - map of Emails to parsed template
 	MailTemplates struct {
 		m map[Email]*template.Template
@@ -106,6 +204,22 @@ const (
 	TakeoffFromAirport Email = "takeoff_from_airport.tpl"
 	// TakeoffComplete - the template's name
 	TakeoffComplete Email = "takeoff_complete.tpl"
+	// LandingAtAirport - the template's name
+	LandingAtAirport Email = "landing_at_airport.tpl"
+	// LandingUnknownAirport - the template's name
+	LandingUnknownAirport Email = "landing_unknown_airport.tpl"
+	// Emergency - the template's name
+	Emergency Email = "emergency.tpl"
+	// ZoneEntered - the template's name
+	ZoneEntered Email = "zone_entered.tpl"
+	// ZoneExited - the template's name
+	ZoneExited Email = "zone_exited.tpl"
+	// Rule - the template's name
+	Rule Email = "rule.tpl"
+	// Orbiting - the template's name
+	Orbiting Email = "orbiting.tpl"
+	// PositionAnomaly - the template's name
+	PositionAnomaly Email = "position_anomaly.tpl"
 )
 
 // GetTemplates returns a list of all known templates
@@ -116,6 +230,14 @@ func GetTemplates() []Email {
 		TakeoffUnknownAirport,
 		TakeoffFromAirport,
 		TakeoffComplete,
+		LandingAtAirport,
+		LandingUnknownAirport,
+		Emergency,
+		ZoneEntered,
+		ZoneExited,
+		Rule,
+		Orbiting,
+		PositionAnomaly,
 	}
 }
 
@@ -271,3 +393,117 @@ func PrepareTakeoffComplete(templates *MailTemplates, to string, params TakeoffC
 
 	return buildEmail(templates, TakeoffComplete, to, subject, params)
 }
+
+// PrepareLandingAtAirport creates an LandingAtAirport and returns a mailer.EmailJob
+// for the email
+func PrepareLandingAtAirport(templates *MailTemplates, to string, params LandingParams) (*mailer.EmailJob, error) {
+	var callsign string
+	if params.CallSign != "" {
+		callsign = " (" + params.CallSign + ")"
+	}
+
+	subject := fmt.Sprintf("[%s] %s%s: landed at %s", params.Project, params.Icao, callsign, params.AirportName)
+
+	return buildEmail(templates, LandingAtAirport, to, subject, params)
+}
+
+// PrepareLandingUnknownAirport creates an LandingUnknownAirport and returns a mailer.EmailJob
+// for the email
+func PrepareLandingUnknownAirport(templates *MailTemplates, to string, params LandingUnknownAirportParams) (*mailer.EmailJob, error) {
+	var callsign string
+	if params.CallSign != "" {
+		callsign = " (" + params.CallSign + ")"
+	}
+
+	subject := fmt.Sprintf("[%s] %s%s: landed at unknown airport", params.Project, params.Icao, callsign)
+
+	return buildEmail(templates, LandingUnknownAirport, to, subject, params)
+}
+
+// PrepareEmergency creates an Emergency and returns a mailer.EmailJob
+// for the email. If kmlFile is not empty, the track so far is attached.
+func PrepareEmergency(templates *MailTemplates, to string, kmlFile []byte, params EmergencyParams) (*mailer.EmailJob, error) {
+	var callsign string
+	if params.CallSign != "" {
+		callsign = " (" + params.CallSign + ")"
+	}
+
+	subject := fmt.Sprintf("[%s] %s%s: emergency (%s)", params.Project, params.Icao, callsign, params.Description)
+	if len(kmlFile) == 0 {
+		return buildEmail(templates, Emergency, to, subject, params)
+	}
+	params.HaveTrack = true
+	return buildEmailWithAttachment(templates, Emergency, to, subject, params, []mailer.EmailAttachment{
+		{
+			Contents: kmlFile,
+			FileName: fmt.Sprintf("%s-%s.kml",
+				params.Icao, params.TimeFmt),
+			ContentType: "application/vnd.google-earth.kml+xml",
+		},
+	})
+}
+
+// PrepareZoneEntered creates an ZoneEntered and returns a mailer.EmailJob
+// for the email
+func PrepareZoneEntered(templates *MailTemplates, to string, params ZoneParams) (*mailer.EmailJob, error) {
+	var callsign string
+	if params.CallSign != "" {
+		callsign = " (" + params.CallSign + ")"
+	}
+
+	subject := fmt.Sprintf("[%s] %s%s: entered %s", params.Project, params.Icao, callsign, params.Zone)
+
+	return buildEmail(templates, ZoneEntered, to, subject, params)
+}
+
+// PrepareZoneExited creates an ZoneExited and returns a mailer.EmailJob
+// for the email
+func PrepareZoneExited(templates *MailTemplates, to string, params ZoneParams) (*mailer.EmailJob, error) {
+	var callsign string
+	if params.CallSign != "" {
+		callsign = " (" + params.CallSign + ")"
+	}
+
+	subject := fmt.Sprintf("[%s] %s%s: exited %s", params.Project, params.Icao, callsign, params.Zone)
+
+	return buildEmail(templates, ZoneExited, to, subject, params)
+}
+
+// PrepareRule creates an Rule and returns a mailer.EmailJob
+// for the email
+func PrepareRule(templates *MailTemplates, to string, params RuleParams) (*mailer.EmailJob, error) {
+	var callsign string
+	if params.CallSign != "" {
+		callsign = " (" + params.CallSign + ")"
+	}
+
+	subject := fmt.Sprintf("[%s] %s%s: %s", params.Project, params.Icao, callsign, params.Rule)
+
+	return buildEmail(templates, Rule, to, subject, params)
+}
+
+// PrepareOrbiting creates an Orbiting and returns a mailer.EmailJob
+// for the email
+func PrepareOrbiting(templates *MailTemplates, to string, params OrbitingParams) (*mailer.EmailJob, error) {
+	var callsign string
+	if params.CallSign != "" {
+		callsign = " (" + params.CallSign + ")"
+	}
+
+	subject := fmt.Sprintf("[%s] %s%s: orbiting", params.Project, params.Icao, callsign)
+
+	return buildEmail(templates, Orbiting, to, subject, params)
+}
+
+// PreparePositionAnomaly creates an PositionAnomaly and returns a mailer.EmailJob
+// for the email
+func PreparePositionAnomaly(templates *MailTemplates, to string, params PositionAnomalyParams) (*mailer.EmailJob, error) {
+	var callsign string
+	if params.CallSign != "" {
+		callsign = " (" + params.CallSign + ")"
+	}
+
+	subject := fmt.Sprintf("[%s] %s%s: position anomaly", params.Project, params.Icao, callsign)
+
+	return buildEmail(templates, PositionAnomaly, to, subject, params)
+}
@@ -8,19 +8,27 @@ import (
 
 func TestGetTemplates(t *testing.T) {
 	tpl := GetTemplates()
-	assert.Equal(t, 5, len(tpl))
+	assert.Equal(t, 13, len(tpl))
 	assert.Equal(t, MapProducedEmail, tpl[0])
 	assert.Equal(t, SpottedInFlight, tpl[1])
 	assert.Equal(t, TakeoffUnknownAirport, tpl[2])
 	assert.Equal(t, TakeoffFromAirport, tpl[3])
 	assert.Equal(t, TakeoffComplete, tpl[4])
+	assert.Equal(t, LandingAtAirport, tpl[5])
+	assert.Equal(t, LandingUnknownAirport, tpl[6])
+	assert.Equal(t, Emergency, tpl[7])
+	assert.Equal(t, ZoneEntered, tpl[8])
+	assert.Equal(t, ZoneExited, tpl[9])
+	assert.Equal(t, Rule, tpl[10])
+	assert.Equal(t, Orbiting, tpl[11])
+	assert.Equal(t, PositionAnomaly, tpl[12])
 }
 
 func TestLoadMailTemplates(t *testing.T) {
 	t.Run("defaults", func(t *testing.T) {
 		tpls, err := LoadMailTemplates(GetTemplates()...)
 		assert.NoError(t, err)
-		assert.Equal(t, 5, len(tpls.m))
+		assert.Equal(t, 13, len(tpls.m))
 		mapProduced, err := tpls.Get(MapProducedEmail)
 		assert.NoError(t, err)
 		assert.NotNil(t, mapProduced)
@@ -75,3 +83,193 @@ func TestPrepareSpottedInFlightEmail(t *testing.T) {
 		assert.True(t, strings.Contains(job.Body, "Place"))
 	})
 }
+
+func TestPrepareLandingEmails(t *testing.T) {
+	t.Run("at airport", func(t *testing.T) {
+		tpls, err := LoadMailTemplates(GetTemplates()...)
+		assert.NoError(t, err)
+		job, err := PrepareLandingAtAirport(tpls, "dest@site.local", LandingParams{
+			Project:     "MyCoolProject",
+			Icao:        "010101",
+			CallSign:    "AF1",
+			AirportName: "Dublin",
+			LandingLocation: Location{
+				Latitude:  53.42,
+				Longitude: -6.27,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(job.Attachments))
+		assert.Equal(t, "dest@site.local", job.To)
+		assert.Equal(t, "[MyCoolProject] 010101 (AF1): landed at Dublin", job.Subject)
+		assert.True(t, strings.Contains(job.Body, "Project: MyCoolProject"))
+		assert.True(t, strings.Contains(job.Body, "has landed at Dublin"))
+		assert.True(t, strings.Contains(job.Body, "#map=13/53.42/-6.27"))
+	})
+	t.Run("unknown airport", func(t *testing.T) {
+		tpls, err := LoadMailTemplates(GetTemplates()...)
+		assert.NoError(t, err)
+		job, err := PrepareLandingUnknownAirport(tpls, "dest@site.local", LandingUnknownAirportParams{
+			Project: "MyCoolProject",
+			Icao:    "010101",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "[MyCoolProject] 010101: landed at unknown airport", job.Subject)
+		assert.True(t, strings.Contains(job.Body, "010101"))
+		assert.True(t, strings.Contains(job.Body, "landed at an unknown airport"))
+	})
+}
+
+func TestPrepareEmergency(t *testing.T) {
+	params := EmergencyParams{
+		Project:     "MyCoolProject",
+		Icao:        "010101",
+		CallSign:    "AF1",
+		Operator:    "Air Force",
+		Squawk:      "7700",
+		Emergency:   "general",
+		Description: "general emergency",
+		TimeFmt:     "Mon, 02 Jan 2006 15:04:05 -0700",
+		Location: Location{
+			Latitude:  53.42,
+			Longitude: -6.27,
+			Altitude:  12000,
+		},
+	}
+	t.Run("without track", func(t *testing.T) {
+		tpls, err := LoadMailTemplates(GetTemplates()...)
+		assert.NoError(t, err)
+		job, err := PrepareEmergency(tpls, "dest@site.local", nil, params)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(job.Attachments))
+		assert.Equal(t, "[MyCoolProject] 010101 (AF1): emergency (general emergency)", job.Subject)
+		assert.True(t, strings.Contains(job.Body, "(Air Force)"))
+		assert.True(t, strings.Contains(job.Body, "Squawk: 7700"))
+		assert.True(t, strings.Contains(job.Body, "Emergency status: general"))
+		assert.True(t, strings.Contains(job.Body, "#map=13/53.42/-6.27"))
+		assert.False(t, strings.Contains(job.Body, "track so far"))
+	})
+	t.Run("with track", func(t *testing.T) {
+		tpls, err := LoadMailTemplates(GetTemplates()...)
+		assert.NoError(t, err)
+		job, err := PrepareEmergency(tpls, "dest@site.local", []byte("<kml></kml>"), params)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(job.Attachments))
+		assert.Equal(t, "application/vnd.google-earth.kml+xml", job.Attachments[0].ContentType)
+		assert.True(t, strings.Contains(job.Body, "track so far"))
+	})
+}
+
+func TestPrepareZoneEmails(t *testing.T) {
+	params := ZoneParams{
+		Project:   "MyCoolProject",
+		Icao:      "010101",
+		CallSign:  "AF1",
+		Zone:      "home",
+		Watchlist: "government",
+		Location: Location{
+			Latitude:  53.42,
+			Longitude: -6.27,
+			Altitude:  3000,
+		},
+	}
+	t.Run("entered", func(t *testing.T) {
+		tpls, err := LoadMailTemplates(GetTemplates()...)
+		assert.NoError(t, err)
+		job, err := PrepareZoneEntered(tpls, "dest@site.local", params)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(job.Attachments))
+		assert.Equal(t, "[MyCoolProject] 010101 (AF1): entered home", job.Subject)
+		assert.True(t, strings.Contains(job.Body, "has entered home"))
+		assert.True(t, strings.Contains(job.Body, "[government]"))
+		assert.True(t, strings.Contains(job.Body, "#map=13/53.42/-6.27"))
+	})
+	t.Run("exited", func(t *testing.T) {
+		tpls, err := LoadMailTemplates(GetTemplates()...)
+		assert.NoError(t, err)
+		job, err := PrepareZoneExited(tpls, "dest@site.local", params)
+		assert.NoError(t, err)
+		assert.Equal(t, "[MyCoolProject] 010101 (AF1): exited home", job.Subject)
+		assert.True(t, strings.Contains(job.Body, "has exited home"))
+	})
+}
+
+func TestPrepareRule(t *testing.T) {
+	t.Run("with location", func(t *testing.T) {
+		tpls, err := LoadMailTemplates(GetTemplates()...)
+		assert.NoError(t, err)
+		job, err := PrepareRule(tpls, "dest@site.local", RuleParams{
+			Project:      "MyCoolProject",
+			Icao:         "AE1234",
+			CallSign:     "RCH123",
+			Rule:         "low_rch",
+			Condition:    `state.CallSign.startsWith("RCH")`,
+			HaveLocation: true,
+			Location: Location{
+				Latitude:  53.42,
+				Longitude: -6.27,
+				Altitude:  3000,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(job.Attachments))
+		assert.Equal(t, "[MyCoolProject] AE1234 (RCH123): low_rch", job.Subject)
+		assert.True(t, strings.Contains(job.Body, "triggered rule low_rch"))
+		assert.True(t, strings.Contains(job.Body, `state.CallSign.startsWith("RCH")`))
+		assert.True(t, strings.Contains(job.Body, "#map=13/53.42/-6.27"))
+	})
+	t.Run("without location", func(t *testing.T) {
+		tpls, err := LoadMailTemplates(GetTemplates()...)
+		assert.NoError(t, err)
+		job, err := PrepareRule(tpls, "dest@site.local", RuleParams{
+			Project: "MyCoolProject",
+			Icao:    "AE1234",
+			Rule:    "low_rch",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "[MyCoolProject] AE1234: low_rch", job.Subject)
+		assert.False(t, strings.Contains(job.Body, "Place"))
+	})
+}
+
+func TestPrepareOrbiting(t *testing.T) {
+	tpls, err := LoadMailTemplates(GetTemplates()...)
+	assert.NoError(t, err)
+	job, err := PrepareOrbiting(tpls, "dest@site.local", OrbitingParams{
+		Project:  "MyCoolProject",
+		Icao:     "4CA123",
+		CallSign: "GARDA1",
+		Centre: Location{
+			Latitude:  53.42,
+			Longitude: -6.27,
+			Altitude:  1500,
+		},
+		Radius: 800,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(job.Attachments))
+	assert.Equal(t, "[MyCoolProject] 4CA123 (GARDA1): orbiting", job.Subject)
+	assert.True(t, strings.Contains(job.Body, "is orbiting"))
+	assert.True(t, strings.Contains(job.Body, "#map=13/53.42/-6.27"))
+	assert.True(t, strings.Contains(job.Body, "800 m"))
+}
+
+func TestPreparePositionAnomaly(t *testing.T) {
+	tpls, err := LoadMailTemplates(GetTemplates()...)
+	assert.NoError(t, err)
+	job, err := PreparePositionAnomaly(tpls, "dest@site.local", PositionAnomalyParams{
+		Project:      "MyCoolProject",
+		Icao:         "4CA123",
+		Description:  "3 implausible positions, the last implying 9000 knots",
+		HaveLocation: true,
+		Location: Location{
+			Latitude:  53.42,
+			Longitude: -6.27,
+			Altitude:  30000,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "[MyCoolProject] 4CA123: position anomaly", job.Subject)
+	assert.True(t, strings.Contains(job.Body, "3 implausible positions, the last implying 9000 knots"))
+	assert.True(t, strings.Contains(job.Body, "#map=13/53.42/-6.27"))
+}
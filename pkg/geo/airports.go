@@ -70,19 +70,30 @@ func (g *NearestAirportGeocoder) findNearbyLocations(gh string) []AirportRecord
 // the location with the shortest distance to (lat, lon) if there was
 // any airports nearby.
 func (g *NearestAirportGeocoder) ReverseGeocode(lat float64, lon float64) (string, float64) {
+	airport, distance := g.NearestAirport(lat, lon)
+	if airport == nil {
+		return "", 0
+	}
+	return airport.Name, distance
+}
+
+// NearestAirport works like ReverseGeocode, but returns the AirportRecord
+// and its distance from (lat, lon). If no airports were nearby, nil is
+// returned.
+func (g *NearestAirportGeocoder) NearestAirport(lat float64, lon float64) (*AirportRecord, float64) {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
 	lgh := geohash.EncodeWithPrecision(lat, lon, g.geoHashChars)
 	airports := g.findNearbyLocations(lgh)
-	var nearestAirport string
+	var nearestAirport *AirportRecord
 	var nearestAirportDistance float64
 	n := len(airports)
 	for i := 0; i < n; i++ {
-		airport := airports[i]
+		airport := &airports[i]
 		distance := Distance(airport.Latitude, airport.Longitude, lat, lon)
-		if nearestAirport == "" || nearestAirportDistance > distance {
-			nearestAirport = airport.Name
+		if nearestAirport == nil || nearestAirportDistance > distance {
+			nearestAirport = airport
 			nearestAirportDistance = distance
 		}
 	}
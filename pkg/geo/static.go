@@ -36,3 +36,15 @@ func Distance(lat1, lon1, lat2, lon2 float64) float64 {
 
 	return 2 * r * math.Asin(math.Sqrt(h))
 }
+
+// Bearing returns the initial bearing in degrees (0-360) from
+// the first point to the second
+func Bearing(lat1, lon1, lat2, lon2 float64) float64 {
+	la1 := lat1 * math.Pi / 180
+	la2 := lat2 * math.Pi / 180
+	dLon := (lon2 - lon1) * math.Pi / 180
+
+	y := math.Sin(dLon) * math.Cos(la2)
+	x := math.Cos(la1)*math.Sin(la2) - math.Sin(la1)*math.Cos(la2)*math.Cos(dLon)
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
@@ -0,0 +1,15 @@
+package geo
+
+import (
+	assert "github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBearing(t *testing.T) {
+	assert.InDelta(t, 0, Bearing(53, -6, 54, -6), 0.001)
+	assert.InDelta(t, 90, Bearing(0, 0, 0, 1), 0.001)
+	assert.InDelta(t, 180, Bearing(54, -6, 53, -6), 0.001)
+	assert.InDelta(t, 270, Bearing(0, 1, 0, 0), 0.001)
+	// Dublin to London Heathrow
+	assert.InDelta(t, 116.5, Bearing(53.421333, -6.270075, 51.4700, -0.4543), 1)
+}
@@ -0,0 +1,40 @@
+package geo
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// CivilTwilightElevation - the sun's elevation in degrees at the
+	// end of civil twilight. Below this, it's considered night.
+	CivilTwilightElevation = -6.0
+)
+
+// SolarElevation returns the elevation of the sun above the horizon in
+// degrees at (lat, lon) and time t. It uses the NOAA approximation,
+// which is accurate to within a degree or so.
+func SolarElevation(t time.Time, lat, lon float64) float64 {
+	t = t.UTC()
+	hour := float64(t.Hour()) + float64(t.Minute())/60 + float64(t.Second())/3600
+	// fractional year in radians
+	gamma := 2 * math.Pi / 365 * (float64(t.YearDay()-1) + (hour-12)/24)
+	// equation of time in minutes, and the sun's declination in radians
+	eqTime := 229.18 * (0.000075 + 0.001868*math.Cos(gamma) - 0.032077*math.Sin(gamma) -
+		0.014615*math.Cos(2*gamma) - 0.040849*math.Sin(2*gamma))
+	decl := 0.006918 - 0.399912*math.Cos(gamma) + 0.070257*math.Sin(gamma) -
+		0.006758*math.Cos(2*gamma) + 0.000907*math.Sin(2*gamma) -
+		0.002697*math.Cos(3*gamma) + 0.00148*math.Sin(3*gamma)
+	// true solar time in minutes gives the hour angle
+	solarTime := hour*60 + eqTime + 4*lon
+	hourAngle := (solarTime/4 - 180) * math.Pi / 180
+	la := lat * math.Pi / 180
+	cosZenith := math.Sin(la)*math.Sin(decl) + math.Cos(la)*math.Cos(decl)*math.Cos(hourAngle)
+	return 90 - math.Acos(math.Max(-1, math.Min(1, cosZenith)))*180/math.Pi
+}
+
+// IsNight returns true if the sun is below CivilTwilightElevation
+// at (lat, lon) and time t.
+func IsNight(t time.Time, lat, lon float64) bool {
+	return SolarElevation(t, lat, lon) < CivilTwilightElevation
+}
@@ -0,0 +1,29 @@
+package geo
+
+import (
+	assert "github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSolarElevation(t *testing.T) {
+	// solar noon in Dublin at the summer solstice: 90 - (53.42 - 23.44)
+	noon := time.Date(2020, time.June, 20, 12, 28, 0, 0, time.UTC)
+	assert.InDelta(t, 59.98, SolarElevation(noon, 53.42, -6.27), 0.5)
+	// midnight: 90 - (53.42 + 23.44) below the horizon
+	midnight := time.Date(2020, time.June, 20, 0, 28, 0, 0, time.UTC)
+	assert.InDelta(t, -13.14, SolarElevation(midnight, 53.42, -6.27), 0.5)
+	// the equator at the equinox
+	equinox := time.Date(2020, time.March, 20, 12, 7, 0, 0, time.UTC)
+	assert.InDelta(t, 90, SolarElevation(equinox, 0, 0), 1)
+	// time zones don't matter
+	assert.Equal(t, SolarElevation(noon, 53.42, -6.27), SolarElevation(noon.In(time.FixedZone("", 3600)), 53.42, -6.27))
+}
+
+func TestIsNight(t *testing.T) {
+	assert.False(t, IsNight(time.Date(2020, time.June, 20, 12, 0, 0, 0, time.UTC), 53.42, -6.27))
+	assert.True(t, IsNight(time.Date(2020, time.December, 20, 22, 0, 0, 0, time.UTC), 53.42, -6.27))
+	// dusk in Dublin in December is shortly after 4pm
+	assert.False(t, IsNight(time.Date(2020, time.December, 20, 16, 0, 0, 0, time.UTC), 53.42, -6.27))
+	assert.True(t, IsNight(time.Date(2020, time.December, 20, 17, 30, 0, 0, time.UTC), 53.42, -6.27))
+}
@@ -0,0 +1,145 @@
+package geo
+
+import (
+	"github.com/pkg/errors"
+	"math"
+)
+
+type (
+	// Point - a decimal latitude and longitude
+	Point struct {
+		Latitude  float64
+		Longitude float64
+	}
+
+	// Zone - a named geographic area. A zone is a circle if
+	// Radius is set, otherwise it is a polygon. Zones should be
+	// created with NewCircleZone or NewPolygonZone.
+	Zone struct {
+		// Name of the zone. Several zones may share a name,
+		// eg, when a zone is made up of multiple polygons.
+		Name string
+		// Center of a circular zone
+		Center Point
+		// Radius of a circular zone in meters
+		Radius float64
+		// Polygon - the vertices of a polygon zone. The polygon
+		// is closed automatically, so the first point needn't be repeated.
+		Polygon []Point
+
+		// bounding box of Polygon, used to skip the
+		// more expensive point-in-polygon test
+		min Point
+		max Point
+	}
+
+	// Zones - a list of zones which can be searched by location
+	Zones struct {
+		zones []Zone
+	}
+)
+
+// NewCircleZone creates a circular zone with a radius in meters
+func NewCircleZone(name string, lat, lon, radius float64) Zone {
+	return Zone{
+		Name:   name,
+		Center: Point{Latitude: lat, Longitude: lon},
+		Radius: radius,
+	}
+}
+
+// NewPolygonZone creates a polygon zone from a list of vertices
+func NewPolygonZone(name string, polygon []Point) Zone {
+	z := Zone{
+		Name:    name,
+		Polygon: polygon,
+		min:     Point{Latitude: math.Inf(1), Longitude: math.Inf(1)},
+		max:     Point{Latitude: math.Inf(-1), Longitude: math.Inf(-1)},
+	}
+	for _, p := range polygon {
+		z.min.Latitude = math.Min(z.min.Latitude, p.Latitude)
+		z.min.Longitude = math.Min(z.min.Longitude, p.Longitude)
+		z.max.Latitude = math.Max(z.max.Latitude, p.Latitude)
+		z.max.Longitude = math.Max(z.max.Longitude, p.Longitude)
+	}
+	return z
+}
+
+// Validate returns an error if the zone has no name, or
+// isn't a valid circle or polygon.
+func (z *Zone) Validate() error {
+	if z.Name == "" {
+		return errors.New("zone has no name")
+	}
+	if z.Radius < 0 {
+		return errors.Errorf("zone %s: radius must be positive", z.Name)
+	} else if z.Radius == 0 && len(z.Polygon) < 3 {
+		return errors.Errorf("zone %s: requires a radius, or a polygon with at least 3 points", z.Name)
+	} else if z.Radius > 0 && len(z.Polygon) > 0 {
+		return errors.Errorf("zone %s: cannot have a radius and a polygon", z.Name)
+	}
+	return nil
+}
+
+// Contains returns true if (lat, lon) is inside the zone. Polygons
+// are treated as flat, so they shouldn't cross the antimeridian.
+func (z *Zone) Contains(lat, lon float64) bool {
+	if z.Radius > 0 {
+		return Distance(z.Center.Latitude, z.Center.Longitude, lat, lon) <= z.Radius
+	}
+	if lat < z.min.Latitude || lat > z.max.Latitude ||
+		lon < z.min.Longitude || lon > z.max.Longitude {
+		return false
+	}
+	// ray casting: count the edges crossed by a ray
+	// heading east from the point
+	var inside bool
+	for i, j := 0, len(z.Polygon)-1; i < len(z.Polygon); j, i = i, i+1 {
+		a, b := z.Polygon[i], z.Polygon[j]
+		if (a.Latitude > lat) != (b.Latitude > lat) &&
+			lon < (b.Longitude-a.Longitude)*(lat-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// NewZones validates zones and returns a Zones
+func NewZones(zones []Zone) (*Zones, error) {
+	for i := range zones {
+		if err := zones[i].Validate(); err != nil {
+			return nil, err
+		}
+		if len(zones[i].Polygon) > 0 {
+			zones[i] = NewPolygonZone(zones[i].Name, zones[i].Polygon)
+		}
+	}
+	return &Zones{zones: zones}, nil
+}
+
+// Len returns the number of zones
+func (z *Zones) Len() int {
+	return len(z.zones)
+}
+
+// Find returns the names of the zones containing (lat, lon).
+// Names are returned once, in the order the zones were defined.
+func (z *Zones) Find(lat, lon float64) []string {
+	var names []string
+	for i := range z.zones {
+		if !z.zones[i].Contains(lat, lon) {
+			continue
+		}
+		var found bool
+		for _, name := range names {
+			if name == z.zones[i].Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			names = append(names, z.zones[i].Name)
+		}
+	}
+	return names
+}
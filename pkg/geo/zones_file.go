@@ -0,0 +1,201 @@
+package geo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type (
+	// geoJSONObject is either a FeatureCollection or a Feature
+	geoJSONObject struct {
+		Type       string                 `json:"type"`
+		Features   []geoJSONObject        `json:"features"`
+		Properties map[string]interface{} `json:"properties"`
+		Geometry   *geoJSONGeometry       `json:"geometry"`
+	}
+	// geoJSONGeometry - coordinates are decoded once the type is known
+	geoJSONGeometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+
+	// kmlPlacemark - only the name and polygons are decoded
+	kmlPlacemark struct {
+		Name     string       `xml:"name"`
+		Polygons []kmlPolygon `xml:"Polygon"`
+		Multi    []kmlPolygon `xml:"MultiGeometry>Polygon"`
+	}
+	// kmlPolygon - only the outer boundary is decoded
+	kmlPolygon struct {
+		Coordinates string `xml:"outerBoundaryIs>LinearRing>coordinates"`
+	}
+)
+
+// ReadGeoJSONZones reads zones from a GeoJSON FeatureCollection or Feature.
+// Polygon and MultiPolygon features become polygon zones, and Point features
+// with a `radius` property (in meters) become circular zones. Zones are named
+// after the feature's `name` property, unless name is set, in which case
+// every zone in the file is given that name. Holes in polygons are ignored.
+func ReadGeoJSONZones(r io.Reader, name string) ([]Zone, error) {
+	obj := geoJSONObject{}
+	err := json.NewDecoder(r).Decode(&obj)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding GeoJSON")
+	}
+	var features []geoJSONObject
+	switch obj.Type {
+	case "FeatureCollection":
+		features = obj.Features
+	case "Feature":
+		features = []geoJSONObject{obj}
+	default:
+		return nil, errors.Errorf("unsupported GeoJSON type: %s", obj.Type)
+	}
+
+	var zones []Zone
+	for i := range features {
+		zoneName := name
+		if zoneName == "" {
+			zoneName, _ = features[i].Properties["name"].(string)
+			if zoneName == "" {
+				return nil, errors.Errorf("feature %d has no name property", i)
+			}
+		}
+		geom := features[i].Geometry
+		if geom == nil {
+			return nil, errors.Errorf("feature %s has no geometry", zoneName)
+		}
+		switch geom.Type {
+		case "Point":
+			var coords []float64
+			if err := json.Unmarshal(geom.Coordinates, &coords); err != nil {
+				return nil, errors.Wrapf(err, "decoding %s coordinates", zoneName)
+			} else if len(coords) < 2 {
+				return nil, errors.Errorf("feature %s has invalid coordinates", zoneName)
+			}
+			radius, ok := features[i].Properties["radius"].(float64)
+			if !ok {
+				return nil, errors.Errorf("point feature %s has no radius property", zoneName)
+			}
+			zones = append(zones, NewCircleZone(zoneName, coords[1], coords[0], radius))
+		case "Polygon":
+			var rings [][][]float64
+			if err := json.Unmarshal(geom.Coordinates, &rings); err != nil {
+				return nil, errors.Wrapf(err, "decoding %s coordinates", zoneName)
+			} else if len(rings) == 0 {
+				return nil, errors.Errorf("feature %s has no coordinates", zoneName)
+			}
+			zones = append(zones, NewPolygonZone(zoneName, geoJSONRing(rings[0])))
+		case "MultiPolygon":
+			var polygons [][][][]float64
+			if err := json.Unmarshal(geom.Coordinates, &polygons); err != nil {
+				return nil, errors.Wrapf(err, "decoding %s coordinates", zoneName)
+			}
+			for _, rings := range polygons {
+				if len(rings) > 0 {
+					zones = append(zones, NewPolygonZone(zoneName, geoJSONRing(rings[0])))
+				}
+			}
+		default:
+			return nil, errors.Errorf("feature %s has unsupported geometry type %s", zoneName, geom.Type)
+		}
+	}
+	return zones, nil
+}
+
+// geoJSONRing converts GeoJSON [lon, lat] positions into Points
+func geoJSONRing(ring [][]float64) []Point {
+	points := make([]Point, 0, len(ring))
+	for _, pos := range ring {
+		if len(pos) >= 2 {
+			points = append(points, Point{Latitude: pos[1], Longitude: pos[0]})
+		}
+	}
+	return points
+}
+
+// ReadKMLZones reads polygon zones from the Placemarks in a KML
+// document. Zones are named after the Placemark, unless name is set,
+// in which case every zone in the file is given that name. Holes
+// in polygons are ignored.
+func ReadKMLZones(r io.Reader, name string) ([]Zone, error) {
+	var zones []Zone
+	decoder := xml.NewDecoder(r)
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "decoding KML")
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "Placemark" {
+			continue
+		}
+		placemark := kmlPlacemark{}
+		if err := decoder.DecodeElement(&placemark, &start); err != nil {
+			return nil, errors.Wrapf(err, "decoding KML placemark")
+		}
+		zoneName := name
+		if zoneName == "" {
+			zoneName = strings.TrimSpace(placemark.Name)
+			if zoneName == "" {
+				return nil, errors.Errorf("placemark %d has no name", len(zones))
+			}
+		}
+		for _, polygon := range append(placemark.Polygons, placemark.Multi...) {
+			points, err := parseKMLCoordinates(polygon.Coordinates)
+			if err != nil {
+				return nil, errors.Wrapf(err, "placemark %s", zoneName)
+			}
+			zones = append(zones, NewPolygonZone(zoneName, points))
+		}
+	}
+	return zones, nil
+}
+
+// parseKMLCoordinates parses a KML coordinates string,
+// a whitespace separated list of lon,lat[,alt] tuples
+func parseKMLCoordinates(coordinates string) ([]Point, error) {
+	var points []Point
+	for _, tuple := range strings.Fields(coordinates) {
+		parts := strings.Split(tuple, ",")
+		if len(parts) < 2 {
+			return nil, errors.Errorf("invalid coordinate: %s", tuple)
+		}
+		lon, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing longitude")
+		}
+		lat, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing latitude")
+		}
+		points = append(points, Point{Latitude: lat, Longitude: lon})
+	}
+	return points, nil
+}
+
+// ReadZonesFile reads zones from a GeoJSON (.geojson or .json)
+// or KML (.kml) file. See ReadGeoJSONZones and ReadKMLZones.
+func ReadZonesFile(file string, name string) ([]Zone, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	lower := strings.ToLower(file)
+	switch {
+	case strings.HasSuffix(lower, ".geojson"), strings.HasSuffix(lower, ".json"):
+		return ReadGeoJSONZones(f, name)
+	case strings.HasSuffix(lower, ".kml"):
+		return ReadKMLZones(f, name)
+	default:
+		return nil, errors.Errorf("unsupported zone file type: %s", file)
+	}
+}
@@ -0,0 +1,150 @@
+package geo
+
+import (
+	assert "github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestZone_Contains(t *testing.T) {
+	t.Run("circle", func(t *testing.T) {
+		z := NewCircleZone("dublin", 53.421333, -6.270075, 5000)
+		assert.NoError(t, z.Validate())
+		assert.True(t, z.Contains(53.421333, -6.270075))
+		assert.True(t, z.Contains(53.44, -6.25))
+		assert.False(t, z.Contains(53.5, -6.270075))
+	})
+	t.Run("polygon", func(t *testing.T) {
+		// a triangle, with the first point not repeated
+		z := NewPolygonZone("triangle", []Point{
+			{Latitude: 53, Longitude: -7},
+			{Latitude: 54, Longitude: -6.5},
+			{Latitude: 53, Longitude: -6},
+		})
+		assert.NoError(t, z.Validate())
+		assert.True(t, z.Contains(53.2, -6.5))
+		assert.False(t, z.Contains(53.9, -6.9))
+		assert.False(t, z.Contains(52.9, -6.5))
+	})
+}
+
+func TestZone_Validate(t *testing.T) {
+	z := NewCircleZone("", 53, -6, 1000)
+	assert.EqualError(t, z.Validate(), "zone has no name")
+	z = NewCircleZone("negative", 53, -6, -1)
+	assert.EqualError(t, z.Validate(), "zone negative: radius must be positive")
+	z = NewPolygonZone("line", []Point{{53, -6}, {54, -6}})
+	assert.EqualError(t, z.Validate(), "zone line: requires a radius, or a polygon with at least 3 points")
+}
+
+func TestZones_Find(t *testing.T) {
+	zones, err := NewZones([]Zone{
+		NewCircleZone("home", 53.42, -6.27, 10000),
+		// polygons created without NewPolygonZone still work
+		{Name: "east", Polygon: []Point{{53, -6.3}, {54, -6.3}, {54, -6}, {53, -6}}},
+		NewCircleZone("home", 53.6, -6.27, 10000),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, zones.Len())
+	assert.Equal(t, []string{"home", "east"}, zones.Find(53.42, -6.25))
+	assert.Equal(t, []string{"home"}, zones.Find(53.6, -6.4))
+	assert.Nil(t, zones.Find(10, 10))
+
+	_, err = NewZones([]Zone{NewCircleZone("", 53, -6, 1)})
+	assert.Error(t, err)
+}
+
+func TestReadGeoJSONZones(t *testing.T) {
+	t.Run("feature collection", func(t *testing.T) {
+		zones, err := ReadGeoJSONZones(strings.NewReader(`{
+  "type": "FeatureCollection",
+  "features": [
+    {"type": "Feature", "properties": {"name": "home", "radius": 5000},
+     "geometry": {"type": "Point", "coordinates": [-6.27, 53.42]}},
+    {"type": "Feature", "properties": {"name": "box"},
+     "geometry": {"type": "Polygon", "coordinates": [[[-7, 53], [-6, 53], [-6, 54], [-7, 54], [-7, 53]]]}},
+    {"type": "Feature", "properties": {"name": "islands"},
+     "geometry": {"type": "MultiPolygon", "coordinates": [
+       [[[-7, 53], [-6, 53], [-6, 54], [-7, 53]]],
+       [[[0, 50], [1, 50], [1, 51], [0, 50]]]
+     ]}}
+  ]
+}`), "")
+		assert.NoError(t, err)
+		assert.Equal(t, 4, len(zones))
+		assert.Equal(t, "home", zones[0].Name)
+		assert.Equal(t, 5000.0, zones[0].Radius)
+		assert.Equal(t, Point{Latitude: 53.42, Longitude: -6.27}, zones[0].Center)
+		assert.Equal(t, "box", zones[1].Name)
+		assert.Equal(t, 5, len(zones[1].Polygon))
+		assert.Equal(t, Point{Latitude: 53, Longitude: -7}, zones[1].Polygon[0])
+		assert.True(t, zones[1].Contains(53.5, -6.5))
+		assert.Equal(t, "islands", zones[2].Name)
+		assert.Equal(t, "islands", zones[3].Name)
+	})
+	t.Run("name override", func(t *testing.T) {
+		zones, err := ReadGeoJSONZones(strings.NewReader(`{"type": "Feature",
+  "geometry": {"type": "Polygon", "coordinates": [[[-7, 53], [-6, 53], [-6, 54], [-7, 53]]]}}`), "override")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(zones))
+		assert.Equal(t, "override", zones[0].Name)
+	})
+	t.Run("errors", func(t *testing.T) {
+		_, err := ReadGeoJSONZones(strings.NewReader(`{"type": "Point"}`), "")
+		assert.EqualError(t, err, "unsupported GeoJSON type: Point")
+		_, err = ReadGeoJSONZones(strings.NewReader(`{"type": "Feature", "properties": {}}`), "")
+		assert.EqualError(t, err, "feature 0 has no name property")
+		_, err = ReadGeoJSONZones(strings.NewReader(`{"type": "Feature", "properties": {"name": "a"},
+  "geometry": {"type": "Point", "coordinates": [-6.27, 53.42]}}`), "")
+		assert.EqualError(t, err, "point feature a has no radius property")
+		_, err = ReadGeoJSONZones(strings.NewReader(`{"type": "Feature", "properties": {"name": "a"},
+  "geometry": {"type": "LineString", "coordinates": [[-6.27, 53.42], [-6, 53]]}}`), "")
+		assert.EqualError(t, err, "feature a has unsupported geometry type LineString")
+	})
+}
+
+func TestReadKMLZones(t *testing.T) {
+	zones, err := ReadKMLZones(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <Folder>
+      <Placemark>
+        <name>box</name>
+        <Polygon>
+          <outerBoundaryIs>
+            <LinearRing>
+              <coordinates>
+                -7,53,0 -6,53,0 -6,54,0 -7,54,0 -7,53,0
+              </coordinates>
+            </LinearRing>
+          </outerBoundaryIs>
+        </Polygon>
+      </Placemark>
+    </Folder>
+    <Placemark>
+      <name>multi</name>
+      <MultiGeometry>
+        <Polygon><outerBoundaryIs><LinearRing><coordinates>0,50 1,50 1,51 0,50</coordinates></LinearRing></outerBoundaryIs></Polygon>
+        <Polygon><outerBoundaryIs><LinearRing><coordinates>2,50 3,50 3,51 2,50</coordinates></LinearRing></outerBoundaryIs></Polygon>
+      </MultiGeometry>
+    </Placemark>
+    <Placemark>
+      <name>point</name>
+      <Point><coordinates>-6,53</coordinates></Point>
+    </Placemark>
+  </Document>
+</kml>`), "")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(zones))
+	assert.Equal(t, "box", zones[0].Name)
+	assert.Equal(t, 5, len(zones[0].Polygon))
+	assert.Equal(t, Point{Latitude: 53, Longitude: -7}, zones[0].Polygon[0])
+	assert.True(t, zones[0].Contains(53.5, -6.5))
+	assert.Equal(t, "multi", zones[1].Name)
+	assert.Equal(t, "multi", zones[2].Name)
+	assert.True(t, zones[2].Contains(50.2, 2.5))
+
+	_, err = ReadKMLZones(strings.NewReader(`<kml><Placemark><name>bad</name><Polygon><outerBoundaryIs><LinearRing>
+<coordinates>-7 53</coordinates></LinearRing></outerBoundaryIs></Polygon></Placemark></kml>`), "")
+	assert.EqualError(t, err, "placemark bad: invalid coordinate: -7")
+}
@@ -24,6 +24,16 @@ const (
     </ScreenOverlay>
 </Document>
 </kml>`
+
+	// mlatStyle is used for parts of the route calculated
+	// by multilateration, as they're less accurate
+	mlatStyle = `
+    <Style id="mlat">
+        <LineStyle>
+            <color>ff00a5ff</color>
+            <width>3</width>
+        </LineStyle>
+    </Style>`
 )
 
 // locationPlacemark generates XML for a location placemark
@@ -39,6 +49,43 @@ func locationPlacemark(name, desc string, altitude int64, latitude, longitude fl
     </Placemark>`, name, desc, longitude, latitude, altitude)
 }
 
+// trackPlacemark generates XML for a route placemark. If mlat is
+// set, the track is drawn using mlatStyle.
+func trackPlacemark(name, desc string, mlat bool, when, coord string) string {
+	style := ""
+	if mlat {
+		name += " (MLAT)"
+		style = `
+        <styleUrl>#mlat</styleUrl>`
+	}
+	return `
+    <Placemark>
+        <name>` + name + `</name>
+        <description>` + desc + `</description>` + style + `
+        <gx:Track>
+            <extrude>1</extrude>
+            <tessellate>1</tessellate>
+            <altitudeMode>absolute</altitudeMode>` + "\n" +
+		when +
+		coord + `        </gx:Track>
+    </Placemark>`
+}
+
+// segment is a part of the route where all positions
+// were or weren't calculated by multilateration
+type segment struct {
+	mlat  bool
+	when  string
+	coord string
+}
+
+// write appends the location to the segment
+func (s *segment) write(l *db.SightingLocation) {
+	s.when += "            <when>" + l.TimeStamp.Format(time.RFC3339) + "</when>\n"
+	s.coord += fmt.Sprintf("            <gx:coord>%f %f %d</gx:coord>\n",
+		l.Longitude, l.Latitude, l.Altitude)
+}
+
 // WriterOptions contains some preprocessed information
 // about the flight
 type WriterOptions struct {
@@ -54,11 +101,11 @@ type WriterOptions struct {
 
 // Writer processes locations into a KML file
 type Writer struct {
-	opt   WriterOptions
-	first *db.SightingLocation
-	last  *db.SightingLocation
-	when  string
-	coord string
+	opt      WriterOptions
+	first    *db.SightingLocation
+	last     *db.SightingLocation
+	segments []*segment
+	haveMlat bool
 }
 
 // NewWriter returns a new Writer initialized with opt
@@ -68,19 +115,26 @@ func NewWriter(opt WriterOptions) *Writer {
 	}
 }
 
-// Write processes the new locationData and appends it to internal state
+// Write processes the new locationData and appends it to internal state.
+// A new segment is started each time the route switches to or from MLAT
+// positions. It begins at the previous position so the route is unbroken.
 func (w *Writer) Write(locationData []db.SightingLocation) {
 	for i := range locationData {
-		w.when += "            <when>" + locationData[i].TimeStamp.Format(time.RFC3339) + "</when>\n"
-		w.coord += fmt.Sprintf("            <gx:coord>%f %f %d</gx:coord>\n",
-			locationData[i].Longitude, locationData[i].Latitude, locationData[i].Altitude)
-	}
-
-	if len(locationData) > 0 {
+		l := &locationData[i]
+		mlat := l.Provenance != nil && *l.Provenance == db.ProvenanceMLAT
+		if len(w.segments) == 0 || w.segments[len(w.segments)-1].mlat != mlat {
+			s := &segment{mlat: mlat}
+			if w.last != nil {
+				s.write(w.last)
+			}
+			w.segments = append(w.segments, s)
+			w.haveMlat = w.haveMlat || mlat
+		}
+		w.segments[len(w.segments)-1].write(l)
 		if w.first == nil {
-			w.first = &locationData[0]
+			w.first = l
 		}
-		w.last = &locationData[len(locationData)-1]
+		w.last = l
 	}
 }
 
@@ -89,19 +143,14 @@ func (w *Writer) Final() (string, error) {
 	if w.first == nil || w.last == nil {
 		return "", errors.New("missing location information")
 	}
-	return openDoc +
-		locationPlacemark(w.opt.SourceName, w.opt.SourceDescription, w.first.Altitude, w.first.Latitude, w.first.Longitude) +
-		locationPlacemark(w.opt.DestinationName, w.opt.DestinationDescription, w.last.Altitude, w.last.Latitude, w.last.Longitude) +
-		`
-    <Placemark>
-        <name>` + w.opt.RouteName + `</name>
-        <description>` + w.opt.RouteDescription + `</description>
-        <gx:Track>
-            <extrude>1</extrude>
-            <tessellate>1</tessellate>
-            <altitudeMode>absolute</altitudeMode>` + "\n" +
-		w.when +
-		w.coord + `        </gx:Track>
-    </Placemark>` +
-		closeDoc, nil
+	doc := openDoc
+	if w.haveMlat {
+		doc += mlatStyle
+	}
+	doc += locationPlacemark(w.opt.SourceName, w.opt.SourceDescription, w.first.Altitude, w.first.Latitude, w.first.Longitude) +
+		locationPlacemark(w.opt.DestinationName, w.opt.DestinationDescription, w.last.Altitude, w.last.Latitude, w.last.Longitude)
+	for _, s := range w.segments {
+		doc += trackPlacemark(w.opt.RouteName, w.opt.RouteDescription, s.mlat, s.when, s.coord)
+	}
+	return doc + closeDoc, nil
 }
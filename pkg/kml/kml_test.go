@@ -3,6 +3,7 @@ package kml
 import (
 	"github.com/afk11/airtrack/pkg/db"
 	assert "github.com/stretchr/testify/require"
+	"strings"
 	"testing"
 	"time"
 )
@@ -106,3 +107,31 @@ func TestWriterBasic(t *testing.T) {
 		assert.Equal(t, ExpectedKml, k)
 	})
 }
+
+func TestWriterMlat(t *testing.T) {
+	mlat := db.ProvenanceMLAT
+	adsb := db.ProvenanceADSB
+	start := time.Date(2020, 05, 22, 20, 12, 49, 0, time.UTC)
+	locations := []db.SightingLocation{
+		{Latitude: 51.1, Longitude: -0.1, Altitude: 100, TimeStamp: start, Provenance: &adsb},
+		{Latitude: 51.2, Longitude: -0.2, Altitude: 200, TimeStamp: start.Add(time.Second * 10), Provenance: &mlat},
+		{Latitude: 51.3, Longitude: -0.3, Altitude: 300, TimeStamp: start.Add(time.Second * 20), Provenance: &mlat},
+		{Latitude: 51.4, Longitude: -0.4, Altitude: 400, TimeStamp: start.Add(time.Second * 30)},
+	}
+	w := NewWriter(WriterOptions{RouteName: "route"})
+	w.Write(locations[:2])
+	w.Write(locations[2:])
+	assert.Equal(t, 3, len(w.segments))
+	k, err := w.Final()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, strings.Count(k, `<Style id="mlat">`))
+	assert.Equal(t, 1, strings.Count(k, "<name>route (MLAT)</name>"))
+	assert.Equal(t, 1, strings.Count(k, "<styleUrl>#mlat</styleUrl>"))
+	assert.Equal(t, 3, strings.Count(k, "<gx:Track>"))
+	// each segment begins where the previous one ended
+	assert.Equal(t, "            <gx:coord>-0.100000 51.100000 100</gx:coord>\n"+
+		"            <gx:coord>-0.200000 51.200000 200</gx:coord>\n"+
+		"            <gx:coord>-0.300000 51.300000 300</gx:coord>\n", w.segments[1].coord)
+	assert.Equal(t, 2, strings.Count(w.segments[2].when, "<when>"))
+}
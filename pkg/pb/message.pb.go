@@ -10,6 +10,8 @@ import (
 	proto "github.com/golang/protobuf/proto"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -25,12 +27,83 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+// Provenance - how the data in a message was obtained. MLAT positions
+// are calculated by a multilateration server from the arrival times at
+// several receivers, so are much less accurate than ADS-B positions.
+type Provenance int32
+
+const (
+	// Unknown - the producer doesn't know where the data came from
+	Provenance_Unknown Provenance = 0
+	// ADSB - broadcast by the aircraft (ADS-B)
+	Provenance_ADSB Provenance = 1
+	// MLAT - calculated by multilateration
+	Provenance_MLAT Provenance = 2
+	// TISB - rebroadcast by ground stations from radar data (TIS-B)
+	Provenance_TISB Provenance = 3
+	// ADSR - ADS-B rebroadcast by ground stations from another link (ADS-R)
+	Provenance_ADSR Provenance = 4
+	// ModeS - Mode S replies which aren't ADS-B, such as altitude replies
+	Provenance_ModeS Provenance = 5
+)
+
+// Enum value maps for Provenance.
+var (
+	Provenance_name = map[int32]string{
+		0: "Unknown",
+		1: "ADSB",
+		2: "MLAT",
+		3: "TISB",
+		4: "ADSR",
+		5: "ModeS",
+	}
+	Provenance_value = map[string]int32{
+		"Unknown": 0,
+		"ADSB":    1,
+		"MLAT":    2,
+		"TISB":    3,
+		"ADSR":    4,
+		"ModeS":   5,
+	}
+)
+
+func (x Provenance) Enum() *Provenance {
+	p := new(Provenance)
+	*p = x
+	return p
+}
+
+func (x Provenance) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Provenance) Descriptor() protoreflect.EnumDescriptor {
+	return file_message_proto_enumTypes[0].Descriptor()
+}
+
+func (Provenance) Type() protoreflect.EnumType {
+	return &file_message_proto_enumTypes[0]
+}
+
+func (x Provenance) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Provenance.Descriptor instead.
+func (Provenance) EnumDescriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{0}
+}
+
 // SourceType - enumeration of types of message producers
 type Source_SourceType int32
 
 const (
 	Source_AdsbExchange Source_SourceType = 0
 	Source_BeastServer  Source_SourceType = 1
+	Source_SbsServer    Source_SourceType = 2
+	Source_AvrServer    Source_SourceType = 3
+	Source_AircraftJson Source_SourceType = 4
+	Source_UatServer    Source_SourceType = 5
 )
 
 // Enum value maps for Source_SourceType.
@@ -38,10 +111,18 @@ var (
 	Source_SourceType_name = map[int32]string{
 		0: "AdsbExchange",
 		1: "BeastServer",
+		2: "SbsServer",
+		3: "AvrServer",
+		4: "AircraftJson",
+		5: "UatServer",
 	}
 	Source_SourceType_value = map[string]int32{
 		"AdsbExchange": 0,
 		"BeastServer":  1,
+		"SbsServer":    2,
+		"AvrServer":    3,
+		"AircraftJson": 4,
+		"UatServer":    5,
 	}
 )
 
@@ -56,11 +137,11 @@ func (x Source_SourceType) String() string {
 }
 
 func (Source_SourceType) Descriptor() protoreflect.EnumDescriptor {
-	return file_message_proto_enumTypes[0].Descriptor()
+	return file_message_proto_enumTypes[1].Descriptor()
 }
 
 func (Source_SourceType) Type() protoreflect.EnumType {
-	return &file_message_proto_enumTypes[0]
+	return &file_message_proto_enumTypes[1]
 }
 
 func (x Source_SourceType) Number() protoreflect.EnumNumber {
@@ -79,7 +160,7 @@ type Source struct {
 	unknownFields protoimpl.UnknownFields
 
 	// Name - name of the producer. ADSB Exchange is 'adsbx'.
-	// Beast Servers use the name from the config entry.
+	// Other producers use the name from the config entry.
 	Name string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
 	// Type - type of producer that produced this message
 	Type Source_SourceType `protobuf:"varint,2,opt,name=Type,proto3,enum=airtrack.Source_SourceType" json:"Type,omitempty"`
@@ -336,6 +417,17 @@ type Message struct {
 	// Signal contains information about the signal strength. Only
 	// set for BEAST messages currently.
 	Signal *Signal `protobuf:"bytes,2,opt,name=Signal,proto3" json:"Signal,omitempty"`
+	// Time - unix time in milliseconds when the message was received.
+	// Only set when replaying a capture, where the tracker uses it
+	// instead of the current time.
+	Time int64 `protobuf:"varint,3,opt,name=Time,proto3" json:"Time,omitempty"`
+	// Provenance - how the data in the message was obtained. It
+	// applies to the position and all other fields in the message.
+	Provenance Provenance `protobuf:"varint,4,opt,name=Provenance,proto3,enum=airtrack.Provenance" json:"Provenance,omitempty"`
+	// Raw - the Mode S or UAT frame the message was decoded from. Only
+	// set by producers which receive frames (BEAST, AVR and raw UAT), and
+	// used to detect the same frame received by several receivers.
+	Raw []byte `protobuf:"bytes,5,opt,name=Raw,proto3" json:"Raw,omitempty"`
 	// Icao - 6 character hex identifier for aircraft
 	Icao string `protobuf:"bytes,10,opt,name=Icao,proto3" json:"Icao,omitempty"`
 	// Squawk - a 4 digit octal squawk code (as a string)
@@ -423,6 +515,11 @@ type Message struct {
 	SIL uint32 `protobuf:"varint,108,opt,name=SIL,proto3" json:"SIL,omitempty"`
 	// SILType: interpretation of SIL: unknown, perhour, persample
 	SILType uint32 `protobuf:"varint,109,opt,name=SILType,proto3" json:"SILType,omitempty"`
+	// HaveEmergency indicates whether Emergency is set
+	HaveEmergency bool `protobuf:"varint,110,opt,name=HaveEmergency,proto3" json:"HaveEmergency,omitempty"`
+	// Emergency: ADS-B emergency/priority status, a superset of the 7x00 squawks (2.2.3.2.7.8.1.1)
+	// One of none, general, lifeguard, minfuel, nordo, unlawful, downed, reserved
+	Emergency string `protobuf:"bytes,111,opt,name=Emergency,proto3" json:"Emergency,omitempty"`
 }
 
 func (x *Message) Reset() {
@@ -471,6 +568,27 @@ func (x *Message) GetSignal() *Signal {
 	return nil
 }
 
+func (x *Message) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *Message) GetProvenance() Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return Provenance_Unknown
+}
+
+func (x *Message) GetRaw() []byte {
+	if x != nil {
+		return x.Raw
+	}
+	return nil
+}
+
 func (x *Message) GetIcao() string {
 	if x != nil {
 		return x.Icao
@@ -786,6 +904,20 @@ func (x *Message) GetSILType() uint32 {
 	return 0
 }
 
+func (x *Message) GetHaveEmergency() bool {
+	if x != nil {
+		return x.HaveEmergency
+	}
+	return false
+}
+
+func (x *Message) GetEmergency() string {
+	if x != nil {
+		return x.Emergency
+	}
+	return ""
+}
+
 // State contains general information about a sighting.
 type State struct {
 	state         protoimpl.MessageState
@@ -905,6 +1037,24 @@ type State struct {
 	SIL uint32 `protobuf:"varint,115,opt,name=SIL,proto3" json:"SIL,omitempty"`
 	// SILType: interpretation of SIL: unknown, perhour, persample
 	SILType uint32 `protobuf:"varint,116,opt,name=SILType,proto3" json:"SILType,omitempty"`
+	// HaveEmergency indicates whether Emergency is set
+	HaveEmergency bool `protobuf:"varint,117,opt,name=HaveEmergency,proto3" json:"HaveEmergency,omitempty"`
+	// Emergency: ADS-B emergency/priority status, a superset of the 7x00 squawks (2.2.3.2.7.8.1.1)
+	// One of none, general, lifeguard, minfuel, nordo, unlawful, downed, reserved
+	Emergency string `protobuf:"bytes,118,opt,name=Emergency,proto3" json:"Emergency,omitempty"`
+	// Zones: names of the configured zones the aircraft is currently inside
+	Zones []string `protobuf:"bytes,119,rep,name=Zones,proto3" json:"Zones,omitempty"`
+	// Phase: the current flight phase, one of ground, takeoff, climb, cruise,
+	// descent, approach, landing or go_around. Empty if not known yet.
+	Phase string `protobuf:"bytes,120,opt,name=Phase,proto3" json:"Phase,omitempty"`
+	// IsOrbiting: true while the aircraft is circling or holding over an area
+	IsOrbiting bool `protobuf:"varint,121,opt,name=IsOrbiting,proto3" json:"IsOrbiting,omitempty"`
+	// Watchlist: label of the first configured watchlist containing the
+	// aircraft's ICAO or registration. Empty if it's not on a watchlist.
+	Watchlist string `protobuf:"bytes,122,opt,name=Watchlist,proto3" json:"Watchlist,omitempty"`
+	// LocationProvenance: how the current position was obtained. Only
+	// meaningful if HaveLocation is set.
+	LocationProvenance Provenance `protobuf:"varint,123,opt,name=LocationProvenance,proto3,enum=airtrack.Provenance" json:"LocationProvenance,omitempty"`
 }
 
 func (x *State) Reset() {
@@ -1324,261 +1474,449 @@ func (x *State) GetSILType() uint32 {
 	return 0
 }
 
+func (x *State) GetHaveEmergency() bool {
+	if x != nil {
+		return x.HaveEmergency
+	}
+	return false
+}
+
+func (x *State) GetEmergency() string {
+	if x != nil {
+		return x.Emergency
+	}
+	return ""
+}
+
+func (x *State) GetZones() []string {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+func (x *State) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *State) GetIsOrbiting() bool {
+	if x != nil {
+		return x.IsOrbiting
+	}
+	return false
+}
+
+func (x *State) GetWatchlist() string {
+	if x != nil {
+		return x.Watchlist
+	}
+	return ""
+}
+
+func (x *State) GetLocationProvenance() Provenance {
+	if x != nil {
+		return x.LocationProvenance
+	}
+	return Provenance_Unknown
+}
+
+// Sighting contains information about a project's current sighting
+// of an aircraft. It's available to filters as `sighting`.
+type Sighting struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// FirstSeen - when the sighting began
+	FirstSeen *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=FirstSeen,proto3" json:"FirstSeen,omitempty"`
+	// Duration - time since the sighting began
+	Duration *durationpb.Duration `protobuf:"bytes,2,opt,name=Duration,proto3" json:"Duration,omitempty"`
+	// HaveOrigin - used to indicate whether Origin and OriginName are set.
+	// Requires the geocode_endpoints feature.
+	HaveOrigin bool `protobuf:"varint,3,opt,name=HaveOrigin,proto3" json:"HaveOrigin,omitempty"`
+	// Origin - ICAO code of the airport where the sighting began
+	Origin string `protobuf:"bytes,4,opt,name=Origin,proto3" json:"Origin,omitempty"`
+	// OriginName - name of the airport where the sighting began
+	OriginName string `protobuf:"bytes,5,opt,name=OriginName,proto3" json:"OriginName,omitempty"`
+}
+
+func (x *Sighting) Reset() {
+	*x = Sighting{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Sighting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sighting) ProtoMessage() {}
+
+func (x *Sighting) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sighting.ProtoReflect.Descriptor instead.
+func (*Sighting) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Sighting) GetFirstSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return nil
+}
+
+func (x *Sighting) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *Sighting) GetHaveOrigin() bool {
+	if x != nil {
+		return x.HaveOrigin
+	}
+	return false
+}
+
+func (x *Sighting) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *Sighting) GetOriginName() string {
+	if x != nil {
+		return x.OriginName
+	}
+	return ""
+}
+
 var File_message_proto protoreflect.FileDescriptor
 
 var file_message_proto_rawDesc = []byte{
 	0x0a, 0x0d, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x08, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x22, 0x7e, 0x0a, 0x06, 0x53, 0x6f, 0x75,
-	0x72, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63, 0x6b,
-	0x2e, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2e, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x79,
-	0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x22, 0x2f, 0x0a, 0x0a, 0x53, 0x6f, 0x75, 0x72,
-	0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x41, 0x64, 0x73, 0x62, 0x45, 0x78,
-	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x42, 0x65, 0x61, 0x73,
-	0x74, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x10, 0x01, 0x22, 0x1c, 0x0a, 0x06, 0x53, 0x69, 0x67,
-	0x6e, 0x61, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x73, 0x73, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x01, 0x52, 0x04, 0x52, 0x73, 0x73, 0x69, 0x22, 0x7e, 0x0a, 0x0c, 0x41, 0x69, 0x72, 0x63, 0x72,
-	0x61, 0x66, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x22, 0x0a, 0x0c, 0x52, 0x65, 0x67, 0x69, 0x73,
-	0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x52,
-	0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x54,
-	0x79, 0x70, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x54,
-	0x79, 0x70, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x0c, 0x0a, 0x01, 0x46, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x01, 0x46, 0x12, 0x20, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x4e, 0x0a, 0x08, 0x4f, 0x70, 0x65, 0x72, 0x61,
-	0x74, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x6f, 0x75, 0x6e, 0x74,
-	0x72, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x43, 0x6f,
-	0x75, 0x6e, 0x74, 0x72, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x0c, 0x0a, 0x01, 0x52, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x52, 0x22, 0xe9, 0x0c, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x2e, 0x53,
-	0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x28, 0x0a,
-	0x06, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
-	0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x52,
-	0x06, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x49, 0x63, 0x61, 0x6f, 0x18,
-	0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x49, 0x63, 0x61, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x53,
-	0x71, 0x75, 0x61, 0x77, 0x6b, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x71, 0x75,
-	0x61, 0x77, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x61, 0x6c, 0x6c, 0x53, 0x69, 0x67, 0x6e, 0x18,
-	0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x43, 0x61, 0x6c, 0x6c, 0x53, 0x69, 0x67, 0x6e, 0x12,
-	0x2c, 0x0a, 0x11, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65,
-	0x74, 0x72, 0x69, 0x63, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x41, 0x6c, 0x74, 0x69,
-	0x74, 0x75, 0x64, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x2e, 0x0a,
-	0x12, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74,
-	0x72, 0x69, 0x63, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x41, 0x6c, 0x74, 0x69, 0x74,
-	0x75, 0x64, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x1a, 0x0a,
-	0x08, 0x4c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x4c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x4c, 0x6f, 0x6e,
-	0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4c, 0x6f,
-	0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x49, 0x73, 0x4f, 0x6e, 0x47,
-	0x72, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x49, 0x73, 0x4f,
-	0x6e, 0x47, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x34, 0x0a, 0x15, 0x56, 0x65, 0x72, 0x74, 0x69,
-	0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63,
-	0x18, 0x28, 0x20, 0x01, 0x28, 0x03, 0x52, 0x15, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c,
-	0x52, 0x61, 0x74, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x3c, 0x0a,
-	0x19, 0x48, 0x61, 0x76, 0x65, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74,
-	0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x29, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x19, 0x48, 0x61, 0x76, 0x65, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61,
-	0x74, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x36, 0x0a, 0x16, 0x56,
-	0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d,
-	0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x2d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x16, 0x56, 0x65, 0x72,
-	0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74,
-	0x72, 0x69, 0x63, 0x12, 0x3e, 0x0a, 0x1a, 0x48, 0x61, 0x76, 0x65, 0x56, 0x65, 0x72, 0x74, 0x69,
-	0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69,
-	0x63, 0x18, 0x2e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1a, 0x48, 0x61, 0x76, 0x65, 0x56, 0x65, 0x72,
-	0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74,
-	0x72, 0x69, 0x63, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x32, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x28, 0x0a, 0x0f, 0x4d, 0x61, 0x67,
-	0x6e, 0x65, 0x74, 0x69, 0x63, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x33, 0x20, 0x01,
-	0x28, 0x01, 0x52, 0x0f, 0x4d, 0x61, 0x67, 0x6e, 0x65, 0x74, 0x69, 0x63, 0x48, 0x65, 0x61, 0x64,
-	0x69, 0x6e, 0x67, 0x12, 0x30, 0x0a, 0x13, 0x48, 0x61, 0x76, 0x65, 0x4d, 0x61, 0x67, 0x6e, 0x65,
-	0x74, 0x69, 0x63, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x34, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x13, 0x48, 0x61, 0x76, 0x65, 0x4d, 0x61, 0x67, 0x6e, 0x65, 0x74, 0x69, 0x63, 0x48, 0x65,
-	0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x20, 0x0a, 0x0b, 0x54, 0x72, 0x75, 0x65, 0x48, 0x65, 0x61,
-	0x64, 0x69, 0x6e, 0x67, 0x18, 0x35, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x54, 0x72, 0x75, 0x65,
-	0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x28, 0x0a, 0x0f, 0x48, 0x61, 0x76, 0x65, 0x54,
-	0x72, 0x75, 0x65, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x36, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x0f, 0x48, 0x61, 0x76, 0x65, 0x54, 0x72, 0x75, 0x65, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e,
-	0x67, 0x12, 0x28, 0x0a, 0x0f, 0x48, 0x61, 0x76, 0x65, 0x46, 0x6d, 0x73, 0x41, 0x6c, 0x74, 0x69,
-	0x74, 0x75, 0x64, 0x65, 0x18, 0x3c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x48, 0x61, 0x76, 0x65,
-	0x46, 0x6d, 0x73, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x46,
-	0x6d, 0x73, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x3d, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x0b, 0x46, 0x6d, 0x73, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x26, 0x0a,
-	0x0e, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x61, 0x76, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18,
-	0x41, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x61, 0x76, 0x48, 0x65,
-	0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x4e, 0x61, 0x76, 0x48, 0x65, 0x61, 0x64,
-	0x69, 0x6e, 0x67, 0x18, 0x42, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x4e, 0x61, 0x76, 0x48, 0x65,
-	0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x61, 0x76,
-	0x51, 0x4e, 0x48, 0x18, 0x43, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x48, 0x61, 0x76, 0x65, 0x4e,
-	0x61, 0x76, 0x51, 0x4e, 0x48, 0x12, 0x16, 0x0a, 0x06, 0x4e, 0x61, 0x76, 0x51, 0x4e, 0x48, 0x18,
-	0x44, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x4e, 0x61, 0x76, 0x51, 0x4e, 0x48, 0x12, 0x22, 0x0a,
-	0x0c, 0x48, 0x61, 0x76, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x46, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x0c, 0x48, 0x61, 0x76, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72,
-	0x79, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x47, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x08, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x20, 0x0a,
-	0x0b, 0x47, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5a, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0b, 0x47, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12,
-	0x2a, 0x0a, 0x10, 0x48, 0x61, 0x76, 0x65, 0x54, 0x72, 0x75, 0x65, 0x41, 0x69, 0x72, 0x53, 0x70,
-	0x65, 0x65, 0x64, 0x18, 0x5b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x48, 0x61, 0x76, 0x65, 0x54,
-	0x72, 0x75, 0x65, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x54,
-	0x72, 0x75, 0x65, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5c, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x0c, 0x54, 0x72, 0x75, 0x65, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12,
-	0x34, 0x0a, 0x15, 0x48, 0x61, 0x76, 0x65, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64,
-	0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x15,
-	0x48, 0x61, 0x76, 0x65, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41, 0x69, 0x72,
-	0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x2c, 0x0a, 0x11, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74,
-	0x65, 0x64, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5e, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x11, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41, 0x69, 0x72, 0x53, 0x70,
-	0x65, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4d, 0x61, 0x63, 0x68, 0x18,
-	0x5f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4d, 0x61, 0x63, 0x68, 0x12,
-	0x12, 0x0a, 0x04, 0x4d, 0x61, 0x63, 0x68, 0x18, 0x60, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x4d,
-	0x61, 0x63, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x48, 0x61, 0x76, 0x65, 0x52, 0x6f, 0x6c, 0x6c, 0x18,
-	0x61, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x48, 0x61, 0x76, 0x65, 0x52, 0x6f, 0x6c, 0x6c, 0x12,
-	0x12, 0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x6c, 0x18, 0x62, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x52,
-	0x6f, 0x6c, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x4e, 0x61, 0x76, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x18,
-	0x63, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x4e, 0x61, 0x76, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x12,
-	0x20, 0x0a, 0x0b, 0x41, 0x44, 0x53, 0x42, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x64,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x41, 0x44, 0x53, 0x42, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x50, 0x18, 0x65, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x50, 0x12, 0x12, 0x0a,
-	0x04, 0x4e, 0x41, 0x43, 0x50, 0x18, 0x66, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x4e, 0x41, 0x43,
-	0x50, 0x12, 0x1a, 0x0a, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x56, 0x18, 0x67, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x56, 0x12, 0x12, 0x0a,
-	0x04, 0x4e, 0x41, 0x43, 0x56, 0x18, 0x68, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x4e, 0x41, 0x43,
-	0x56, 0x12, 0x20, 0x0a, 0x0b, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f,
-	0x18, 0x69, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x49, 0x43, 0x42,
-	0x61, 0x72, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f, 0x18, 0x6a,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f, 0x12, 0x18, 0x0a,
-	0x07, 0x48, 0x61, 0x76, 0x65, 0x53, 0x49, 0x4c, 0x18, 0x6b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
-	0x48, 0x61, 0x76, 0x65, 0x53, 0x49, 0x4c, 0x12, 0x10, 0x0a, 0x03, 0x53, 0x49, 0x4c, 0x18, 0x6c,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x53, 0x49, 0x4c, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x49, 0x4c,
-	0x54, 0x79, 0x70, 0x65, 0x18, 0x6d, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x53, 0x49, 0x4c, 0x54,
-	0x79, 0x70, 0x65, 0x22, 0x99, 0x0f, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a,
-	0x04, 0x49, 0x63, 0x61, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x49, 0x63, 0x61,
-	0x6f, 0x12, 0x2a, 0x0a, 0x04, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x16, 0x2e, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x2e, 0x41, 0x69, 0x72, 0x63, 0x72,
-	0x61, 0x66, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x22, 0x0a,
-	0x0c, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0c, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x43, 0x6f, 0x64,
-	0x65, 0x12, 0x2e, 0x0a, 0x08, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x2e, 0x4f,
-	0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x08, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f,
-	0x72, 0x12, 0x30, 0x0a, 0x0a, 0x4c, 0x61, 0x73, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63, 0x6b,
-	0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x52, 0x0a, 0x4c, 0x61, 0x73, 0x74, 0x53, 0x69, 0x67,
-	0x6e, 0x61, 0x6c, 0x12, 0x36, 0x0a, 0x16, 0x48, 0x61, 0x76, 0x65, 0x41, 0x6c, 0x74, 0x69, 0x74,
-	0x75, 0x64, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x0a, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x16, 0x48, 0x61, 0x76, 0x65, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64,
-	0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x2e, 0x0a, 0x12, 0x41,
-	0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69,
-	0x63, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64,
-	0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x34, 0x0a, 0x15, 0x48,
-	0x61, 0x76, 0x65, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65,
-	0x74, 0x72, 0x69, 0x63, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x15, 0x48, 0x61, 0x76, 0x65,
+	0x08, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xbd, 0x01, 0x0a, 0x06, 0x53,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x04, 0x54, 0x79, 0x70,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61,
+	0x63, 0x6b, 0x2e, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2e, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x22, 0x6e, 0x0a, 0x0a, 0x53, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x41, 0x64, 0x73, 0x62,
+	0x45, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x42, 0x65,
+	0x61, 0x73, 0x74, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x53,
+	0x62, 0x73, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x76,
+	0x72, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c, 0x41, 0x69, 0x72,
+	0x63, 0x72, 0x61, 0x66, 0x74, 0x4a, 0x73, 0x6f, 0x6e, 0x10, 0x04, 0x12, 0x0d, 0x0a, 0x09, 0x55,
+	0x61, 0x74, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x10, 0x05, 0x22, 0x1c, 0x0a, 0x06, 0x53, 0x69,
+	0x67, 0x6e, 0x61, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x73, 0x73, 0x69, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x04, 0x52, 0x73, 0x73, 0x69, 0x22, 0x7e, 0x0a, 0x0c, 0x41, 0x69, 0x72, 0x63,
+	0x72, 0x61, 0x66, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x22, 0x0a, 0x0c, 0x52, 0x65, 0x67, 0x69,
+	0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08,
+	0x54, 0x79, 0x70, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x54, 0x79, 0x70, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x0c, 0x0a, 0x01, 0x46, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x01, 0x46, 0x12, 0x20, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x44, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x4e, 0x0a, 0x08, 0x4f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x72, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x0c, 0x0a, 0x01, 0x52, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x52, 0x22, 0x89, 0x0e, 0x0a, 0x07, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x2e,
+	0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x28,
+	0x0a, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c,
+	0x52, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x69, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x34, 0x0a, 0x0a,
+	0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x14, 0x2e, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x76,
+	0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x0a, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e,
+	0x63, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x52, 0x61, 0x77, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x03, 0x52, 0x61, 0x77, 0x12, 0x12, 0x0a, 0x04, 0x49, 0x63, 0x61, 0x6f, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x49, 0x63, 0x61, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x71, 0x75, 0x61,
+	0x77, 0x6b, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x71, 0x75, 0x61, 0x77, 0x6b,
+	0x12, 0x1a, 0x0a, 0x08, 0x43, 0x61, 0x6c, 0x6c, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x43, 0x61, 0x6c, 0x6c, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x2c, 0x0a, 0x11,
 	0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69,
-	0x63, 0x12, 0x2c, 0x0a, 0x11, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x47, 0x65, 0x6f,
-	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x41, 0x6c,
-	0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12,
-	0x22, 0x0a, 0x0c, 0x48, 0x61, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
-	0x14, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x48, 0x61, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18,
-	0x15, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x4c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12,
-	0x1c, 0x0a, 0x09, 0x4c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x16, 0x20, 0x01,
-	0x28, 0x01, 0x52, 0x09, 0x4c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x22, 0x0a,
-	0x0c, 0x48, 0x61, 0x76, 0x65, 0x43, 0x61, 0x6c, 0x6c, 0x73, 0x69, 0x67, 0x6e, 0x18, 0x1e, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x0c, 0x48, 0x61, 0x76, 0x65, 0x43, 0x61, 0x6c, 0x6c, 0x73, 0x69, 0x67,
-	0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x61, 0x6c, 0x6c, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x1f, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x08, 0x43, 0x61, 0x6c, 0x6c, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x1e, 0x0a,
-	0x0a, 0x48, 0x61, 0x76, 0x65, 0x53, 0x71, 0x75, 0x61, 0x77, 0x6b, 0x18, 0x28, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x0a, 0x48, 0x61, 0x76, 0x65, 0x53, 0x71, 0x75, 0x61, 0x77, 0x6b, 0x12, 0x16, 0x0a,
-	0x06, 0x53, 0x71, 0x75, 0x61, 0x77, 0x6b, 0x18, 0x29, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53,
-	0x71, 0x75, 0x61, 0x77, 0x6b, 0x12, 0x20, 0x0a, 0x0b, 0x48, 0x61, 0x76, 0x65, 0x43, 0x6f, 0x75,
-	0x6e, 0x74, 0x72, 0x79, 0x18, 0x32, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x48, 0x61, 0x76, 0x65,
-	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x6f, 0x75, 0x6e, 0x74,
-	0x72, 0x79, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x33, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x43, 0x6f,
-	0x75, 0x6e, 0x74, 0x72, 0x79, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x75,
-	0x6e, 0x74, 0x72, 0x79, 0x18, 0x34, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x6f, 0x75, 0x6e,
-	0x74, 0x72, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x49, 0x73, 0x4f, 0x6e, 0x47, 0x72, 0x6f, 0x75, 0x6e,
-	0x64, 0x18, 0x3c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x49, 0x73, 0x4f, 0x6e, 0x47, 0x72, 0x6f,
-	0x75, 0x6e, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x61, 0x76, 0x51, 0x4e,
-	0x48, 0x18, 0x3d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x61, 0x76,
-	0x51, 0x4e, 0x48, 0x12, 0x16, 0x0a, 0x06, 0x4e, 0x61, 0x76, 0x51, 0x4e, 0x48, 0x18, 0x3e, 0x20,
-	0x01, 0x28, 0x01, 0x52, 0x06, 0x4e, 0x61, 0x76, 0x51, 0x4e, 0x48, 0x12, 0x3e, 0x0a, 0x1a, 0x48,
-	0x61, 0x76, 0x65, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x42,
-	0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x46, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x1a, 0x48, 0x61, 0x76, 0x65, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74,
-	0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x36, 0x0a, 0x16, 0x56,
-	0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d,
-	0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x47, 0x20, 0x01, 0x28, 0x03, 0x52, 0x16, 0x56, 0x65, 0x72,
-	0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74,
-	0x72, 0x69, 0x63, 0x12, 0x3c, 0x0a, 0x19, 0x48, 0x61, 0x76, 0x65, 0x56, 0x65, 0x72, 0x74, 0x69,
-	0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63,
-	0x18, 0x4b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x19, 0x48, 0x61, 0x76, 0x65, 0x56, 0x65, 0x72, 0x74,
+	0x63, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64,
+	0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x2e, 0x0a, 0x12, 0x41, 0x6c,
+	0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65,
+	0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x61,
+	0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x4c, 0x61,
+	0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x4c, 0x6f, 0x6e, 0x67, 0x69, 0x74,
+	0x75, 0x64, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4c, 0x6f, 0x6e, 0x67, 0x69,
+	0x74, 0x75, 0x64, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x49, 0x73, 0x4f, 0x6e, 0x47, 0x72, 0x6f, 0x75,
+	0x6e, 0x64, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x49, 0x73, 0x4f, 0x6e, 0x47, 0x72,
+	0x6f, 0x75, 0x6e, 0x64, 0x12, 0x34, 0x0a, 0x15, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c,
+	0x52, 0x61, 0x74, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x28, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x15, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74,
+	0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x3c, 0x0a, 0x19, 0x48, 0x61,
+	0x76, 0x65, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x47, 0x65,
+	0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x29, 0x20, 0x01, 0x28, 0x08, 0x52, 0x19, 0x48,
+	0x61, 0x76, 0x65, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x47,
+	0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x36, 0x0a, 0x16, 0x56, 0x65, 0x72, 0x74,
+	0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x18, 0x2d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x16, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63,
+	0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x12, 0x3e, 0x0a, 0x1a, 0x48, 0x61, 0x76, 0x65, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c,
+	0x52, 0x61, 0x74, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x2e,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x1a, 0x48, 0x61, 0x76, 0x65, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63,
+	0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x12, 0x14, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x32, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x28, 0x0a, 0x0f, 0x4d, 0x61, 0x67, 0x6e, 0x65, 0x74,
+	0x69, 0x63, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x33, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0f, 0x4d, 0x61, 0x67, 0x6e, 0x65, 0x74, 0x69, 0x63, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67,
+	0x12, 0x30, 0x0a, 0x13, 0x48, 0x61, 0x76, 0x65, 0x4d, 0x61, 0x67, 0x6e, 0x65, 0x74, 0x69, 0x63,
+	0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x34, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x48,
+	0x61, 0x76, 0x65, 0x4d, 0x61, 0x67, 0x6e, 0x65, 0x74, 0x69, 0x63, 0x48, 0x65, 0x61, 0x64, 0x69,
+	0x6e, 0x67, 0x12, 0x20, 0x0a, 0x0b, 0x54, 0x72, 0x75, 0x65, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e,
+	0x67, 0x18, 0x35, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x54, 0x72, 0x75, 0x65, 0x48, 0x65, 0x61,
+	0x64, 0x69, 0x6e, 0x67, 0x12, 0x28, 0x0a, 0x0f, 0x48, 0x61, 0x76, 0x65, 0x54, 0x72, 0x75, 0x65,
+	0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x36, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x48,
+	0x61, 0x76, 0x65, 0x54, 0x72, 0x75, 0x65, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x28,
+	0x0a, 0x0f, 0x48, 0x61, 0x76, 0x65, 0x46, 0x6d, 0x73, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64,
+	0x65, 0x18, 0x3c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x48, 0x61, 0x76, 0x65, 0x46, 0x6d, 0x73,
+	0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x46, 0x6d, 0x73, 0x41,
+	0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x3d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x46,
+	0x6d, 0x73, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x48, 0x61,
+	0x76, 0x65, 0x4e, 0x61, 0x76, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x41, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0e, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x61, 0x76, 0x48, 0x65, 0x61, 0x64, 0x69,
+	0x6e, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x4e, 0x61, 0x76, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67,
+	0x18, 0x42, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x4e, 0x61, 0x76, 0x48, 0x65, 0x61, 0x64, 0x69,
+	0x6e, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x61, 0x76, 0x51, 0x4e, 0x48,
+	0x18, 0x43, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x61, 0x76, 0x51,
+	0x4e, 0x48, 0x12, 0x16, 0x0a, 0x06, 0x4e, 0x61, 0x76, 0x51, 0x4e, 0x48, 0x18, 0x44, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x06, 0x4e, 0x61, 0x76, 0x51, 0x4e, 0x48, 0x12, 0x22, 0x0a, 0x0c, 0x48, 0x61,
+	0x76, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x46, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0c, 0x48, 0x61, 0x76, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x1a,
+	0x0a, 0x08, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x47, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x47, 0x72,
+	0x6f, 0x75, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5a, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x47, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x2a, 0x0a, 0x10,
+	0x48, 0x61, 0x76, 0x65, 0x54, 0x72, 0x75, 0x65, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64,
+	0x18, 0x5b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x48, 0x61, 0x76, 0x65, 0x54, 0x72, 0x75, 0x65,
+	0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x54, 0x72, 0x75, 0x65,
+	0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5c, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c,
+	0x54, 0x72, 0x75, 0x65, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x34, 0x0a, 0x15,
+	0x48, 0x61, 0x76, 0x65, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41, 0x69, 0x72,
+	0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x15, 0x48, 0x61, 0x76,
+	0x65, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65,
+	0x65, 0x64, 0x12, 0x2c, 0x0a, 0x11, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41,
+	0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5e, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x49,
+	0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64,
+	0x12, 0x1a, 0x0a, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4d, 0x61, 0x63, 0x68, 0x18, 0x5f, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4d, 0x61, 0x63, 0x68, 0x12, 0x12, 0x0a, 0x04,
+	0x4d, 0x61, 0x63, 0x68, 0x18, 0x60, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x4d, 0x61, 0x63, 0x68,
+	0x12, 0x1a, 0x0a, 0x08, 0x48, 0x61, 0x76, 0x65, 0x52, 0x6f, 0x6c, 0x6c, 0x18, 0x61, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x48, 0x61, 0x76, 0x65, 0x52, 0x6f, 0x6c, 0x6c, 0x12, 0x12, 0x0a, 0x04,
+	0x52, 0x6f, 0x6c, 0x6c, 0x18, 0x62, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x52, 0x6f, 0x6c, 0x6c,
+	0x12, 0x1a, 0x0a, 0x08, 0x4e, 0x61, 0x76, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x63, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x08, 0x4e, 0x61, 0x76, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b,
+	0x41, 0x44, 0x53, 0x42, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x64, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0b, 0x41, 0x44, 0x53, 0x42, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a,
+	0x0a, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x50, 0x18, 0x65, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x50, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x41,
+	0x43, 0x50, 0x18, 0x66, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x4e, 0x41, 0x43, 0x50, 0x12, 0x1a,
+	0x0a, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x56, 0x18, 0x67, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x56, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x41,
+	0x43, 0x56, 0x18, 0x68, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x4e, 0x41, 0x43, 0x56, 0x12, 0x20,
+	0x0a, 0x0b, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f, 0x18, 0x69, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0b, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f,
+	0x12, 0x18, 0x0a, 0x07, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f, 0x18, 0x6a, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x48, 0x61,
+	0x76, 0x65, 0x53, 0x49, 0x4c, 0x18, 0x6b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x48, 0x61, 0x76,
+	0x65, 0x53, 0x49, 0x4c, 0x12, 0x10, 0x0a, 0x03, 0x53, 0x49, 0x4c, 0x18, 0x6c, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x03, 0x53, 0x49, 0x4c, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x49, 0x4c, 0x54, 0x79, 0x70,
+	0x65, 0x18, 0x6d, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x53, 0x49, 0x4c, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x24, 0x0a, 0x0d, 0x48, 0x61, 0x76, 0x65, 0x45, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63,
+	0x79, 0x18, 0x6e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x48, 0x61, 0x76, 0x65, 0x45, 0x6d, 0x65,
+	0x72, 0x67, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x45, 0x6d, 0x65, 0x72, 0x67, 0x65,
+	0x6e, 0x63, 0x79, 0x18, 0x6f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x45, 0x6d, 0x65, 0x72, 0x67,
+	0x65, 0x6e, 0x63, 0x79, 0x22, 0x8d, 0x11, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x49, 0x63, 0x61, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x49, 0x63,
+	0x61, 0x6f, 0x12, 0x2a, 0x0a, 0x04, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x2e, 0x41, 0x69, 0x72, 0x63,
+	0x72, 0x61, 0x66, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x22,
+	0x0a, 0x0c, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x2e, 0x0a, 0x08, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x2e,
+	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x08, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x6f, 0x72, 0x12, 0x30, 0x0a, 0x0a, 0x4c, 0x61, 0x73, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63,
+	0x6b, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x52, 0x0a, 0x4c, 0x61, 0x73, 0x74, 0x53, 0x69,
+	0x67, 0x6e, 0x61, 0x6c, 0x12, 0x36, 0x0a, 0x16, 0x48, 0x61, 0x76, 0x65, 0x41, 0x6c, 0x74, 0x69,
+	0x74, 0x75, 0x64, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x16, 0x48, 0x61, 0x76, 0x65, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75,
+	0x64, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x2e, 0x0a, 0x12,
+	0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75,
+	0x64, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x34, 0x0a, 0x15,
+	0x48, 0x61, 0x76, 0x65, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x47, 0x65, 0x6f, 0x6d,
+	0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x15, 0x48, 0x61, 0x76,
+	0x65, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x12, 0x2c, 0x0a, 0x11, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x47, 0x65,
+	0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x41,
+	0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x12, 0x22, 0x0a, 0x0c, 0x48, 0x61, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x14, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x48, 0x61, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65,
+	0x18, 0x15, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x4c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x4c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x16, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x09, 0x4c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x22,
+	0x0a, 0x0c, 0x48, 0x61, 0x76, 0x65, 0x43, 0x61, 0x6c, 0x6c, 0x73, 0x69, 0x67, 0x6e, 0x18, 0x1e,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x48, 0x61, 0x76, 0x65, 0x43, 0x61, 0x6c, 0x6c, 0x73, 0x69,
+	0x67, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x61, 0x6c, 0x6c, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x1f,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x43, 0x61, 0x6c, 0x6c, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x1e,
+	0x0a, 0x0a, 0x48, 0x61, 0x76, 0x65, 0x53, 0x71, 0x75, 0x61, 0x77, 0x6b, 0x18, 0x28, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0a, 0x48, 0x61, 0x76, 0x65, 0x53, 0x71, 0x75, 0x61, 0x77, 0x6b, 0x12, 0x16,
+	0x0a, 0x06, 0x53, 0x71, 0x75, 0x61, 0x77, 0x6b, 0x18, 0x29, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x53, 0x71, 0x75, 0x61, 0x77, 0x6b, 0x12, 0x20, 0x0a, 0x0b, 0x48, 0x61, 0x76, 0x65, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x32, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x48, 0x61, 0x76,
+	0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x72, 0x79, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x33, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x34, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x49, 0x73, 0x4f, 0x6e, 0x47, 0x72, 0x6f, 0x75,
+	0x6e, 0x64, 0x18, 0x3c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x49, 0x73, 0x4f, 0x6e, 0x47, 0x72,
+	0x6f, 0x75, 0x6e, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x61, 0x76, 0x51,
+	0x4e, 0x48, 0x18, 0x3d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x61,
+	0x76, 0x51, 0x4e, 0x48, 0x12, 0x16, 0x0a, 0x06, 0x4e, 0x61, 0x76, 0x51, 0x4e, 0x48, 0x18, 0x3e,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x4e, 0x61, 0x76, 0x51, 0x4e, 0x48, 0x12, 0x3e, 0x0a, 0x1a,
+	0x48, 0x61, 0x76, 0x65, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65,
+	0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x46, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x1a, 0x48, 0x61, 0x76, 0x65, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61,
+	0x74, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x36, 0x0a, 0x16,
+	0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x42, 0x61, 0x72, 0x6f,
+	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x47, 0x20, 0x01, 0x28, 0x03, 0x52, 0x16, 0x56, 0x65,
+	0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x42, 0x61, 0x72, 0x6f, 0x6d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x12, 0x3c, 0x0a, 0x19, 0x48, 0x61, 0x76, 0x65, 0x56, 0x65, 0x72, 0x74,
 	0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69,
-	0x63, 0x12, 0x34, 0x0a, 0x15, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74,
-	0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x4c, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x15, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x47, 0x65,
-	0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x1c, 0x0a, 0x09, 0x48, 0x61, 0x76, 0x65, 0x54,
-	0x72, 0x61, 0x63, 0x6b, 0x18, 0x50, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x48, 0x61, 0x76, 0x65,
-	0x54, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x51,
-	0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x28, 0x0a, 0x0f, 0x48,
-	0x61, 0x76, 0x65, 0x46, 0x6d, 0x73, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x55,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x48, 0x61, 0x76, 0x65, 0x46, 0x6d, 0x73, 0x41, 0x6c, 0x74,
-	0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x46, 0x6d, 0x73, 0x41, 0x6c, 0x74, 0x69,
-	0x74, 0x75, 0x64, 0x65, 0x18, 0x56, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x46, 0x6d, 0x73, 0x41,
-	0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x48, 0x61, 0x76, 0x65, 0x4e,
-	0x61, 0x76, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x57, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x0e, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x61, 0x76, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12,
-	0x1e, 0x0a, 0x0a, 0x4e, 0x61, 0x76, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x58, 0x20,
-	0x01, 0x28, 0x01, 0x52, 0x0a, 0x4e, 0x61, 0x76, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12,
-	0x28, 0x0a, 0x0f, 0x48, 0x61, 0x76, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x70, 0x65,
-	0x65, 0x64, 0x18, 0x5a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x48, 0x61, 0x76, 0x65, 0x47, 0x72,
-	0x6f, 0x75, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x47, 0x72, 0x6f,
-	0x75, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b,
-	0x47, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x2a, 0x0a, 0x10, 0x48,
-	0x61, 0x76, 0x65, 0x54, 0x72, 0x75, 0x65, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18,
-	0x5c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x48, 0x61, 0x76, 0x65, 0x54, 0x72, 0x75, 0x65, 0x41,
-	0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x54, 0x72, 0x75, 0x65, 0x41,
-	0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5d, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x54,
-	0x72, 0x75, 0x65, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x34, 0x0a, 0x15, 0x48,
-	0x61, 0x76, 0x65, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41, 0x69, 0x72, 0x53,
-	0x70, 0x65, 0x65, 0x64, 0x18, 0x5e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x15, 0x48, 0x61, 0x76, 0x65,
-	0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65,
-	0x64, 0x12, 0x2c, 0x0a, 0x11, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41, 0x69,
-	0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5f, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x49, 0x6e,
-	0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12,
-	0x1a, 0x0a, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4d, 0x61, 0x63, 0x68, 0x18, 0x60, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4d, 0x61, 0x63, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x4d,
-	0x61, 0x63, 0x68, 0x18, 0x61, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x4d, 0x61, 0x63, 0x68, 0x12,
-	0x1a, 0x0a, 0x08, 0x4e, 0x61, 0x76, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x63, 0x20, 0x01, 0x28,
-	0x0d, 0x52, 0x08, 0x4e, 0x61, 0x76, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x48,
-	0x61, 0x76, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x64, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x0c, 0x48, 0x61, 0x76, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12,
-	0x1a, 0x0a, 0x08, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x65, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x48,
-	0x61, 0x76, 0x65, 0x52, 0x6f, 0x6c, 0x6c, 0x18, 0x69, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x48,
-	0x61, 0x76, 0x65, 0x52, 0x6f, 0x6c, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x6c, 0x18,
-	0x6a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x52, 0x6f, 0x6c, 0x6c, 0x12, 0x20, 0x0a, 0x0b, 0x41,
-	0x44, 0x53, 0x42, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x6b, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x0b, 0x41, 0x44, 0x53, 0x42, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a,
-	0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x50, 0x18, 0x6c, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x50, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x41, 0x43,
-	0x50, 0x18, 0x6d, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x4e, 0x41, 0x43, 0x50, 0x12, 0x1a, 0x0a,
-	0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x56, 0x18, 0x6e, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x56, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x41, 0x43,
-	0x56, 0x18, 0x6f, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x4e, 0x41, 0x43, 0x56, 0x12, 0x20, 0x0a,
-	0x0b, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f, 0x18, 0x70, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x0b, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f, 0x12,
-	0x18, 0x0a, 0x07, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f, 0x18, 0x71, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x07, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x48, 0x61, 0x76,
-	0x65, 0x53, 0x49, 0x4c, 0x18, 0x72, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x48, 0x61, 0x76, 0x65,
-	0x53, 0x49, 0x4c, 0x12, 0x10, 0x0a, 0x03, 0x53, 0x49, 0x4c, 0x18, 0x73, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x03, 0x53, 0x49, 0x4c, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x49, 0x4c, 0x54, 0x79, 0x70, 0x65,
-	0x18, 0x74, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x53, 0x49, 0x4c, 0x54, 0x79, 0x70, 0x65, 0x42,
-	0x22, 0x5a, 0x20, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x66,
-	0x6b, 0x31, 0x31, 0x2f, 0x61, 0x69, 0x72, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x2f, 0x70, 0x6b, 0x67,
-	0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x63, 0x18, 0x4b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x19, 0x48, 0x61, 0x76, 0x65, 0x56, 0x65, 0x72,
+	0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x12, 0x34, 0x0a, 0x15, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61,
+	0x74, 0x65, 0x47, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x4c, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x15, 0x56, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x47,
+	0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x1c, 0x0a, 0x09, 0x48, 0x61, 0x76, 0x65,
+	0x54, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x50, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x48, 0x61, 0x76,
+	0x65, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x18,
+	0x51, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x28, 0x0a, 0x0f,
+	0x48, 0x61, 0x76, 0x65, 0x46, 0x6d, 0x73, 0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18,
+	0x55, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x48, 0x61, 0x76, 0x65, 0x46, 0x6d, 0x73, 0x41, 0x6c,
+	0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x46, 0x6d, 0x73, 0x41, 0x6c, 0x74,
+	0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x56, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x46, 0x6d, 0x73,
+	0x41, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x48, 0x61, 0x76, 0x65,
+	0x4e, 0x61, 0x76, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x57, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0e, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x61, 0x76, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67,
+	0x12, 0x1e, 0x0a, 0x0a, 0x4e, 0x61, 0x76, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x58,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x4e, 0x61, 0x76, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67,
+	0x12, 0x28, 0x0a, 0x0f, 0x48, 0x61, 0x76, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x70,
+	0x65, 0x65, 0x64, 0x18, 0x5a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x48, 0x61, 0x76, 0x65, 0x47,
+	0x72, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x47, 0x72,
+	0x6f, 0x75, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5b, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0b, 0x47, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x2a, 0x0a, 0x10,
+	0x48, 0x61, 0x76, 0x65, 0x54, 0x72, 0x75, 0x65, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64,
+	0x18, 0x5c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x48, 0x61, 0x76, 0x65, 0x54, 0x72, 0x75, 0x65,
+	0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x54, 0x72, 0x75, 0x65,
+	0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5d, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c,
+	0x54, 0x72, 0x75, 0x65, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x34, 0x0a, 0x15,
+	0x48, 0x61, 0x76, 0x65, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41, 0x69, 0x72,
+	0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x15, 0x48, 0x61, 0x76,
+	0x65, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65,
+	0x65, 0x64, 0x12, 0x2c, 0x0a, 0x11, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41,
+	0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x5f, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x49,
+	0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x41, 0x69, 0x72, 0x53, 0x70, 0x65, 0x65, 0x64,
+	0x12, 0x1a, 0x0a, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4d, 0x61, 0x63, 0x68, 0x18, 0x60, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4d, 0x61, 0x63, 0x68, 0x12, 0x12, 0x0a, 0x04,
+	0x4d, 0x61, 0x63, 0x68, 0x18, 0x61, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x4d, 0x61, 0x63, 0x68,
+	0x12, 0x1a, 0x0a, 0x08, 0x4e, 0x61, 0x76, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x63, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x08, 0x4e, 0x61, 0x76, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x22, 0x0a, 0x0c,
+	0x48, 0x61, 0x76, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x64, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0c, 0x48, 0x61, 0x76, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79,
+	0x12, 0x1a, 0x0a, 0x08, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x65, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08,
+	0x48, 0x61, 0x76, 0x65, 0x52, 0x6f, 0x6c, 0x6c, 0x18, 0x69, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
+	0x48, 0x61, 0x76, 0x65, 0x52, 0x6f, 0x6c, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x6c,
+	0x18, 0x6a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x52, 0x6f, 0x6c, 0x6c, 0x12, 0x20, 0x0a, 0x0b,
+	0x41, 0x44, 0x53, 0x42, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x6b, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0b, 0x41, 0x44, 0x53, 0x42, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a,
+	0x0a, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x50, 0x18, 0x6c, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x50, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x41,
+	0x43, 0x50, 0x18, 0x6d, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x4e, 0x41, 0x43, 0x50, 0x12, 0x1a,
+	0x0a, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x56, 0x18, 0x6e, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x08, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x41, 0x43, 0x56, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x41,
+	0x43, 0x56, 0x18, 0x6f, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x4e, 0x41, 0x43, 0x56, 0x12, 0x20,
+	0x0a, 0x0b, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f, 0x18, 0x70, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0b, 0x48, 0x61, 0x76, 0x65, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f,
+	0x12, 0x18, 0x0a, 0x07, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f, 0x18, 0x71, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x4e, 0x49, 0x43, 0x42, 0x61, 0x72, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x48, 0x61,
+	0x76, 0x65, 0x53, 0x49, 0x4c, 0x18, 0x72, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x48, 0x61, 0x76,
+	0x65, 0x53, 0x49, 0x4c, 0x12, 0x10, 0x0a, 0x03, 0x53, 0x49, 0x4c, 0x18, 0x73, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x03, 0x53, 0x49, 0x4c, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x49, 0x4c, 0x54, 0x79, 0x70,
+	0x65, 0x18, 0x74, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x53, 0x49, 0x4c, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x24, 0x0a, 0x0d, 0x48, 0x61, 0x76, 0x65, 0x45, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63,
+	0x79, 0x18, 0x75, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x48, 0x61, 0x76, 0x65, 0x45, 0x6d, 0x65,
+	0x72, 0x67, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x45, 0x6d, 0x65, 0x72, 0x67, 0x65,
+	0x6e, 0x63, 0x79, 0x18, 0x76, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x45, 0x6d, 0x65, 0x72, 0x67,
+	0x65, 0x6e, 0x63, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x5a, 0x6f, 0x6e, 0x65, 0x73, 0x18, 0x77, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x05, 0x5a, 0x6f, 0x6e, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x50, 0x68,
+	0x61, 0x73, 0x65, 0x18, 0x78, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x50, 0x68, 0x61, 0x73, 0x65,
+	0x12, 0x1e, 0x0a, 0x0a, 0x49, 0x73, 0x4f, 0x72, 0x62, 0x69, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x79,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x49, 0x73, 0x4f, 0x72, 0x62, 0x69, 0x74, 0x69, 0x6e, 0x67,
+	0x12, 0x1c, 0x0a, 0x09, 0x57, 0x61, 0x74, 0x63, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x7a, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x57, 0x61, 0x74, 0x63, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x44,
+	0x0a, 0x12, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x18, 0x7b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x61, 0x69, 0x72,
+	0x74, 0x72, 0x61, 0x63, 0x6b, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65,
+	0x52, 0x12, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x22, 0xd3, 0x01, 0x0a, 0x08, 0x53, 0x69, 0x67, 0x68, 0x74, 0x69, 0x6e,
+	0x67, 0x12, 0x38, 0x0a, 0x09, 0x46, 0x69, 0x72, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x46, 0x69, 0x72, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x12, 0x35, 0x0a, 0x08, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x48, 0x61, 0x76, 0x65, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x48, 0x61, 0x76, 0x65, 0x4f, 0x72, 0x69, 0x67,
+	0x69, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x4f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x2a, 0x4c, 0x0a, 0x0a, 0x50, 0x72,
+	0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x6e, 0x6b, 0x6e,
+	0x6f, 0x77, 0x6e, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x41, 0x44, 0x53, 0x42, 0x10, 0x01, 0x12,
+	0x08, 0x0a, 0x04, 0x4d, 0x4c, 0x41, 0x54, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x49, 0x53,
+	0x42, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x41, 0x44, 0x53, 0x52, 0x10, 0x04, 0x12, 0x09, 0x0a,
+	0x05, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x10, 0x05, 0x42, 0x22, 0x5a, 0x20, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x66, 0x6b, 0x31, 0x31, 0x2f, 0x61, 0x69, 0x72,
+	0x74, 0x72, 0x61, 0x63, 0x6b, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1593,29 +1931,37 @@ func file_message_proto_rawDescGZIP() []byte {
 	return file_message_proto_rawDescData
 }
 
-var file_message_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_message_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_message_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_message_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_message_proto_goTypes = []interface{}{
-	(Source_SourceType)(0), // 0: airtrack.Source.SourceType
-	(*Source)(nil),         // 1: airtrack.Source
-	(*Signal)(nil),         // 2: airtrack.Signal
-	(*AircraftInfo)(nil),   // 3: airtrack.AircraftInfo
-	(*Operator)(nil),       // 4: airtrack.Operator
-	(*Message)(nil),        // 5: airtrack.Message
-	(*State)(nil),          // 6: airtrack.State
+	(Provenance)(0),               // 0: airtrack.Provenance
+	(Source_SourceType)(0),        // 1: airtrack.Source.SourceType
+	(*Source)(nil),                // 2: airtrack.Source
+	(*Signal)(nil),                // 3: airtrack.Signal
+	(*AircraftInfo)(nil),          // 4: airtrack.AircraftInfo
+	(*Operator)(nil),              // 5: airtrack.Operator
+	(*Message)(nil),               // 6: airtrack.Message
+	(*State)(nil),                 // 7: airtrack.State
+	(*Sighting)(nil),              // 8: airtrack.Sighting
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 10: google.protobuf.Duration
 }
 var file_message_proto_depIdxs = []int32{
-	0, // 0: airtrack.Source.Type:type_name -> airtrack.Source.SourceType
-	1, // 1: airtrack.Message.Source:type_name -> airtrack.Source
-	2, // 2: airtrack.Message.Signal:type_name -> airtrack.Signal
-	3, // 3: airtrack.State.Info:type_name -> airtrack.AircraftInfo
-	4, // 4: airtrack.State.Operator:type_name -> airtrack.Operator
-	2, // 5: airtrack.State.LastSignal:type_name -> airtrack.Signal
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	1,  // 0: airtrack.Source.Type:type_name -> airtrack.Source.SourceType
+	2,  // 1: airtrack.Message.Source:type_name -> airtrack.Source
+	3,  // 2: airtrack.Message.Signal:type_name -> airtrack.Signal
+	0,  // 3: airtrack.Message.Provenance:type_name -> airtrack.Provenance
+	4,  // 4: airtrack.State.Info:type_name -> airtrack.AircraftInfo
+	5,  // 5: airtrack.State.Operator:type_name -> airtrack.Operator
+	3,  // 6: airtrack.State.LastSignal:type_name -> airtrack.Signal
+	0,  // 7: airtrack.State.LocationProvenance:type_name -> airtrack.Provenance
+	9,  // 8: airtrack.Sighting.FirstSeen:type_name -> google.protobuf.Timestamp
+	10, // 9: airtrack.Sighting.Duration:type_name -> google.protobuf.Duration
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_message_proto_init() }
@@ -1696,14 +2042,26 @@ func file_message_proto_init() {
 				return nil
 			}
 		}
+		file_message_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Sighting); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_message_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   6,
+			NumEnums:      2,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,72 @@
+package readsb
+
+import (
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	// AvrPrefix - prefix for AVR frames without a timestamp
+	AvrPrefix = '*'
+	// AvrMlatPrefix - prefix for AVR frames beginning with a
+	// 48 bit MLAT timestamp
+	AvrMlatPrefix = '@'
+	// AvrSuffix - terminates an AVR frame
+	AvrSuffix = ';'
+
+	// avrTimestampHexLen - length of the hex encoded MLAT timestamp
+	avrTimestampHexLen = 12
+)
+
+// AvrToBeast converts an AVR text frame (eg, `*8D4840D6202CC371C32CE0576098;`
+// or `@016CE3671C748D4840D6202CC371C32CE0576098;`) into a BEAST binary frame
+// so it can be decoded by ParseMessage. AVR frames have no signal level,
+// so it is always zero.
+func AvrToBeast(frame string) ([]byte, error) {
+	frame = strings.TrimSpace(frame)
+	if len(frame) < 2 || frame[len(frame)-1] != AvrSuffix {
+		return nil, errors.Errorf("invalid AVR frame: %s", frame)
+	}
+	var timestamp uint64
+	payload := frame[1 : len(frame)-1]
+	switch frame[0] {
+	case AvrPrefix:
+	case AvrMlatPrefix:
+		if len(payload) < avrTimestampHexLen {
+			return nil, errors.Errorf("AVR frame too short for timestamp: %s", frame)
+		}
+		ts, err := strconv.ParseUint(payload[:avrTimestampHexLen], 16, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding AVR timestamp")
+		}
+		timestamp = ts
+		payload = payload[avrTimestampHexLen:]
+	default:
+		return nil, errors.Errorf("unsupported AVR frame type: %c", frame[0])
+	}
+
+	msg, err := hex.DecodeString(payload)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding AVR frame")
+	}
+	b, err := EncodeBeastFrame(msg, timestamp, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unexpected AVR message")
+	}
+	return b, nil
+}
+
+// ParseAvrMessage converts an AVR frame to BEAST and decodes it
+// with ParseMessage.
+func ParseAvrMessage(d *Decoder, frame string) ([]*ModesMessage, error) {
+	b, err := AvrToBeast(frame)
+	if err != nil {
+		return nil, err
+	}
+	// ParseMessage only decodes a frame once the start of the
+	// next frame is in the buffer, so add the escape byte
+	msgs, _, err := ParseMessage(d, append(b, beastEscape))
+	return msgs, err
+}
@@ -0,0 +1,43 @@
+package readsb
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAvrToBeast(t *testing.T) {
+	t.Run("no timestamp", func(t *testing.T) {
+		b, err := AvrToBeast("*8D4840D6202CC371C32CE0576098;\r\n")
+		assert.NoError(t, err)
+		assert.Equal(t, "1a33"+"000000000000"+"00"+"8d4840d6202cc371c32ce0576098", hex.EncodeToString(b))
+	})
+	t.Run("mlat timestamp", func(t *testing.T) {
+		b, err := AvrToBeast("@016CE3671C745D4840D6A2C5F1;")
+		assert.NoError(t, err)
+		assert.Equal(t, "1a32"+"016ce3671c74"+"00"+"5d4840d6a2c5f1", hex.EncodeToString(b))
+	})
+	t.Run("mode ac", func(t *testing.T) {
+		b, err := AvrToBeast("*7700;")
+		assert.NoError(t, err)
+		assert.Equal(t, "1a31"+"000000000000"+"00"+"7700", hex.EncodeToString(b))
+	})
+	t.Run("escapes", func(t *testing.T) {
+		b, err := AvrToBeast("@00000000001A5D1A40D6A2C5F1;")
+		assert.NoError(t, err)
+		assert.Equal(t, "1a32"+"00000000001a1a"+"00"+"5d1a1a40d6a2c5f1", hex.EncodeToString(b))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, frame := range []string{
+			"",
+			"*8D4840D6202CC371C32CE0576098",
+			"#8D4840D6202CC371C32CE0576098;",
+			"*8D4840D6202CC371C32CE05760;",
+			"*8D4840D6202CC371C32CE05760ZZ;",
+			"@016CE3671C;",
+		} {
+			_, err := AvrToBeast(frame)
+			assert.Error(t, err, frame)
+		}
+	})
+}
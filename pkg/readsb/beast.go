@@ -0,0 +1,55 @@
+package readsb
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	// beastEscape - marks the start of a BEAST frame, and is
+	// doubled when it appears in frame data
+	beastEscape = 0x1a
+	// beastMaxTimestamp - BEAST timestamps are 48 bits
+	beastMaxTimestamp = 1<<48 - 1
+)
+
+// EncodeBeastFrame encodes a Mode A/C or Mode S message as a BEAST
+// binary frame. timestamp is the 48 bit 12MHz receiver clock, and
+// signal is the signal level as sent by the receiver (sqrt of the
+// signal level, scaled to 255).
+func EncodeBeastFrame(msg []byte, timestamp uint64, signal byte) ([]byte, error) {
+	var msgType byte
+	switch len(msg) {
+	case ModeACMsgBytes:
+		msgType = ASCIIIntZero + 1
+	case ModeSShortMsgBytes:
+		msgType = ASCIIIntZero + 2
+	case ModeSLongMsgBytes:
+		msgType = ASCIIIntZero + 3
+	default:
+		return nil, errors.Errorf("unexpected message length %d", len(msg))
+	}
+	if timestamp > beastMaxTimestamp {
+		return nil, errors.Errorf("timestamp exceeds 48 bits")
+	}
+
+	// escape, type, timestamp, signal, message. allow for
+	// some escaped bytes
+	b := make([]byte, 0, 2+2*(6+1+len(msg)))
+	b = append(b, beastEscape, msgType)
+	for i := 5; i >= 0; i-- {
+		b = appendBeastByte(b, byte(timestamp>>(8*uint(i))))
+	}
+	b = appendBeastByte(b, signal)
+	for _, c := range msg {
+		b = appendBeastByte(b, c)
+	}
+	return b, nil
+}
+
+// appendBeastByte appends c to b, escaping it if necessary
+func appendBeastByte(b []byte, c byte) []byte {
+	if c == beastEscape {
+		b = append(b, beastEscape)
+	}
+	return append(b, c)
+}
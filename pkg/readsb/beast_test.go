@@ -0,0 +1,28 @@
+package readsb
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEncodeBeastFrame(t *testing.T) {
+	msg, err := hex.DecodeString("8d4840d6202cc371c32ce0576098")
+	assert.NoError(t, err)
+
+	b, err := EncodeBeastFrame(msg, 0x016ce3671c74, 0x8f)
+	assert.NoError(t, err)
+	assert.Equal(t, "1a33"+"016ce3671c74"+"8f"+"8d4840d6202cc371c32ce0576098", hex.EncodeToString(b))
+
+	t.Run("escapes", func(t *testing.T) {
+		b, err := EncodeBeastFrame([]byte{0x1a, 0x00}, 0x1a, 0x1a)
+		assert.NoError(t, err)
+		assert.Equal(t, "1a31"+"00000000001a1a"+"1a1a"+"1a1a00", hex.EncodeToString(b))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := EncodeBeastFrame(msg[:10], 0, 0)
+		assert.Error(t, err)
+		_, err = EncodeBeastFrame(msg, 1<<48, 0)
+		assert.Error(t, err)
+	})
+}
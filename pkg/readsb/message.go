@@ -215,6 +215,13 @@ int modesmessage_is_opstatus_cc_lw_valid(struct modesMessage *mm) {
 int aircraft_get_nac_p(struct aircraft *aircraft) {
     return aircraft->meta.nac_p;
 }
+
+// Sets the receiver location used for receiver-relative CPR decoding
+void modes_set_user_location(struct _Modes *modes, double lat, double lon) {
+    modes->fUserLat = lat;
+    modes->fUserLon = lon;
+    modes->bUserFlags |= MODES_USER_LATLON_VALID;
+}
 */
 import "C"
 
@@ -247,6 +254,7 @@ type (
 	// our aircraft state. This state is required for location decoding.
 	Decoder struct {
 		modes *C.struct__Modes
+		now   func() time.Time
 	}
 
 	// ModesMessage simply wraps a modesMessage pointer so we can pass it around
@@ -698,6 +706,16 @@ func (m *ModesMessage) GetDecodeLocation() (float64, float64, error) {
 	return lat, lon, nil
 }
 
+// GetDataSource returns where the message came from. Messages with the
+// magic MLAT timestamp were synthesized by an MLAT server, and are
+// reported as SourceMLAT.
+func (m *ModesMessage) GetDataSource() DataSource {
+	if C.modesmessage_is_from_mlat(m.msg) == 1 || m.msg.timestampMsg == C.MAGIC_MLAT_TIMESTAMP {
+		return SourceMLAT
+	}
+	return DataSource(m.msg.source)
+}
+
 // IsOnGround will return whether the aircraft is on ground, or ErrNoData if
 // this is unknown or otherwise uncertain.
 func (m *ModesMessage) IsOnGround() (bool, error) {
@@ -741,6 +759,15 @@ func (m *ModesMessage) GetNavHeading() (float64, error) {
 	return float64(m.msg.nav.heading), nil
 }
 
+// GetEmergency returns the ADS-B emergency/priority status, or ErrNoData
+// if the data is not set.
+func (m *ModesMessage) GetEmergency() (string, error) {
+	if C.modesmessage_is_emergency_valid(m.msg) != 1 {
+		return "", ErrNoData
+	}
+	return C.GoString(C.emergency_to_string(m.msg.emergency)), nil
+}
+
 // GetNavQNH returns the altimiter setting (QFE or QNH/QNE)
 func (m *ModesMessage) GetNavQNH() (float64, error) {
 	if C.modesmessage_is_nav_qnh_valid(m.msg) != 1 {
@@ -764,6 +791,17 @@ func (m *ModesMessage) GetMsg() ([]byte, error) {
 	return C.GoBytes(unsafe.Pointer(&m.msg.msg), 14), nil
 }
 
+// GetRawMessage returns the message bytes. Unlike GetMsg, the
+// result is the length of the message type.
+func (m *ModesMessage) GetRawMessage() []byte {
+	return C.GoBytes(unsafe.Pointer(&m.msg.msg), C.int(m.msg.msgbits/8))
+}
+
+// GetTimestamp returns the 12MHz receiver timestamp of the message
+func (m *ModesMessage) GetTimestamp() uint64 {
+	return uint64(m.msg.timestampMsg)
+}
+
 // ParseMessage attempts to decode and process any messages it can find in b.
 func ParseMessage(d *Decoder, b []byte) ([]*ModesMessage, int, error) {
 	var ret []*ModesMessage
@@ -869,7 +907,7 @@ func DecodeBinMessage(decoder *Decoder, m []byte, p int, withModeAC bool) (*C.st
 			}
 		}
 		mm.timestampMsg = C.ulong(t)
-		mm.sysTimestampMsg = C.ulong(time.Now().Unix() * 1000)
+		mm.sysTimestampMsg = C.ulong(decoder.now().Unix() * 1000)
 
 		// grab the signal level
 		ch = m[p]
@@ -918,9 +956,24 @@ func DecodeBinMessage(decoder *Decoder, m []byte, p int, withModeAC bool) (*C.st
 func NewDecoder() *Decoder {
 	return &Decoder{
 		modes: &C.struct__Modes{},
+		now:   time.Now,
 	}
 }
 
+// SetClock overrides the function used to timestamp received
+// messages. This is used when replaying recorded messages, where
+// the time of the recording must be used instead of the current time.
+func (d *Decoder) SetClock(now func() time.Time) {
+	d.now = now
+}
+
+// SetReceiverLocation sets the location of the receiver, so positions
+// can be decoded relative to it from a single message, including surface
+// positions which can't be decoded without a reference location.
+func (d *Decoder) SetReceiverLocation(lat, lon float64) {
+	C.modes_set_user_location(d.modes, C.double(lat), C.double(lon))
+}
+
 // NumBitsToCorrect sets the number of bits we should
 // correct based on the CRC
 func (d *Decoder) NumBitsToCorrect(nbits int) {
@@ -3,6 +3,7 @@ package readsb
 import (
 	"encoding/hex"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -28,3 +29,21 @@ func TestParseExtendedSquitterTargetStateAndStatus(t *testing.T) {
 	assert.False(t, isOnGround)
 	assert.Equal(t, 17, modes.GetMessageType())
 }
+
+func TestParseAvrMessage(t *testing.T) {
+	IcaoFilterInitOnce()
+	ModeACInitOnce()
+	ModesChecksumInitOnce(1)
+
+	decoder := NewDecoder()
+	decoder.NumBitsToCorrect(1)
+
+	msgs, err := ParseAvrMessage(decoder, "*8D4840D6202CC371C32CE0576098;")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(msgs))
+	assert.Equal(t, "4840D6", msgs[0].GetIcaoHex())
+	assert.Equal(t, 17, msgs[0].GetMessageType())
+	callsign, err := msgs[0].GetCallsign()
+	assert.NoError(t, err)
+	assert.Equal(t, "KLM1023", strings.TrimSpace(callsign))
+}
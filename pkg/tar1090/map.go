@@ -2,6 +2,7 @@ package tar1090
 
 import (
 	"encoding/json"
+	"github.com/afk11/airtrack/pkg/coverage"
 	"github.com/afk11/airtrack/pkg/readsb/aircraftdb"
 	"github.com/afk11/airtrack/pkg/tracker"
 	"github.com/gorilla/mux"
@@ -115,6 +116,28 @@ type jsonAircraft struct {
 	Aircraft []*tracker.JSONAircraft `json:"aircraft"`
 }
 
+// jsonOutline defines the JSON structure returned for outline.json. Each
+// point is [latitude, longitude, altitude]. The points are repeated under
+// actualRange, where newer versions of tar1090 expect them.
+type jsonOutline struct {
+	Points      [][3]float64 `json:"points"`
+	ActualRange struct {
+		Last24h struct {
+			Points [][3]float64 `json:"points"`
+		} `json:"last24h"`
+	} `json:"actualRange"`
+}
+
+// jsonReceiver defines the JSON structure returned for receiver.json.
+// The location is only set if the receiver's location is known.
+type jsonReceiver struct {
+	Version   string   `json:"version"`
+	Refresh   int      `json:"refresh"`
+	History   int      `json:"history"`
+	Latitude  *float64 `json:"lat,omitempty"`
+	Longitude *float64 `json:"lon,omitempty"`
+}
+
 // assetResponseHandler provides a HTTP handler to serve a static asset
 type assetResponseHandler struct {
 	name   string
@@ -155,6 +178,15 @@ func NewTar1090Map(ma tracker.MapAccess, maxHistory int) *Map {
 type Map struct {
 	m tracker.MapAccess
 	h *History
+	c *coverage.Coverage
+}
+
+// SetCoverage causes the map to show the location and range outline
+// of a receiver in c. The first receiver is used, unless the receiver
+// parameter is passed to outline.json. It must be called before
+// RegisterRoutes.
+func (t *Map) SetCoverage(c *coverage.Coverage) {
+	t.c = c
 }
 
 // MapService returns the name of the map service. See MapService.MapService.
@@ -198,6 +230,9 @@ func (t *Map) RegisterRoutes(r *mux.Router) error {
 	r.HandleFunc("/{project}/data/aircraft.json", t.AircraftJSONHandler)
 	r.HandleFunc("/{project}/data/history_{file}.json", t.HistoryJSONHandler)
 	r.HandleFunc("/{project}/data/receiver.json", t.ReceiverJSONHandler)
+	if t.c != nil {
+		r.HandleFunc("/{project}/data/outline.json", t.OutlineJSONHandler)
+	}
 	r.HandleFunc("/{project}/db2/icao_aircraft_types.js", assetResponseHandler{"types.json", aircraftdb.Asset}.responseHandler)
 	r.HandleFunc("/{project}/db2/files.js", assetResponseHandler{"files.json", aircraftdb.Asset}.responseHandler)
 
@@ -235,7 +270,20 @@ func (t *Map) ReceiverJSONHandler(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(404)
 		return
 	}
-	data := []byte("{ \"version\" : \"v3.8.3\", \"refresh\" : 1000, \"history\" : " + strconv.Itoa(count) + " }")
+	receiver := jsonReceiver{
+		Version: "v3.8.3",
+		Refresh: 1000,
+		History: count,
+	}
+	if outline, ok := t.outline(""); ok {
+		receiver.Latitude = &outline.Latitude
+		receiver.Longitude = &outline.Longitude
+	}
+	data, err := json.Marshal(receiver)
+	if err != nil {
+		w.WriteHeader(500)
+		panic(err)
+	}
 	_, err = w.Write(data)
 	if err != nil {
 		w.WriteHeader(500)
@@ -243,6 +291,50 @@ func (t *Map) ReceiverJSONHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// outline returns the coverage of the named receiver,
+// or the first receiver if name is empty.
+func (t *Map) outline(name string) (*coverage.Outline, bool) {
+	if t.c == nil {
+		return nil, false
+	}
+	if name == "" {
+		receivers := t.c.Receivers()
+		if len(receivers) == 0 {
+			return nil, false
+		}
+		name = receivers[0]
+	}
+	return t.c.Outline(name)
+}
+
+// OutlineJSONHandler implements the HTTP handler for outline.json
+func (t *Map) OutlineJSONHandler(w http.ResponseWriter, r *http.Request) {
+	outline, ok := t.outline(r.URL.Query().Get("receiver"))
+	if !ok {
+		w.WriteHeader(404)
+		return
+	}
+	res := jsonOutline{Points: make([][3]float64, 0, len(outline.Points))}
+	for _, p := range outline.Points {
+		var alt float64
+		if p.Altitude != nil {
+			alt = float64(*p.Altitude)
+		}
+		res.Points = append(res.Points, [3]float64{p.Latitude, p.Longitude, alt})
+	}
+	res.ActualRange.Last24h.Points = res.Points
+	data, err := json.Marshal(res)
+	if err != nil {
+		w.WriteHeader(500)
+		panic(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(data)
+	if err != nil {
+		log.Infof("error writing response: %s", err.Error())
+	}
+}
+
 // AircraftJSONHandler implements the HTTP handler for aircraft.json
 func (t *Map) AircraftJSONHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
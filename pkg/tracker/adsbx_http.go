@@ -42,3 +42,15 @@ type AdsbxAircraft struct {
 	Opicao       string `json:"opicao"`
 	Country      string `json:"cou"`
 }
+
+// AdsbxV2Response - structure of a response from the v2 API.
+// Aircraft use the same format as readsb's aircraft.json.
+//easyjson:json
+type AdsbxV2Response struct {
+	Aircraft []AircraftJSONAircraft `json:"ac"`
+	Msg      string                 `json:"msg"`
+	Now      int64                  `json:"now"`
+	Total    int64                  `json:"total"`
+	CTime    int64                  `json:"ctime"`
+	PTime    int64                  `json:"ptime"`
+}
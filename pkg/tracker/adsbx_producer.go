@@ -10,7 +10,9 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -18,10 +20,79 @@ import (
 const (
 	// DefaultAdsbxEndpoint - the default URL to use
 	DefaultAdsbxEndpoint = "https://adsbexchange.com/api/aircraft/json/"
+	// DefaultAdsbxV2Endpoint - the default base URL for the v2 API.
+	// The path of the query is appended to it.
+	DefaultAdsbxV2Endpoint = "https://adsbexchange.com/api/aircraft/v2/"
 	//curl -H 'api-auth: abcd' https://adsbexchange.com/api/aircraft/v2/all | jq -r '.ac[] | select(.hex == "4ca13f")'
 	//curl -H 'api-auth: abcd' https://adsbexchange.com/api/aircraft/json   | jq -r '.ac[] | select(.icao == "4CA13F")'
+
+	// DefaultAdsbxInterval - the default time between requests
+	DefaultAdsbxInterval = time.Second * 2
+	// AdsbxMaxDistance - the largest radius, in nautical miles,
+	// accepted by the v2 API
+	AdsbxMaxDistance = 250
+
+	// AdsbxQueryAll - v2 query for every aircraft
+	AdsbxQueryAll = "all"
+	// AdsbxQueryRadius - v2 query for aircraft within a distance of a point
+	AdsbxQueryRadius = "radius"
+	// AdsbxQueryHex - v2 query for an aircraft by ICAO hex
+	AdsbxQueryHex = "hex"
+	// AdsbxQueryCallsign - v2 query for aircraft by callsign
+	AdsbxQueryCallsign = "callsign"
+	// AdsbxQueryRegistration - v2 query for an aircraft by registration
+	AdsbxQueryRegistration = "registration"
 )
 
+// AdsbxQuery selects the aircraft requested from the v2 API.
+type AdsbxQuery struct {
+	// Mode - one of the AdsbxQuery constants
+	Mode string
+	// Latitude - centre of a radius query
+	Latitude float64
+	// Longitude - centre of a radius query
+	Longitude float64
+	// Distance - radius of a radius query, in nautical miles
+	Distance int64
+	// Value - the hex, callsign or registration to request
+	Value string
+}
+
+// Path returns the path for the query, relative to the v2 endpoint.
+// An error is returned if the query is invalid.
+func (q *AdsbxQuery) Path() (string, error) {
+	switch q.Mode {
+	case AdsbxQueryAll:
+		return "all", nil
+	case AdsbxQueryRadius:
+		if q.Latitude < -90 || q.Latitude > 90 {
+			return "", errors.Errorf("invalid latitude %f", q.Latitude)
+		} else if q.Longitude < -180 || q.Longitude > 180 {
+			return "", errors.Errorf("invalid longitude %f", q.Longitude)
+		} else if q.Distance < 1 || q.Distance > AdsbxMaxDistance {
+			return "", errors.Errorf("distance must be between 1 and %d nautical miles", AdsbxMaxDistance)
+		}
+		return fmt.Sprintf("lat/%s/lon/%s/dist/%d/",
+			strconv.FormatFloat(q.Latitude, 'f', -1, 64),
+			strconv.FormatFloat(q.Longitude, 'f', -1, 64),
+			q.Distance), nil
+	case AdsbxQueryHex, AdsbxQueryCallsign, AdsbxQueryRegistration:
+		if q.Value == "" {
+			return "", errors.Errorf("%s query is missing a value", q.Mode)
+		}
+		value := q.Value
+		if q.Mode == AdsbxQueryHex {
+			if _, err := strconv.ParseUint(value, 16, 32); err != nil || len(value) != 6 {
+				return "", errors.Errorf("invalid hex '%s'", value)
+			}
+			value = strings.ToLower(value)
+		}
+		return q.Mode + "/" + url.PathEscape(value) + "/", nil
+	default:
+		return "", errors.Errorf("unknown query mode '%s'", q.Mode)
+	}
+}
+
 // jsonDecodeError is a custom error type used to indicate
 // that json parsing failed. It contains the decoding error,
 // and the response data which lead to the error.
@@ -40,13 +111,49 @@ func (e *jsonDecodeError) Error() string {
 	return e.err.Error()
 }
 
+// rateLimitError is returned when ADSB Exchange responds with
+// 429 Too Many Requests. retryAfter is the delay requested by
+// the Retry-After header, or zero if it was not provided.
+type rateLimitError struct {
+	retryAfter time.Duration
+}
+
+// Return the error message.
+func (e *rateLimitError) Error() string {
+	return "rate limited by adsbx"
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which
+// is either a number of seconds or a HTTP date. Zero is returned
+// if the value is missing or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	t, err := http.ParseTime(value)
+	if err != nil || t.Before(now) {
+		return 0
+	}
+	return t.Sub(now)
+}
+
 // AdsbxProducer - See Producer.
 // This type is responsible for polling the ADSB Exchange API using
 // the provided url and apikey. The JSON result is parsed and into messages
-// which are written to the messages channel.
+// which are written to the messages channel. If a query is set, the v2
+// API is used instead of the legacy endpoint.
 type AdsbxProducer struct {
 	url                 string
 	apikey              string
+	query               *AdsbxQuery
+	interval            time.Duration
+	lastSuccess         time.Time
 	panicIfStuck        bool
 	messages            chan *pb.Message
 	wg                  sync.WaitGroup
@@ -62,7 +169,27 @@ func NewAdsbxProducer(msgs chan *pb.Message, url string, apikey string) *AdsbxPr
 		jsonPayloadDumpFile: "/tmp/airtrack-adsbx-json-payload",
 		url:                 url,
 		apikey:              apikey,
+		interval:            DefaultAdsbxInterval,
+	}
+}
+
+// NewAdsbxV2Producer returns an AdsbxProducer which requests the
+// aircraft selected by query from the v2 API. endpoint is the base
+// URL of the v2 API, see DefaultAdsbxV2Endpoint.
+func NewAdsbxV2Producer(msgs chan *pb.Message, endpoint string, apikey string, query *AdsbxQuery) (*AdsbxProducer, error) {
+	path, err := query.Path()
+	if err != nil {
+		return nil, err
 	}
+	p := NewAdsbxProducer(msgs, strings.TrimSuffix(endpoint, "/")+"/"+path, apikey)
+	p.query = query
+	return p, nil
+}
+
+// SetInterval sets the minimum time between requests. Must
+// be called before Start.
+func (p *AdsbxProducer) SetInterval(interval time.Duration) {
+	p.interval = interval
 }
 
 // PanicIfStuck sets whether the producer should panic
@@ -101,15 +228,17 @@ func (p *AdsbxProducer) GetAdsbx(ctx context.Context, client *http.Client, msgs
 		return err
 	}
 	req.Header.Add("api-auth", p.apikey)
-	req.WithContext(ctx)
 
-	resp, err := client.Do(req)
+	resp, err := client.Do(req.WithContext(ctx))
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		adsbxRateLimited.Inc()
+		return &rateLimitError{parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())}
+	} else if resp.StatusCode != http.StatusOK {
 		return errors.Errorf("adsbx request (%d) received not-ok code %d", numReq, resp.StatusCode)
 	}
 
@@ -119,6 +248,19 @@ func (p *AdsbxProducer) GetAdsbx(ctx context.Context, client *http.Client, msgs
 		return err
 	}
 
+	if p.query != nil {
+		// positions older than this were sent by the previous request
+		maxPosAge := (p.interval + time.Second).Seconds()
+		if !p.lastSuccess.IsZero() {
+			maxPosAge = (start.Sub(p.lastSuccess) + time.Second).Seconds()
+		}
+		err = p.processV2(ctx, body, msgs, source, maxPosAge)
+		if err == nil {
+			p.lastSuccess = start
+		}
+		return err
+	}
+
 	err = easyjson.Unmarshal(body, msg)
 	if err != nil {
 		return &jsonDecodeError{err, body}
@@ -127,6 +269,7 @@ func (p *AdsbxProducer) GetAdsbx(ctx context.Context, client *http.Client, msgs
 	for _, ac := range msg.Aircraft {
 		msg := &pb.Message{
 			Source:             source,
+			Provenance:         provenanceFromAdsbx(&ac),
 			Icao:               ac.Icao,
 			Squawk:             ac.Sqk,
 			CallSign:           ac.Call,
@@ -167,13 +310,51 @@ func (p *AdsbxProducer) GetAdsbx(ctx context.Context, client *http.Client, msgs
 	return nil
 }
 
+// processV2 decodes a response from the v2 API and sends a message
+// for each aircraft with an ICAO address. The position is only
+// included if it was updated less than maxPosAge seconds ago.
+func (p *AdsbxProducer) processV2(ctx context.Context, body []byte, msgs chan *pb.Message, source *pb.Source, maxPosAge float64) error {
+	res := &AdsbxV2Response{}
+	err := easyjson.Unmarshal(body, res)
+	if err != nil {
+		return &jsonDecodeError{err, body}
+	}
+	for i := range res.Aircraft {
+		ac := &res.Aircraft[i]
+		// non-ICAO addresses are prefixed with ~
+		if len(ac.Hex) != 6 {
+			continue
+		}
+		msg := aircraftJSONToMessage(ac, maxPosAge)
+		msg.Icao = strings.ToUpper(ac.Hex)
+		msg.Source = source
+		select {
+		case msgs <- msg:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
+
+// rateLimitWait returns how long to wait after the retryCount'th
+// consecutive rate limited request. The backoff is used unless the
+// server asked us to wait longer with the Retry-After header.
+func rateLimitWait(retryCount int, retryAfter time.Duration) time.Duration {
+	wait := time.Duration(10*retryCount) * time.Second
+	if retryAfter > wait {
+		wait = retryAfter
+	}
+	return wait
+}
+
 // producer is a goroutine which periodically calls GetAdsbx to
 // receive messages from ADSB Exchange. It terminates if the
 // stop signal is received from the provided context.
 func (p *AdsbxProducer) producer(ctx context.Context) {
 	defer p.wg.Done()
 
-	normalWait := time.Second * 2
+	normalWait := p.interval
 	wait := normalWait
 	src := &pb.Source{
 		Type: pb.Source_AdsbExchange,
@@ -197,8 +378,16 @@ func (p *AdsbxProducer) producer(ctx context.Context) {
 	for {
 		select {
 		case <-time.After(wait):
+			start := time.Now()
 			err := p.GetAdsbx(ctx, client, p.messages, src)
 			if err != nil {
+				if rlErr, ok := (err).(*rateLimitError); ok {
+					degradedService = true
+					retryCount++
+					wait = rateLimitWait(retryCount, rlErr.retryAfter)
+					log.Warnf("adsbx request (%d) was rate limited, sleeping %s", p.numReqs, wait)
+					continue
+				}
 				jsonErr, ok := (err).(*jsonDecodeError)
 				if ok {
 					if p.jsonPayloadDumpFile != "" {
@@ -224,7 +413,11 @@ func (p *AdsbxProducer) producer(ctx context.Context) {
 				log.Warnf("adsbexchange - normal service restored after %d retries", retryCount)
 				degradedService = false
 				retryCount = 0
-				wait = normalWait
+			}
+			// the interval is measured from the start of the request
+			wait = normalWait - time.Since(start)
+			if wait < 0 {
+				wait = 0
 			}
 		case <-ctx.Done():
 			return
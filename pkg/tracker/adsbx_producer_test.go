@@ -0,0 +1,132 @@
+package tracker
+
+import (
+	"context"
+	"github.com/afk11/airtrack/pkg/pb"
+	assert "github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const adsbxV2TestResponse = `{"ac":[
+  {"hex":"4ca2d6","type":"adsb_icao","flight":"RYR4GW  ","r":"EI-DWF","t":"B738","alt_baro":37000,"alt_geom":37525,"gs":452.1,"track":273.5,"baro_rate":-64,"squawk":"2312","category":"A3","lat":53.123456,"lon":-6.654321,"seen_pos":0.3,"mlat":[],"tisb":[],"messages":120,"seen":0.1,"rssi":-20.1},
+  {"hex":"406b8a","type":"mlat","alt_baro":"ground","lat":53.421,"lon":-6.27,"seen_pos":30.5,"mlat":["lat","lon"],"tisb":[],"messages":10,"seen":25.2},
+  {"hex":"~2d1e4f","type":"tisb_other","alt_baro":1200,"messages":5,"seen":1.0}
+],"msg":"No error","now":1603000000123,"total":3,"ctime":1603000000120,"ptime":12}`
+
+func TestAdsbxQuery_Path(t *testing.T) {
+	for _, tc := range []struct {
+		query AdsbxQuery
+		path  string
+	}{
+		{AdsbxQuery{Mode: AdsbxQueryAll}, "all"},
+		{AdsbxQuery{Mode: AdsbxQueryRadius, Latitude: 53.35, Longitude: -6.26, Distance: 50}, "lat/53.35/lon/-6.26/dist/50/"},
+		{AdsbxQuery{Mode: AdsbxQueryHex, Value: "4CA13F"}, "hex/4ca13f/"},
+		{AdsbxQuery{Mode: AdsbxQueryCallsign, Value: "RYR4GW"}, "callsign/RYR4GW/"},
+		{AdsbxQuery{Mode: AdsbxQueryRegistration, Value: "EI-DWF"}, "registration/EI-DWF/"},
+	} {
+		path, err := tc.query.Path()
+		assert.NoError(t, err)
+		assert.Equal(t, tc.path, path)
+	}
+
+	for _, q := range []AdsbxQuery{
+		{},
+		{Mode: "box"},
+		{Mode: AdsbxQueryRadius, Latitude: 91, Distance: 50},
+		{Mode: AdsbxQueryRadius, Longitude: -181, Distance: 50},
+		{Mode: AdsbxQueryRadius, Latitude: 53.35, Longitude: -6.26},
+		{Mode: AdsbxQueryRadius, Latitude: 53.35, Longitude: -6.26, Distance: AdsbxMaxDistance + 1},
+		{Mode: AdsbxQueryHex},
+		{Mode: AdsbxQueryHex, Value: "4ca13"},
+		{Mode: AdsbxQueryHex, Value: "zzzzzz"},
+		{Mode: AdsbxQueryCallsign},
+	} {
+		_, err := q.Path()
+		assert.Error(t, err, q.Mode)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 10, 18, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-5", now))
+	assert.Equal(t, time.Second*30, parseRetryAfter("30", now))
+	assert.Equal(t, time.Minute, parseRetryAfter("Sun, 18 Oct 2020 12:01:00 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("Sun, 18 Oct 2020 11:59:00 GMT", now))
+}
+
+func TestRateLimitWait(t *testing.T) {
+	assert.Equal(t, time.Second*10, rateLimitWait(1, 0))
+	assert.Equal(t, time.Second*30, rateLimitWait(1, time.Second*30))
+	// the backoff is used if it's longer than Retry-After
+	assert.Equal(t, time.Second*40, rateLimitWait(4, time.Second*30))
+}
+
+func TestAdsbxProducer_V2(t *testing.T) {
+	var requests []*http.Request
+	limited := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if limited {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(adsbxV2TestResponse))
+	}))
+	defer srv.Close()
+
+	_, err := NewAdsbxV2Producer(make(chan *pb.Message), srv.URL, "abcd", &AdsbxQuery{Mode: AdsbxQueryRadius})
+	assert.Error(t, err)
+
+	msgs := make(chan *pb.Message, 10)
+	query := &AdsbxQuery{Mode: AdsbxQueryRadius, Latitude: 53.35, Longitude: -6.26, Distance: 50}
+	p, err := NewAdsbxV2Producer(msgs, srv.URL+"/api/aircraft/v2/", "abcd", query)
+	assert.NoError(t, err)
+	source := &pb.Source{Type: pb.Source_AdsbExchange, Name: "adsbx"}
+	assert.NoError(t, p.GetAdsbx(context.Background(), srv.Client(), msgs, source))
+	close(msgs)
+
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, "/api/aircraft/v2/lat/53.35/lon/-6.26/dist/50/", requests[0].URL.Path)
+	assert.Equal(t, "abcd", requests[0].Header.Get("api-auth"))
+
+	var received []*pb.Message
+	for msg := range msgs {
+		received = append(received, msg)
+	}
+	// non-ICAO address is skipped
+	assert.Equal(t, 2, len(received))
+
+	msg := received[0]
+	assert.Equal(t, "4CA2D6", msg.Icao)
+	assert.Equal(t, source, msg.Source)
+	assert.Equal(t, pb.Provenance_ADSB, msg.Provenance)
+	assert.Equal(t, "RYR4GW", msg.CallSign)
+	assert.Equal(t, "2312", msg.Squawk)
+	assert.Equal(t, "37000", msg.AltitudeBarometric)
+	assert.Equal(t, "53.12345600", msg.Latitude)
+	assert.Equal(t, "-6.65432100", msg.Longitude)
+	assert.Equal(t, "452.1", msg.GroundSpeed)
+	assert.Equal(t, -20.1, msg.Signal.Rssi)
+
+	msg = received[1]
+	assert.Equal(t, "406B8A", msg.Icao)
+	assert.Equal(t, pb.Provenance_MLAT, msg.Provenance)
+	assert.True(t, msg.IsOnGround)
+	// position is older than the interval
+	assert.Equal(t, "", msg.Latitude)
+
+	t.Run("rate limited", func(t *testing.T) {
+		limited = true
+		err := p.GetAdsbx(context.Background(), srv.Client(), make(chan *pb.Message), source)
+		assert.Error(t, err)
+		rlErr, ok := err.(*rateLimitError)
+		assert.True(t, ok)
+		assert.Equal(t, time.Second*30, rlErr.retryAfter)
+	})
+}
@@ -0,0 +1,53 @@
+package tracker
+
+// AircraftJSONResponse - structure of the aircraft.json file
+// written by readsb, dump1090-fa and tar1090
+//easyjson:json
+type AircraftJSONResponse struct {
+	Now      float64                `json:"now"`
+	Messages int64                  `json:"messages"`
+	Aircraft []AircraftJSONAircraft `json:"aircraft"`
+}
+
+// AircraftJSONAircraft - structure of an aircraft object in
+// aircraft.json. Optional fields are pointers so a missing
+// value can be distinguished from zero.
+//easyjson:json
+type AircraftJSONAircraft struct {
+	Hex            string      `json:"hex"`
+	Type           string      `json:"type"`
+	Flight         string      `json:"flight"`
+	AltBaro        interface{} `json:"alt_baro"`
+	AltGeom        *int64      `json:"alt_geom"`
+	GroundSpeed    *float64    `json:"gs"`
+	IAS            *uint64     `json:"ias"`
+	TAS            *uint64     `json:"tas"`
+	Mach           *float64    `json:"mach"`
+	Track          *float64    `json:"track"`
+	Roll           *float64    `json:"roll"`
+	MagHeading     *float64    `json:"mag_heading"`
+	TrueHeading    *float64    `json:"true_heading"`
+	BaroRate       *int64      `json:"baro_rate"`
+	GeomRate       *int64      `json:"geom_rate"`
+	Squawk         string      `json:"squawk"`
+	Emergency      string      `json:"emergency"`
+	Category       string      `json:"category"`
+	NavQNH         *float64    `json:"nav_qnh"`
+	NavAltitudeFMS *int64      `json:"nav_altitude_fms"`
+	NavHeading     *float64    `json:"nav_heading"`
+	NavModes       []string    `json:"nav_modes"`
+	Lat            *float64    `json:"lat"`
+	Lon            *float64    `json:"lon"`
+	SeenPos        *float64    `json:"seen_pos"`
+	Version        *int64      `json:"version"`
+	NicBaro        *uint32     `json:"nic_baro"`
+	NacP           *uint32     `json:"nac_p"`
+	NacV           *uint32     `json:"nac_v"`
+	Sil            *uint32     `json:"sil"`
+	SilType        string      `json:"sil_type"`
+	Mlat           []string    `json:"mlat"`
+	Tisb           []string    `json:"tisb"`
+	Messages       int64       `json:"messages"`
+	Seen           float64     `json:"seen"`
+	Rssi           *float64    `json:"rssi"`
+}
@@ -0,0 +1,273 @@
+package tracker
+
+import (
+	"context"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/afk11/airtrack/pkg/readsb"
+	"github.com/mailru/easyjson"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAircraftJSONInterval - the default time between polls.
+	// readsb and dump1090 write aircraft.json every second.
+	DefaultAircraftJSONInterval = time.Second
+)
+
+// AircraftJSONProducer - implements Producer.
+// This type polls an aircraft.json file written by a local readsb,
+// dump1090-fa or tar1090 instance. The location can be a file path
+// or a http(s) URL. Only aircraft which received messages since the
+// previous poll are written to the messages channel.
+type AircraftJSONProducer struct {
+	name         string
+	location     string
+	interval     time.Duration
+	client       *http.Client
+	messages     chan *pb.Message
+	lastMessages map[string]int64
+	wg           sync.WaitGroup
+	canceller    func()
+}
+
+// NewAircraftJSONProducer initializes a new AircraftJSONProducer.
+func NewAircraftJSONProducer(msgs chan *pb.Message, location string, interval time.Duration, name string) *AircraftJSONProducer {
+	return &AircraftJSONProducer{
+		messages:     msgs,
+		location:     location,
+		interval:     interval,
+		name:         name,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		lastMessages: make(map[string]int64),
+	}
+}
+
+// Name - see Producer.Name()
+func (p *AircraftJSONProducer) Name() string {
+	return p.name
+}
+
+// Start - see Producer.Start()
+// This function starts the producer goroutine.
+func (p *AircraftJSONProducer) Start() {
+	p.wg.Add(1)
+	ctx, canceller := context.WithCancel(context.Background())
+	p.canceller = canceller
+	go p.producer(ctx)
+}
+
+// producer is a goroutine which calls poll every interval
+// until the stop signal is received from the provided context.
+func (p *AircraftJSONProducer) producer(ctx context.Context) {
+	defer p.wg.Done()
+	source := &pb.Source{
+		Type: pb.Source_AircraftJson,
+		Name: p.name,
+	}
+	for {
+		select {
+		case <-time.After(p.interval):
+			err := p.poll(ctx, source)
+			if err != nil {
+				log.Warnf("aircraft.json %s: %s", p.name, err.Error())
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// poll loads aircraft.json and sends a message for each aircraft
+// which has received messages since the previous poll.
+func (p *AircraftJSONProducer) poll(ctx context.Context, source *pb.Source) error {
+	body, err := p.load(ctx)
+	if err != nil {
+		return err
+	}
+	res := &AircraftJSONResponse{}
+	err = easyjson.Unmarshal(body, res)
+	if err != nil {
+		return errors.Wrapf(err, "decoding %s", p.location)
+	}
+
+	// positions older than this were sent by a previous poll
+	maxPosAge := (p.interval + time.Second).Seconds()
+	lastMessages := make(map[string]int64, len(res.Aircraft))
+	for i := range res.Aircraft {
+		ac := &res.Aircraft[i]
+		// non-ICAO addresses are prefixed with ~
+		if len(ac.Hex) != 6 {
+			continue
+		}
+		icao := strings.ToUpper(ac.Hex)
+		lastMessages[icao] = ac.Messages
+		if last, ok := p.lastMessages[icao]; ok && last == ac.Messages {
+			continue
+		}
+		msg := aircraftJSONToMessage(ac, maxPosAge)
+		msg.Icao = icao
+		msg.Source = source
+		select {
+		case p.messages <- msg:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	p.lastMessages = lastMessages
+	return nil
+}
+
+// load reads the contents of location. If location is
+// a http(s) URL it is requested, otherwise it's read as a file.
+func (p *AircraftJSONProducer) load(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(p.location, "http://") && !strings.HasPrefix(p.location, "https://") {
+		return ioutil.ReadFile(p.location)
+	}
+	req, err := http.NewRequest("GET", p.location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("received not-ok code %d", resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// Stop sends the cancel signal to the producer goroutine
+// and blocks until it finishes processing
+func (p *AircraftJSONProducer) Stop() {
+	p.canceller()
+	p.wg.Wait()
+}
+
+// aircraftJSONToMessage converts ac into a pb.Message. Icao and Source
+// are left for the caller. The position is only included if it was
+// updated less than maxPosAge seconds ago.
+func aircraftJSONToMessage(ac *AircraftJSONAircraft, maxPosAge float64) *pb.Message {
+	msg := &pb.Message{
+		Provenance: provenanceFromAircraftJSON(ac),
+		CallSign:   strings.TrimSpace(ac.Flight),
+		Squawk:     ac.Squawk,
+	}
+	switch alt := ac.AltBaro.(type) {
+	case float64:
+		msg.AltitudeBarometric = strconv.FormatInt(int64(alt), 10)
+	case string:
+		msg.IsOnGround = alt == "ground"
+	}
+	if ac.AltGeom != nil {
+		msg.AltitudeGeometric = strconv.FormatInt(*ac.AltGeom, 10)
+	}
+	if ac.Lat != nil && ac.Lon != nil && ac.SeenPos != nil && *ac.SeenPos < maxPosAge {
+		msg.Latitude = strconv.FormatFloat(*ac.Lat, 'f', 8, 64)
+		msg.Longitude = strconv.FormatFloat(*ac.Lon, 'f', 8, 64)
+	}
+	if ac.GroundSpeed != nil {
+		msg.GroundSpeed = strconv.FormatFloat(*ac.GroundSpeed, 'f', 1, 64)
+	}
+	if ac.Track != nil {
+		msg.Track = strconv.FormatFloat(*ac.Track, 'f', 6, 64)
+	}
+	if ac.MagHeading != nil {
+		msg.HaveMagneticHeading = true
+		msg.MagneticHeading = *ac.MagHeading
+	}
+	if ac.TrueHeading != nil {
+		msg.HaveTrueHeading = true
+		msg.TrueHeading = *ac.TrueHeading
+	}
+	if ac.BaroRate != nil {
+		msg.HaveVerticalRateBarometric = true
+		msg.VerticalRateBarometric = *ac.BaroRate
+	}
+	if ac.GeomRate != nil {
+		msg.HaveVerticalRateGeometric = true
+		msg.VerticalRateGeometric = *ac.GeomRate
+	}
+	if ac.Emergency != "" {
+		msg.HaveEmergency = true
+		msg.Emergency = ac.Emergency
+	}
+	if ac.Category != "" {
+		msg.HaveCategory = true
+		msg.Category = ac.Category
+	}
+	if ac.NavAltitudeFMS != nil {
+		msg.HaveFmsAltitude = true
+		msg.FmsAltitude = *ac.NavAltitudeFMS
+	}
+	if ac.NavHeading != nil {
+		msg.HaveNavHeading = true
+		msg.NavHeading = *ac.NavHeading
+	}
+	if ac.NavQNH != nil {
+		msg.HaveNavQNH = true
+		msg.NavQNH = *ac.NavQNH
+	}
+	for _, name := range ac.NavModes {
+		for _, mode := range readsb.AllNavModes {
+			if names := mode.NavModesList(); len(names) == 1 && names[0] == name {
+				msg.NavModes |= uint32(mode)
+			}
+		}
+	}
+	if ac.TAS != nil {
+		msg.HaveTrueAirSpeed = true
+		msg.TrueAirSpeed = *ac.TAS
+	}
+	if ac.IAS != nil {
+		msg.HaveIndicatedAirSpeed = true
+		msg.IndicatedAirSpeed = *ac.IAS
+	}
+	if ac.Mach != nil {
+		msg.HaveMach = true
+		msg.Mach = *ac.Mach
+	}
+	if ac.Roll != nil {
+		msg.HaveRoll = true
+		msg.Roll = *ac.Roll
+	}
+	if ac.Version != nil {
+		msg.ADSBVersion = *ac.Version
+	}
+	if ac.NacP != nil {
+		msg.HaveNACP = true
+		msg.NACP = *ac.NacP
+	}
+	if ac.NacV != nil {
+		msg.HaveNACV = true
+		msg.NACV = *ac.NacV
+	}
+	if ac.NicBaro != nil {
+		msg.HaveNICBaro = true
+		msg.NICBaro = *ac.NicBaro
+	}
+	if ac.Sil != nil {
+		msg.HaveSIL = true
+		msg.SIL = *ac.Sil
+		switch ac.SilType {
+		case "perhour":
+			msg.SILType = uint32(readsb.SILPerHour)
+		case "persample":
+			msg.SILType = uint32(readsb.SILPerSample)
+		default:
+			msg.SILType = uint32(readsb.SILUnknown)
+		}
+	}
+	if ac.Rssi != nil {
+		msg.Signal = &pb.Signal{Rssi: *ac.Rssi}
+	}
+	return msg
+}
@@ -0,0 +1,101 @@
+package tracker
+
+import (
+	"context"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/afk11/airtrack/pkg/readsb"
+	assert "github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const aircraftJSONTestFile = `{ "now" : 1603000000.1,
+  "messages" : 4242,
+  "aircraft" : [
+    {"hex":"4ca2d6","type":"adsb_icao","flight":"RYR4GW  ","alt_baro":37000,"alt_geom":37525,"gs":452.1,"track":273.5,"baro_rate":-64,"squawk":"7700","emergency":"none","category":"A3","nav_qnh":1013.6,"nav_altitude_fms":37008,"nav_heading":270.0,"nav_modes":["autopilot","tcas"],"lat":53.123456,"lon":-6.654321,"nic":8,"rc":186,"seen_pos":0.3,"version":2,"nic_baro":1,"nac_p":9,"nac_v":1,"sil":3,"sil_type":"perhour","gva":2,"sda":2,"mlat":[],"tisb":[],"messages":120,"seen":0.1,"rssi":-20.1},
+    {"hex":"406b8a","alt_baro":"ground","gs":12.5,"lat":53.421,"lon":-6.27,"seen_pos":30.5,"messages":10,"seen":25.2,"rssi":-30.0},
+    {"hex":"~2d1e4f","type":"tisb_other","alt_baro":1200,"messages":5,"seen":1.0}
+  ]
+}`
+
+func TestAircraftJSONProducer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airtrack-aircraft-json")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "aircraft.json")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(aircraftJSONTestFile), 0644))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(aircraftJSONTestFile))
+	}))
+	defer srv.Close()
+
+	for _, location := range []string{file, srv.URL + "/data/aircraft.json"} {
+		t.Run(location, func(t *testing.T) {
+			msgs := make(chan *pb.Message, 10)
+			p := NewAircraftJSONProducer(msgs, location, DefaultAircraftJSONInterval, "local")
+			source := &pb.Source{Type: pb.Source_AircraftJson, Name: "local"}
+			assert.NoError(t, p.poll(context.Background(), source))
+			close(msgs)
+			var received []*pb.Message
+			for msg := range msgs {
+				received = append(received, msg)
+			}
+			// non-ICAO address is skipped
+			assert.Equal(t, 2, len(received))
+
+			msg := received[0]
+			assert.Equal(t, "4CA2D6", msg.Icao)
+			assert.Equal(t, source, msg.Source)
+			assert.Equal(t, "RYR4GW", msg.CallSign)
+			assert.Equal(t, "7700", msg.Squawk)
+			assert.Equal(t, "37000", msg.AltitudeBarometric)
+			assert.Equal(t, "37525", msg.AltitudeGeometric)
+			assert.False(t, msg.IsOnGround)
+			assert.Equal(t, "53.12345600", msg.Latitude)
+			assert.Equal(t, "-6.65432100", msg.Longitude)
+			assert.Equal(t, "452.1", msg.GroundSpeed)
+			assert.Equal(t, "273.500000", msg.Track)
+			assert.True(t, msg.HaveVerticalRateBarometric)
+			assert.Equal(t, int64(-64), msg.VerticalRateBarometric)
+			assert.False(t, msg.HaveVerticalRateGeometric)
+			assert.True(t, msg.HaveCategory)
+			assert.Equal(t, "A3", msg.Category)
+			assert.Equal(t, 1013.6, msg.NavQNH)
+			assert.Equal(t, int64(37008), msg.FmsAltitude)
+			assert.Equal(t, 270.0, msg.NavHeading)
+			assert.Equal(t, int64(2), msg.ADSBVersion)
+			assert.Equal(t, uint32(9), msg.NACP)
+			assert.True(t, msg.HaveSIL)
+			assert.Equal(t, uint32(readsb.SILPerHour), msg.SILType)
+			assert.Equal(t, -20.1, msg.Signal.Rssi)
+			assert.Equal(t, pb.Provenance_ADSB, msg.Provenance)
+
+			msg = received[1]
+			assert.Equal(t, "406B8A", msg.Icao)
+			assert.True(t, msg.IsOnGround)
+			assert.Equal(t, "", msg.AltitudeBarometric)
+			// position is older than the poll interval
+			assert.Equal(t, "", msg.Latitude)
+			assert.Equal(t, "", msg.Longitude)
+			assert.False(t, msg.HaveCategory)
+			assert.Equal(t, pb.Provenance_Unknown, msg.Provenance)
+
+			// nothing new since the last poll
+			msgs = make(chan *pb.Message, 10)
+			p.messages = msgs
+			assert.NoError(t, p.poll(context.Background(), source))
+			assert.Equal(t, 0, len(msgs))
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		p := NewAircraftJSONProducer(make(chan *pb.Message), filepath.Join(dir, "missing.json"), time.Second, "local")
+		assert.Error(t, p.poll(context.Background(), &pb.Source{}))
+	})
+}
@@ -0,0 +1,108 @@
+package tracker
+
+import (
+	"context"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/afk11/airtrack/pkg/readsb"
+	"sync"
+	"time"
+)
+
+// AvrProducer - implements Producer.
+// This type represents a connection to an AVR (raw hex) server,
+// typically dump1090's port 30002. Frames are converted to BEAST
+// and decoded by readsb in the same way as BeastProducer.
+type AvrProducer struct {
+	name      string
+	decoder   *readsb.Decoder
+	reader    *lineReader
+	output    *Output
+	wg        sync.WaitGroup
+	canceller func()
+}
+
+// NewAvrProducer initializes a new AvrProducer.
+func NewAvrProducer(msgs chan *pb.Message, host string, port uint16, name string) *AvrProducer {
+	p := &AvrProducer{
+		name:    name,
+		decoder: readsb.NewDecoder(),
+	}
+	source := &pb.Source{
+		Type: pb.Source_AvrServer,
+		Name: name,
+	}
+	p.reader = newLineReader(msgs, "avr", source, host, port, func(line string) ([]*pb.Message, error) {
+		return p.decode(line, source)
+	})
+	return p
+}
+
+// Name - see Producer.Name()
+func (p *AvrProducer) Name() string {
+	return p.name
+}
+
+// SetOutput causes decoded messages to be written to o's
+// BEAST output servers. It must be called before Start.
+func (p *AvrProducer) SetOutput(o *Output) {
+	p.output = o
+}
+
+// Start - see Producer.Start()
+// This function starts the producer goroutine, and the readsb
+// periodic update goroutine.
+func (p *AvrProducer) Start() {
+	p.wg.Add(2)
+	ctx, canceller := context.WithCancel(context.Background())
+	p.canceller = canceller
+	go p.trackPeriodicUpdate(ctx)
+	go func() {
+		defer p.wg.Done()
+		p.reader.run(ctx)
+	}()
+}
+
+// trackPeriodicUpdate is a goroutine that triggers readsb
+// to check for missing aircraft. It terminates when the provided
+// context signals done.
+func (p *AvrProducer) trackPeriodicUpdate(ctx context.Context) {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-time.After(time.Second * 30):
+			readsb.TrackPeriodicUpdate(p.decoder)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// decode parses an AVR frame and converts the decoded
+// messages. AVR frames have no signal level, so Signal
+// is not set.
+func (p *AvrProducer) decode(line string, source *pb.Source) ([]*pb.Message, error) {
+	msgs, err := readsb.ParseAvrMessage(p.decoder, line)
+	if err != nil {
+		return nil, err
+	}
+	protos := make([]*pb.Message, 0, len(msgs))
+	for i := range msgs {
+		proto := decodeModesMessage(p.decoder, msgs[i], source)
+		if proto == nil {
+			continue
+		}
+		proto.Signal = nil
+		if p.output != nil {
+			writeBeastOutput(p.output, proto.Icao, msgs[i])
+		}
+		protos = append(protos, proto)
+	}
+	return protos, nil
+}
+
+// Stop sends the cancel signal to the producer + trackPeriodicUpdate goroutines
+// and blocks until they finish processing
+func (p *AvrProducer) Stop() {
+	p.canceller()
+	p.wg.Wait()
+}
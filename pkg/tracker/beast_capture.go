@@ -0,0 +1,83 @@
+package tracker
+
+import (
+	"bufio"
+	"encoding/binary"
+	"github.com/pkg/errors"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// beastCaptureHeaderLen - length of the header preceding each
+	// chunk in a capture file: an 8 byte timestamp (unix nanoseconds)
+	// and a 4 byte length, both big endian.
+	beastCaptureHeaderLen = 12
+	// beastCaptureMaxChunk - upper limit on the size of a chunk
+	// in a capture file, used to reject corrupt files.
+	beastCaptureMaxChunk = 1 << 20
+)
+
+// BeastCaptureWriter records the raw BEAST byte stream received by
+// a BeastProducer. Each chunk is prefixed with the time it was received,
+// so it can be replayed later with BeastReplayProducer.
+type BeastCaptureWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewBeastCaptureWriter initializes a new BeastCaptureWriter writing to w.
+func NewBeastCaptureWriter(w io.Writer) *BeastCaptureWriter {
+	return &BeastCaptureWriter{w: w}
+}
+
+// Write appends a chunk of data received at t to the capture.
+func (c *BeastCaptureWriter) Write(t time.Time, data []byte) error {
+	if len(data) > beastCaptureMaxChunk {
+		return errors.Errorf("chunk too large (%d bytes)", len(data))
+	}
+	var hdr [beastCaptureHeaderLen]byte
+	binary.BigEndian.PutUint64(hdr[0:8], uint64(t.UnixNano()))
+	binary.BigEndian.PutUint32(hdr[8:12], uint32(len(data)))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.w.Write(hdr[:]); err != nil {
+		return errors.Wrapf(err, "writing chunk header")
+	}
+	if _, err := c.w.Write(data); err != nil {
+		return errors.Wrapf(err, "writing chunk")
+	}
+	return nil
+}
+
+// BeastCaptureReader reads chunks written by BeastCaptureWriter.
+type BeastCaptureReader struct {
+	r *bufio.Reader
+}
+
+// NewBeastCaptureReader initializes a new BeastCaptureReader reading from r.
+func NewBeastCaptureReader(r io.Reader) *BeastCaptureReader {
+	return &BeastCaptureReader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next chunk and the time it was received. io.EOF
+// is returned once the end of the capture is reached.
+func (c *BeastCaptureReader) Next() (time.Time, []byte, error) {
+	var hdr [beastCaptureHeaderLen]byte
+	if _, err := io.ReadFull(c.r, hdr[:]); err == io.EOF {
+		return time.Time{}, nil, io.EOF
+	} else if err != nil {
+		return time.Time{}, nil, errors.Wrapf(err, "reading chunk header")
+	}
+	t := time.Unix(0, int64(binary.BigEndian.Uint64(hdr[0:8])))
+	size := binary.BigEndian.Uint32(hdr[8:12])
+	if size > beastCaptureMaxChunk {
+		return time.Time{}, nil, errors.Errorf("chunk too large (%d bytes)", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(c.r, data); err != nil {
+		return time.Time{}, nil, errors.Wrapf(err, "reading chunk")
+	}
+	return t, data, nil
+}
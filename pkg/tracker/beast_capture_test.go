@@ -0,0 +1,44 @@
+package tracker
+
+import (
+	"bytes"
+	assert "github.com/stretchr/testify/require"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestBeastCapture(t *testing.T) {
+	t0 := time.Unix(1603000000, 123456789)
+	chunks := [][]byte{
+		{0x1a, 0x32, 0x00, 0x01},
+		{},
+		bytes.Repeat([]byte{0x1a}, 2048),
+	}
+	buf := &bytes.Buffer{}
+	w := NewBeastCaptureWriter(buf)
+	for i, chunk := range chunks {
+		assert.NoError(t, w.Write(t0.Add(time.Duration(i)*time.Second), chunk))
+	}
+
+	r := NewBeastCaptureReader(bytes.NewReader(buf.Bytes()))
+	for i, chunk := range chunks {
+		recvTime, data, err := r.Next()
+		assert.NoError(t, err)
+		assert.True(t, t0.Add(time.Duration(i)*time.Second).Equal(recvTime))
+		assert.Equal(t, chunk, data)
+	}
+	_, _, err := r.Next()
+	assert.Equal(t, io.EOF, err)
+
+	t.Run("truncated", func(t *testing.T) {
+		r := NewBeastCaptureReader(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+		for range chunks[:len(chunks)-1] {
+			_, _, err := r.Next()
+			assert.NoError(t, err)
+		}
+		_, _, err := r.Next()
+		assert.Error(t, err)
+		assert.NotEqual(t, io.EOF, err)
+	})
+}
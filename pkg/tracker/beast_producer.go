@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/afk11/airtrack/pkg/pb"
 	"github.com/afk11/airtrack/pkg/readsb"
+	log "github.com/sirupsen/logrus"
 	"math"
 	"net"
 	"strconv"
@@ -20,6 +21,8 @@ type BeastProducer struct {
 	port      uint16
 	decoder   *readsb.Decoder
 	messages  chan *pb.Message
+	recorder  *BeastCaptureWriter
+	output    *Output
 	wg        sync.WaitGroup
 	canceller func()
 }
@@ -40,6 +43,24 @@ func (p *BeastProducer) Name() string {
 	return p.name
 }
 
+// Record causes the raw BEAST stream to be written to w
+// as it's received. It must be called before Start.
+func (p *BeastProducer) Record(w *BeastCaptureWriter) {
+	p.recorder = w
+}
+
+// SetOutput causes decoded messages to be written to o's
+// BEAST output servers. It must be called before Start.
+func (p *BeastProducer) SetOutput(o *Output) {
+	p.output = o
+}
+
+// SetReceiverLocation sets the location of the receiver, which
+// readsb uses to decode positions. It must be called before Start.
+func (p *BeastProducer) SetReceiverLocation(lat, lon float64) {
+	p.decoder.SetReceiverLocation(lat, lon)
+}
+
 // Start - see Producer.Start()
 // This function starts the producer goroutine, and the readsb
 // periodic update goroutine.
@@ -112,7 +133,7 @@ func (p *BeastProducer) producer(ctx context.Context) {
 			m := 1024
 			recvBuf := make([]byte, m)
 
-			_, err := conn.Read(recvBuf[:]) // recv data
+			n, err := conn.Read(recvBuf[:]) // recv data
 			if err != nil {
 				_ = conn.Close()
 				select {
@@ -122,6 +143,12 @@ func (p *BeastProducer) producer(ctx context.Context) {
 				}
 				break // not continue, we want outer loop to rerun
 			}
+			if p.recorder != nil {
+				err = p.recorder.Write(time.Now(), recvBuf[:n])
+				if err != nil {
+					log.Warnf("beast %s: failed to record data: %s", p.name, err.Error())
+				}
+			}
 
 			// append receivedData to connection buffer for leftovers from
 			// last read
@@ -133,128 +160,13 @@ func (p *BeastProducer) producer(ctx context.Context) {
 				panic(err)
 			}
 			for i := range msgs {
-				msg := msgs[i]
-				// call this early so we initialize msg with processed state
-				ac := readsb.TrackUpdateFromMessage(p.decoder, msg)
-				if ac == nil {
-					continue
-				}
-				recvTime := msg.SysMessageTime()
-				proto := &pb.Message{
-					Icao:   msg.GetIcaoHex(),
-					Source: &source,
-				}
-				if category, err := ac.GetCategory(); err == nil {
-					proto.HaveCategory = true
-					proto.Category = category
-				} else if category, err := msg.GetCategory(); err == nil {
-					proto.HaveCategory = true
-					proto.Category = category
-				}
-
-				if adsbVersion, err := ac.GetAdsbVersion(); err == nil {
-					proto.ADSBVersion = adsbVersion
-				}
-				if sil, silType, err := ac.GetSIL(recvTime); err == nil {
-					proto.HaveSIL = true
-					proto.SIL = sil
-					proto.SILType = uint32(silType)
-				}
-				if sil, silType, err := msg.GetSIL(); err == nil {
-					proto.HaveSIL = true
-					proto.SIL = sil
-					proto.SILType = uint32(silType)
-				}
-
-				if nacp, err := msg.GetNACP(); err == nil {
-					proto.HaveNACP = true
-					proto.NACP = nacp
-				}
-				if nacv, err := msg.GetNACV(); err == nil {
-					proto.HaveNACV = true
-					proto.NACV = nacv
-				}
-				if nacv, err := msg.GetNICBaro(); err == nil {
-					proto.HaveNICBaro = true
-					proto.NICBaro = nacv
-				}
-
-				if navModes, err := msg.GetNavModes(); err == nil {
-					proto.NavModes = uint32(navModes)
-				}
-				if qnh, err := msg.GetNavQNH(); err == nil {
-					proto.HaveNavQNH = true
-					proto.NavQNH = qnh
-				}
-				if squawk, err := msg.GetSquawk(); err == nil {
-					proto.Squawk = squawk
-				}
-				if callsign, err := msg.GetCallsign(); err == nil {
-					proto.CallSign = callsign
-				}
-				if altitude, err := msg.GetAltitudeGeom(); err == nil {
-					proto.AltitudeGeometric = strconv.FormatInt(altitude, 10)
-				}
-				if altitude, err := msg.GetAltitudeBaro(); err == nil {
-					proto.AltitudeBarometric = strconv.FormatInt(altitude, 10)
-				}
-				if rate, err := msg.GetRateGeom(); err == nil {
-					proto.HaveVerticalRateGeometric = true
-					proto.VerticalRateGeometric = int64(rate)
-				}
-				if rate, err := msg.GetRateBaro(); err == nil {
-					proto.HaveVerticalRateBarometric = true
-					proto.VerticalRateBarometric = int64(rate)
-				}
-				if heading, headingType, err := msg.GetHeading(); err == nil {
-					switch headingType {
-					case readsb.HeadingGroundTrack:
-						proto.Track = strconv.FormatFloat(heading, 'f', 6, 64)
-					case readsb.HeadingMagnetic:
-						proto.MagneticHeading = heading
-					case readsb.HeadingTrue:
-						proto.TrueHeading = heading
+				proto := decodeModesMessage(p.decoder, msgs[i], &source)
+				if proto != nil {
+					if p.output != nil {
+						writeBeastOutput(p.output, proto.Icao, msgs[i])
 					}
+					p.messages <- proto
 				}
-				if gs, err := msg.GetGroundSpeed(); err == nil {
-					proto.GroundSpeed = strconv.FormatFloat(gs, 'f', 1, 64)
-				}
-				if alt, err := msg.GetFmsAltitude(); err == nil {
-					proto.HaveFmsAltitude = true
-					proto.FmsAltitude = alt
-				}
-				if navHeading, err := msg.GetNavHeading(); err == nil {
-					proto.HaveNavHeading = true
-					proto.NavHeading = navHeading
-				}
-				if tas, err := msg.GetTrueAirSpeed(); err == nil {
-					proto.HaveTrueAirSpeed = true
-					proto.TrueAirSpeed = tas
-				}
-				if ias, err := msg.GetIndicatedAirSpeed(); err == nil {
-					proto.HaveIndicatedAirSpeed = true
-					proto.IndicatedAirSpeed = ias
-				}
-				if mach, err := msg.GetMach(); err == nil {
-					proto.HaveMach = true
-					proto.Mach = mach
-				}
-				if roll, err := msg.GetRoll(); err == nil {
-					proto.HaveRoll = true
-					proto.Roll = roll
-				}
-				if onground, err := msg.IsOnGround(); err == nil {
-					proto.IsOnGround = onground
-				}
-				if signalLevel, err := msg.GetSignalLevel(); err == nil {
-					proto.Signal = &pb.Signal{Rssi: 10 * math.Log10(signalLevel)}
-				}
-				if lat, lon, err := msg.GetDecodeLocation(); err == nil {
-					proto.Latitude = strconv.FormatFloat(lat, 'f', 8, 64)
-					proto.Longitude = strconv.FormatFloat(lon, 'f', 8, 64)
-				}
-
-				p.messages <- proto
 			}
 			// If we did parse anything, move the tail of the message to
 			// the start of the connection buffer for next time.
@@ -272,3 +184,151 @@ func (p *BeastProducer) Stop() {
 	p.canceller()
 	p.wg.Wait()
 }
+
+// decodeModesMessage updates the decoder's aircraft state with msg,
+// and converts msg into a pb.Message. nil is returned if readsb
+// doesn't accept the message.
+func decodeModesMessage(decoder *readsb.Decoder, msg *readsb.ModesMessage, source *pb.Source) *pb.Message {
+	// call this early so we initialize msg with processed state
+	ac := readsb.TrackUpdateFromMessage(decoder, msg)
+	if ac == nil {
+		return nil
+	}
+	recvTime := msg.SysMessageTime()
+	proto := &pb.Message{
+		Icao:       msg.GetIcaoHex(),
+		Source:     source,
+		Provenance: provenanceFromDataSource(msg.GetDataSource()),
+		Raw:        msg.GetRawMessage(),
+	}
+	if category, err := ac.GetCategory(); err == nil {
+		proto.HaveCategory = true
+		proto.Category = category
+	} else if category, err := msg.GetCategory(); err == nil {
+		proto.HaveCategory = true
+		proto.Category = category
+	}
+
+	if adsbVersion, err := ac.GetAdsbVersion(); err == nil {
+		proto.ADSBVersion = adsbVersion
+	}
+	if sil, silType, err := ac.GetSIL(recvTime); err == nil {
+		proto.HaveSIL = true
+		proto.SIL = sil
+		proto.SILType = uint32(silType)
+	}
+	if sil, silType, err := msg.GetSIL(); err == nil {
+		proto.HaveSIL = true
+		proto.SIL = sil
+		proto.SILType = uint32(silType)
+	}
+
+	if nacp, err := msg.GetNACP(); err == nil {
+		proto.HaveNACP = true
+		proto.NACP = nacp
+	}
+	if nacv, err := msg.GetNACV(); err == nil {
+		proto.HaveNACV = true
+		proto.NACV = nacv
+	}
+	if nacv, err := msg.GetNICBaro(); err == nil {
+		proto.HaveNICBaro = true
+		proto.NICBaro = nacv
+	}
+
+	if navModes, err := msg.GetNavModes(); err == nil {
+		proto.NavModes = uint32(navModes)
+	}
+	if qnh, err := msg.GetNavQNH(); err == nil {
+		proto.HaveNavQNH = true
+		proto.NavQNH = qnh
+	}
+	if squawk, err := msg.GetSquawk(); err == nil {
+		proto.Squawk = squawk
+	}
+	if emergency, err := msg.GetEmergency(); err == nil {
+		proto.HaveEmergency = true
+		proto.Emergency = emergency
+	}
+	if callsign, err := msg.GetCallsign(); err == nil {
+		proto.CallSign = callsign
+	}
+	if altitude, err := msg.GetAltitudeGeom(); err == nil {
+		proto.AltitudeGeometric = strconv.FormatInt(altitude, 10)
+	}
+	if altitude, err := msg.GetAltitudeBaro(); err == nil {
+		proto.AltitudeBarometric = strconv.FormatInt(altitude, 10)
+	}
+	if rate, err := msg.GetRateGeom(); err == nil {
+		proto.HaveVerticalRateGeometric = true
+		proto.VerticalRateGeometric = int64(rate)
+	}
+	if rate, err := msg.GetRateBaro(); err == nil {
+		proto.HaveVerticalRateBarometric = true
+		proto.VerticalRateBarometric = int64(rate)
+	}
+	if heading, headingType, err := msg.GetHeading(); err == nil {
+		switch headingType {
+		case readsb.HeadingGroundTrack:
+			proto.Track = strconv.FormatFloat(heading, 'f', 6, 64)
+		case readsb.HeadingMagnetic:
+			proto.MagneticHeading = heading
+		case readsb.HeadingTrue:
+			proto.TrueHeading = heading
+		}
+	}
+	if gs, err := msg.GetGroundSpeed(); err == nil {
+		proto.GroundSpeed = strconv.FormatFloat(gs, 'f', 1, 64)
+	}
+	if alt, err := msg.GetFmsAltitude(); err == nil {
+		proto.HaveFmsAltitude = true
+		proto.FmsAltitude = alt
+	}
+	if navHeading, err := msg.GetNavHeading(); err == nil {
+		proto.HaveNavHeading = true
+		proto.NavHeading = navHeading
+	}
+	if tas, err := msg.GetTrueAirSpeed(); err == nil {
+		proto.HaveTrueAirSpeed = true
+		proto.TrueAirSpeed = tas
+	}
+	if ias, err := msg.GetIndicatedAirSpeed(); err == nil {
+		proto.HaveIndicatedAirSpeed = true
+		proto.IndicatedAirSpeed = ias
+	}
+	if mach, err := msg.GetMach(); err == nil {
+		proto.HaveMach = true
+		proto.Mach = mach
+	}
+	if roll, err := msg.GetRoll(); err == nil {
+		proto.HaveRoll = true
+		proto.Roll = roll
+	}
+	if onground, err := msg.IsOnGround(); err == nil {
+		proto.IsOnGround = onground
+	}
+	if signalLevel, err := msg.GetSignalLevel(); err == nil {
+		proto.Signal = &pb.Signal{Rssi: 10 * math.Log10(signalLevel)}
+	}
+	if lat, lon, err := msg.GetDecodeLocation(); err == nil {
+		proto.Latitude = strconv.FormatFloat(lat, 'f', 8, 64)
+		proto.Longitude = strconv.FormatFloat(lon, 'f', 8, 64)
+	}
+
+	return proto
+}
+
+// writeBeastOutput encodes msg as a BEAST frame and writes it to o
+func writeBeastOutput(o *Output, icao string, msg *readsb.ModesMessage) {
+	raw := msg.GetRawMessage()
+	var signal byte
+	if level, err := msg.GetSignalLevel(); err == nil {
+		signal = byte(math.Round(math.Sqrt(level) * 255))
+	}
+	frame, err := readsb.EncodeBeastFrame(raw, msg.GetTimestamp(), signal)
+	if err != nil {
+		log.Debugf("%s: failed to encode BEAST frame: %s", icao, err.Error())
+		return
+	}
+	o.WriteBeastFrame(icao, raw, frame)
+}
@@ -0,0 +1,153 @@
+package tracker
+
+import (
+	"context"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/afk11/airtrack/pkg/readsb"
+	"github.com/pkg/errors"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// ReplaySpeedRealTime - replays a capture at the speed it was recorded
+	ReplaySpeedRealTime float64 = 1
+	// ReplaySpeedUnlimited - replays a capture as fast as possible
+	ReplaySpeedUnlimited float64 = 0
+)
+
+// BeastReplayProducer - implements Producer.
+// This type replays a capture written by BeastCaptureWriter, decoding it
+// with readsb in the same way as BeastProducer. Messages have Time set
+// to the time they were recorded, so the tracker should be configured
+// with Options.UseMessageTime.
+type BeastReplayProducer struct {
+	name      string
+	r         io.Reader
+	speed     float64
+	decoder   *readsb.Decoder
+	messages  chan *pb.Message
+	done      chan struct{}
+	err       error
+	wg        sync.WaitGroup
+	canceller func()
+}
+
+// NewBeastReplayProducer initializes a new BeastReplayProducer reading
+// from r. speed is a multiple of the recorded speed, so ReplaySpeedRealTime
+// replays in real time, and 10 replays ten times faster. ReplaySpeedUnlimited
+// replays as fast as the tracker processes messages.
+func NewBeastReplayProducer(msgs chan *pb.Message, r io.Reader, speed float64, name string) *BeastReplayProducer {
+	return &BeastReplayProducer{
+		messages: msgs,
+		r:        r,
+		speed:    speed,
+		name:     name,
+		decoder:  readsb.NewDecoder(),
+		done:     make(chan struct{}),
+	}
+}
+
+// Name - see Producer.Name()
+func (p *BeastReplayProducer) Name() string {
+	return p.name
+}
+
+// Start - see Producer.Start()
+// This function starts the producer goroutine.
+func (p *BeastReplayProducer) Start() {
+	p.wg.Add(1)
+	ctx, canceller := context.WithCancel(context.Background())
+	p.canceller = canceller
+	go p.producer(ctx)
+}
+
+// Done returns a channel which is closed once the
+// capture has been replayed, or an error occurred.
+func (p *BeastReplayProducer) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err returns the error which stopped the replay, if any.
+// It should only be called after Done is closed.
+func (p *BeastReplayProducer) Err() error {
+	return p.err
+}
+
+// producer is a goroutine that reads the capture and sends
+// decoded messages over the messages channel. readsb uses
+// the recorded time instead of the current time.
+func (p *BeastReplayProducer) producer(ctx context.Context) {
+	defer p.wg.Done()
+	defer close(p.done)
+	source := &pb.Source{
+		Type: pb.Source_BeastServer,
+		Name: p.name,
+	}
+	reader := NewBeastCaptureReader(p.r)
+	var msgTime, firstMsgTime, lastPeriodicUpdate time.Time
+	p.decoder.SetClock(func() time.Time {
+		return msgTime
+	})
+	start := time.Now()
+	var buf []byte
+	for {
+		t, data, err := reader.Next()
+		if err == io.EOF {
+			return
+		} else if err != nil {
+			p.err = errors.Wrapf(err, "reading capture")
+			return
+		}
+		if firstMsgTime.IsZero() {
+			firstMsgTime = t
+			lastPeriodicUpdate = t
+		}
+		if p.speed > 0 {
+			wait := time.Duration(float64(t.Sub(firstMsgTime))/p.speed) - time.Since(start)
+			if wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+		msgTime = t
+		// BeastProducer does this every 30 seconds
+		if msgTime.Sub(lastPeriodicUpdate) >= time.Second*30 {
+			readsb.TrackPeriodicUpdate(p.decoder)
+			lastPeriodicUpdate = msgTime
+		}
+
+		buf = append(buf, data...)
+		msgs, som, err := readsb.ParseMessage(p.decoder, buf)
+		if err != nil {
+			p.err = errors.Wrapf(err, "parsing capture")
+			return
+		}
+		for i := range msgs {
+			proto := decodeModesMessage(p.decoder, msgs[i], source)
+			if proto == nil {
+				continue
+			}
+			proto.Time = msgTime.UnixNano() / int64(time.Millisecond)
+			select {
+			case p.messages <- proto:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if som > 0 {
+			buf = buf[som:]
+		}
+	}
+}
+
+// Stop sends the cancel signal to the producer goroutine
+// and blocks until it finishes processing
+func (p *BeastReplayProducer) Stop() {
+	p.canceller()
+	p.wg.Wait()
+}
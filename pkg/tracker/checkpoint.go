@@ -0,0 +1,672 @@
+package tracker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/afk11/airtrack/pkg/db"
+	"github.com/afk11/airtrack/pkg/geo"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+const (
+	// DefaultCheckpointInterval - default time between checkpoints
+	DefaultCheckpointInterval = time.Second * 30
+	// checkpointVersion - version of the checkpoint file format
+	checkpointVersion = 1
+)
+
+type (
+	// checkpoint is the tracker state written to the checkpoint
+	// file. It contains the open session of each project, and every
+	// sighting in memory along with its project observations.
+	checkpoint struct {
+		Version int `json:"version"`
+		// Time - the tracker's time, which is the message time when replaying
+		Time time.Time `json:"time"`
+		// WrittenAt - the wall clock time the checkpoint was written,
+		// used to check opt.CheckpointMaxAge
+		WrittenAt time.Time            `json:"written_at"`
+		Sessions  []checkpointSession  `json:"sessions"`
+		Sightings []checkpointSighting `json:"sightings"`
+	}
+	// checkpointSession identifies the db.Session of a project
+	checkpointSession struct {
+		Project    string `json:"project"`
+		SessionID  uint64 `json:"session_id"`
+		Identifier string `json:"identifier"`
+	}
+	// checkpointSighting contains a Sighting. The orbit detector's
+	// positions are not saved, so orbits are detected again once
+	// the aircraft completes another circle.
+	checkpointSighting struct {
+		// State - the pb.State, encoded as protobuf
+		State             []byte       `json:"state"`
+		Tags              SightingTags `json:"tags"`
+		FirstSeen         time.Time    `json:"first_seen"`
+		LastSeen          time.Time    `json:"last_seen"`
+		SearchedCountry   bool         `json:"searched_country"`
+		SearchedOperator  bool         `json:"searched_operator"`
+		SearchedInfo      bool         `json:"searched_info"`
+		AircraftID        uint64       `json:"aircraft_id,omitempty"`
+		OnGroundCandidate bool         `json:"onground_candidate"`
+		OnGroundCounter   int64        `json:"onground_counter"`
+
+		Phase               FlightPhase `json:"phase"`
+		HaveLiftoffAltitude bool        `json:"have_liftoff_altitude"`
+		LiftoffAltitude     int64       `json:"liftoff_altitude"`
+		Elevation           int64       `json:"elevation"`
+		HaveDescentTop      bool        `json:"have_descent_top"`
+		DescentTop          int64       `json:"descent_top"`
+		DescentAltitude     int64       `json:"descent_altitude"`
+
+		Orbiting        bool               `json:"orbiting"`
+		OrbitLatitude   float64            `json:"orbit_latitude"`
+		OrbitLongitude  float64            `json:"orbit_longitude"`
+		OrbitRadius     float64            `json:"orbit_radius"`
+		HavePosition    bool               `json:"have_position"`
+		Position        checkpointPosition `json:"position"`
+		PositionAnomaly int64              `json:"position_anomalies"`
+
+		Observations []checkpointObservation `json:"observations"`
+	}
+	// checkpointPosition contains the last accepted position
+	// of the positionValidator
+	checkpointPosition struct {
+		Time      time.Time `json:"time"`
+		Latitude  float64   `json:"latitude"`
+		Longitude float64   `json:"longitude"`
+		HaveNACP  bool      `json:"have_nacp"`
+		NACP      uint32    `json:"nacp"`
+	}
+	// checkpointObservation contains a ProjectObservation. SightingID is
+	// zero if the db.Sighting wasn't created yet.
+	checkpointObservation struct {
+		Project            string                          `json:"project"`
+		SightingID         uint64                          `json:"sighting_id,omitempty"`
+		FirstSeen          time.Time                       `json:"first_seen"`
+		LastSeen           time.Time                       `json:"last_seen"`
+		LastLocation       time.Time                       `json:"last_location"`
+		HaveCallsign       bool                            `json:"have_callsign"`
+		Callsign           string                          `json:"callsign"`
+		HaveSquawk         bool                            `json:"have_squawk"`
+		Squawk             string                          `json:"squawk"`
+		Phase              FlightPhase                     `json:"phase"`
+		Emergency          string                          `json:"emergency"`
+		Zones              []string                        `json:"zones"`
+		PositionAnomalies  int64                           `json:"position_anomalies"`
+		RuleTriggered      map[EmailNotification]time.Time `json:"rule_triggered"`
+		Origin             *checkpointGeocodeLocation      `json:"origin,omitempty"`
+		Destination        *checkpointGeocodeLocation      `json:"destination,omitempty"`
+		OriginSaved        bool                            `json:"origin_saved"`
+		DestinationSaved   bool                            `json:"destination_saved"`
+		HaveAltBaro        bool                            `json:"have_altitude_baro"`
+		AltitudeBaro       int64                           `json:"altitude_baro"`
+		HaveAltGeom        bool                            `json:"have_altitude_geom"`
+		AltitudeGeom       int64                           `json:"altitude_geom"`
+		HaveGS             bool                            `json:"have_gs"`
+		GS                 float64                         `json:"gs"`
+		HaveTrack          bool                            `json:"have_track"`
+		Track              float64                         `json:"track"`
+		Tags               SightingTags                    `json:"tags"`
+		HaveLocation       bool                            `json:"have_location"`
+		Latitude           float64                         `json:"latitude"`
+		Longitude          float64                         `json:"longitude"`
+		LocationProvenance pb.Provenance                   `json:"location_provenance"`
+		LocationCount      int64                           `json:"location_count"`
+		Receivers          map[string]checkpointReceiver   `json:"receivers,omitempty"`
+	}
+	// checkpointReceiver contains an observedReceiver
+	checkpointReceiver struct {
+		FirstSeen     time.Time `json:"first_seen"`
+		LastSeen      time.Time `json:"last_seen"`
+		HaveRssi      bool      `json:"have_rssi"`
+		BestRssi      float64   `json:"best_rssi"`
+		Saved         bool      `json:"saved"`
+		SavedLastSeen time.Time `json:"saved_last_seen"`
+		Dirty         bool      `json:"dirty"`
+	}
+	// checkpointGeocodeLocation contains a GeocodeLocation
+	checkpointGeocodeLocation struct {
+		Ok       bool              `json:"ok"`
+		Lat      float64           `json:"lat"`
+		Long     float64           `json:"long"`
+		Address  string            `json:"address"`
+		Airport  geo.AirportRecord `json:"airport"`
+		Distance float64           `json:"distance"`
+	}
+)
+
+// readCheckpoint reads the checkpoint file. If the file
+// doesn't exist, nil is returned without an error.
+func readCheckpoint(file string) (*checkpoint, error) {
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "reading checkpoint file")
+	}
+	cp := &checkpoint{}
+	err = json.Unmarshal(data, cp)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding checkpoint file")
+	} else if cp.Version != checkpointVersion {
+		return nil, errors.Errorf("unsupported checkpoint version %d", cp.Version)
+	}
+	return cp, nil
+}
+
+// writeCheckpoint writes cp to file. The checkpoint is written
+// to a temporary file first, so file is always complete.
+func writeCheckpoint(file string, cp *checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return errors.Wrapf(err, "encoding checkpoint")
+	}
+	tmp := file + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "writing checkpoint file")
+	}
+	err = os.Rename(tmp, file)
+	if err != nil {
+		return errors.Wrapf(err, "renaming checkpoint file")
+	}
+	return nil
+}
+
+// newCheckpointLocation converts l for the checkpoint
+func newCheckpointLocation(l *GeocodeLocation) *checkpointGeocodeLocation {
+	if l == nil {
+		return nil
+	}
+	return &checkpointGeocodeLocation{
+		Ok:       l.ok,
+		Lat:      l.lat,
+		Long:     l.long,
+		Address:  l.address,
+		Airport:  l.airport,
+		Distance: l.distance,
+	}
+}
+
+// geocodeLocation converts the checkpoint location back
+// into a GeocodeLocation
+func (l *checkpointGeocodeLocation) geocodeLocation() *GeocodeLocation {
+	if l == nil {
+		return nil
+	}
+	return &GeocodeLocation{
+		ok:       l.Ok,
+		lat:      l.Lat,
+		long:     l.Long,
+		address:  l.Address,
+		airport:  l.Airport,
+		distance: l.Distance,
+	}
+}
+
+// startCheckpointTask is a goroutine that periodically writes
+// the checkpoint file, and stops if the stop signal is received from ctx.
+func (t *Tracker) startCheckpointTask(ctx context.Context) {
+	for {
+		select {
+		case <-time.After(t.opt.CheckpointInterval):
+			err := t.saveCheckpoint()
+			if err != nil {
+				log.Warnf("failed to write checkpoint: %s", err.Error())
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// saveCheckpoint writes the current state to the checkpoint file
+func (t *Tracker) saveCheckpoint() error {
+	begin := time.Now()
+	cp, err := t.newCheckpoint(t.now())
+	if err != nil {
+		return err
+	}
+	err = writeCheckpoint(t.opt.CheckpointFile, cp)
+	if err != nil {
+		return err
+	}
+	log.Debugf("wrote checkpoint (took %s)  sessions:%d  sightings:%d",
+		time.Since(begin), len(cp.Sessions), len(cp.Sightings))
+	return nil
+}
+
+// newCheckpoint captures the sessions of all projects,
+// and the state of every sighting.
+func (t *Tracker) newCheckpoint(now time.Time) (*checkpoint, error) {
+	cp := &checkpoint{
+		Version:   checkpointVersion,
+		Time:      now,
+		WrittenAt: time.Now(),
+	}
+	t.projectMu.RLock()
+	for _, p := range t.projects {
+		cp.Sessions = append(cp.Sessions, checkpointSession{
+			Project:    p.Name,
+			SessionID:  p.Session.ID,
+			Identifier: p.Session.Identifier,
+		})
+	}
+	t.projectMu.RUnlock()
+
+	t.sightingMu.Lock()
+	sightings := make([]*Sighting, 0, len(t.sighting))
+	for _, s := range t.sighting {
+		sightings = append(sightings, s)
+	}
+	t.sightingMu.Unlock()
+
+	cp.Sightings = make([]checkpointSighting, 0, len(sightings))
+	for _, s := range sightings {
+		s.mu.Lock()
+		cs, err := newCheckpointSighting(s)
+		s.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		cp.Sightings = append(cp.Sightings, cs)
+	}
+	return cp, nil
+}
+
+// newCheckpointSighting captures the state of s, which
+// must be locked by the caller.
+func newCheckpointSighting(s *Sighting) (checkpointSighting, error) {
+	state, err := proto.Marshal(&s.State)
+	if err != nil {
+		return checkpointSighting{}, errors.Wrapf(err, "encoding state of %s", s.State.Icao)
+	}
+	cs := checkpointSighting{
+		State:               state,
+		Tags:                s.Tags,
+		FirstSeen:           s.firstSeen,
+		LastSeen:            s.lastSeen,
+		SearchedCountry:     s.searchedCountry,
+		SearchedOperator:    s.searchedOperator,
+		SearchedInfo:        s.searchedInfo,
+		OnGroundCandidate:   s.onGroundCandidate,
+		OnGroundCounter:     s.onGroundCounter,
+		Phase:               s.phase.phase,
+		HaveLiftoffAltitude: s.phase.haveLiftoffAltitude,
+		LiftoffAltitude:     s.phase.liftoffAltitude,
+		Elevation:           s.phase.elevation,
+		HaveDescentTop:      s.phase.haveDescentTop,
+		DescentTop:          s.phase.descentTop,
+		DescentAltitude:     s.phase.descentAltitude,
+		Orbiting:            s.orbit.orbiting,
+		OrbitLatitude:       s.orbit.latitude,
+		OrbitLongitude:      s.orbit.longitude,
+		OrbitRadius:         s.orbit.radius,
+		HavePosition:        s.position.haveLast,
+		Position: checkpointPosition{
+			Time:      s.position.last.time,
+			Latitude:  s.position.last.lat,
+			Longitude: s.position.last.lon,
+			HaveNACP:  s.position.last.haveNACP,
+			NACP:      s.position.last.nacp,
+		},
+		PositionAnomaly: s.position.anomalies,
+	}
+	if s.a != nil {
+		cs.AircraftID = s.a.ID
+	}
+	for _, o := range s.observedBy {
+		o.mu.RLock()
+		co := checkpointObservation{
+			Project:            o.project.Name,
+			FirstSeen:          o.firstSeen,
+			LastSeen:           o.lastSeen,
+			LastLocation:       o.lastLocation,
+			HaveCallsign:       o.haveCallsign,
+			Callsign:           o.callsign,
+			HaveSquawk:         o.haveSquawk,
+			Squawk:             o.squawk,
+			Phase:              o.phase,
+			Emergency:          o.emergency,
+			Zones:              append([]string(nil), o.zones...),
+			PositionAnomalies:  o.positionAnomalies,
+			Origin:             newCheckpointLocation(o.origin),
+			Destination:        newCheckpointLocation(o.destination),
+			OriginSaved:        o.originSaved,
+			DestinationSaved:   o.destinationSaved,
+			HaveAltBaro:        o.haveAltBaro,
+			AltitudeBaro:       o.altitudeBaro,
+			HaveAltGeom:        o.haveAltGeom,
+			AltitudeGeom:       o.altitudeGeom,
+			HaveGS:             o.haveGS,
+			GS:                 o.gs,
+			HaveTrack:          o.haveTrack,
+			Track:              o.track,
+			Tags:               o.tags,
+			HaveLocation:       o.haveLocation,
+			Latitude:           o.latitude,
+			Longitude:          o.longitude,
+			LocationProvenance: o.locationProvenance,
+			LocationCount:      o.locationCount,
+		}
+		if o.sighting != nil {
+			co.SightingID = o.sighting.ID
+		}
+		if o.ruleTriggered != nil {
+			co.RuleTriggered = make(map[EmailNotification]time.Time, len(o.ruleTriggered))
+			for name, triggered := range o.ruleTriggered {
+				co.RuleTriggered[name] = triggered
+			}
+		}
+		if o.receivers != nil {
+			co.Receivers = make(map[string]checkpointReceiver, len(o.receivers))
+			for receiver, r := range o.receivers {
+				co.Receivers[receiver] = checkpointReceiver{
+					FirstSeen:     r.firstSeen,
+					LastSeen:      r.lastSeen,
+					HaveRssi:      r.haveRssi,
+					BestRssi:      r.bestRssi,
+					Saved:         r.saved,
+					SavedLastSeen: r.savedLastSeen,
+					Dirty:         r.dirty,
+				}
+			}
+		}
+		o.mu.RUnlock()
+		cs.Observations = append(cs.Observations, co)
+	}
+	return cs, nil
+}
+
+// restoredSession returns the session of project p in the checkpoint
+// read by New, or nil if it can't be reused. The session is only reused
+// if the checkpoint is recent enough, the session is still open, and it
+// records the same features as p.
+func (t *Tracker) restoredSession(project *db.Project, p *Project) (*db.Session, error) {
+	if t.restore == nil || t.restoreStale {
+		return nil, nil
+	}
+	for _, cs := range t.restore.Sessions {
+		if cs.Project != p.Name {
+			continue
+		}
+		session, err := t.database.GetSessionByIdentifier(project, cs.Identifier)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Wrap(err, "load checkpoint session")
+		}
+		if session.ClosedAt != nil ||
+			session.WithSquawks != p.IsFeatureEnabled(TrackSquawks) ||
+			session.WithTransmissionTypes != p.IsFeatureEnabled(TrackTxTypes) ||
+			session.WithCallSigns != p.IsFeatureEnabled(TrackCallSigns) {
+			return nil, nil
+		}
+		return session, nil
+	}
+	return nil, nil
+}
+
+// Restore restores the sightings in the checkpoint read by New. It
+// must be called after the projects are added, and before Start.
+// Observations are restored for projects which resumed their session in
+// AddProject. The open sightings and sessions of other projects are closed
+// at the time of the checkpoint. If the checkpoint is older than
+// opt.CheckpointMaxAge, no sightings are restored.
+func (t *Tracker) Restore() error {
+	cp := t.restore
+	t.restore = nil
+	if cp == nil {
+		return nil
+	}
+
+	t.projectMu.RLock()
+	defer t.projectMu.RUnlock()
+	resumed := make(map[string]*Project)
+	for _, p := range t.projects {
+		for _, cs := range cp.Sessions {
+			if cs.Project == p.Name && cs.SessionID == p.Session.ID {
+				resumed[p.Name] = p
+			}
+		}
+	}
+
+	t.sightingMu.Lock()
+	defer t.sightingMu.Unlock()
+	closeSightings := make([]*db.Sighting, 0)
+	var numRestored int
+	for _, cs := range cp.Sightings {
+		s, err := t.newSightingFromCheckpoint(cs)
+		if err != nil {
+			return err
+		}
+		for _, co := range cs.Observations {
+			var sighting *db.Sighting
+			if co.SightingID != 0 {
+				sighting, err = t.database.GetSightingByID(co.SightingID)
+				if err == sql.ErrNoRows {
+					continue
+				} else if err != nil {
+					return errors.Wrapf(err, "loading sighting %d", co.SightingID)
+				} else if sighting.ClosedAt != nil {
+					// closed after the checkpoint was written
+					continue
+				}
+			}
+			p, ok := resumed[co.Project]
+			if !ok {
+				if sighting != nil {
+					closeSightings = append(closeSightings, sighting)
+				}
+				continue
+			}
+			o := newObservationFromCheckpoint(p, s, sighting, co)
+			s.observedBy[p.Session.ID] = o
+			p.obsMu.Lock()
+			p.Observations[s.State.Icao] = o
+			p.obsMu.Unlock()
+			numRestored++
+			for _, l := range t.projectAcUpdateListeners {
+				l.NewAircraft(p, s)
+			}
+		}
+		if !t.restoreStale {
+			t.sighting[s.State.Icao] = s
+		}
+	}
+
+	limit := 100
+	for i := 0; i < len(closeSightings); i += limit {
+		err := t.database.CloseSightingBatch(closeSightings[i:min(i+limit, len(closeSightings))], cp.Time)
+		if err != nil {
+			return errors.Wrapf(err, "closing batch of sightings")
+		}
+	}
+	for _, cs := range cp.Sessions {
+		if _, ok := resumed[cs.Project]; ok {
+			continue
+		}
+		err := t.closeCheckpointSession(cs, cp.Time)
+		if err != nil {
+			return err
+		}
+	}
+
+	if t.restoreStale {
+		log.Infof("checkpoint from %s is too old, closed %d sightings",
+			cp.Time.Format(time.RFC822), len(closeSightings))
+	} else {
+		log.Infof("restored %d aircraft and %d sightings from checkpoint, closed %d sightings",
+			len(t.sighting), numRestored, len(closeSightings))
+	}
+	aircraftCountVec.WithLabelValues().Set(float64(len(t.sighting)))
+	return nil
+}
+
+// closeCheckpointSession closes the session in the checkpoint if it's
+// still open. It's used for projects which didn't resume their session.
+func (t *Tracker) closeCheckpointSession(cs checkpointSession, closedAt time.Time) error {
+	project, err := t.database.GetProject(cs.Project)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "query project")
+	}
+	session, err := t.database.GetSessionByIdentifier(project, cs.Identifier)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "load checkpoint session")
+	} else if session.ClosedAt != nil {
+		return nil
+	}
+	res, err := t.database.CloseSession(session, closedAt)
+	if err != nil {
+		return errors.Wrapf(err, "closing session")
+	} else if err = db.CheckRowsUpdated(res, 1); err != nil {
+		return errors.Wrap(err, "should have updated 1 session")
+	}
+	return nil
+}
+
+// newSightingFromCheckpoint creates a Sighting from cs
+func (t *Tracker) newSightingFromCheckpoint(cs checkpointSighting) (*Sighting, error) {
+	s := &Sighting{
+		Tags:              cs.Tags,
+		firstSeen:         cs.FirstSeen,
+		lastSeen:          cs.LastSeen,
+		searchedCountry:   cs.SearchedCountry,
+		searchedOperator:  cs.SearchedOperator,
+		searchedInfo:      cs.SearchedInfo,
+		observedBy:        make(map[uint64]*ProjectObservation),
+		onGroundCandidate: cs.OnGroundCandidate,
+		onGroundCounter:   cs.OnGroundCounter,
+		phase: flightPhase{
+			phase:               cs.Phase,
+			haveLiftoffAltitude: cs.HaveLiftoffAltitude,
+			liftoffAltitude:     cs.LiftoffAltitude,
+			elevation:           cs.Elevation,
+			haveDescentTop:      cs.HaveDescentTop,
+			descentTop:          cs.DescentTop,
+			descentAltitude:     cs.DescentAltitude,
+		},
+		orbit: orbitDetector{
+			orbiting:  cs.Orbiting,
+			latitude:  cs.OrbitLatitude,
+			longitude: cs.OrbitLongitude,
+			radius:    cs.OrbitRadius,
+		},
+		position: positionValidator{
+			haveLast: cs.HavePosition,
+			last: positionFix{
+				time:     cs.Position.Time,
+				lat:      cs.Position.Latitude,
+				lon:      cs.Position.Longitude,
+				haveNACP: cs.Position.HaveNACP,
+				nacp:     cs.Position.NACP,
+			},
+			anomalies: cs.PositionAnomaly,
+		},
+	}
+	err := proto.Unmarshal(cs.State, &s.State)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding checkpoint state")
+	}
+	if cs.AircraftID != 0 {
+		s.a, err = t.database.GetAircraftByID(cs.AircraftID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "loading aircraft %s", s.State.Icao)
+		}
+	}
+	return s, nil
+}
+
+// newObservationFromCheckpoint creates a ProjectObservation of s for p
+// from co. sighting is nil if the db.Sighting wasn't created yet.
+func newObservationFromCheckpoint(p *Project, s *Sighting, sighting *db.Sighting, co checkpointObservation) *ProjectObservation {
+	o := &ProjectObservation{
+		project:            p,
+		mem:                s,
+		sighting:           sighting,
+		firstSeen:          co.FirstSeen,
+		lastSeen:           co.LastSeen,
+		lastLocation:       co.LastLocation,
+		haveCallsign:       co.HaveCallsign,
+		callsign:           co.Callsign,
+		haveSquawk:         co.HaveSquawk,
+		squawk:             co.Squawk,
+		phase:              co.Phase,
+		emergency:          co.Emergency,
+		zones:              co.Zones,
+		positionAnomalies:  co.PositionAnomalies,
+		ruleTriggered:      co.RuleTriggered,
+		origin:             co.Origin.geocodeLocation(),
+		destination:        co.Destination.geocodeLocation(),
+		originSaved:        co.OriginSaved,
+		destinationSaved:   co.DestinationSaved,
+		haveAltBaro:        co.HaveAltBaro,
+		altitudeBaro:       co.AltitudeBaro,
+		haveAltGeom:        co.HaveAltGeom,
+		altitudeGeom:       co.AltitudeGeom,
+		haveGS:             co.HaveGS,
+		gs:                 co.GS,
+		haveTrack:          co.HaveTrack,
+		track:              co.Track,
+		tags:               co.Tags,
+		haveLocation:       co.HaveLocation,
+		latitude:           co.Latitude,
+		longitude:          co.Longitude,
+		locationProvenance: co.LocationProvenance,
+		locationCount:      co.LocationCount,
+		// the origin and destination are saved if necessary
+		// by the next database update
+		dirty: true,
+	}
+	if co.Receivers != nil {
+		o.receivers = make(map[string]*observedReceiver, len(co.Receivers))
+		for receiver, r := range co.Receivers {
+			o.receivers[receiver] = &observedReceiver{
+				firstSeen:     r.FirstSeen,
+				lastSeen:      r.LastSeen,
+				haveRssi:      r.HaveRssi,
+				bestRssi:      r.BestRssi,
+				saved:         r.Saved,
+				savedLastSeen: r.SavedLastSeen,
+				dirty:         r.Dirty,
+			}
+		}
+	}
+	return o
+}
+
+// stopWithCheckpoint finishes Stop once the checkpoint is written.
+// Sightings and sessions are left open, so they can be resumed by Restore.
+func (t *Tracker) stopWithCheckpoint() error {
+	t.sightingMu.Lock()
+	log.Infof("saved %d aircraft in view to checkpoint", len(t.sighting))
+	t.sighting = make(map[string]*Sighting)
+	t.sightingMu.Unlock()
+
+	t.projectMu.RLock()
+	defer t.projectMu.RUnlock()
+	for _, p := range t.projects {
+		p.obsMu.Lock()
+		p.Observations = make(map[string]*ProjectObservation)
+		p.obsMu.Unlock()
+	}
+	numListeners := len(t.projectStatusListeners)
+	for i := 0; i < numListeners; i++ {
+		for _, p := range t.projects {
+			t.projectStatusListeners[i].Deactivated(p)
+		}
+	}
+	return nil
+}
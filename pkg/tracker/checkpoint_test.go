@@ -0,0 +1,185 @@
+package tracker
+
+import (
+	"github.com/afk11/airtrack/pkg/config"
+	"github.com/afk11/airtrack/pkg/db"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/afk11/airtrack/pkg/readsb/aircraftdb"
+	"github.com/afk11/airtrack/pkg/test"
+	assert "github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTracker_Checkpoint(t *testing.T) {
+	projCfg := config.Project{
+		Name:     "testproj",
+		Features: []string{string(TrackCallSigns)},
+		Notifications: &config.Notifications{
+			Enabled: []string{string(SpottedInFlight)},
+			Webhooks: []config.Webhook{
+				{URL: "http://127.0.0.1/hook1"},
+			},
+		},
+	}
+	msg := &pb.Message{Source: beastSource, Icao: "4CA123", CallSign: "RYR2LE", Provenance: pb.Provenance_MLAT,
+		AltitudeBarometric: "5000", Latitude: "53.421333", Longitude: "-6.270075"}
+
+	// run starts a tracker using the checkpoint file, and processes
+	// msg if it's not nil. The tracker is stopped before returning.
+	run := func(t *testing.T, database db.Database, file string, maxAge time.Duration, msg *pb.Message, f func(tr *Tracker, proj *Project)) *testWebhookSender {
+		sender := &testWebhookSender{}
+		tr, err := New(database, Options{
+			SightingTimeout:         time.Second * 30,
+			OnGroundUpdateThreshold: 1,
+			Webhooks:                sender,
+			AircraftDb:              aircraftdb.New(),
+			CheckpointFile:          file,
+			CheckpointInterval:      time.Minute,
+			CheckpointMaxAge:        maxAge,
+		})
+		assert.NoError(t, err)
+		proj, err := InitProject(projCfg)
+		assert.NoError(t, err)
+		assert.NoError(t, tr.AddProject(proj))
+		assert.NoError(t, tr.Restore())
+		tr.Start(make(chan *pb.Message))
+		if msg != nil {
+			now := time.Now()
+			s := tr.getSighting(msg.Icao, now)
+			assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+			assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+			s.mu.Unlock()
+		}
+		f(tr, proj)
+		assert.NoError(t, tr.Stop())
+		return sender
+	}
+
+	t.Run("restore", func(t *testing.T) {
+		dbConn, dialect, _, closer := test.InitDBUp()
+		defer closer()
+		database := db.NewDatabase(dbConn, dialect)
+		dir, err := ioutil.TempDir("", "checkpoint")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+		file := filepath.Join(dir, "checkpoint.json")
+
+		var session *db.Session
+		var sighting *db.Sighting
+		sender := run(t, database, file, time.Minute, msg, func(tr *Tracker, proj *Project) {
+			assert.NoError(t, tr.processDatabaseUpdates())
+			session = proj.Session
+			sighting = proj.Observations[msg.Icao].sighting
+			assert.NotNil(t, sighting)
+		})
+		assert.Equal(t, 1, len(sender.jobs))
+		assert.Equal(t, string(SpottedInFlight), sender.jobs[0].Event)
+
+		// the session and sighting are still open
+		_, err = os.Stat(file)
+		assert.NoError(t, err)
+		sighting, err = database.GetSightingByID(sighting.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, sighting.ClosedAt)
+
+		update := &pb.Message{Source: beastSource, Icao: "4CA123", AltitudeBarometric: "5200"}
+		sender = run(t, database, file, time.Minute, update, func(tr *Tracker, proj *Project) {
+			assert.Equal(t, session.ID, proj.Session.ID)
+			assert.True(t, tr.IsObserving(proj.Name, msg.Icao))
+			o := proj.Observations[msg.Icao]
+			assert.Equal(t, sighting.ID, o.sighting.ID)
+			assert.Equal(t, "RYR2LE", o.CallSign())
+			assert.Equal(t, int64(5200), o.AltitudeBarometric())
+			assert.True(t, o.HaveLocation())
+			assert.Equal(t, pb.Provenance_MLAT, o.locationProvenance)
+
+			s := tr.sighting[msg.Icao]
+			assert.NotNil(t, s.a)
+			assert.Equal(t, "RYR2LE", s.State.CallSign)
+			assert.Equal(t, 53.421333, s.State.Latitude)
+		})
+		// the aircraft was already spotted
+		assert.Equal(t, 0, len(sender.jobs))
+	})
+
+	t.Run("message time", func(t *testing.T) {
+		dbConn, dialect, _, closer := test.InitDBUp()
+		defer closer()
+		database := db.NewDatabase(dbConn, dialect)
+		dir, err := ioutil.TempDir("", "checkpoint")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+		file := filepath.Join(dir, "checkpoint.json")
+
+		run(t, database, file, time.Minute, msg, func(tr *Tracker, proj *Project) {
+			assert.NoError(t, tr.processDatabaseUpdates())
+		})
+		// a replayed capture is checkpointed with the message time
+		cp, err := readCheckpoint(file)
+		assert.NoError(t, err)
+		cp.Time = cp.Time.Add(-time.Hour * 24 * 365)
+		assert.NoError(t, writeCheckpoint(file, cp))
+
+		// it's restored because the file was written recently
+		run(t, database, file, time.Minute, nil, func(tr *Tracker, proj *Project) {
+			assert.True(t, tr.IsObserving(proj.Name, msg.Icao))
+		})
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		dbConn, dialect, _, closer := test.InitDBUp()
+		defer closer()
+		database := db.NewDatabase(dbConn, dialect)
+		dir, err := ioutil.TempDir("", "checkpoint")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+		file := filepath.Join(dir, "checkpoint.json")
+
+		var session *db.Session
+		var sighting *db.Sighting
+		run(t, database, file, time.Minute, msg, func(tr *Tracker, proj *Project) {
+			assert.NoError(t, tr.processDatabaseUpdates())
+			session = proj.Session
+			sighting = proj.Observations[msg.Icao].sighting
+		})
+
+		run(t, database, file, time.Nanosecond, nil, func(tr *Tracker, proj *Project) {
+			assert.NotEqual(t, session.ID, proj.Session.ID)
+			assert.False(t, tr.IsObserving(proj.Name, msg.Icao))
+			assert.Equal(t, 0, len(tr.sighting))
+		})
+
+		// the old sighting and session were closed at the checkpoint time
+		sighting, err = database.GetSightingByID(sighting.ID)
+		assert.NoError(t, err)
+		assert.NotNil(t, sighting.ClosedAt)
+		project, err := database.GetProject(projCfg.Name)
+		assert.NoError(t, err)
+		session, err = database.GetSessionByIdentifier(project, session.Identifier)
+		assert.NoError(t, err)
+		assert.NotNil(t, session.ClosedAt)
+	})
+}
+
+func TestCheckpointSighting_Phase(t *testing.T) {
+	s := NewSighting("4CA123", time.Now())
+	s.phase = flightPhase{
+		phase:           PhaseDescent,
+		elevation:       5400,
+		haveDescentTop:  true,
+		descentTop:      12000,
+		descentAltitude: 9000,
+	}
+	cs, err := newCheckpointSighting(s)
+	assert.NoError(t, err)
+	// the descent survives a restart, so a touchdown is still a landing
+	tr := &Tracker{}
+	restored, err := tr.newSightingFromCheckpoint(cs)
+	assert.NoError(t, err)
+	assert.Equal(t, s.phase, restored.phase)
+	assert.True(t, restored.phase.sustainedDescent())
+}
@@ -0,0 +1,98 @@
+package tracker
+
+import (
+	"github.com/afk11/airtrack/pkg/pb"
+	"time"
+)
+
+const (
+	// DefaultDedupWindow - identical messages received within this
+	// window (eg, by several receivers) are only processed once
+	DefaultDedupWindow = time.Second
+)
+
+// dedupCache remembers keys for a window of time so repeated
+// messages can be detected. It isn't safe for concurrent use.
+type dedupCache struct {
+	window    time.Duration
+	seen      map[string]dedupEntry
+	lastPurge time.Time
+}
+
+// dedupEntry records when a key was last accepted, and the
+// source it was accepted from.
+type dedupEntry struct {
+	time   time.Time
+	source string
+}
+
+// newDedupCache creates a dedupCache which treats keys
+// seen again within window as duplicates.
+func newDedupCache(window time.Duration) *dedupCache {
+	return &dedupCache{
+		window: window,
+		seen:   make(map[string]dedupEntry),
+	}
+}
+
+// isDuplicate returns true if key was seen within the de-duplication
+// window. Expired keys are purged periodically.
+func (c *dedupCache) isDuplicate(key string, now time.Time) bool {
+	c.purge(now)
+	if e, ok := c.seen[key]; ok && now.Sub(e.time) <= c.window {
+		return true
+	}
+	c.seen[key] = dedupEntry{time: now}
+	return false
+}
+
+// isDuplicateFrom returns true if key was seen within the de-duplication
+// window from a different source. Repeats from the source which first
+// sent the key aren't duplicates, and restart the window.
+func (c *dedupCache) isDuplicateFrom(key string, source string, now time.Time) bool {
+	c.purge(now)
+	if e, ok := c.seen[key]; ok && now.Sub(e.time) <= c.window && e.source != source {
+		return true
+	}
+	c.seen[key] = dedupEntry{time: now, source: source}
+	return false
+}
+
+// purge removes expired keys, at most once per window.
+func (c *dedupCache) purge(now time.Time) {
+	if now.Sub(c.lastPurge) <= c.window {
+		return
+	}
+	for k, e := range c.seen {
+		if now.Sub(e.time) > c.window {
+			delete(c.seen, k)
+		}
+	}
+	c.lastPurge = now
+}
+
+// messageDedupKey returns the key used to detect msg being received
+// by several receivers. It's the ICAO and the raw frame, or an empty
+// string if the message wasn't decoded from a frame.
+func messageDedupKey(msg *pb.Message) string {
+	if len(msg.Raw) == 0 {
+		return ""
+	}
+	return msg.Icao + string(msg.Raw)
+}
+
+// isDuplicateMessage returns true if the same frame was processed from
+// another receiver within opt.DedupWindow. Always false if de-duplication
+// is disabled, or if the message has no raw frame.
+func (t *Tracker) isDuplicateMessage(msg *pb.Message, now time.Time) bool {
+	if t.dedup == nil {
+		return false
+	}
+	key := messageDedupKey(msg)
+	if key == "" {
+		return false
+	}
+	t.dedupMu.Lock()
+	defer t.dedupMu.Unlock()
+	return t.dedup.isDuplicateFrom(key, msg.Source.GetName(), now)
+}
@@ -0,0 +1,63 @@
+package tracker
+
+import (
+	"github.com/afk11/airtrack/pkg/pb"
+	assert "github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestDedupCache(t *testing.T) {
+	now := time.Now()
+	c := newDedupCache(time.Second)
+	assert.False(t, c.isDuplicate("a", now))
+	assert.True(t, c.isDuplicate("a", now.Add(500*time.Millisecond)))
+	assert.False(t, c.isDuplicate("b", now.Add(500*time.Millisecond)))
+	// expired, and purged
+	assert.False(t, c.isDuplicate("a", now.Add(2*time.Second)))
+	_, ok := c.seen["b"]
+	assert.False(t, ok)
+}
+
+func TestDedupCache_From(t *testing.T) {
+	now := time.Now()
+	c := newDedupCache(time.Second)
+	assert.False(t, c.isDuplicateFrom("a", "home", now))
+	assert.True(t, c.isDuplicateFrom("a", "roof", now.Add(100*time.Millisecond)))
+	// repeated by the same receiver
+	assert.False(t, c.isDuplicateFrom("a", "home", now.Add(200*time.Millisecond)))
+	assert.False(t, c.isDuplicateFrom("a", "home", now.Add(300*time.Millisecond)))
+	assert.True(t, c.isDuplicateFrom("a", "roof", now.Add(400*time.Millisecond)))
+	// expired
+	assert.False(t, c.isDuplicateFrom("a", "roof", now.Add(2*time.Second)))
+}
+
+func TestMessageDedupKey(t *testing.T) {
+	raw := []byte{0x5d, 0x4c, 0xa1, 0x23, 0x58, 0x3b, 0x1e}
+	home := &pb.Message{
+		Source: &pb.Source{Name: "home", Type: pb.Source_BeastServer},
+		Signal: &pb.Signal{Rssi: -12.5},
+		Time:   1000,
+		Icao:   "4CA123", AltitudeBarometric: "5000", Raw: raw,
+	}
+	other := &pb.Message{
+		Source: &pb.Source{Name: "roof", Type: pb.Source_BeastServer},
+		Icao:   "4CA123", AltitudeBarometric: "5000", Raw: raw,
+	}
+	assert.NotEqual(t, "", messageDedupKey(home))
+	assert.Equal(t, messageDedupKey(home), messageDedupKey(other))
+
+	// a different frame
+	other.Raw = []byte{0x5d, 0x4c, 0xa1, 0x23, 0x58, 0x3b, 0x1f}
+	assert.NotEqual(t, messageDedupKey(home), messageDedupKey(other))
+	// the same frame for another aircraft
+	other.Raw, other.Icao = raw, "4CA124"
+	assert.NotEqual(t, messageDedupKey(home), messageDedupKey(other))
+
+	// messages without a frame are never duplicates
+	adsbx := &pb.Message{
+		Source: &pb.Source{Name: "adsbx", Type: pb.Source_AdsbExchange},
+		Icao:   "4CA123", AltitudeBarometric: "5000",
+	}
+	assert.Equal(t, "", messageDedupKey(adsbx))
+}
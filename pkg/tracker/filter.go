@@ -0,0 +1,299 @@
+package tracker
+
+import (
+	"github.com/afk11/airtrack/pkg/geo"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/afk11/airtrack/pkg/watchlist"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/common"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter/functions"
+	"github.com/google/cel-go/parser"
+	"github.com/pkg/errors"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const (
+	// distanceKmFunction - distance_km(lat, lon) returns the distance
+	// in kilometers between the aircraft and (lat, lon)
+	distanceKmFunction = "distance_km"
+	// isNightFunction - is_night() returns true if it's night at the
+	// aircraft's location
+	isNightFunction = "is_night"
+	// icaoInRangeFunction - icao_in_range(icao, from, to) returns true
+	// if the hex ICAO is within the inclusive range [from, to]
+	icaoInRangeFunction = "icao_in_range"
+	// watchlistFunction - watchlist(name) returns the named watchlist as
+	// a map of ICAO hexes and registrations to their label
+	watchlistFunction = "watchlist"
+	// watchlistLabelFunction - watchlist_label(key) returns the label of
+	// the first watchlist containing the ICAO hex or registration
+	watchlistLabelFunction = "watchlist_label"
+)
+
+// watchlistsType is the type of the watchlists variable
+var watchlistsType = types.NewTypeValue("airtrack.Watchlists")
+
+// filterWatchlists wraps the tracker's watchlists so they can be
+// passed to the watchlist functions through the activation. w
+// is nil if no watchlists are configured.
+type filterWatchlists struct {
+	w *watchlist.Watchlists
+}
+
+// ConvertToNative implements ref.Val
+func (f filterWatchlists) ConvertToNative(typeDesc reflect.Type) (interface{}, error) {
+	return nil, errors.Errorf("watchlists cannot be converted to %v", typeDesc)
+}
+
+// ConvertToType implements ref.Val
+func (f filterWatchlists) ConvertToType(typeVal ref.Type) ref.Val {
+	if typeVal == types.TypeType {
+		return watchlistsType
+	}
+	return types.NewErr("watchlists cannot be converted to %s", typeVal.TypeName())
+}
+
+// Equal implements ref.Val
+func (f filterWatchlists) Equal(other ref.Val) ref.Val {
+	o, ok := other.(filterWatchlists)
+	return types.Bool(ok && o.w == f.w)
+}
+
+// Type implements ref.Val
+func (f filterWatchlists) Type() ref.Type {
+	return watchlistsType
+}
+
+// Value implements ref.Val
+func (f filterWatchlists) Value() interface{} {
+	return f.w
+}
+
+// filterMacros rewrite calls to the helpers which depend on
+// the aircraft's state, so they can be evaluated by functions
+// which have no access to the activation:
+//
+//	distance_km(lat, lon) => distance_km(state, lat, lon)
+//	is_night()            => is_night(state, now)
+//	watchlist(name)       => watchlist(watchlists, name)
+//	watchlist_label(key)  => watchlist_label(watchlists, key)
+var filterMacros = []parser.Macro{
+	parser.NewGlobalMacro(distanceKmFunction, 2, func(eh parser.ExprHelper, target *exprpb.Expr, args []*exprpb.Expr) (*exprpb.Expr, *common.Error) {
+		return eh.GlobalCall(distanceKmFunction, eh.Ident("state"), args[0], args[1]), nil
+	}),
+	parser.NewGlobalMacro(isNightFunction, 0, func(eh parser.ExprHelper, target *exprpb.Expr, args []*exprpb.Expr) (*exprpb.Expr, *common.Error) {
+		return eh.GlobalCall(isNightFunction, eh.Ident("state"), eh.Ident("now")), nil
+	}),
+	parser.NewGlobalMacro(watchlistFunction, 1, func(eh parser.ExprHelper, target *exprpb.Expr, args []*exprpb.Expr) (*exprpb.Expr, *common.Error) {
+		return eh.GlobalCall(watchlistFunction, eh.Ident("watchlists"), args[0]), nil
+	}),
+	parser.NewGlobalMacro(watchlistLabelFunction, 1, func(eh parser.ExprHelper, target *exprpb.Expr, args []*exprpb.Expr) (*exprpb.Expr, *common.Error) {
+		return eh.GlobalCall(watchlistLabelFunction, eh.Ident("watchlists"), args[0]), nil
+	}),
+}
+
+// filterDeclarations declares the variables and functions
+// available to filters in addition to msg and state.
+var filterDeclarations = []*exprpb.Decl{
+	decls.NewIdent("sighting",
+		decls.NewObjectType("airtrack.Sighting"),
+		nil),
+	decls.NewVar("now", decls.Timestamp),
+	decls.NewVar("hour", decls.Int),
+	decls.NewVar("watchlists", decls.Dyn),
+	decls.NewVar("AdsbExchangeSource", decls.Int),
+	decls.NewVar("BeastSource", decls.Int),
+	decls.NewVar("SbsSource", decls.Int),
+	decls.NewVar("AvrSource", decls.Int),
+	decls.NewVar("AircraftJSONSource", decls.Int),
+	decls.NewVar("UatSource", decls.Int),
+	decls.NewVar("UnknownProvenance", decls.Int),
+	decls.NewVar("AdsbProvenance", decls.Int),
+	decls.NewVar("MlatProvenance", decls.Int),
+	decls.NewVar("TisbProvenance", decls.Int),
+	decls.NewVar("AdsrProvenance", decls.Int),
+	decls.NewVar("ModeSProvenance", decls.Int),
+	decls.NewFunction(distanceKmFunction,
+		decls.NewOverload("distance_km_state_double_double",
+			[]*exprpb.Type{decls.NewObjectType("airtrack.State"), decls.Double, decls.Double},
+			decls.Double)),
+	decls.NewFunction(isNightFunction,
+		decls.NewOverload("is_night_state_timestamp",
+			[]*exprpb.Type{decls.NewObjectType("airtrack.State"), decls.Timestamp},
+			decls.Bool)),
+	decls.NewFunction(icaoInRangeFunction,
+		decls.NewOverload("icao_in_range_string_string_string",
+			[]*exprpb.Type{decls.String, decls.String, decls.String},
+			decls.Bool)),
+	decls.NewFunction(watchlistFunction,
+		decls.NewOverload("watchlist_dyn_string",
+			[]*exprpb.Type{decls.Dyn, decls.String},
+			decls.NewMapType(decls.String, decls.String))),
+	decls.NewFunction(watchlistLabelFunction,
+		decls.NewOverload("watchlist_label_dyn_string",
+			[]*exprpb.Type{decls.Dyn, decls.String},
+			decls.String)),
+}
+
+// filterFunctions are the implementations of the
+// functions in filterDeclarations
+var filterFunctions = []*functions.Overload{
+	{
+		Operator: distanceKmFunction,
+		Function: distanceKm,
+	},
+	{
+		Operator: isNightFunction,
+		Binary:   isNight,
+	},
+	{
+		Operator: icaoInRangeFunction,
+		Function: icaoInRange,
+	},
+	{
+		Operator: watchlistFunction,
+		Binary:   watchlistEntries,
+	},
+	{
+		Operator: watchlistLabelFunction,
+		Binary:   watchlistLabel,
+	},
+}
+
+// newFilterEnv creates the CEL environment for project filters
+// and rules. Expressions can use the msg, state and sighting variables,
+// the current time, the source type constants and the helper functions.
+func newFilterEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Types(&pb.Source{}, &pb.Message{}, &pb.State{}, &pb.Sighting{}),
+		cel.Macros(filterMacros...),
+		cel.Declarations(
+			decls.NewIdent("msg",
+				decls.NewObjectType("airtrack.Message"),
+				nil),
+			decls.NewIdent("state",
+				decls.NewObjectType("airtrack.State"),
+				nil),
+		),
+		cel.Declarations(filterDeclarations...))
+}
+
+// newFilterProgram creates a program for the checked expression
+// with the helper functions.
+func newFilterProgram(env *cel.Env, checked *cel.Ast) (cel.Program, error) {
+	return env.Program(checked, cel.Functions(filterFunctions...))
+}
+
+// distanceKm implements distance_km(state, lat, lon). If the aircraft
+// has no location, +Inf is returned so comparisons are false.
+func distanceKm(args ...ref.Val) ref.Val {
+	if len(args) != 3 {
+		return types.NoSuchOverloadErr()
+	}
+	state, ok := args[0].Value().(*pb.State)
+	lat, latOk := args[1].(types.Double)
+	lon, lonOk := args[2].(types.Double)
+	if !ok || !latOk || !lonOk {
+		return types.NoSuchOverloadErr()
+	} else if !state.HaveLocation {
+		return types.Double(math.Inf(1))
+	}
+	return types.Double(geo.Distance(state.Latitude, state.Longitude, float64(lat), float64(lon)) / 1000)
+}
+
+// isNight implements is_night(state, now). If the aircraft
+// has no location, false is returned.
+func isNight(lhs ref.Val, rhs ref.Val) ref.Val {
+	state, ok := lhs.Value().(*pb.State)
+	ts, tsOk := rhs.(types.Timestamp)
+	if !ok || !tsOk {
+		return types.NoSuchOverloadErr()
+	} else if !state.HaveLocation {
+		return types.False
+	}
+	now, err := ptypes.Timestamp(ts.Timestamp)
+	if err != nil {
+		return types.NewErr("is_night: %v", err)
+	}
+	return types.Bool(geo.IsNight(now, state.Latitude, state.Longitude))
+}
+
+// icaoInRange implements icao_in_range(icao, from, to). False is
+// returned if any of the arguments are not valid hex.
+func icaoInRange(args ...ref.Val) ref.Val {
+	if len(args) != 3 {
+		return types.NoSuchOverloadErr()
+	}
+	var values [3]uint64
+	for i := range args {
+		s, ok := args[i].(types.String)
+		if !ok {
+			return types.NoSuchOverloadErr()
+		}
+		v, err := strconv.ParseUint(string(s), 16, 32)
+		if err != nil {
+			return types.False
+		}
+		values[i] = v
+	}
+	return types.Bool(values[0] >= values[1] && values[0] <= values[2])
+}
+
+// watchlistEntries implements watchlist(watchlists, name). An empty
+// map is returned if the watchlist doesn't exist.
+func watchlistEntries(lhs ref.Val, rhs ref.Val) ref.Val {
+	w, ok := lhs.(filterWatchlists)
+	name, nameOk := rhs.(types.String)
+	if !ok || !nameOk {
+		return types.NoSuchOverloadErr()
+	} else if w.w == nil {
+		return types.NewStringStringMap(types.DefaultTypeAdapter, map[string]string{})
+	}
+	return types.NewStringStringMap(types.DefaultTypeAdapter, w.w.Get(string(name)))
+}
+
+// watchlistLabel implements watchlist_label(watchlists, key). An
+// empty string is returned if no watchlist contains key.
+func watchlistLabel(lhs ref.Val, rhs ref.Val) ref.Val {
+	w, ok := lhs.(filterWatchlists)
+	key, keyOk := rhs.(types.String)
+	if !ok || !keyOk {
+		return types.NoSuchOverloadErr()
+	} else if w.w == nil {
+		return types.String("")
+	}
+	label, _ := w.w.Label(string(key))
+	return types.String(label)
+}
+
+// newFilterSighting creates the sighting variable from the project's
+// observation, which must be locked by the caller. observation can be
+// nil if the aircraft isn't sighted yet, in which case the sighting
+// begins now.
+func newFilterSighting(observation *ProjectObservation, now time.Time) (*pb.Sighting, error) {
+	firstSeen := now
+	sighting := &pb.Sighting{}
+	if observation != nil {
+		firstSeen = observation.firstSeen
+		if observation.origin != nil && observation.origin.ok {
+			sighting.HaveOrigin = true
+			sighting.Origin = observation.origin.airport.Code
+			sighting.OriginName = observation.origin.airport.Name
+		}
+	}
+	var err error
+	sighting.FirstSeen, err = ptypes.TimestampProto(firstSeen)
+	if err != nil {
+		return nil, errors.Wrapf(err, "converting first seen time")
+	}
+	sighting.Duration = ptypes.DurationProto(now.Sub(firstSeen))
+	return sighting, nil
+}
@@ -0,0 +1,148 @@
+package tracker
+
+import (
+	"github.com/afk11/airtrack/pkg/config"
+	"github.com/afk11/airtrack/pkg/geo"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/afk11/airtrack/pkg/watchlist"
+	assert "github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// evalFilter initializes a project with filter, and
+// evaluates it against the provided inputs
+func evalFilter(t *testing.T, filter string, state *pb.State, observation *ProjectObservation, now time.Time) bool {
+	p, err := InitProject(config.Project{Name: "filtertest", Filter: filter})
+	assert.NoError(t, err)
+	sighting, err := newFilterSighting(observation, now)
+	assert.NoError(t, err)
+	passed, err := checkIfPassesFilter(p.Program, &pb.Message{}, state, sighting, nil, now)
+	assert.NoError(t, err)
+	return passed
+}
+
+func TestFilter_DistanceKm(t *testing.T) {
+	now := time.Now()
+	dublin := &pb.State{HaveLocation: true, Latitude: 53.421333, Longitude: -6.270075}
+	assert.True(t, evalFilter(t, `distance_km(53.35, -6.26) < 10.0`, dublin, nil, now))
+	assert.False(t, evalFilter(t, `distance_km(51.47, -0.45) < 100.0`, dublin, nil, now))
+	// no location, never close
+	assert.False(t, evalFilter(t, `distance_km(53.35, -6.26) < 10.0`, &pb.State{}, nil, now))
+	assert.True(t, evalFilter(t, `!(distance_km(53.35, -6.26) < 10.0)`, &pb.State{}, nil, now))
+}
+
+func TestFilter_IsNight(t *testing.T) {
+	dublin := &pb.State{HaveLocation: true, Latitude: 53.421333, Longitude: -6.270075}
+	midnight := time.Date(2020, time.December, 20, 0, 0, 0, 0, time.UTC)
+	noon := time.Date(2020, time.December, 20, 12, 0, 0, 0, time.UTC)
+	assert.True(t, evalFilter(t, `is_night()`, dublin, nil, midnight))
+	assert.False(t, evalFilter(t, `is_night()`, dublin, nil, noon))
+	assert.False(t, evalFilter(t, `is_night()`, &pb.State{}, nil, midnight))
+}
+
+func TestFilter_Time(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	now := time.Date(2020, time.December, 20, 9, 30, 0, 0, loc)
+	state := &pb.State{}
+	assert.True(t, evalFilter(t, `hour == 9`, state, nil, now))
+	assert.True(t, evalFilter(t, `hour == 14`, state, nil, now.UTC()))
+	assert.True(t, evalFilter(t, `now == timestamp("2020-12-20T14:30:00Z")`, state, nil, now))
+}
+
+func TestFilter_Sighting(t *testing.T) {
+	now := time.Now()
+	state := &pb.State{}
+	// new sightings begin now
+	assert.True(t, evalFilter(t, `sighting.Duration == duration("0s") && !sighting.HaveOrigin`, state, nil, now))
+
+	o := &ProjectObservation{
+		firstSeen: now.Add(-time.Minute * 10),
+		origin: &GeocodeLocation{
+			ok:      true,
+			airport: geo.AirportRecord{Code: "EIDW", Name: "Dublin Airport"},
+		},
+	}
+	assert.True(t, evalFilter(t, `sighting.Duration > duration("9m")`, state, o, now))
+	assert.True(t, evalFilter(t, `sighting.FirstSeen < now - duration("9m")`, state, o, now))
+	assert.True(t, evalFilter(t, `sighting.HaveOrigin && sighting.Origin == "EIDW" && sighting.OriginName == "Dublin Airport"`, state, o, now))
+}
+
+func TestFilter_IcaoInRange(t *testing.T) {
+	now := time.Now()
+	assert.True(t, evalFilter(t, `icao_in_range(state.Icao, "A00000", "AFFFFF")`, &pb.State{Icao: "AE1234"}, nil, now))
+	assert.True(t, evalFilter(t, `icao_in_range(state.Icao, "4CA000", "4CAFFF")`, &pb.State{Icao: "4ca123"}, nil, now))
+	assert.False(t, evalFilter(t, `icao_in_range(state.Icao, "A00000", "AFFFFF")`, &pb.State{Icao: "4CA123"}, nil, now))
+	// invalid hex never matches
+	assert.False(t, evalFilter(t, `icao_in_range(state.Icao, "A00000", "AFFFFF")`, &pb.State{Icao: "~12345"}, nil, now))
+}
+
+func TestFilter_Provenance(t *testing.T) {
+	eval := func(filter string, msg *pb.Message, state *pb.State) bool {
+		p, err := InitProject(config.Project{Name: "filtertest", Filter: filter})
+		assert.NoError(t, err)
+		passed, err := checkIfPassesFilter(p.Program, msg, state, nil, nil, time.Now())
+		assert.NoError(t, err)
+		return passed
+	}
+	mlat := &pb.Message{Provenance: pb.Provenance_MLAT}
+	adsb := &pb.Message{Provenance: pb.Provenance_ADSB}
+	assert.True(t, eval(`msg.Provenance == MlatProvenance`, mlat, &pb.State{}))
+	assert.False(t, eval(`msg.Provenance == MlatProvenance`, adsb, &pb.State{}))
+	assert.True(t, eval(`msg.Provenance == UnknownProvenance`, &pb.Message{}, &pb.State{}))
+	state := &pb.State{HaveLocation: true, LocationProvenance: pb.Provenance_TISB}
+	assert.True(t, eval(`state.HaveLocation && state.LocationProvenance == TisbProvenance`, adsb, state))
+	assert.False(t, eval(`state.LocationProvenance in [AdsbProvenance, AdsrProvenance, ModeSProvenance]`, adsb, state))
+}
+
+func TestFilter_TypeErrors(t *testing.T) {
+	for _, filter := range []string{
+		`distance_km(53, -6) < 10.0`,
+		`distance_km(53.0, -6.0, 1.0) < 10.0`,
+		`is_night(1)`,
+		`icao_in_range(state.Icao, 1, 2)`,
+		`sighting.Destination == ""`,
+		`hour == "9"`,
+		`watchlist(1)`,
+		`watchlist_label("4CA123") == 1`,
+	} {
+		_, err := InitProject(config.Project{Name: "filtertest", Filter: filter})
+		assert.Error(t, err, filter)
+		assert.Contains(t, err.Error(), "type errors in filter expression", filter)
+	}
+}
+
+func TestFilter_Watchlist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watchlist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "gov.csv")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("icao,registration,label\n4CA123,EI-GOV,government\n"), 0644))
+	w := watchlist.NewWatchlists()
+	assert.NoError(t, w.Add("gov", file, ""))
+
+	eval := func(filter string, state *pb.State, w *watchlist.Watchlists) bool {
+		p, err := InitProject(config.Project{Name: "filtertest", Filter: filter})
+		assert.NoError(t, err)
+		passed, err := checkIfPassesFilter(p.Program, &pb.Message{}, state, nil, w, time.Now())
+		assert.NoError(t, err)
+		return passed
+	}
+	gov := &pb.State{Icao: "4CA123"}
+	other := &pb.State{Icao: "4CA999", Info: &pb.AircraftInfo{Registration: "EI-GOV"}}
+	assert.True(t, eval(`state.Icao in watchlist("gov")`, gov, w))
+	assert.False(t, eval(`state.Icao in watchlist("gov")`, other, w))
+	assert.True(t, eval(`has(state.Info) && state.Info.Registration in watchlist("gov")`, other, w))
+	assert.True(t, eval(`watchlist("gov")[state.Icao] == "government"`, gov, w))
+	assert.True(t, eval(`watchlist_label(state.Icao) == "government"`, gov, w))
+	assert.True(t, eval(`watchlist_label(state.Icao) == ""`, other, w))
+	// unknown watchlists are empty
+	assert.False(t, eval(`state.Icao in watchlist("unknown")`, gov, w))
+	// as are all watchlists if none are configured
+	assert.False(t, eval(`state.Icao in watchlist("gov")`, gov, nil))
+	assert.True(t, eval(`watchlist_label(state.Icao) == ""`, gov, nil))
+}
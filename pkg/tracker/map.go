@@ -239,6 +239,9 @@ func (j *JSONAircraft) UpdateWithState(state *pb.State) {
 		j.Category = state.Category
 	}
 	j.Squawk = state.Squawk
+	if state.HaveEmergency {
+		j.Emergency = state.Emergency
+	}
 	j.MagneticHeading = state.Track
 	j.Track = state.Track
 	j.BarometricAltitude = state.AltitudeBarometric
@@ -11,11 +11,26 @@ var (
 		Name:      "messages_total",
 		Help:      "The total number of processed messages",
 	})
+	msgsDuplicate = promauto.NewCounter(prometheus.CounterOpts{
+		Subsystem: "airtrack",
+		Name:      "messages_duplicate",
+		Help:      "The total number of messages dropped as duplicates received by another receiver",
+	})
 	msgsFiltered = promauto.NewCounter(prometheus.CounterOpts{
 		Subsystem: "airtrack",
 		Name:      "messages_filtered",
 		Help:      "The total number of filtered messages",
 	})
+	adsbxRateLimited = promauto.NewCounter(prometheus.CounterOpts{
+		Subsystem: "airtrack",
+		Name:      "adsbx_rate_limited",
+		Help:      "The total number of ADSB Exchange requests rejected with 429 Too Many Requests",
+	})
+	positionsRejected = promauto.NewCounter(prometheus.CounterOpts{
+		Subsystem: "airtrack",
+		Name:      "positions_rejected",
+		Help:      "The total number of positions rejected as implausible",
+	})
 	aircraftCountVec = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Subsystem: "airtrack",
@@ -0,0 +1,149 @@
+package tracker
+
+import (
+	"github.com/afk11/airtrack/pkg/geo"
+	"github.com/afk11/airtrack/pkg/pb"
+	"math"
+	"time"
+)
+
+const (
+	// orbitWindow - positions older than this are
+	// forgotten by the orbit detector
+	orbitWindow = time.Minute * 10
+	// orbitMaxPoints - maximum number of positions
+	// kept by the orbit detector
+	orbitMaxPoints = 1200
+	// orbitMaxRadius - the most recent full circle must be within
+	// this distance (in meters) of its centre to be an orbit. Large
+	// enough to include holding patterns.
+	orbitMaxRadius = 10000.0
+	// orbitTurn - cumulative change in track (in degrees)
+	// required for a full circle
+	orbitTurn = 360.0
+)
+
+type (
+	// orbitPoint is a position in the orbit detector's window
+	orbitPoint struct {
+		time time.Time
+		lat  float64
+		lon  float64
+		// turn is the change in track (in degrees, positive
+		// is clockwise) since the previous point
+		turn float64
+	}
+	// orbitDetector recognizes aircraft circling or holding over
+	// an area. It keeps a rolling window of positions, and the
+	// aircraft is orbiting while the track changed by a full circle
+	// within orbitMaxRadius of the centre of those positions.
+	orbitDetector struct {
+		points    []orbitPoint
+		haveTrack bool
+		track     float64
+
+		orbiting bool
+		// centre and radius (in meters) of the most recent circle
+		latitude  float64
+		longitude float64
+		radius    float64
+	}
+)
+
+// normalizeTurn maps a change in track to (-180, 180]
+func normalizeTurn(turn float64) float64 {
+	turn = math.Mod(turn, 360)
+	if turn > 180 {
+		turn -= 360
+	} else if turn <= -180 {
+		turn += 360
+	}
+	return turn
+}
+
+// reset forgets all positions. It returns true if the
+// aircraft was orbiting.
+func (o *orbitDetector) reset() bool {
+	wasOrbiting := o.orbiting
+	*o = orbitDetector{}
+	return wasOrbiting
+}
+
+// update adds the aircraft's latest position and re-evaluates whether
+// it's orbiting. The track is used if known, otherwise it's the bearing
+// from the previous position. It returns true if orbiting changed.
+func (o *orbitDetector) update(now time.Time, state *pb.State) bool {
+	if state.IsOnGround || !state.HaveLocation {
+		return o.reset()
+	}
+
+	haveTrack := state.HaveTrack
+	track := state.Track
+	if !haveTrack && len(o.points) > 0 {
+		last := o.points[len(o.points)-1]
+		if last.lat == state.Latitude && last.lon == state.Longitude {
+			return false
+		}
+		haveTrack = true
+		track = geo.Bearing(last.lat, last.lon, state.Latitude, state.Longitude)
+	}
+	p := orbitPoint{time: now, lat: state.Latitude, lon: state.Longitude}
+	if haveTrack && o.haveTrack {
+		p.turn = normalizeTurn(track - o.track)
+	}
+	if haveTrack {
+		o.haveTrack = true
+		o.track = track
+	}
+
+	o.points = append(o.points, p)
+	start := 0
+	for start < len(o.points) && (now.Sub(o.points[start].time) > orbitWindow || len(o.points)-start > orbitMaxPoints) {
+		start++
+	}
+	o.points = o.points[start:]
+
+	wasOrbiting := o.orbiting
+	o.orbiting = o.detect()
+	return o.orbiting != wasOrbiting
+}
+
+// detect walks back from the latest position until the track has
+// changed by a full circle, and checks those positions are within
+// orbitMaxRadius of their centre.
+func (o *orbitDetector) detect() bool {
+	var turn float64
+	first := -1
+	for i := len(o.points) - 1; i > 0; i-- {
+		turn += o.points[i].turn
+		if math.Abs(turn) >= orbitTurn {
+			first = i - 1
+			break
+		}
+	}
+	if first == -1 {
+		return false
+	}
+
+	circle := o.points[first:]
+	var lat, lon float64
+	for _, p := range circle {
+		lat += p.lat
+		lon += p.lon
+	}
+	lat /= float64(len(circle))
+	lon /= float64(len(circle))
+
+	var total float64
+	for _, p := range circle {
+		d := geo.Distance(lat, lon, p.lat, p.lon)
+		if d > orbitMaxRadius {
+			return false
+		}
+		total += d
+	}
+	o.latitude = lat
+	o.longitude = lon
+	o.radius = total / float64(len(circle))
+	return true
+}
@@ -0,0 +1,105 @@
+package tracker
+
+import (
+	"github.com/afk11/airtrack/pkg/geo"
+	"github.com/afk11/airtrack/pkg/pb"
+	assert "github.com/stretchr/testify/require"
+	"math"
+	"testing"
+	"time"
+)
+
+// circlePosition returns the position at angle (in degrees, clockwise
+// from north) on a circle of radius meters around lat, lon, and the
+// track of an aircraft flying clockwise around it.
+func circlePosition(lat, lon, radius, angle float64) (float64, float64, float64) {
+	rad := angle * math.Pi / 180
+	dLat := radius * math.Cos(rad) / 111320
+	dLon := radius * math.Sin(rad) / (111320 * math.Cos(lat*math.Pi/180))
+	return lat + dLat, lon + dLon, math.Mod(angle+90, 360)
+}
+
+func TestOrbitDetector(t *testing.T) {
+	t.Run("circling", func(t *testing.T) {
+		o := orbitDetector{}
+		now := time.Now()
+		var changed int
+		for angle := 0.0; angle <= 400; angle += 10 {
+			lat, lon, track := circlePosition(53.35, -6.26, 1000, angle)
+			if o.update(now, &pb.State{HaveLocation: true, Latitude: lat, Longitude: lon, HaveTrack: true, Track: track}) {
+				changed++
+			}
+			now = now.Add(time.Second * 5)
+		}
+		assert.Equal(t, 1, changed)
+		assert.True(t, o.orbiting)
+		assert.InDelta(t, 0, geo.Distance(53.35, -6.26, o.latitude, o.longitude), 100)
+		assert.InDelta(t, 1000, o.radius, 50)
+	})
+	t.Run("without track", func(t *testing.T) {
+		o := orbitDetector{}
+		now := time.Now()
+		for angle := 0.0; angle <= 400; angle += 10 {
+			lat, lon, _ := circlePosition(53.35, -6.26, 1000, angle)
+			o.update(now, &pb.State{HaveLocation: true, Latitude: lat, Longitude: lon})
+			now = now.Add(time.Second * 5)
+		}
+		assert.True(t, o.orbiting)
+	})
+	t.Run("straight", func(t *testing.T) {
+		o := orbitDetector{}
+		now := time.Now()
+		for i := 0; i < 100; i++ {
+			// alternating small corrections cancel out
+			track := 90.0
+			if i%2 == 0 {
+				track = 95
+			}
+			o.update(now, &pb.State{HaveLocation: true, Latitude: 53.35, Longitude: -6.26 + float64(i)*0.01, HaveTrack: true, Track: track})
+			now = now.Add(time.Second * 5)
+		}
+		assert.False(t, o.orbiting)
+	})
+	t.Run("wide turn", func(t *testing.T) {
+		o := orbitDetector{}
+		now := time.Now()
+		for angle := 0.0; angle <= 400; angle += 10 {
+			lat, lon, track := circlePosition(53.35, -6.26, 20000, angle)
+			o.update(now, &pb.State{HaveLocation: true, Latitude: lat, Longitude: lon, HaveTrack: true, Track: track})
+			now = now.Add(time.Second * 5)
+		}
+		assert.False(t, o.orbiting)
+	})
+	t.Run("leaving", func(t *testing.T) {
+		o := orbitDetector{}
+		now := time.Now()
+		for angle := 0.0; angle <= 400; angle += 10 {
+			lat, lon, track := circlePosition(53.35, -6.26, 1000, angle)
+			o.update(now, &pb.State{HaveLocation: true, Latitude: lat, Longitude: lon, HaveTrack: true, Track: track})
+			now = now.Add(time.Second * 5)
+		}
+		assert.True(t, o.orbiting)
+		// heading east, away from the orbit
+		for i := 1; o.orbiting && i < 100; i++ {
+			o.update(now, &pb.State{HaveLocation: true, Latitude: 53.35, Longitude: -6.26 + float64(i)*0.01, HaveTrack: true, Track: 90})
+			now = now.Add(time.Second * 5)
+		}
+		assert.False(t, o.orbiting)
+	})
+	t.Run("expires", func(t *testing.T) {
+		o := orbitDetector{}
+		now := time.Now()
+		for angle := 0.0; angle <= 400; angle += 10 {
+			lat, lon, track := circlePosition(53.35, -6.26, 1000, angle)
+			o.update(now, &pb.State{HaveLocation: true, Latitude: lat, Longitude: lon, HaveTrack: true, Track: track})
+			now = now.Add(time.Minute)
+		}
+		assert.False(t, o.orbiting)
+	})
+	t.Run("landing resets", func(t *testing.T) {
+		o := orbitDetector{orbiting: true, points: []orbitPoint{{}}}
+		assert.True(t, o.update(time.Now(), &pb.State{IsOnGround: true}))
+		assert.False(t, o.orbiting)
+		assert.Equal(t, 0, len(o.points))
+	})
+}
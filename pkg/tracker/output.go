@@ -0,0 +1,262 @@
+package tracker
+
+import (
+	"bytes"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// BeastOutputFormat - output server format for BEAST binary frames
+	BeastOutputFormat = "beast"
+	// SbsOutputFormat - output server format for SBS (BaseStation) CSV
+	SbsOutputFormat = "sbs"
+
+	// DefaultOutputDedupWindow - identical messages received within
+	// this window (eg, by several receivers) are only sent once
+	DefaultOutputDedupWindow = time.Second
+	// DefaultOutputBufferSize - the number of messages buffered for
+	// a client before it's considered too slow and is disconnected
+	DefaultOutputBufferSize = 1024
+	// outputWriteTimeout - write deadline for output clients
+	outputWriteTimeout = time.Second * 10
+)
+
+type (
+	// OutputFilter returns true if messages about an
+	// aircraft should be sent to an OutputServer
+	OutputFilter func(icao string) bool
+
+	// outputClient is a single connection to an OutputServer.
+	// data is never closed, done is closed when the client
+	// should be disconnected.
+	outputClient struct {
+		conn      net.Conn
+		data      chan []byte
+		done      chan struct{}
+		closeOnce sync.Once
+	}
+
+	// OutputServer listens for TCP connections and writes
+	// messages to clients in BEAST or SBS format.
+	OutputServer struct {
+		format     string
+		addr       string
+		filter     OutputFilter
+		listener   net.Listener
+		mu         sync.Mutex
+		clients    map[*outputClient]struct{}
+		bufferSize int
+		wg         sync.WaitGroup
+	}
+
+	// Output de-duplicates the merged stream of messages from all
+	// receivers and writes it to OutputServers. BEAST frames are written
+	// by producers decoding with readsb, and SBS messages are written by
+	// the Tracker once messages have been processed.
+	Output struct {
+		mu      sync.Mutex
+		dedup   *dedupCache
+		servers []*OutputServer
+	}
+)
+
+// close signals the client's writer to disconnect. Safe to
+// call more than once.
+func (c *outputClient) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+// NewOutputServer creates an OutputServer listening on addr. format
+// must be BeastOutputFormat or SbsOutputFormat. If filter is not nil,
+// only messages about aircraft it accepts are sent.
+func NewOutputServer(format string, addr string, filter OutputFilter) (*OutputServer, error) {
+	if format != BeastOutputFormat && format != SbsOutputFormat {
+		return nil, errors.Errorf("unsupported output format: %s", format)
+	}
+	return &OutputServer{
+		format:     format,
+		addr:       addr,
+		filter:     filter,
+		clients:    make(map[*outputClient]struct{}),
+		bufferSize: DefaultOutputBufferSize,
+	}, nil
+}
+
+// Format returns the format of the server
+func (s *OutputServer) Format() string {
+	return s.format
+}
+
+// Addr returns the address the server is listening on.
+// Only valid after Start.
+func (s *OutputServer) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Start begins listening and accepting connections
+func (s *OutputServer) Start() error {
+	l, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return errors.Wrapf(err, "listening on %s", s.addr)
+	}
+	s.listener = l
+	s.wg.Add(1)
+	go s.accept()
+	return nil
+}
+
+// accept is a goroutine which accepts connections until
+// the listener is closed.
+func (s *OutputServer) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		c := &outputClient{
+			conn: conn,
+			data: make(chan []byte, s.bufferSize),
+			done: make(chan struct{}),
+		}
+		s.mu.Lock()
+		s.clients[c] = struct{}{}
+		s.mu.Unlock()
+		log.Debugf("%s output: client connected from %s", s.format, conn.RemoteAddr())
+		s.wg.Add(1)
+		go s.write(c)
+	}
+}
+
+// write is a goroutine which writes data to the client until
+// it's closed or a write fails.
+func (s *OutputServer) write(c *outputClient) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.mu.Unlock()
+		_ = c.conn.Close()
+		log.Debugf("%s output: client %s disconnected", s.format, c.conn.RemoteAddr())
+	}()
+	for {
+		select {
+		case data := <-c.data:
+			err := c.conn.SetWriteDeadline(time.Now().Add(outputWriteTimeout))
+			if err != nil {
+				return
+			}
+			_, err = c.conn.Write(data)
+			if err != nil {
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// send writes data to every client if the filter accepts
+// icao. Clients which aren't keeping up are disconnected.
+func (s *OutputServer) send(icao string, data []byte) {
+	if s.filter != nil && !s.filter(icao) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		select {
+		case c.data <- data:
+		default:
+			c.close()
+		}
+	}
+}
+
+// Stop closes the listener and disconnects all clients
+func (s *OutputServer) Stop() {
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	s.mu.Lock()
+	for c := range s.clients {
+		c.close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// NewOutput creates an Output. Messages are de-duplicated
+// if they're received again within window.
+func NewOutput(window time.Duration) *Output {
+	return &Output{
+		dedup: newDedupCache(window),
+	}
+}
+
+// AddServer adds s to the list of servers messages are written to
+func (o *Output) AddServer(s *OutputServer) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.servers = append(o.servers, s)
+}
+
+// WriteBeastFrame writes frame to BEAST output servers. msg is the Mode S
+// message contained in frame, and is used to detect duplicates received
+// by another receiver.
+func (o *Output) WriteBeastFrame(icao string, msg []byte, frame []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.dedup.isDuplicate(BeastOutputFormat+string(msg), time.Now()) {
+		return
+	}
+	for _, s := range o.servers {
+		if s.format == BeastOutputFormat {
+			s.send(icao, frame)
+		}
+	}
+}
+
+// WriteMessage writes msg to SBS output servers. Duplicates
+// received by another receiver are skipped.
+func (o *Output) WriteMessage(msg *pb.Message, msgTime time.Time) {
+	lines := FormatSbsMessage(msg, msgTime)
+	if len(lines) == 0 {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var buf bytes.Buffer
+	for _, line := range lines {
+		// the timestamp fields aren't part of the key
+		if o.dedup.isDuplicate(SbsOutputFormat+sbsDedupKey(line), time.Now()) {
+			continue
+		}
+		buf.WriteString(line)
+		buf.WriteString("\r\n")
+	}
+	if buf.Len() == 0 {
+		return
+	}
+	for _, s := range o.servers {
+		if s.format == SbsOutputFormat {
+			s.send(msg.Icao, buf.Bytes())
+		}
+	}
+}
+
+// Stop stops all output servers
+func (o *Output) Stop() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, s := range o.servers {
+		s.Stop()
+	}
+}
@@ -0,0 +1,84 @@
+package tracker
+
+import (
+	"bufio"
+	"github.com/afk11/airtrack/pkg/pb"
+	assert "github.com/stretchr/testify/require"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func dialOutput(t *testing.T, s *OutputServer) net.Conn {
+	conn, err := net.Dial("tcp", s.Addr().String())
+	assert.NoError(t, err)
+	assert.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	// wait for the server to register the client
+	for i := 0; i < 100; i++ {
+		s.mu.Lock()
+		n := len(s.clients)
+		s.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return conn
+}
+
+func TestOutput(t *testing.T) {
+	_, err := NewOutputServer("json", "127.0.0.1:0", nil)
+	assert.Error(t, err)
+
+	beast, err := NewOutputServer(BeastOutputFormat, "127.0.0.1:0", nil)
+	assert.NoError(t, err)
+	sbs, err := NewOutputServer(SbsOutputFormat, "127.0.0.1:0", func(icao string) bool {
+		return icao == "4CA2D6"
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, beast.Start())
+	assert.NoError(t, sbs.Start())
+
+	o := NewOutput(DefaultOutputDedupWindow)
+	o.AddServer(beast)
+	o.AddServer(sbs)
+	defer o.Stop()
+
+	beastConn := dialOutput(t, beast)
+	defer beastConn.Close()
+	sbsConn := dialOutput(t, sbs)
+	defer sbsConn.Close()
+
+	t.Run("beast", func(t *testing.T) {
+		msg1 := []byte{0x5d, 0x48, 0x40, 0xd6, 0xa2, 0xc5, 0xf1}
+		msg2 := []byte{0x5d, 0x48, 0x40, 0xd6, 0xa2, 0xc5, 0xf2}
+		o.WriteBeastFrame("4840D6", msg1, []byte("frame1"))
+		// received by another receiver
+		o.WriteBeastFrame("4840D6", msg1, []byte("frame2"))
+		o.WriteBeastFrame("4840D6", msg2, []byte("frame3"))
+
+		buf := make([]byte, 12)
+		_, err := io.ReadFull(beastConn, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "frame1frame3", string(buf))
+	})
+
+	t.Run("sbs", func(t *testing.T) {
+		now := time.Date(2020, 10, 18, 12, 0, 0, 0, time.UTC)
+		// filtered out
+		o.WriteMessage(&pb.Message{Icao: "ABCDEF", Squawk: "1234"}, now)
+		o.WriteMessage(&pb.Message{Icao: "4CA2D6", Squawk: "7700"}, now)
+		// duplicate of the previous message
+		o.WriteMessage(&pb.Message{Icao: "4CA2D6", Squawk: "7700"}, now.Add(time.Millisecond))
+		o.WriteMessage(&pb.Message{Icao: "4CA2D6", CallSign: "RYR4GW"}, now)
+
+		r := bufio.NewReader(sbsConn)
+		line, err := r.ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "MSG,6,1,1,4CA2D6,1,2020/10/18,12:00:00.000,2020/10/18,12:00:00.000,,,,,,,,7700,,,,0\r\n", line)
+		line, err = r.ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "MSG,1,1,1,4CA2D6,1,2020/10/18,12:00:00.000,2020/10/18,12:00:00.000,RYR4GW,,,,,,,,,,,\r\n", line)
+	})
+}
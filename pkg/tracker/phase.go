@@ -0,0 +1,185 @@
+package tracker
+
+import (
+	"github.com/afk11/airtrack/pkg/pb"
+)
+
+// FlightPhase is the phase of flight an aircraft is in
+type FlightPhase string
+
+const (
+	// PhaseUnknown - not enough information to determine the phase yet
+	PhaseUnknown FlightPhase = ""
+	// PhaseGround - parked or taxiing
+	PhaseGround FlightPhase = "ground"
+	// PhaseTakeoff - from liftoff until the aircraft is takeoffHeight
+	// above the liftoff altitude, or levels off
+	PhaseTakeoff FlightPhase = "takeoff"
+	// PhaseClimb - climbing faster than levelVerticalRate
+	PhaseClimb FlightPhase = "climb"
+	// PhaseCruise - neither climbing or descending faster than levelVerticalRate
+	PhaseCruise FlightPhase = "cruise"
+	// PhaseDescent - descending faster than levelVerticalRate
+	PhaseDescent FlightPhase = "descent"
+	// PhaseApproach - descending below landingApproachAltitude above the
+	// ground elevation. Level segments don't end the approach, only a
+	// go-around or landing does.
+	PhaseApproach FlightPhase = "approach"
+	// PhaseLanding - from touchdown after an approach or a sustained
+	// descent, until the aircraft slows to taxiSpeed
+	PhaseLanding FlightPhase = "landing"
+	// PhaseGoAround - climbing away from an approach. Ends once the aircraft
+	// leaves landingApproachAltitude or starts another approach.
+	PhaseGoAround FlightPhase = "go_around"
+
+	// levelVerticalRate - aircraft climbing or descending slower
+	// than this rate (in ft/min) are considered level
+	levelVerticalRate int64 = 300
+	// takeoffHeight - height (in ft) above the liftoff
+	// altitude where the takeoff phase ends
+	takeoffHeight int64 = 1500
+	// taxiSpeed - ground speed (in knots) below which
+	// the landing phase ends
+	taxiSpeed float64 = 40
+	// landingDescentHeight - touching down after descending at least
+	// this height (in ft) since the last climb is a landing, even if
+	// the approach wasn't detected
+	landingDescentHeight int64 = 1000
+)
+
+// IsAirborne returns true if the phase is one where the aircraft is flying
+func (p FlightPhase) IsAirborne() bool {
+	switch p {
+	case PhaseTakeoff, PhaseClimb, PhaseCruise, PhaseDescent, PhaseApproach, PhaseGoAround:
+		return true
+	default:
+		return false
+	}
+}
+
+// flightPhase is the flight phase state machine for a sighting. It's
+// driven by the on ground flag, altitude, vertical rate and ground speed.
+type flightPhase struct {
+	phase FlightPhase
+	// liftoffAltitude - the first altitude seen in the takeoff phase
+	haveLiftoffAltitude bool
+	liftoffAltitude     int64
+	// elevation - the ground elevation (in ft) which the approach
+	// altitude is measured from. Zero (sea level) if unknown.
+	elevation int64
+	// descentTop - the highest altitude since the aircraft last
+	// climbed, and descentAltitude is the latest altitude
+	haveDescentTop  bool
+	descentTop      int64
+	descentAltitude int64
+}
+
+// setElevation sets the ground elevation (in ft) below the aircraft,
+// usually the elevation of the nearest airport.
+func (f *flightPhase) setElevation(elevation int64) {
+	f.elevation = elevation
+}
+
+// sustainedDescent returns true if the aircraft descended at
+// least landingDescentHeight since it last climbed.
+func (f *flightPhase) sustainedDescent() bool {
+	return f.haveDescentTop && f.descentTop-f.descentAltitude >= landingDescentHeight
+}
+
+// update moves to the next phase based on state. It returns
+// true if the phase changed.
+func (f *flightPhase) update(state *pb.State) bool {
+	next := f.next(state)
+	if state.IsOnGround {
+		f.haveDescentTop = false
+	} else if state.HaveAltitudeBarometric {
+		alt := state.AltitudeBarometric
+		climbing := state.HaveVerticalRateBarometric && state.VerticalRateBarometric > levelVerticalRate
+		if !f.haveDescentTop || climbing || alt > f.descentTop {
+			f.haveDescentTop = true
+			f.descentTop = alt
+		}
+		f.descentAltitude = alt
+	}
+	if next == PhaseTakeoff {
+		if f.phase != PhaseTakeoff {
+			f.haveLiftoffAltitude = false
+		}
+		if !f.haveLiftoffAltitude && state.HaveAltitudeBarometric {
+			f.haveLiftoffAltitude = true
+			f.liftoffAltitude = state.AltitudeBarometric
+		}
+	}
+	if next == f.phase {
+		return false
+	}
+	f.phase = next
+	return true
+}
+
+// next returns the phase which follows the current phase given state
+func (f *flightPhase) next(state *pb.State) FlightPhase {
+	if state.IsOnGround {
+		switch f.phase {
+		case PhaseApproach:
+			return PhaseLanding
+		case PhaseLanding:
+			if state.HaveGroundSpeed && state.GroundSpeed > taxiSpeed {
+				return PhaseLanding
+			}
+		default:
+			// the approach is missed if the ground elevation is
+			// wrong, so touching down after a descent is a landing
+			if f.phase.IsAirborne() && f.sustainedDescent() {
+				return PhaseLanding
+			}
+		}
+		return PhaseGround
+	}
+
+	haveRate := state.HaveVerticalRateBarometric
+	rate := state.VerticalRateBarometric
+	haveAlt := state.HaveAltitudeBarometric
+	alt := state.AltitudeBarometric
+	// height above the ground elevation
+	height := alt - f.elevation
+	switch f.phase {
+	case PhaseGround, PhaseLanding:
+		// liftoff, or a touch and go
+		if !haveRate || rate >= 0 {
+			return PhaseTakeoff
+		}
+	case PhaseTakeoff:
+		climbing := !haveRate || rate > levelVerticalRate
+		belowTakeoffHeight := !haveAlt || !f.haveLiftoffAltitude || alt < f.liftoffAltitude+takeoffHeight
+		if climbing && belowTakeoffHeight {
+			return PhaseTakeoff
+		}
+	case PhaseApproach:
+		if haveRate && rate > levelVerticalRate {
+			return PhaseGoAround
+		}
+		return PhaseApproach
+	case PhaseGoAround:
+		if haveAlt && height <= landingApproachAltitude && (!haveRate || rate >= -levelVerticalRate) {
+			return PhaseGoAround
+		}
+	}
+
+	if !haveRate {
+		if f.phase.IsAirborne() {
+			return f.phase
+		}
+		return PhaseUnknown
+	}
+	switch {
+	case rate > levelVerticalRate:
+		return PhaseClimb
+	case rate < 0 && haveAlt && height <= landingApproachAltitude:
+		return PhaseApproach
+	case rate < -levelVerticalRate:
+		return PhaseDescent
+	default:
+		return PhaseCruise
+	}
+}
@@ -0,0 +1,134 @@
+package tracker
+
+import (
+	"github.com/afk11/airtrack/pkg/pb"
+	assert "github.com/stretchr/testify/require"
+	"testing"
+)
+
+func phaseState(onGround bool, alt int64, rate int64, gs float64) *pb.State {
+	return &pb.State{
+		IsOnGround:                 onGround,
+		HaveAltitudeBarometric:     !onGround,
+		AltitudeBarometric:         alt,
+		HaveVerticalRateBarometric: !onGround,
+		VerticalRateBarometric:     rate,
+		HaveGroundSpeed:            true,
+		GroundSpeed:                gs,
+	}
+}
+
+func TestFlightPhase(t *testing.T) {
+	f := flightPhase{}
+	for i, step := range []struct {
+		state   *pb.State
+		phase   FlightPhase
+		changed bool
+	}{
+		{phaseState(true, 0, 0, 10), PhaseGround, true},
+		{phaseState(true, 0, 0, 130), PhaseGround, false},
+		{phaseState(false, 300, 2000, 150), PhaseTakeoff, true},
+		{phaseState(false, 1500, 2500, 170), PhaseTakeoff, false},
+		// takeoffHeight above the liftoff altitude
+		{phaseState(false, 1800, 2500, 200), PhaseClimb, true},
+		{phaseState(false, 35000, 0, 450), PhaseCruise, true},
+		{phaseState(false, 35000, -200, 450), PhaseCruise, false},
+		{phaseState(false, 20000, -1500, 400), PhaseDescent, true},
+		{phaseState(false, 2900, -700, 160), PhaseApproach, true},
+		// level segments don't end the approach
+		{phaseState(false, 2000, 0, 150), PhaseApproach, false},
+		{phaseState(true, 0, 0, 130), PhaseLanding, true},
+		{phaseState(true, 0, 0, 60), PhaseLanding, false},
+		{phaseState(true, 0, 0, 20), PhaseGround, true},
+	} {
+		changed := f.update(step.state)
+		assert.Equal(t, step.phase, f.phase, "step %d", i)
+		assert.Equal(t, step.changed, changed, "step %d", i)
+	}
+}
+
+func TestFlightPhase_GoAround(t *testing.T) {
+	f := flightPhase{phase: PhaseApproach}
+	f.update(phaseState(false, 500, 1500, 140))
+	assert.Equal(t, PhaseGoAround, f.phase)
+	// a circuit at low altitude
+	f.update(phaseState(false, 1500, 0, 120))
+	assert.Equal(t, PhaseGoAround, f.phase)
+	f.update(phaseState(false, 1000, -600, 120))
+	assert.Equal(t, PhaseApproach, f.phase)
+
+	// climbing away
+	f = flightPhase{phase: PhaseGoAround}
+	f.update(phaseState(false, 3500, 1500, 200))
+	assert.Equal(t, PhaseClimb, f.phase)
+
+	// touching down after a go-around without another
+	// approach isn't a landing
+	f = flightPhase{phase: PhaseGoAround}
+	f.update(phaseState(true, 0, 0, 100))
+	assert.Equal(t, PhaseGround, f.phase)
+}
+
+func TestFlightPhase_HighElevation(t *testing.T) {
+	// the approach is measured from the ground elevation
+	f := flightPhase{phase: PhaseDescent}
+	f.setElevation(5400)
+	f.update(phaseState(false, 12000, -1500, 250))
+	assert.Equal(t, PhaseDescent, f.phase)
+	f.update(phaseState(false, 8000, -700, 160))
+	assert.Equal(t, PhaseApproach, f.phase)
+	f.update(phaseState(true, 0, 0, 130))
+	assert.Equal(t, PhaseLanding, f.phase)
+
+	// without the elevation, touching down after a
+	// sustained descent is still a landing
+	f = flightPhase{phase: PhaseCruise}
+	f.update(phaseState(false, 12000, -1500, 250))
+	assert.Equal(t, PhaseDescent, f.phase)
+	f.update(phaseState(false, 5600, 0, 140))
+	assert.Equal(t, PhaseCruise, f.phase)
+	f.update(phaseState(true, 0, 0, 130))
+	assert.Equal(t, PhaseLanding, f.phase)
+
+	// climbing resets the descent
+	f = flightPhase{phase: PhaseCruise}
+	f.update(phaseState(false, 12000, -1500, 250))
+	f.update(phaseState(false, 6000, 1500, 180))
+	f.update(phaseState(false, 6500, 0, 140))
+	f.update(phaseState(true, 0, 0, 130))
+	assert.Equal(t, PhaseGround, f.phase)
+}
+
+func TestFlightPhase_TouchAndGo(t *testing.T) {
+	f := flightPhase{phase: PhaseApproach}
+	f.update(phaseState(true, 0, 0, 100))
+	assert.Equal(t, PhaseLanding, f.phase)
+	f.update(phaseState(false, 200, 800, 110))
+	assert.Equal(t, PhaseTakeoff, f.phase)
+	// levelling off ends the takeoff
+	f.update(phaseState(false, 1000, 100, 110))
+	assert.Equal(t, PhaseCruise, f.phase)
+}
+
+func TestFlightPhase_Unknown(t *testing.T) {
+	f := flightPhase{}
+	// airborne without a vertical rate
+	changed := f.update(&pb.State{HaveAltitudeBarometric: true, AltitudeBarometric: 10000})
+	assert.False(t, changed)
+	assert.Equal(t, PhaseUnknown, f.phase)
+
+	// keeps the current phase without a vertical rate
+	f = flightPhase{phase: PhaseCruise}
+	f.update(&pb.State{HaveAltitudeBarometric: true, AltitudeBarometric: 10000})
+	assert.Equal(t, PhaseCruise, f.phase)
+
+	// takeoff without altitude or vertical rate
+	f = flightPhase{phase: PhaseGround}
+	f.update(&pb.State{})
+	assert.Equal(t, PhaseTakeoff, f.phase)
+	assert.False(t, f.haveLiftoffAltitude)
+	f.update(&pb.State{HaveAltitudeBarometric: true, AltitudeBarometric: 5400})
+	assert.Equal(t, PhaseTakeoff, f.phase)
+	assert.True(t, f.haveLiftoffAltitude)
+	assert.Equal(t, int64(5400), f.liftoffAltitude)
+}
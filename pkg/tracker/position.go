@@ -0,0 +1,163 @@
+package tracker
+
+import (
+	"github.com/afk11/airtrack/pkg/geo"
+	"math"
+	"time"
+)
+
+const (
+	// DefaultMaxPositionSpeed - default speed (in knots) above which
+	// a position implied by the distance from the previous position
+	// is rejected
+	DefaultMaxPositionSpeed = 1500.0
+	// positionMinTolerance - distance (in meters) allowed between two
+	// positions in addition to the speed limit, for positions without NACp
+	positionMinTolerance = 500.0
+	// positionAnomalyThreshold - number of rejected positions before a
+	// position anomaly is reported. A run of this many positions which are
+	// consistent with each other replaces the last accepted position.
+	positionAnomalyThreshold = 3
+	// positionAnomalyWindow - rejected positions further apart
+	// than this aren't counted towards the same anomaly
+	positionAnomalyWindow = time.Minute * 5
+	// metersPerSecondPerKnot - conversion factor for knots
+	metersPerSecondPerKnot = 0.514444
+)
+
+type (
+	// positionFix is a position reported by an aircraft
+	positionFix struct {
+		time     time.Time
+		lat      float64
+		lon      float64
+		haveNACP bool
+		nacp     uint32
+	}
+	// positionValidator rejects positions which imply the aircraft
+	// travelled faster than the maximum speed since the last accepted
+	// position, such as bad CPR decodes or spoofed positions.
+	positionValidator struct {
+		haveLast bool
+		last     positionFix
+
+		// candidate is the last rejected position. If several rejected
+		// positions are consistent, the last accepted position was bad.
+		haveCandidate  bool
+		candidate      positionFix
+		candidateCount int
+
+		rejections    int
+		lastRejection time.Time
+		// anomalies - number of times positionAnomalyThreshold
+		// positions were rejected
+		anomalies int64
+		// rejectedSpeed - speed in knots implied by the last
+		// rejected position
+		rejectedSpeed float64
+	}
+)
+
+// positionAccuracy returns the estimated position uncertainty
+// in meters for a NACp value (2.2.5.1.35). positionMinTolerance
+// is returned if NACp is unknown.
+func positionAccuracy(haveNACP bool, nacp uint32) float64 {
+	if !haveNACP {
+		return positionMinTolerance
+	}
+	switch nacp {
+	case 11, 10:
+		return 10
+	case 9:
+		return 30
+	case 8:
+		return 92.6
+	case 7:
+		return 185.2
+	case 6:
+		return 555.6
+	case 5:
+		return 926
+	case 4:
+		return 1852
+	case 3:
+		return 3704
+	case 2:
+		return 7408
+	case 1:
+		return 18520
+	}
+	return positionMinTolerance
+}
+
+// plausible returns whether the aircraft could have travelled from one
+// position to the other without exceeding maxSpeed (in knots), allowing
+// for the accuracy of both positions. The implied speed is also returned.
+func plausible(from, to positionFix, maxSpeed float64) (bool, float64) {
+	dt := to.time.Sub(from.time).Seconds()
+	if dt < 0 {
+		dt = 0
+	}
+	d := geo.Distance(from.lat, from.lon, to.lat, to.lon)
+	allowed := maxSpeed*metersPerSecondPerKnot*dt +
+		positionAccuracy(from.haveNACP, from.nacp) + positionAccuracy(to.haveNACP, to.nacp)
+
+	speed := math.Inf(1)
+	if dt > 0 {
+		speed = d / dt / metersPerSecondPerKnot
+	} else if d == 0 {
+		speed = 0
+	}
+	return d <= allowed, speed
+}
+
+// check returns true if the position should be accepted. Checks are
+// disabled if maxSpeed is zero.
+func (v *positionValidator) check(fix positionFix, maxSpeed float64) bool {
+	if maxSpeed <= 0 || !v.haveLast {
+		v.accept(fix)
+		return true
+	}
+	ok, speed := plausible(v.last, fix, maxSpeed)
+	if ok {
+		v.accept(fix)
+		return true
+	}
+
+	v.rejectedSpeed = speed
+	if fix.time.Sub(v.lastRejection) > positionAnomalyWindow {
+		v.rejections = 0
+	}
+	v.rejections++
+	v.lastRejection = fix.time
+	if v.rejections == positionAnomalyThreshold {
+		v.anomalies++
+	}
+
+	if v.haveCandidate {
+		if consistent, _ := plausible(v.candidate, fix, maxSpeed); consistent {
+			v.candidateCount++
+		} else {
+			v.candidateCount = 1
+		}
+	} else {
+		v.candidateCount = 1
+	}
+	v.haveCandidate = true
+	v.candidate = fix
+	if v.candidateCount >= positionAnomalyThreshold {
+		// the aircraft really is here, the last
+		// accepted position was bad
+		v.accept(fix)
+		return true
+	}
+	return false
+}
+
+// accept makes fix the last accepted position
+func (v *positionValidator) accept(fix positionFix) {
+	v.haveLast = true
+	v.last = fix
+	v.haveCandidate = false
+	v.candidateCount = 0
+}
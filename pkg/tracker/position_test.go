@@ -0,0 +1,75 @@
+package tracker
+
+import (
+	assert "github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPositionValidator(t *testing.T) {
+	now := time.Now()
+	fix := func(seconds int, lat, lon float64) positionFix {
+		return positionFix{time: now.Add(time.Second * time.Duration(seconds)), lat: lat, lon: lon}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		v := positionValidator{}
+		assert.True(t, v.check(fix(0, 53.4, -6.2), 0))
+		assert.True(t, v.check(fix(1, 10, 10), 0))
+	})
+	t.Run("plausible", func(t *testing.T) {
+		v := positionValidator{}
+		assert.True(t, v.check(fix(0, 53.4, -6.2), DefaultMaxPositionSpeed))
+		// ~4.6km in 30 seconds, 300 knots
+		assert.True(t, v.check(fix(30, 53.44, -6.2), DefaultMaxPositionSpeed))
+		// small differences with the same timestamp
+		assert.True(t, v.check(fix(30, 53.4401, -6.2), DefaultMaxPositionSpeed))
+		assert.Equal(t, 0, v.rejections)
+	})
+	t.Run("jumps", func(t *testing.T) {
+		v := positionValidator{}
+		assert.True(t, v.check(fix(0, 53.4, -6.2), DefaultMaxPositionSpeed))
+		assert.False(t, v.check(fix(1, 51.4, -0.4), DefaultMaxPositionSpeed))
+		assert.Greater(t, v.rejectedSpeed, DefaultMaxPositionSpeed)
+		assert.True(t, v.check(fix(2, 53.4, -6.2), DefaultMaxPositionSpeed))
+		assert.False(t, v.check(fix(3, 40.1, -73.4), DefaultMaxPositionSpeed))
+		assert.Equal(t, int64(0), v.anomalies)
+		assert.False(t, v.check(fix(4, 51.4, -0.4), DefaultMaxPositionSpeed))
+		assert.Equal(t, 3, v.rejections)
+		assert.Equal(t, int64(1), v.anomalies)
+		assert.Equal(t, 53.4, v.last.lat)
+	})
+	t.Run("rejections expire", func(t *testing.T) {
+		v := positionValidator{}
+		assert.True(t, v.check(fix(0, 53.4, -6.2), DefaultMaxPositionSpeed))
+		assert.False(t, v.check(fix(1, 51.4, -0.4), DefaultMaxPositionSpeed))
+		assert.False(t, v.check(fix(2, 40.1, -73.4), DefaultMaxPositionSpeed))
+		assert.False(t, v.check(fix(400, 10.0, 10.0), DefaultMaxPositionSpeed))
+		assert.Equal(t, 1, v.rejections)
+		assert.Equal(t, int64(0), v.anomalies)
+	})
+	t.Run("bad first position", func(t *testing.T) {
+		v := positionValidator{}
+		assert.True(t, v.check(fix(0, 10.0, 10.0), DefaultMaxPositionSpeed))
+		assert.False(t, v.check(fix(1, 53.4, -6.2), DefaultMaxPositionSpeed))
+		assert.False(t, v.check(fix(2, 53.4001, -6.2), DefaultMaxPositionSpeed))
+		// consistent positions replace the bad one
+		assert.True(t, v.check(fix(3, 53.4002, -6.2), DefaultMaxPositionSpeed))
+		assert.Equal(t, 53.4002, v.last.lat)
+		assert.True(t, v.check(fix(4, 53.4003, -6.2), DefaultMaxPositionSpeed))
+	})
+	t.Run("nacp", func(t *testing.T) {
+		// 19km in one second is too far for an accurate position
+		v := positionValidator{}
+		assert.True(t, v.check(fix(0, 53.4, -6.2), DefaultMaxPositionSpeed))
+		accurate := fix(1, 53.57, -6.2)
+		accurate.haveNACP = true
+		accurate.nacp = 9
+		assert.False(t, v.check(accurate, DefaultMaxPositionSpeed))
+		// but within the uncertainty of NACp 1
+		inaccurate := fix(1, 53.57, -6.2)
+		inaccurate.haveNACP = true
+		inaccurate.nacp = 1
+		assert.True(t, v.check(inaccurate, DefaultMaxPositionSpeed))
+	})
+}
@@ -1,5 +1,16 @@
 package tracker
 
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"net"
+	"time"
+)
+
 // Producer - this interface is exposed by Producer types.
 // Such types are responsible for writing messages to the messages channel.
 type Producer interface {
@@ -12,3 +23,126 @@ type Producer interface {
 	// to finish.
 	Stop()
 }
+
+const (
+	// DefaultMinReconnectDelay - delay before a text based producer
+	// reconnects after the first failure. Doubles after each consecutive
+	// failure.
+	DefaultMinReconnectDelay = time.Second
+	// DefaultMaxReconnectDelay - upper limit for the reconnect delay
+	DefaultMaxReconnectDelay = time.Minute
+
+	// lineReadTimeout - connections with no data for this
+	// long are closed and reopened
+	lineReadTimeout = time.Second * 60
+)
+
+// lineReader reads newline delimited messages from a TCP server and
+// writes them to the messages channel. It's shared by producers of
+// text based formats (SBS, AVR).
+type lineReader struct {
+	kind              string
+	addr              string
+	source            *pb.Source
+	messages          chan *pb.Message
+	parse             func(line string) ([]*pb.Message, error)
+	minReconnectDelay time.Duration
+	maxReconnectDelay time.Duration
+}
+
+// newLineReader creates a lineReader for host:port. kind is the
+// format name for log messages, and parse converts a line into
+// zero or more messages.
+func newLineReader(msgs chan *pb.Message, kind string, source *pb.Source, host string, port uint16, parse func(line string) ([]*pb.Message, error)) *lineReader {
+	return &lineReader{
+		kind:              kind,
+		addr:              fmt.Sprintf("%s:%d", host, port),
+		source:            source,
+		messages:          msgs,
+		parse:             parse,
+		minReconnectDelay: DefaultMinReconnectDelay,
+		maxReconnectDelay: DefaultMaxReconnectDelay,
+	}
+}
+
+// run connects to the server and reads messages until the connection
+// fails. It then reconnects, waiting longer after each consecutive
+// failure. It returns once ctx is cancelled.
+func (r *lineReader) run(ctx context.Context) {
+	dialer := net.Dialer{Timeout: time.Second * 5}
+	delay := r.minReconnectDelay
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", r.addr)
+		if err == nil {
+			err = r.readMessages(ctx, conn)
+			_ = conn.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		if err == nil {
+			// received some messages, so treat as a fresh failure
+			delay = r.minReconnectDelay
+			log.Warnf("%s server %s closed connection, reconnecting in %s", r.kind, r.source.Name, delay)
+		} else {
+			log.Warnf("%s server %s: %s (reconnecting in %s)", r.kind, r.source.Name, err.Error(), delay)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		delay *= 2
+		if delay > r.maxReconnectDelay {
+			delay = r.maxReconnectDelay
+		}
+	}
+}
+
+// readMessages reads lines from conn and writes parsed messages to the
+// messages channel. It returns when the connection is closed (with a nil
+// error if any message was received), or when ctx is cancelled.
+func (r *lineReader) readMessages(ctx context.Context, conn net.Conn) error {
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-finished:
+		}
+	}()
+
+	var received bool
+	scanner := bufio.NewScanner(conn)
+	for {
+		err := conn.SetReadDeadline(time.Now().Add(lineReadTimeout))
+		if err != nil {
+			return err
+		}
+		if !scanner.Scan() {
+			if received {
+				return nil
+			} else if err := scanner.Err(); err != nil {
+				return err
+			}
+			return errors.New("connection closed before any messages were received")
+		}
+		msgs, err := r.parse(scanner.Text())
+		if err != nil {
+			log.Debugf("%s server %s: %s", r.kind, r.source.Name, err.Error())
+			continue
+		}
+		for _, msg := range msgs {
+			received = true
+			msg.Source = r.source
+			select {
+			case r.messages <- msg:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
@@ -3,7 +3,6 @@ package tracker
 import (
 	"github.com/afk11/airtrack/pkg/config"
 	"github.com/afk11/airtrack/pkg/db"
-	"github.com/afk11/airtrack/pkg/pb"
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/checker/decls"
 	"github.com/pkg/errors"
@@ -17,6 +16,30 @@ type (
 	// EmailNotification represents an email topic to which the project is subscribed.
 	EmailNotification string
 
+	// ProjectWebhook is a HTTP endpoint which receives the project's events
+	ProjectWebhook struct {
+		// URL - the endpoint events are POSTed to
+		URL string
+		// Secret - key used to sign requests. Can be empty.
+		Secret string
+		// Notifications - list of topics the webhook is subscribed to
+		Notifications []EmailNotification
+	}
+
+	// Rule is a custom project event. It's triggered when
+	// Program evaluates to true.
+	Rule struct {
+		// Name - the event name
+		Name EmailNotification
+		// Condition - the CEL expression
+		Condition string
+		// Program - the parsed Condition
+		Program cel.Program
+		// Cooldown - if zero, the rule triggers once per sighting. Otherwise,
+		// it can trigger again once Cooldown has passed.
+		Cooldown time.Duration
+	}
+
 	// Project represents an active tracking project
 	Project struct {
 		// Name of the project
@@ -37,6 +60,10 @@ type (
 		NotifyEmail string
 		// EmailNotifications - list of topics the project is subscribed to
 		EmailNotifications []EmailNotification
+		// Webhooks - list of HTTP endpoints receiving notifications
+		Webhooks []ProjectWebhook
+		// Rules - list of custom events
+		Rules []*Rule
 
 		// ReopenSightings - whether to reopen a sighting if it was seen within `ReopenSightingsInterval`
 		ReopenSightings bool
@@ -60,6 +87,13 @@ type (
 	}
 )
 
+const (
+	// RuleTriggerOnce - the rule triggers once per sighting
+	RuleTriggerOnce = "once"
+	// RuleTriggerCooldown - the rule can trigger again after its cooldown
+	RuleTriggerCooldown = "cooldown"
+)
+
 const (
 	// TrackTxTypes - track ADSB message types (only for BEAST messages)
 	TrackTxTypes Feature = "track_tx_types"
@@ -71,8 +105,14 @@ const (
 	TrackKmlLocation Feature = "track_kml"
 	// TrackTakeoff - (logs only) log when a takeoff begins/ends
 	TrackTakeoff Feature = "track_takeoff"
+	// TrackLanding - (logs only) log when an aircraft lands
+	TrackLanding Feature = "track_landing"
 	// GeocodeEndpoints - (logs only) geolocate the source + destination airport
 	GeocodeEndpoints Feature = "geocode_endpoints"
+	// TrackPhases - track the flight phase and maintain history
+	TrackPhases Feature = "track_phases"
+	// TrackReceivers - track which receivers heard the aircraft
+	TrackReceivers Feature = "track_receivers"
 
 	// MapProduced - the notification about a new map
 	MapProduced EmailNotification = "map_produced"
@@ -84,6 +124,23 @@ const (
 	TakeoffUnknownAirport EmailNotification = "takeoff_unknown_airport"
 	// TakeoffComplete - the notification about an aircraft that levels off after takeoff
 	TakeoffComplete EmailNotification = "takeoff_complete"
+	// LandingAtAirport - the notification about an aircraft that just touched down
+	LandingAtAirport EmailNotification = "landing_at_airport"
+	// LandingUnknownAirport - the notification about an aircraft that just touched down at an unknown airport
+	LandingUnknownAirport EmailNotification = "landing_unknown_airport"
+	// Emergency - the notification about an aircraft squawking 7500, 7600 or
+	// 7700, or broadcasting an ADS-B emergency status
+	Emergency EmailNotification = "emergency"
+	// ZoneEntered - the notification about an aircraft entering a zone
+	ZoneEntered EmailNotification = "zone_entered"
+	// ZoneExited - the notification about an aircraft leaving a zone
+	ZoneExited EmailNotification = "zone_exited"
+	// Orbiting - the notification about an aircraft circling
+	// or holding over an area
+	Orbiting EmailNotification = "orbiting"
+	// PositionAnomaly - the notification about an aircraft
+	// repeatedly reporting implausible positions
+	PositionAnomaly EmailNotification = "position_anomaly"
 
 	// DefaultSightingReopenInterval - default interval for sighting reopen behavior
 	DefaultSightingReopenInterval = time.Minute * 5
@@ -102,8 +159,14 @@ func FeatureFromString(f string) (Feature, error) {
 		return TrackKmlLocation, nil
 	case string(TrackTakeoff):
 		return TrackTakeoff, nil
+	case string(TrackLanding):
+		return TrackLanding, nil
 	case string(GeocodeEndpoints):
 		return GeocodeEndpoints, nil
+	case string(TrackPhases):
+		return TrackPhases, nil
+	case string(TrackReceivers):
+		return TrackReceivers, nil
 	}
 	return "", errors.Errorf("unknown feature: %s", f)
 }
@@ -121,10 +184,35 @@ func EmailNotificationFromString(n string) (EmailNotification, error) {
 		return TakeoffComplete, nil
 	case string(TakeoffUnknownAirport):
 		return TakeoffUnknownAirport, nil
+	case string(LandingAtAirport):
+		return LandingAtAirport, nil
+	case string(LandingUnknownAirport):
+		return LandingUnknownAirport, nil
+	case string(Emergency):
+		return Emergency, nil
+	case string(ZoneEntered):
+		return ZoneEntered, nil
+	case string(ZoneExited):
+		return ZoneExited, nil
+	case string(Orbiting):
+		return Orbiting, nil
+	case string(PositionAnomaly):
+		return PositionAnomaly, nil
 	}
 	return "", errors.Errorf("unknown email notification: %s", n)
 }
 
+// notificationFromString returns the project's Rule event named n, or
+// the EmailNotification for n
+func (p *Project) notificationFromString(n string) (EmailNotification, error) {
+	for _, r := range p.Rules {
+		if string(r.Name) == n {
+			return r.Name, nil
+		}
+	}
+	return EmailNotificationFromString(n)
+}
+
 // IsFeatureEnabled returns whether the project has Feature f enabled
 func (p *Project) IsFeatureEnabled(f Feature) bool {
 	for _, pf := range p.Features {
@@ -135,6 +223,15 @@ func (p *Project) IsFeatureEnabled(f Feature) bool {
 	return false
 }
 
+// IsObserving returns whether the project currently has an
+// observation for the aircraft
+func (p *Project) IsObserving(icao string) bool {
+	p.obsMu.RLock()
+	defer p.obsMu.RUnlock()
+	_, ok := p.Observations[icao]
+	return ok
+}
+
 // IsEmailNotificationEnabled returns whether the project has EmailNotification n enabled
 func (p *Project) IsEmailNotificationEnabled(n EmailNotification) bool {
 	for _, ni := range p.EmailNotifications {
@@ -145,6 +242,27 @@ func (p *Project) IsEmailNotificationEnabled(n EmailNotification) bool {
 	return false
 }
 
+// IsWebhookNotificationEnabled returns whether any of the projects webhooks
+// has EmailNotification n enabled
+func (p *Project) IsWebhookNotificationEnabled(n EmailNotification) bool {
+	for i := range p.Webhooks {
+		if p.Webhooks[i].IsNotificationEnabled(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotificationEnabled returns whether the webhook has EmailNotification n enabled
+func (w *ProjectWebhook) IsNotificationEnabled(n EmailNotification) bool {
+	for _, ni := range w.Notifications {
+		if ni == n {
+			return true
+		}
+	}
+	return false
+}
+
 // InitProject initializes a project from its configuration or an error upon failure.
 func InitProject(cfg config.Project) (*Project, error) {
 	if cfg.Disabled {
@@ -181,50 +299,123 @@ func InitProject(cfg config.Project) (*Project, error) {
 		p.Features = append(p.Features, feature)
 	}
 
-	if cfg.Notifications != nil {
-		if cfg.Notifications.Email == "" {
-			return nil, errors.Errorf("notifications missing value for email")
+	if p.Filter != "" || len(cfg.Rules) > 0 {
+		env, err := newFilterEnv()
+		if err != nil {
+			return nil, err
 		}
-		p.NotifyEmail = cfg.Notifications.Email
-		for _, n := range cfg.Notifications.Enabled {
-			notification, err := EmailNotificationFromString(n)
+		if p.Filter != "" {
+			parsed, issues := env.Parse(cfg.Filter)
+			if issues != nil && issues.Err() != nil {
+				return nil, errors.Wrap(issues.Err(), "failed to parse filter expression")
+			}
+			checked, issues := env.Check(parsed)
+			if issues != nil && issues.Err() != nil {
+				return nil, errors.Wrap(issues.Err(), "type errors in filter expression")
+			}
+			prg, err := newFilterProgram(env, checked)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to initialize filter")
+			}
+			p.Program = prg
+		}
+		for i := range cfg.Rules {
+			rule, err := initRule(env, cfg.Rules[i])
 			if err != nil {
 				return nil, err
 			}
-			p.EmailNotifications = append(p.EmailNotifications, notification)
+			for _, other := range p.Rules {
+				if other.Name == rule.Name {
+					return nil, errors.Errorf("duplicated rule name: %s", rule.Name)
+				}
+			}
+			p.Rules = append(p.Rules, rule)
 		}
 	}
 
-	if p.Filter != "" {
-		env, err := cel.NewEnv(
-			cel.Types(&pb.Source{}, &pb.Message{}, &pb.State{}),
-			cel.Declarations(
-				decls.NewIdent("msg",
-					decls.NewObjectType("airtrack.Message"),
-					nil),
-				decls.NewIdent("state",
-					decls.NewObjectType("airtrack.State"),
-					nil),
-				decls.NewVar("AdsbExchangeSource", decls.Int),
-				decls.NewVar("BeastSource", decls.Int),
-			))
-
-		if err != nil {
-			return nil, err
+	if cfg.Notifications != nil {
+		if cfg.Notifications.Email == "" && len(cfg.Notifications.Webhooks) == 0 {
+			return nil, errors.Errorf("notifications missing value for email")
 		}
-		parsed, issues := env.Parse(cfg.Filter)
-		if issues != nil && issues.Err() != nil {
-			return nil, errors.Wrap(issues.Err(), "failed to parse filter expression")
+		notifications := make([]EmailNotification, 0, len(cfg.Notifications.Enabled))
+		for _, n := range cfg.Notifications.Enabled {
+			notification, err := p.notificationFromString(n)
+			if err != nil {
+				return nil, err
+			}
+			notifications = append(notifications, notification)
 		}
-		checked, issues := env.Check(parsed)
-		if issues != nil && issues.Err() != nil {
-			return nil, errors.Wrap(issues.Err(), "type errors in filter expression")
+		if cfg.Notifications.Email != "" {
+			p.NotifyEmail = cfg.Notifications.Email
+			p.EmailNotifications = notifications
 		}
-		prg, err := env.Program(checked)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to initialize filter")
+		for i, wh := range cfg.Notifications.Webhooks {
+			if wh.URL == "" {
+				return nil, errors.Errorf("webhook %d missing value for url", i)
+			}
+			w := ProjectWebhook{
+				URL:           wh.URL,
+				Secret:        wh.Secret,
+				Notifications: notifications,
+			}
+			if len(wh.Enabled) > 0 {
+				w.Notifications = make([]EmailNotification, 0, len(wh.Enabled))
+				for _, n := range wh.Enabled {
+					notification, err := p.notificationFromString(n)
+					if err != nil {
+						return nil, err
+					}
+					w.Notifications = append(w.Notifications, notification)
+				}
+			}
+			p.Webhooks = append(p.Webhooks, w)
 		}
-		p.Program = prg
 	}
 	return &p, nil
 }
+
+// initRule validates the rule configuration and parses its condition.
+// Rule names must not clash with the built-in event names.
+func initRule(env *cel.Env, cfg config.Rule) (*Rule, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("rule missing value for name")
+	} else if _, err := EmailNotificationFromString(cfg.Name); err == nil {
+		return nil, errors.Errorf("rule %s: name is used by a built-in event", cfg.Name)
+	} else if cfg.Condition == "" {
+		return nil, errors.Errorf("rule %s: missing value for condition", cfg.Name)
+	}
+	r := &Rule{
+		Name:      EmailNotification(cfg.Name),
+		Condition: cfg.Condition,
+	}
+	switch cfg.Trigger {
+	case "", RuleTriggerOnce:
+		if cfg.Cooldown != 0 {
+			return nil, errors.Errorf("rule %s: cooldown requires the %s trigger", cfg.Name, RuleTriggerCooldown)
+		}
+	case RuleTriggerCooldown:
+		if cfg.Cooldown <= 0 {
+			return nil, errors.Errorf("rule %s: cooldown must be greater than zero", cfg.Name)
+		}
+		r.Cooldown = time.Duration(cfg.Cooldown) * time.Second
+	default:
+		return nil, errors.Errorf("rule %s: unknown trigger: %s", cfg.Name, cfg.Trigger)
+	}
+
+	parsed, issues := env.Parse(cfg.Condition)
+	if issues != nil && issues.Err() != nil {
+		return nil, errors.Wrapf(issues.Err(), "rule %s: failed to parse condition", cfg.Name)
+	}
+	checked, issues := env.Check(parsed)
+	if issues != nil && issues.Err() != nil {
+		return nil, errors.Wrapf(issues.Err(), "rule %s: type errors in condition", cfg.Name)
+	} else if checked.ResultType().GetPrimitive() != decls.Bool.GetPrimitive() {
+		return nil, errors.Errorf("rule %s: condition must return a bool", cfg.Name)
+	}
+	prg, err := newFilterProgram(env, checked)
+	if err != nil {
+		return nil, errors.Wrapf(err, "rule %s: failed to initialize condition", cfg.Name)
+	}
+	r.Program = prg
+	return r, nil
+}
@@ -3,6 +3,7 @@ package tracker
 import (
 	"github.com/afk11/airtrack/pkg/config"
 	assert "github.com/stretchr/testify/require"
+	"strings"
 	"testing"
 	"time"
 )
@@ -25,10 +26,13 @@ func enToString(en ...EmailNotification) []string {
 var allFeatures = []Feature{
 	TrackCallSigns, TrackSquawks, TrackTakeoff,
 	TrackKmlLocation, TrackTxTypes, GeocodeEndpoints,
+	TrackLanding, TrackPhases, TrackReceivers,
 }
 var allNotifications = []EmailNotification{
 	MapProduced, SpottedInFlight, TakeoffFromAirport,
-	TakeoffUnknownAirport, TakeoffComplete,
+	TakeoffUnknownAirport, TakeoffComplete, LandingAtAirport,
+	LandingUnknownAirport, Emergency, ZoneEntered, ZoneExited,
+	Orbiting, PositionAnomaly,
 }
 
 func TestInitProject(t *testing.T) {
@@ -271,3 +275,119 @@ func TestInitProject_InvalidEmailNotification(t *testing.T) {
 	assert.Nil(t, p)
 	assert.Equal(t, "unknown email notification: invalid-event", err.Error())
 }
+func TestInitProject_Webhooks(t *testing.T) {
+	cfg := config.Project{
+		Name: "myproj",
+		Notifications: &config.Notifications{
+			Enabled: []string{string(MapProduced), string(SpottedInFlight)},
+			Webhooks: []config.Webhook{
+				{URL: "https://hooks.local/1", Secret: "abcd"},
+				{URL: "https://hooks.local/2", Enabled: []string{string(TakeoffComplete)}},
+			},
+		},
+	}
+	p, err := InitProject(cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, "", p.NotifyEmail)
+	assert.Equal(t, 0, len(p.EmailNotifications))
+	assert.False(t, p.IsEmailNotificationEnabled(MapProduced))
+
+	assert.Equal(t, 2, len(p.Webhooks))
+	assert.Equal(t, "https://hooks.local/1", p.Webhooks[0].URL)
+	assert.Equal(t, "abcd", p.Webhooks[0].Secret)
+	assert.Equal(t, []EmailNotification{MapProduced, SpottedInFlight}, p.Webhooks[0].Notifications)
+	assert.Equal(t, "https://hooks.local/2", p.Webhooks[1].URL)
+	assert.Equal(t, "", p.Webhooks[1].Secret)
+	assert.Equal(t, []EmailNotification{TakeoffComplete}, p.Webhooks[1].Notifications)
+
+	assert.True(t, p.IsWebhookNotificationEnabled(MapProduced))
+	assert.True(t, p.IsWebhookNotificationEnabled(SpottedInFlight))
+	assert.True(t, p.IsWebhookNotificationEnabled(TakeoffComplete))
+	assert.False(t, p.IsWebhookNotificationEnabled(TakeoffFromAirport))
+}
+func TestInitProject_WebhookMissingURL(t *testing.T) {
+	cfg := config.Project{
+		Name: "myproj",
+		Notifications: &config.Notifications{
+			Enabled:  []string{string(MapProduced)},
+			Webhooks: []config.Webhook{{Secret: "abcd"}},
+		},
+	}
+	p, err := InitProject(cfg)
+	assert.Error(t, err)
+	assert.Nil(t, p)
+	assert.Equal(t, "webhook 0 missing value for url", err.Error())
+}
+func TestInitProject_InvalidWebhookNotification(t *testing.T) {
+	cfg := config.Project{
+		Name: "myproj",
+		Notifications: &config.Notifications{
+			Webhooks: []config.Webhook{{URL: "https://hooks.local/1", Enabled: []string{"invalid-event"}}},
+		},
+	}
+	p, err := InitProject(cfg)
+	assert.Error(t, err)
+	assert.Nil(t, p)
+	assert.Equal(t, "unknown email notification: invalid-event", err.Error())
+}
+func TestInitProject_Rules(t *testing.T) {
+	cfg := config.Project{
+		Name: "myproj",
+		Rules: []config.Rule{
+			{Name: "low_rch", Condition: `state.CallSign.startsWith("RCH") && state.AltitudeBarometric < 5000`},
+			{Name: "fast", Condition: `state.GroundSpeed > 600.0`, Trigger: RuleTriggerCooldown, Cooldown: 600},
+		},
+		Notifications: &config.Notifications{
+			Email:   "test-email@local.localhost",
+			Enabled: []string{string(SpottedInFlight), "low_rch"},
+			Webhooks: []config.Webhook{
+				{URL: "https://hooks.local/1", Enabled: []string{"fast"}},
+			},
+		},
+	}
+	p, err := InitProject(cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Nil(t, p.Program)
+	assert.Equal(t, 2, len(p.Rules))
+	assert.Equal(t, EmailNotification("low_rch"), p.Rules[0].Name)
+	assert.Equal(t, cfg.Rules[0].Condition, p.Rules[0].Condition)
+	assert.NotNil(t, p.Rules[0].Program)
+	assert.Equal(t, time.Duration(0), p.Rules[0].Cooldown)
+	assert.Equal(t, EmailNotification("fast"), p.Rules[1].Name)
+	assert.Equal(t, time.Minute*10, p.Rules[1].Cooldown)
+	assert.True(t, p.IsEmailNotificationEnabled("low_rch"))
+	assert.False(t, p.IsEmailNotificationEnabled("fast"))
+	assert.True(t, p.IsWebhookNotificationEnabled("fast"))
+}
+func TestInitProject_InvalidRules(t *testing.T) {
+	for _, tc := range []struct {
+		rule config.Rule
+		err  string
+	}{
+		{config.Rule{Condition: "true"}, "rule missing value for name"},
+		{config.Rule{Name: string(MapProduced), Condition: "true"}, "rule map_produced: name is used by a built-in event"},
+		{config.Rule{Name: "r"}, "rule r: missing value for condition"},
+		{config.Rule{Name: "r", Condition: "true", Cooldown: 10}, "rule r: cooldown requires the cooldown trigger"},
+		{config.Rule{Name: "r", Condition: "true", Trigger: RuleTriggerCooldown}, "rule r: cooldown must be greater than zero"},
+		{config.Rule{Name: "r", Condition: "true", Trigger: "always"}, "rule r: unknown trigger: always"},
+		{config.Rule{Name: "r", Condition: "state.CallSign"}, "rule r: condition must return a bool"},
+	} {
+		p, err := InitProject(config.Project{Name: "myproj", Rules: []config.Rule{tc.rule}})
+		assert.Nil(t, p)
+		assert.EqualError(t, err, tc.err)
+	}
+
+	p, err := InitProject(config.Project{Name: "myproj", Rules: []config.Rule{{Name: "r", Condition: "state.Unknown"}}})
+	assert.Nil(t, p)
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "rule r: type errors in condition"))
+
+	p, err = InitProject(config.Project{Name: "myproj", Rules: []config.Rule{
+		{Name: "r", Condition: "true"},
+		{Name: "r", Condition: "false"},
+	}})
+	assert.Nil(t, p)
+	assert.EqualError(t, err, "duplicated rule name: r")
+}
@@ -0,0 +1,97 @@
+package tracker
+
+import (
+	"github.com/afk11/airtrack/pkg/db"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/afk11/airtrack/pkg/readsb"
+	"strings"
+)
+
+// provenanceFromDataSource converts the readsb data source
+// of a decoded message into a Provenance.
+func provenanceFromDataSource(source readsb.DataSource) pb.Provenance {
+	switch source {
+	case readsb.SourceADSB:
+		return pb.Provenance_ADSB
+	case readsb.SourceMLAT:
+		return pb.Provenance_MLAT
+	case readsb.SourceTISB:
+		return pb.Provenance_TISB
+	case readsb.SourceADSR:
+		return pb.Provenance_ADSR
+	case readsb.SourceModeS, readsb.SourceModeSChecked:
+		return pb.Provenance_ModeS
+	default:
+		return pb.Provenance_Unknown
+	}
+}
+
+// provenanceFromAircraftJSON determines the Provenance of an aircraft
+// in aircraft.json. The mlat and tisb lists name the fields which were
+// derived from MLAT or TIS-B, and take precedence over the address type
+// if they include the position.
+func provenanceFromAircraftJSON(ac *AircraftJSONAircraft) pb.Provenance {
+	for _, field := range ac.Mlat {
+		if field == "lat" {
+			return pb.Provenance_MLAT
+		}
+	}
+	for _, field := range ac.Tisb {
+		if field == "lat" {
+			return pb.Provenance_TISB
+		}
+	}
+	switch {
+	case strings.HasPrefix(ac.Type, "adsb_"):
+		return pb.Provenance_ADSB
+	case strings.HasPrefix(ac.Type, "adsr_"):
+		return pb.Provenance_ADSR
+	case strings.HasPrefix(ac.Type, "tisb_"):
+		return pb.Provenance_TISB
+	case ac.Type == "mlat":
+		return pb.Provenance_MLAT
+	case ac.Type == "mode_s":
+		return pb.Provenance_ModeS
+	default:
+		return pb.Provenance_Unknown
+	}
+}
+
+// provenanceFromAdsbx determines the Provenance of an aircraft from
+// ADSB Exchange. The transponder type (trt) is used if the position
+// didn't come from MLAT or TIS-B: 1 is Mode S, and 2-5 are ADS-B.
+func provenanceFromAdsbx(ac *AdsbxAircraft) pb.Provenance {
+	if ac.Mlat == "1" {
+		return pb.Provenance_MLAT
+	} else if ac.Tisb == "1" {
+		return pb.Provenance_TISB
+	}
+	switch ac.Trt {
+	case "1":
+		return pb.Provenance_ModeS
+	case "2", "3", "4", "5":
+		return pb.Provenance_ADSB
+	default:
+		return pb.Provenance_Unknown
+	}
+}
+
+// locationProvenance returns the value stored in the provenance
+// column of sighting_location for p, or nil if p isn't a source
+// of positions.
+func locationProvenance(p pb.Provenance) *string {
+	var provenance string
+	switch p {
+	case pb.Provenance_ADSB:
+		provenance = db.ProvenanceADSB
+	case pb.Provenance_MLAT:
+		provenance = db.ProvenanceMLAT
+	case pb.Provenance_TISB:
+		provenance = db.ProvenanceTISB
+	case pb.Provenance_ADSR:
+		provenance = db.ProvenanceADSR
+	default:
+		return nil
+	}
+	return &provenance
+}
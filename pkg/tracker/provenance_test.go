@@ -0,0 +1,59 @@
+package tracker
+
+import (
+	"github.com/afk11/airtrack/pkg/db"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/afk11/airtrack/pkg/readsb"
+	assert "github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestProvenanceFromDataSource(t *testing.T) {
+	assert.Equal(t, pb.Provenance_ADSB, provenanceFromDataSource(readsb.SourceADSB))
+	assert.Equal(t, pb.Provenance_MLAT, provenanceFromDataSource(readsb.SourceMLAT))
+	assert.Equal(t, pb.Provenance_TISB, provenanceFromDataSource(readsb.SourceTISB))
+	assert.Equal(t, pb.Provenance_ADSR, provenanceFromDataSource(readsb.SourceADSR))
+	assert.Equal(t, pb.Provenance_ModeS, provenanceFromDataSource(readsb.SourceModeS))
+	assert.Equal(t, pb.Provenance_ModeS, provenanceFromDataSource(readsb.SourceModeSChecked))
+	assert.Equal(t, pb.Provenance_Unknown, provenanceFromDataSource(readsb.SourceModeAC))
+	assert.Equal(t, pb.Provenance_Unknown, provenanceFromDataSource(readsb.SourceInvalid))
+}
+
+func TestProvenanceFromAircraftJSON(t *testing.T) {
+	for _, tc := range []struct {
+		ac       AircraftJSONAircraft
+		expected pb.Provenance
+	}{
+		{AircraftJSONAircraft{Type: "adsb_icao"}, pb.Provenance_ADSB},
+		{AircraftJSONAircraft{Type: "adsb_icao_nt"}, pb.Provenance_ADSB},
+		{AircraftJSONAircraft{Type: "adsr_icao"}, pb.Provenance_ADSR},
+		{AircraftJSONAircraft{Type: "tisb_trackfile"}, pb.Provenance_TISB},
+		{AircraftJSONAircraft{Type: "mlat"}, pb.Provenance_MLAT},
+		{AircraftJSONAircraft{Type: "mode_s"}, pb.Provenance_ModeS},
+		{AircraftJSONAircraft{Type: "other"}, pb.Provenance_Unknown},
+		{AircraftJSONAircraft{}, pb.Provenance_Unknown},
+		// the position came from MLAT
+		{AircraftJSONAircraft{Type: "adsb_icao", Mlat: []string{"lat", "lon"}}, pb.Provenance_MLAT},
+		{AircraftJSONAircraft{Type: "adsb_icao", Tisb: []string{"lat", "lon"}}, pb.Provenance_TISB},
+		{AircraftJSONAircraft{Type: "adsb_icao", Mlat: []string{"gs"}}, pb.Provenance_ADSB},
+	} {
+		assert.Equal(t, tc.expected, provenanceFromAircraftJSON(&tc.ac), tc.ac.Type)
+	}
+}
+
+func TestProvenanceFromAdsbx(t *testing.T) {
+	assert.Equal(t, pb.Provenance_MLAT, provenanceFromAdsbx(&AdsbxAircraft{Mlat: "1", Trt: "1"}))
+	assert.Equal(t, pb.Provenance_TISB, provenanceFromAdsbx(&AdsbxAircraft{Tisb: "1", Trt: "0"}))
+	assert.Equal(t, pb.Provenance_ModeS, provenanceFromAdsbx(&AdsbxAircraft{Mlat: "0", Tisb: "0", Trt: "1"}))
+	assert.Equal(t, pb.Provenance_ADSB, provenanceFromAdsbx(&AdsbxAircraft{Mlat: "0", Tisb: "0", Trt: "5"}))
+	assert.Equal(t, pb.Provenance_Unknown, provenanceFromAdsbx(&AdsbxAircraft{Trt: "0"}))
+}
+
+func TestLocationProvenance(t *testing.T) {
+	assert.Equal(t, db.ProvenanceADSB, *locationProvenance(pb.Provenance_ADSB))
+	assert.Equal(t, db.ProvenanceMLAT, *locationProvenance(pb.Provenance_MLAT))
+	assert.Equal(t, db.ProvenanceTISB, *locationProvenance(pb.Provenance_TISB))
+	assert.Equal(t, db.ProvenanceADSR, *locationProvenance(pb.Provenance_ADSR))
+	assert.Nil(t, locationProvenance(pb.Provenance_ModeS))
+	assert.Nil(t, locationProvenance(pb.Provenance_Unknown))
+}
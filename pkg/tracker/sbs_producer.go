@@ -0,0 +1,245 @@
+package tracker
+
+import (
+	"context"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Field positions in a BaseStation MSG record
+const (
+	sbsFieldMessageType  = 0
+	sbsFieldTransmission = 1
+	sbsFieldSessionID    = 2
+	sbsFieldAircraftID   = 3
+	sbsFieldIcao         = 4
+	sbsFieldFlightID     = 5
+	sbsFieldDateGen      = 6
+	sbsFieldTimeGen      = 7
+	sbsFieldDateLogged   = 8
+	sbsFieldTimeLogged   = 9
+	sbsFieldCallSign     = 10
+	sbsFieldAltitude     = 11
+	sbsFieldGroundSpeed  = 12
+	sbsFieldTrack        = 13
+	sbsFieldLatitude     = 14
+	sbsFieldLongitude    = 15
+	sbsFieldVerticalRate = 16
+	sbsFieldSquawk       = 17
+	sbsFieldIsOnGround   = 21
+	sbsNumFields         = 22
+)
+
+// SbsProducer - implements Producer.
+// This type represents a connection to an SBS (BaseStation)
+// server, typically dump1090's port 30003.
+type SbsProducer struct {
+	name      string
+	reader    *lineReader
+	wg        sync.WaitGroup
+	canceller func()
+}
+
+// NewSbsProducer initializes a new SbsProducer.
+func NewSbsProducer(msgs chan *pb.Message, host string, port uint16, name string) *SbsProducer {
+	source := &pb.Source{
+		Type: pb.Source_SbsServer,
+		Name: name,
+	}
+	return &SbsProducer{
+		name: name,
+		reader: newLineReader(msgs, "sbs", source, host, port, func(line string) ([]*pb.Message, error) {
+			msg, err := ParseSbsMessage(line)
+			if err != nil || msg == nil {
+				return nil, err
+			}
+			return []*pb.Message{msg}, nil
+		}),
+	}
+}
+
+// Name - see Producer.Name()
+func (p *SbsProducer) Name() string {
+	return p.name
+}
+
+// Start - see Producer.Start()
+// This function starts the producer goroutine.
+func (p *SbsProducer) Start() {
+	p.wg.Add(1)
+	ctx, canceller := context.WithCancel(context.Background())
+	p.canceller = canceller
+	go func() {
+		defer p.wg.Done()
+		p.reader.run(ctx)
+	}()
+}
+
+// Stop sends the cancel signal to the producer goroutine
+// and blocks until it finishes processing
+func (p *SbsProducer) Stop() {
+	p.canceller()
+	p.wg.Wait()
+}
+
+// ParseSbsMessage parses a BaseStation CSV line. MSG records (transmission
+// types 1-8) are converted into a pb.Message without a Source. Other record
+// types (SEL, ID, AIR, STA, CLK) are ignored and return a nil message.
+func ParseSbsMessage(line string) (*pb.Message, error) {
+	fields := strings.Split(strings.TrimSpace(line), ",")
+	if fields[sbsFieldMessageType] != "MSG" {
+		return nil, nil
+	} else if len(fields) < sbsNumFields {
+		return nil, errors.Errorf("MSG record has %d fields, expected %d", len(fields), sbsNumFields)
+	}
+	transmission, err := strconv.Atoi(fields[sbsFieldTransmission])
+	if err != nil || transmission < 1 || transmission > 8 {
+		return nil, errors.Errorf("invalid transmission type: %s", fields[sbsFieldTransmission])
+	}
+	icao := strings.ToUpper(fields[sbsFieldIcao])
+	if len(icao) != 6 {
+		return nil, errors.Errorf("invalid icao: %s", fields[sbsFieldIcao])
+	} else if _, err := strconv.ParseUint(icao, 16, 32); err != nil {
+		return nil, errors.Errorf("invalid icao: %s", fields[sbsFieldIcao])
+	}
+
+	msg := &pb.Message{
+		Icao: icao,
+	}
+	if callsign := strings.TrimSpace(fields[sbsFieldCallSign]); callsign != "" {
+		msg.CallSign = callsign
+	}
+	if v := fields[sbsFieldAltitude]; v != "" {
+		altitude, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing altitude")
+		}
+		msg.AltitudeBarometric = strconv.FormatInt(altitude, 10)
+	}
+	if v := fields[sbsFieldGroundSpeed]; v != "" {
+		gs, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing ground speed")
+		}
+		msg.GroundSpeed = strconv.FormatFloat(gs, 'f', 1, 64)
+	}
+	if v := fields[sbsFieldTrack]; v != "" {
+		track, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing track")
+		}
+		msg.Track = strconv.FormatFloat(track, 'f', 6, 64)
+	}
+	if fields[sbsFieldLatitude] != "" && fields[sbsFieldLongitude] != "" {
+		lat, err := strconv.ParseFloat(fields[sbsFieldLatitude], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing latitude")
+		}
+		lon, err := strconv.ParseFloat(fields[sbsFieldLongitude], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing longitude")
+		}
+		msg.Latitude = strconv.FormatFloat(lat, 'f', 8, 64)
+		msg.Longitude = strconv.FormatFloat(lon, 'f', 8, 64)
+	}
+	if v := fields[sbsFieldVerticalRate]; v != "" {
+		rate, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing vertical rate")
+		}
+		msg.HaveVerticalRateBarometric = true
+		msg.VerticalRateBarometric = rate
+	}
+	if v := fields[sbsFieldSquawk]; v != "" {
+		msg.Squawk = v
+	}
+	// flags are -1 when set, though some implementations use 1
+	if v := fields[sbsFieldIsOnGround]; v == "-1" || v == "1" {
+		msg.IsOnGround = true
+	}
+	return msg, nil
+}
+
+// FormatSbsMessage converts msg into BaseStation MSG records received at t.
+// A record is produced for each transmission type which has data in msg,
+// so the result is empty if msg doesn't contain anything useful.
+func FormatSbsMessage(msg *pb.Message, t time.Time) []string {
+	var lines []string
+	record := func(transmission int, set func(fields []string)) {
+		fields := make([]string, sbsNumFields)
+		fields[sbsFieldMessageType] = "MSG"
+		fields[sbsFieldTransmission] = strconv.Itoa(transmission)
+		fields[sbsFieldSessionID] = "1"
+		fields[sbsFieldAircraftID] = "1"
+		fields[sbsFieldIcao] = msg.Icao
+		fields[sbsFieldFlightID] = "1"
+		fields[sbsFieldDateGen] = t.Format("2006/01/02")
+		fields[sbsFieldTimeGen] = t.Format("15:04:05.000")
+		fields[sbsFieldDateLogged] = fields[sbsFieldDateGen]
+		fields[sbsFieldTimeLogged] = fields[sbsFieldTimeGen]
+		set(fields)
+		lines = append(lines, strings.Join(fields, ","))
+	}
+	onGround := func(fields []string) {
+		if msg.IsOnGround {
+			fields[sbsFieldIsOnGround] = "-1"
+		} else {
+			fields[sbsFieldIsOnGround] = "0"
+		}
+	}
+
+	if msg.CallSign != "" {
+		record(1, func(fields []string) {
+			fields[sbsFieldCallSign] = msg.CallSign
+		})
+	}
+	if msg.Latitude != "" && msg.Longitude != "" {
+		record(3, func(fields []string) {
+			fields[sbsFieldAltitude] = msg.AltitudeBarometric
+			fields[sbsFieldLatitude] = msg.Latitude
+			fields[sbsFieldLongitude] = msg.Longitude
+			onGround(fields)
+		})
+	} else if msg.AltitudeBarometric != "" && msg.Squawk == "" {
+		record(5, func(fields []string) {
+			fields[sbsFieldAltitude] = msg.AltitudeBarometric
+			onGround(fields)
+		})
+	}
+	if msg.GroundSpeed != "" || msg.Track != "" || msg.HaveVerticalRateBarometric || msg.HaveVerticalRateGeometric {
+		record(4, func(fields []string) {
+			fields[sbsFieldGroundSpeed] = msg.GroundSpeed
+			fields[sbsFieldTrack] = msg.Track
+			if msg.HaveVerticalRateBarometric {
+				fields[sbsFieldVerticalRate] = strconv.FormatInt(msg.VerticalRateBarometric, 10)
+			} else if msg.HaveVerticalRateGeometric {
+				fields[sbsFieldVerticalRate] = strconv.FormatInt(msg.VerticalRateGeometric, 10)
+			}
+		})
+	}
+	if msg.Squawk != "" {
+		record(6, func(fields []string) {
+			fields[sbsFieldAltitude] = msg.AltitudeBarometric
+			fields[sbsFieldSquawk] = msg.Squawk
+			onGround(fields)
+		})
+	}
+	return lines
+}
+
+// sbsDedupKey returns line without the date and time fields,
+// so the same message received twice produces the same key.
+func sbsDedupKey(line string) string {
+	fields := strings.Split(line, ",")
+	if len(fields) < sbsNumFields {
+		return line
+	}
+	for i := sbsFieldDateGen; i <= sbsFieldTimeLogged; i++ {
+		fields[i] = ""
+	}
+	return strings.Join(fields, ",")
+}
@@ -0,0 +1,150 @@
+package tracker
+
+import (
+	"github.com/afk11/airtrack/pkg/pb"
+	assert "github.com/stretchr/testify/require"
+	"net"
+	"testing"
+	"time"
+)
+
+// captured from a dump1090-fa port 30003
+var sbsTestLines = []string{
+	"MSG,1,1,1,4CA2D6,1,2020/10/18,12:00:00.000,2020/10/18,12:00:00.000,RYR4GW  ,,,,,,,,,,,0",
+	"MSG,3,1,1,4CA2D6,1,2020/10/18,12:00:00.100,2020/10/18,12:00:00.100,,37000,,,53.12345,-6.54321,,,0,0,0,0",
+	"MSG,4,1,1,4CA2D6,1,2020/10/18,12:00:00.200,2020/10/18,12:00:00.200,,,452,273.5,,,-64,,,,,0",
+	"MSG,6,1,1,4CA2D6,1,2020/10/18,12:00:00.300,2020/10/18,12:00:00.300,,37000,,,,,,7700,0,1,0,0",
+	"MSG,2,1,1,4ca2d6,1,2020/10/18,12:00:00.400,2020/10/18,12:00:00.400,,,12,90,53.42,-6.24,,,,,,-1",
+}
+
+func TestParseSbsMessage(t *testing.T) {
+	msgs := make([]*pb.Message, len(sbsTestLines))
+	for i, line := range sbsTestLines {
+		msg, err := ParseSbsMessage(line)
+		assert.NoError(t, err)
+		assert.NotNil(t, msg)
+		assert.Equal(t, "4CA2D6", msg.Icao)
+		msgs[i] = msg
+	}
+
+	assert.Equal(t, "RYR4GW", msgs[0].CallSign)
+	assert.False(t, msgs[0].IsOnGround)
+
+	assert.Equal(t, "37000", msgs[1].AltitudeBarometric)
+	assert.Equal(t, "53.12345000", msgs[1].Latitude)
+	assert.Equal(t, "-6.54321000", msgs[1].Longitude)
+
+	assert.Equal(t, "452.0", msgs[2].GroundSpeed)
+	assert.Equal(t, "273.500000", msgs[2].Track)
+	assert.True(t, msgs[2].HaveVerticalRateBarometric)
+	assert.Equal(t, int64(-64), msgs[2].VerticalRateBarometric)
+	assert.Equal(t, "", msgs[2].Latitude)
+
+	assert.Equal(t, "7700", msgs[3].Squawk)
+
+	assert.True(t, msgs[4].IsOnGround)
+
+	t.Run("ignored", func(t *testing.T) {
+		for _, line := range []string{
+			"SEL,,496,2286,4CA4E5,27215,2010/02/19,18:06:07.710,2010/02/19,18:06:07.710,RYR1427",
+			"STA,,5,179,400AE7,10103,2008/11/28,14:58:51.153,2008/11/28,14:58:51.153,RM",
+			"",
+		} {
+			msg, err := ParseSbsMessage(line)
+			assert.NoError(t, err)
+			assert.Nil(t, msg)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, line := range []string{
+			"MSG,3,1,1,4CA2D6,1",
+			"MSG,9,1,1,4CA2D6,1,2020/10/18,12:00:00.000,2020/10/18,12:00:00.000,,,,,,,,,,,,0",
+			"MSG,3,1,1,XYZ,1,2020/10/18,12:00:00.000,2020/10/18,12:00:00.000,,,,,,,,,,,,0",
+			"MSG,3,1,1,4CA2D6,1,2020/10/18,12:00:00.000,2020/10/18,12:00:00.000,,FL370,,,,,,,,,,0",
+			"MSG,3,1,1,4CA2D6,1,2020/10/18,12:00:00.000,2020/10/18,12:00:00.000,,,,,north,-6.5,,,,,,0",
+		} {
+			msg, err := ParseSbsMessage(line)
+			assert.Error(t, err, line)
+			assert.Nil(t, msg)
+		}
+	})
+}
+
+func TestFormatSbsMessage(t *testing.T) {
+	recvTime := time.Date(2020, 10, 18, 12, 0, 0, 100e6, time.UTC)
+	msg := &pb.Message{
+		Icao:                       "4CA2D6",
+		CallSign:                   "RYR4GW",
+		AltitudeBarometric:         "37000",
+		Latitude:                   "53.12345000",
+		Longitude:                  "-6.54321000",
+		GroundSpeed:                "452.0",
+		Track:                      "273.500000",
+		HaveVerticalRateBarometric: true,
+		VerticalRateBarometric:     -64,
+		Squawk:                     "7700",
+	}
+	lines := FormatSbsMessage(msg, recvTime)
+	assert.Equal(t, []string{
+		"MSG,1,1,1,4CA2D6,1,2020/10/18,12:00:00.100,2020/10/18,12:00:00.100,RYR4GW,,,,,,,,,,,",
+		"MSG,3,1,1,4CA2D6,1,2020/10/18,12:00:00.100,2020/10/18,12:00:00.100,,37000,,,53.12345000,-6.54321000,,,,,,0",
+		"MSG,4,1,1,4CA2D6,1,2020/10/18,12:00:00.100,2020/10/18,12:00:00.100,,,452.0,273.500000,,,-64,,,,,",
+		"MSG,6,1,1,4CA2D6,1,2020/10/18,12:00:00.100,2020/10/18,12:00:00.100,,37000,,,,,,7700,,,,0",
+	}, lines)
+
+	// records can be parsed by ParseSbsMessage
+	for _, line := range lines {
+		parsed, err := ParseSbsMessage(line)
+		assert.NoError(t, err)
+		assert.Equal(t, msg.Icao, parsed.Icao)
+	}
+
+	t.Run("altitude only", func(t *testing.T) {
+		lines := FormatSbsMessage(&pb.Message{Icao: "4CA2D6", AltitudeBarometric: "100", IsOnGround: true}, recvTime)
+		assert.Equal(t, []string{
+			"MSG,5,1,1,4CA2D6,1,2020/10/18,12:00:00.100,2020/10/18,12:00:00.100,,100,,,,,,,,,,-1",
+		}, lines)
+	})
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, 0, len(FormatSbsMessage(&pb.Message{Icao: "4CA2D6"}, recvTime)))
+	})
+}
+
+func TestSbsProducer(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+
+	// replays the captured lines, then drops the connection
+	// so the producer has to reconnect
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			for _, line := range sbsTestLines {
+				_, _ = conn.Write([]byte(line + "\r\n"))
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	msgs := make(chan *pb.Message)
+	addr := l.Addr().(*net.TCPAddr)
+	p := NewSbsProducer(msgs, "127.0.0.1", uint16(addr.Port), "standin")
+	p.reader.minReconnectDelay = time.Millisecond
+	p.Start()
+	defer p.Stop()
+
+	for i := 0; i < len(sbsTestLines)*2; i++ {
+		select {
+		case msg := <-msgs:
+			assert.Equal(t, "4CA2D6", msg.Icao)
+			assert.Equal(t, pb.Source_SbsServer, msg.Source.Type)
+			assert.Equal(t, "standin", msg.Source.Name)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("timeout waiting for message %d", i)
+		}
+	}
+}
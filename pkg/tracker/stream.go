@@ -0,0 +1,273 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// StreamNewAircraftEvent - SSE event name sent when a project
+	// opens a sighting, and for each aircraft in view when a client connects
+	StreamNewAircraftEvent = "new_aircraft"
+	// StreamUpdatedAircraftEvent - SSE event name sent when an
+	// aircraft's state is updated
+	StreamUpdatedAircraftEvent = "updated_aircraft"
+	// StreamLostAircraftEvent - SSE event name sent when a project
+	// closes a sighting
+	StreamLostAircraftEvent = "lost_aircraft"
+
+	// DefaultStreamBufferSize - the number of events buffered for
+	// a client before it's considered too slow and is disconnected
+	DefaultStreamBufferSize = 256
+	// DefaultStreamKeepAlive - the interval between keep-alive comments
+	// sent to each client. Also used as the write deadline.
+	DefaultStreamKeepAlive = time.Second * 15
+)
+
+type (
+	// streamClient is a single connection to the stream.
+	// events is never closed, done is closed when the client
+	// should be disconnected.
+	streamClient struct {
+		events    chan []byte
+		done      chan struct{}
+		closeOnce sync.Once
+	}
+
+	// AircraftStream pushes project aircraft updates to clients using
+	// Server-Sent Events. Implements MapService so it's served by the
+	// AircraftMap, and ProjectAircraftUpdateListener so it can be
+	// registered with the Tracker.
+	AircraftStream struct {
+		m          MapAccess
+		mu         sync.RWMutex
+		clients    map[string]map[*streamClient]struct{}
+		bufferSize int
+		keepAlive  time.Duration
+		done       chan struct{}
+		stopOnce   sync.Once
+	}
+)
+
+// close signals the client's handler to disconnect. Safe to
+// call more than once.
+func (c *streamClient) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+// NewAircraftStream creates a new AircraftStream. m is used to
+// send the aircraft currently in view when a client connects.
+func NewAircraftStream(m MapAccess) *AircraftStream {
+	return &AircraftStream{
+		m:          m,
+		clients:    make(map[string]map[*streamClient]struct{}),
+		bufferSize: DefaultStreamBufferSize,
+		keepAlive:  DefaultStreamKeepAlive,
+		done:       make(chan struct{}),
+	}
+}
+
+// MapService returns the name of the map service. See MapService.MapService.
+func (s *AircraftStream) MapService() string {
+	return StreamMapService
+}
+
+// RegisterRoutes registers the stream endpoint. See MapService.RegisterRoutes.
+func (s *AircraftStream) RegisterRoutes(r *mux.Router) error {
+	r.HandleFunc("/{project}", s.streamHandler).Methods("GET")
+	return nil
+}
+
+// UpdateHistory does nothing, the stream has no history.
+// See MapService.UpdateHistory.
+func (s *AircraftStream) UpdateHistory(projNames []string) error {
+	return nil
+}
+
+// NewAircraft - see ProjectAircraftUpdateListener.NewAircraft
+func (s *AircraftStream) NewAircraft(p *Project, sighting *Sighting) {
+	s.publish(p, StreamNewAircraftEvent, sighting)
+}
+
+// UpdatedAircraft - see ProjectAircraftUpdateListener.UpdatedAircraft
+func (s *AircraftStream) UpdatedAircraft(p *Project, sighting *Sighting) {
+	s.publish(p, StreamUpdatedAircraftEvent, sighting)
+}
+
+// LostAircraft - see ProjectAircraftUpdateListener.LostAircraft
+func (s *AircraftStream) LostAircraft(p *Project, sighting *Sighting) {
+	s.publish(p, StreamLostAircraftEvent, sighting)
+}
+
+// Stop disconnects all clients. Clients are served on hijacked
+// connections so are not closed by http.Server.Shutdown.
+func (s *AircraftStream) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// publish encodes the sighting and sends it to each of the projects
+// clients. This is called by the tracker so must not block: clients
+// whose buffer is full are disconnected, and will receive the current
+// aircraft when they reconnect.
+func (s *AircraftStream) publish(p *Project, event string, sighting *Sighting) {
+	if !p.ShouldMap {
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	clients := s.clients[p.Name]
+	if len(clients) == 0 {
+		return
+	}
+	ac := &JSONAircraft{
+		Hex: sighting.State.Icao,
+	}
+	ac.UpdateWithState(&sighting.State)
+	data, err := encodeStreamEvent(event, ac)
+	if err != nil {
+		log.Warnf("stream: %s", err.Error())
+		return
+	}
+	for c := range clients {
+		select {
+		case c.events <- data:
+		default:
+			log.Debugf("stream: disconnecting slow client (project %s)", p.Name)
+			c.close()
+		}
+	}
+}
+
+// subscribe registers a new client for project
+func (s *AircraftStream) subscribe(project string) *streamClient {
+	c := &streamClient{
+		events: make(chan []byte, s.bufferSize),
+		done:   make(chan struct{}),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.clients[project]; !ok {
+		s.clients[project] = make(map[*streamClient]struct{})
+	}
+	s.clients[project][c] = struct{}{}
+	return c
+}
+
+// unsubscribe removes the client for project
+func (s *AircraftStream) unsubscribe(project string, c *streamClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.clients[project], c)
+	if len(s.clients[project]) == 0 {
+		delete(s.clients, project)
+	}
+}
+
+// streamHandler serves the event stream for a project. The client is
+// subscribed before the aircraft currently in view are sent, so no
+// update is missed. The connection is hijacked so the server's write
+// timeout doesn't apply.
+func (s *AircraftStream) streamHandler(w http.ResponseWriter, r *http.Request) {
+	projName := mux.Vars(r)["project"]
+	c := s.subscribe(projName)
+	defer s.unsubscribe(projName, c)
+
+	var snapshot [][]byte
+	err := s.m.GetProjectAircraft(projName, func(_ int64, aircraft []*JSONAircraft) error {
+		snapshot = make([][]byte, 0, len(aircraft))
+		for _, ac := range aircraft {
+			data, err := encodeStreamEvent(StreamNewAircraftEvent, ac)
+			if err != nil {
+				return err
+			}
+			snapshot = append(snapshot, data)
+		}
+		return nil
+	})
+	if err == ErrUnknownProject {
+		http.Error(w, "unknown project", http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Warnf("stream: %s", err.Error())
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	header := w.Header().Clone()
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		log.Warnf("stream: hijacking connection: %s", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	write := func(data []byte) error {
+		err := conn.SetWriteDeadline(time.Now().Add(s.keepAlive))
+		if err != nil {
+			return err
+		}
+		if _, err = rw.Write(data); err != nil {
+			return err
+		}
+		return rw.Flush()
+	}
+
+	err = write(streamResponseHeader(header))
+	for i := 0; err == nil && i < len(snapshot); i++ {
+		err = write(snapshot[i])
+	}
+
+	keepAlive := time.NewTicker(s.keepAlive)
+	defer keepAlive.Stop()
+	for err == nil {
+		select {
+		case data := <-c.events:
+			err = write(data)
+		case <-keepAlive.C:
+			err = write([]byte(": keep-alive\n\n"))
+		case <-c.done:
+			return
+		case <-s.done:
+			return
+		}
+	}
+	log.Debugf("stream: client disconnected (project %s): %s", projName, err.Error())
+}
+
+// streamResponseHeader produces the HTTP response header for the
+// event stream. The body is terminated by closing the connection.
+func streamResponseHeader(header http.Header) []byte {
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "close")
+	var b bytes.Buffer
+	b.WriteString("HTTP/1.1 200 OK\r\n")
+	_ = header.Write(&b)
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
+
+// encodeStreamEvent encodes ac as a Server-Sent Event
+func encodeStreamEvent(event string, ac *JSONAircraft) ([]byte, error) {
+	data, err := json.Marshal(ac)
+	if err != nil {
+		return nil, errors.Wrapf(err, "encoding %s event", event)
+	}
+	return []byte(fmt.Sprintf("event: %s\ndata: %s\n\n", event, data)), nil
+}
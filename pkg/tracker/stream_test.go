@@ -0,0 +1,91 @@
+package tracker
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/afk11/airtrack/pkg/config"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/gorilla/mux"
+	assert "github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// readStreamEvent reads the next event from r, skipping comments
+func readStreamEvent(t *testing.T, r *bufio.Reader) (string, *JSONAircraft) {
+	var event string
+	ac := &JSONAircraft{}
+	for {
+		line, err := r.ReadString('\n')
+		assert.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && event != "":
+			return event, ac
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), ac))
+		}
+	}
+}
+
+func TestAircraftStream(t *testing.T) {
+	m, err := NewAircraftMap(&config.MapSettings{})
+	assert.NoError(t, err)
+	p1, err := InitProject(config.Project{Name: "project-one"})
+	assert.NoError(t, err)
+	NewMapProjectStatusListener(m).Activated(p1)
+	mapListener := NewMapProjectAircraftUpdateListener(m)
+
+	stream := NewAircraftStream(m)
+	defer stream.Stop()
+	r := mux.NewRouter()
+	assert.NoError(t, stream.RegisterRoutes(r.PathPrefix("/"+stream.MapService()).Subrouter()))
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	t.Run("unknown project", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "/stream/unknown")
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("events", func(t *testing.T) {
+		s1 := &Sighting{State: pb.State{Icao: "424242", CallSign: "AF1"}}
+		mapListener.NewAircraft(p1, s1)
+
+		res, err := http.Get(srv.URL + "/stream/project-one")
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+		rd := bufio.NewReader(res.Body)
+
+		// aircraft already in view are sent first
+		event, ac := readStreamEvent(t, rd)
+		assert.Equal(t, StreamNewAircraftEvent, event)
+		assert.Equal(t, "424242", ac.Hex)
+		assert.Equal(t, "AF1", ac.Flight)
+
+		s1.State.Squawk = "7700"
+		stream.UpdatedAircraft(p1, s1)
+		event, ac = readStreamEvent(t, rd)
+		assert.Equal(t, StreamUpdatedAircraftEvent, event)
+		assert.Equal(t, "7700", ac.Squawk)
+
+		s2 := &Sighting{State: pb.State{Icao: "ABCDEF"}}
+		stream.NewAircraft(p1, s2)
+		event, ac = readStreamEvent(t, rd)
+		assert.Equal(t, StreamNewAircraftEvent, event)
+		assert.Equal(t, "ABCDEF", ac.Hex)
+
+		stream.LostAircraft(p1, s1)
+		event, ac = readStreamEvent(t, rd)
+		assert.Equal(t, StreamLostAircraftEvent, event)
+		assert.Equal(t, "424242", ac.Hex)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/afk11/airtrack/pkg/aircraft/ccode"
+	"github.com/afk11/airtrack/pkg/coverage"
 	"github.com/afk11/airtrack/pkg/db"
 	"github.com/afk11/airtrack/pkg/email"
 	"github.com/afk11/airtrack/pkg/geo"
@@ -14,6 +15,9 @@ import (
 	"github.com/afk11/airtrack/pkg/pb"
 	"github.com/afk11/airtrack/pkg/readsb"
 	"github.com/afk11/airtrack/pkg/readsb/aircraftdb"
+	"github.com/afk11/airtrack/pkg/watchlist"
+	"github.com/afk11/airtrack/pkg/webhook"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types"
 	"github.com/google/uuid"
@@ -21,6 +25,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
+	"math"
+	"os"
 	"strconv"
 	"sync"
 	"time"
@@ -29,6 +35,18 @@ import (
 
 const (
 	locationFetchBatchSize int64 = 500
+	// lostAircraftCheckInterval - time between checks for aircraft
+	// which have gone out of view
+	lostAircraftCheckInterval = time.Second * 5
+	// landingApproachAltitude - aircraft descending below this height
+	// (in ft) above the ground elevation are considered to be on approach
+	landingApproachAltitude int64 = 3000
+	// groundElevationMaxAltitude - the ground elevation is looked up for
+	// aircraft below this altitude (in ft). The highest airports are
+	// around 14000ft.
+	groundElevationMaxAltitude int64 = 18000
+	// feetPerMeter - converts airport elevations to feet
+	feetPerMeter = 3.28084
 	// DefaultNearestAirportMaxAltitude - default max altitude (in ft)
 	// for nearest airport
 	DefaultNearestAirportMaxAltitude int64 = 1400
@@ -41,11 +59,17 @@ const (
 	// DefaultGeoHashLength - length of geohashes to use when bucketing
 	// airlines by region
 	DefaultGeoHashLength uint = 4
+	// receiverUpdateInterval - the last seen time of a sighting's
+	// receiver is saved at most once per interval, and when the
+	// aircraft is lost
+	receiverUpdateInterval = time.Minute
 
 	// Dump1090MapService - name of the dump1090 map service
 	Dump1090MapService = "dump1090"
 	// Tar1090MapService - name of the tar1090 map service
 	Tar1090MapService = "tar1090"
+	// StreamMapService - name of the live aircraft stream service
+	StreamMapService = "stream"
 )
 
 var (
@@ -70,8 +94,56 @@ type (
 		// configured with the Sightings.LocationUpdateInterval configuration option.
 		LocationUpdateInterval time.Duration
 
+		// DedupWindow - frames received again within this window (eg,
+		// by several receivers) are only processed once. Zero disables
+		// de-duplication. It can be configured with the
+		// Sighting.DedupWindowMs configuration option.
+		DedupWindow time.Duration
+
+		// MaxPositionSpeed - positions implying the aircraft travelled
+		// faster than this speed (in knots) since the last accepted
+		// position are rejected. Zero disables the check.
+		MaxPositionSpeed float64
+
+		// UseMessageTime causes the tracker to use pb.Message.Time instead
+		// of the current time. Lost aircraft checks are triggered by the
+		// message time as well, so a replayed capture produces the same
+		// result regardless of how fast it's replayed.
+		UseMessageTime bool
+
+		// CheckpointFile - if set, the tracker state is written to this
+		// file every CheckpointInterval and on shutdown, and restored
+		// at startup if the file is newer than CheckpointMaxAge. Open
+		// sightings and sessions are left open on shutdown, so they
+		// continue after the restart.
+		CheckpointFile     string
+		CheckpointInterval time.Duration
+		CheckpointMaxAge   time.Duration
+
+		// TimeZone is used for the hour filter variable.
+		// If nil, the local time zone is used.
+		TimeZone *time.Location
+
+		// Output - if set, processed messages are written to
+		// its SBS output servers
+		Output *Output
+
 		AirportGeocoder *geo.NearestAirportGeocoder
 		Mailer          mailer.MailSender
+		Webhooks        webhook.Sender
+
+		// Zones - if set, State.Zones is updated with the zones
+		// containing the aircraft's location
+		Zones *geo.Zones
+
+		// Coverage - if set, positions are recorded in the coverage
+		// of the receiver which heard them
+		Coverage *coverage.Coverage
+
+		// Watchlists - if set, State.Watchlist is updated with the
+		// label of the first watchlist containing the aircraft, and
+		// filters can use the watchlist functions
+		Watchlists *watchlist.Watchlists
 
 		CountryCodes *iso3166.Store
 		Allocations  ccode.CountryAllocationSearcher
@@ -79,12 +151,15 @@ type (
 	}
 	// GeocodeLocation contains the result of a geocode search.
 	// If ok is false, the search was unsuccessful and the other fields are empty.
-	// If ok is true, the lat,long & address fields will be set.
+	// If ok is true, the lat,long & address fields will be set, along with
+	// the matched airport and its distance from lat,long.
 	GeocodeLocation struct {
-		ok      bool
-		lat     float64
-		long    float64
-		address string
+		ok       bool
+		lat      float64
+		long     float64
+		address  string
+		airport  geo.AirportRecord
+		distance float64
 	}
 	// callsignLog records a new callsign for a sighting and the time it was observed.
 	callsignLog struct {
@@ -102,16 +177,49 @@ type (
 		// not necessarily available if the sighting is new
 		sighting *db.Sighting
 	}
+	// phaseLog records a new flight phase for a sighting and the time it was observed.
+	phaseLog struct {
+		phase FlightPhase
+		time  time.Time
+		// sighting is only set in the database processing routine, as it's
+		// not necessarily available if the sighting is new
+		sighting *db.Sighting
+	}
 	// locationLog records a new alt/lat/lon position for a sighting and the time it was observed.
 	locationLog struct {
-		alt  int64
-		lat  float64
-		lon  float64
-		time time.Time
+		alt        int64
+		lat        float64
+		lon        float64
+		time       time.Time
+		provenance pb.Provenance
 		// sighting is only set in the database processing routine, as it's
 		// not necessarily available if the sighting is new
 		sighting *db.Sighting
 	}
+	// receiverLog records a receiver which heard the aircraft during a sighting.
+	// update is set if the receiver was already saved.
+	receiverLog struct {
+		receiver  string
+		firstSeen time.Time
+		lastSeen  time.Time
+		bestRssi  *float64
+		update    bool
+		// sighting is only set in the database processing routine, as it's
+		// not necessarily available if the sighting is new
+		sighting *db.Sighting
+	}
+	// observedReceiver contains what's known about a receiver which
+	// heard the aircraft. savedLastSeen is the last seen time written
+	// to the database, and dirty is set if the record needs to be saved.
+	observedReceiver struct {
+		firstSeen     time.Time
+		lastSeen      time.Time
+		haveRssi      bool
+		bestRssi      float64
+		saved         bool
+		savedLastSeen time.Time
+		dirty         bool
+	}
 	// ProjectObservation contains information about a sighting from the point of
 	// view of a particular project.
 	ProjectObservation struct {
@@ -126,13 +234,30 @@ type (
 		callsign     string
 		haveSquawk   bool
 		squawk       string
-
-		origin      *GeocodeLocation
-		destination *GeocodeLocation
+		phase        FlightPhase
+		// emergency is the code (see emergencyCode) for the current
+		// emergency episode, or empty if there isn't one
+		emergency string
+		// zones the aircraft was inside at the last location update
+		zones []string
+		// positionAnomalies is the number of position anomalies
+		// (see positionValidator) already notified
+		positionAnomalies int64
+		// ruleTriggered maps Rule names to the time they last triggered
+		ruleTriggered map[EmailNotification]time.Time
+		// receivers maps receiver names to what's known about them.
+		// Only used if the TrackReceivers feature is enabled.
+		receivers map[string]*observedReceiver
+
+		origin           *GeocodeLocation
+		destination      *GeocodeLocation
+		originSaved      bool
+		destinationSaved bool
 
 		dirty        bool
 		csLogs       []callsignLog
 		squawkLogs   []squawkLog
+		phaseLogs    []phaseLog
 		locationLogs []locationLog
 		haveAltBaro  bool
 		altitudeBaro int64
@@ -148,11 +273,12 @@ type (
 
 		tags SightingTags
 
-		haveLocation  bool
-		latitude      float64
-		longitude     float64
-		locationCount int64
-		mu            sync.RWMutex
+		haveLocation       bool
+		latitude           float64
+		longitude          float64
+		locationProvenance pb.Provenance
+		locationCount      int64
+		mu                 sync.RWMutex
 	}
 	// FlightTime contains calculated information about the flight time
 	FlightTime struct {
@@ -179,6 +305,9 @@ type (
 		observedBy        map[uint64]*ProjectObservation
 		onGroundCandidate bool
 		onGroundCounter   int64
+		phase             flightPhase
+		orbit             orbitDetector
+		position          positionValidator
 
 		mu sync.RWMutex
 	}
@@ -196,8 +325,28 @@ type (
 		consumerCanceller        context.CancelFunc
 		lostAcCanceller          context.CancelFunc
 		dbFlushCanceller         context.CancelFunc
+		checkpointCanceller      context.CancelFunc
 		consumerWG               sync.WaitGroup
+		notifyWG                 sync.WaitGroup
 		mailTemplates            *email.MailTemplates
+
+		// dedup detects messages received by several receivers.
+		// It's nil if opt.DedupWindow is zero.
+		dedup   *dedupCache
+		dedupMu sync.Mutex
+
+		// restore is the checkpoint read by New. It's used by
+		// AddProject and Restore, and is cleared by Restore.
+		// restoreStale is set if the checkpoint is older than
+		// opt.CheckpointMaxAge.
+		restore      *checkpoint
+		restoreStale bool
+
+		// clock is the latest message time. Only used if
+		// opt.UseMessageTime is set.
+		clock         time.Time
+		lastLostCheck time.Time
+		clockMu       sync.Mutex
 	}
 	// lostSighting contains information needed to process an aircraft
 	// that has gone out of view
@@ -207,10 +356,21 @@ type (
 		session *db.Session
 	}
 	// SightingTags contains some meta information about the flight.
+	// The tags are derived from the flight phase (see State.Phase),
+	// except for IsOrbiting.
 	SightingTags struct {
-		// IsInTakeoff - this is set to true if we observe the aircraft
-		// transitioning from on_ground=true to on_ground=false.
+		// IsInTakeoff - this is set to true while the aircraft
+		// is in the takeoff phase.
 		IsInTakeoff bool
+		// IsOnApproach - this is set to true while the aircraft
+		// is in the approach phase.
+		IsOnApproach bool
+		// HasLanded - this is set to true when the aircraft enters
+		// the landing phase, and cleared when it takes off again.
+		HasLanded bool
+		// IsOrbiting - this is set to true while the aircraft is
+		// circling or holding over an area (see orbitDetector).
+		IsOrbiting bool
 	}
 )
 
@@ -221,6 +381,8 @@ func NewProjectObservation(p *Project, s *Sighting, msgTime time.Time) *ProjectO
 		project:   p,
 		firstSeen: msgTime,
 		lastSeen:  msgTime,
+		// anomalies before the project observed the aircraft aren't notified
+		positionAnomalies: s.position.anomalies,
 	}
 }
 
@@ -289,6 +451,21 @@ func (o *ProjectObservation) SetSquawk(squawk string, track bool, msgTime time.T
 	return nil
 }
 
+// SetPhase updates the current flight phase for the sighting, and if
+// track is true, creates a phase log to be written to the database.
+func (o *ProjectObservation) SetPhase(phase FlightPhase, track bool, msgTime time.Time) {
+	if track {
+		if o.phase != PhaseUnknown {
+			log.Infof("[session %d] %s: updated phase %s -> %s", o.project.Session.ID, o.mem.State.Icao, o.phase, phase)
+		} else {
+			log.Infof("[session %d] %s: found phase %s", o.project.Session.ID, o.mem.State.Icao, phase)
+		}
+		o.dirty = true
+		o.phaseLogs = append(o.phaseLogs, phaseLog{phase, msgTime, nil})
+	}
+	o.phase = phase
+}
+
 // Squawk returns the current squawk, or an empty string if unknown
 func (o *ProjectObservation) Squawk() string {
 	return o.squawk
@@ -342,6 +519,12 @@ func (o *ProjectObservation) Location() (float64, float64) {
 	return o.latitude, o.longitude
 }
 
+// SetLocationProvenance sets how the next location passed to SetLocation
+// was obtained, so it's recorded with the location log.
+func (o *ProjectObservation) SetLocationProvenance(provenance pb.Provenance) {
+	o.locationProvenance = provenance
+}
+
 // SetLocation updates the current location for the sighting, and if track
 // is true, creates a location log to be written to the database.
 func (o *ProjectObservation) SetLocation(lat, lon float64, track bool, msgTime time.Time) error {
@@ -353,7 +536,7 @@ func (o *ProjectObservation) SetLocation(lat, lon float64, track bool, msgTime t
 	if track && o.haveAltBaro {
 		o.dirty = true
 		o.locationLogs = append(o.locationLogs, locationLog{
-			o.AltitudeBarometric(), lat, lon, msgTime, nil,
+			o.AltitudeBarometric(), lat, lon, msgTime, o.locationProvenance, nil,
 		})
 		log.Infof("[session %d] %s: new position: altitude %dft, position (%f, %f) #pos=%d",
 			o.project.Session.ID, o.mem.State.Icao, o.AltitudeBarometric(), lat, lon, o.locationCount)
@@ -368,6 +551,52 @@ func (o *ProjectObservation) SetLocation(lat, lon float64, track bool, msgTime t
 	return nil
 }
 
+// RecordReceiver notes that receiver heard the aircraft at msgTime. rssi
+// is the signal strength if haveRssi is set. The receiver is saved when
+// it's first seen, when the signal strength improves, and periodically
+// while it continues to hear the aircraft.
+func (o *ProjectObservation) RecordReceiver(receiver string, haveRssi bool, rssi float64, msgTime time.Time) {
+	if o.receivers == nil {
+		o.receivers = make(map[string]*observedReceiver)
+	}
+	r, ok := o.receivers[receiver]
+	if !ok {
+		r = &observedReceiver{
+			firstSeen: msgTime,
+			dirty:     true,
+		}
+		o.receivers[receiver] = r
+	}
+	if msgTime.After(r.lastSeen) {
+		r.lastSeen = msgTime
+	}
+	if haveRssi && (!r.haveRssi || rssi > r.bestRssi) {
+		r.haveRssi = true
+		r.bestRssi = rssi
+		r.dirty = true
+	}
+	if r.saved && r.lastSeen.Sub(r.savedLastSeen) >= receiverUpdateInterval {
+		r.dirty = true
+	}
+	if r.dirty {
+		o.dirty = true
+	}
+}
+
+// flushReceivers marks receivers which heard the aircraft since
+// they were last saved as dirty. Used when the aircraft is lost,
+// so the final last seen times are saved.
+func (o *ProjectObservation) flushReceivers() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, r := range o.receivers {
+		if r.lastSeen.After(r.savedLastSeen) {
+			r.dirty = true
+			o.dirty = true
+		}
+	}
+}
+
 // NewSighting initializes a new sighting for aircraft with this ICAO
 func NewSighting(icao string, now time.Time) *Sighting {
 	return &Sighting{
@@ -392,14 +621,30 @@ func New(database db.Database, opt Options) (*Tracker, error) {
 		return nil, errors.Wrapf(err, "loading email templates")
 	}
 
-	return &Tracker{
+	t := &Tracker{
 		sighting:                 make(map[string]*Sighting),
 		database:                 database,
 		opt:                      opt,
 		projectStatusListeners:   make([]ProjectStatusListener, 0),
 		projectAcUpdateListeners: make([]ProjectAircraftUpdateListener, 0),
 		mailTemplates:            tpls,
-	}, nil
+	}
+	if opt.DedupWindow > 0 {
+		t.dedup = newDedupCache(opt.DedupWindow)
+	}
+	if opt.CheckpointFile != "" {
+		if opt.CheckpointInterval <= 0 {
+			return nil, errors.New("invalid checkpoint interval - must be greater than zero")
+		}
+		t.restore, err = readCheckpoint(opt.CheckpointFile)
+		if err != nil {
+			return nil, err
+		}
+		if t.restore != nil {
+			t.restoreStale = time.Since(t.restore.WrittenAt) > opt.CheckpointMaxAge
+		}
+	}
+	return t, nil
 }
 
 // RegisterProjectStatusListener - accepts a new ProjectStatusListener to
@@ -432,11 +677,19 @@ func (t *Tracker) Start(msgs chan *pb.Message) {
 	}
 	lostAcCtx, lostAcCanceller := context.WithCancel(context.Background())
 	t.lostAcCanceller = lostAcCanceller
-	go t.checkForLostAircraft(lostAcCtx)
+	if !t.opt.UseMessageTime {
+		go t.checkForLostAircraft(lostAcCtx)
+	}
 
 	dbFlushCtx, dbFlushCanceller := context.WithCancel(context.Background())
 	t.dbFlushCanceller = dbFlushCanceller
 	go t.startDatabaseTask(dbFlushCtx)
+
+	checkpointCtx, checkpointCanceller := context.WithCancel(context.Background())
+	t.checkpointCanceller = checkpointCanceller
+	if t.opt.CheckpointFile != "" {
+		go t.startCheckpointTask(checkpointCtx)
+	}
 }
 
 func min(a, b int) int {
@@ -447,23 +700,44 @@ func min(a, b int) int {
 }
 
 // Stop begins the shutdown routine by signalling stop to goroutines,
-// flushes state to disk, and closes open sightings cleanly.
+// flushes state to disk, and closes open sightings cleanly. If
+// opt.CheckpointFile is set, the state is written to the checkpoint
+// file instead, and sightings and sessions are left open.
 func (t *Tracker) Stop() error {
 	log.Info("shutting down tracker")
 	log.Debug("await consumers to finish")
 	// Wait for consumers to finish their work
 	t.consumerWG.Wait()
+	log.Debug("await background notifications")
+	t.notifyWG.Wait()
 	log.Debug("cancel lost aircraft handler")
 	t.lostAcCanceller()
 	log.Debug("cancel background database updates")
 	t.dbFlushCanceller()
+	log.Debug("cancel checkpoints")
+	t.checkpointCanceller()
 	log.Debug("flush database updates")
 	err := t.processDatabaseUpdates()
 	if err != nil {
 		return errors.Wrapf(err, "flushing database updates")
 	}
 
+	if t.opt.CheckpointFile != "" {
+		err = t.saveCheckpoint()
+		if err == nil {
+			return t.stopWithCheckpoint()
+		}
+		// the previous checkpoint is out of date once
+		// the sightings are closed
+		log.Warnf("failed to write checkpoint, closing sightings: %s", err.Error())
+		if err := os.Remove(t.opt.CheckpointFile); err != nil && !os.IsNotExist(err) {
+			log.Warnf("failed to remove checkpoint: %s", err.Error())
+		}
+	}
+
 	// Take lock ourselves to cleanup pSightings and delete map
+	t.projectMu.RLock()
+	defer t.projectMu.RUnlock()
 	t.sightingMu.Lock()
 	defer t.sightingMu.Unlock()
 
@@ -501,7 +775,7 @@ func (t *Tracker) Stop() error {
 	log.Infof("closed with %d aircraft being monitored", pAircraft)
 
 	t.sighting = make(map[string]*Sighting)
-	now := time.Now()
+	now := t.now()
 	// Split this into batches, full list can cause too many variables sqlite error
 	limit := 100
 	for i := 0; i < len(pSightings); i += limit {
@@ -512,8 +786,6 @@ func (t *Tracker) Stop() error {
 	}
 
 	// Close session
-	t.projectMu.RLock()
-	defer t.projectMu.RUnlock()
 	for _, p := range t.projects {
 		res, err := t.database.CloseSession(p.Session, now)
 		if err != nil {
@@ -556,18 +828,26 @@ func (t *Tracker) AddProject(p *Project) error {
 		return errors.Wrap(err, "query project")
 	}
 
-	sessID, err := uuid.NewUUID()
-	if err != nil {
-		return errors.Wrap(err, "failed to generate session id")
-	}
-	_, err = t.database.CreateSession(project, sessID.String(),
-		p.IsFeatureEnabled(TrackSquawks), p.IsFeatureEnabled(TrackTxTypes), p.IsFeatureEnabled(TrackCallSigns))
+	// resume the session from the checkpoint if possible
+	session, err := t.restoredSession(project, p)
 	if err != nil {
-		return errors.Wrap(err, "create session record")
-	}
-	session, err := t.database.GetSessionByIdentifier(project, sessID.String())
-	if err != nil {
-		return errors.Wrap(err, "load session record")
+		return err
+	} else if session != nil {
+		log.Infof("[session %d] resuming session of project %s", session.ID, p.Name)
+	} else {
+		sessID, err := uuid.NewUUID()
+		if err != nil {
+			return errors.Wrap(err, "failed to generate session id")
+		}
+		_, err = t.database.CreateSession(project, sessID.String(),
+			p.IsFeatureEnabled(TrackSquawks), p.IsFeatureEnabled(TrackTxTypes), p.IsFeatureEnabled(TrackCallSigns))
+		if err != nil {
+			return errors.Wrap(err, "create session record")
+		}
+		session, err = t.database.GetSessionByIdentifier(project, sessID.String())
+		if err != nil {
+			return errors.Wrap(err, "load session record")
+		}
 	}
 	p.Project = project
 	p.Session = session
@@ -585,6 +865,176 @@ func (t *Tracker) AddProject(p *Project) error {
 	return nil
 }
 
+// IsObserving returns whether the named project currently
+// has an observation for the aircraft
+func (t *Tracker) IsObserving(project string, icao string) bool {
+	t.projectMu.RLock()
+	defer t.projectMu.RUnlock()
+	for _, p := range t.projects {
+		if p.Name == project {
+			return p.IsObserving(icao)
+		}
+	}
+	return false
+}
+
+// UpdateProject applies the configuration of p to the active project
+// with the same name. The session and open sightings are kept, so
+// aircraft in view continue to be tracked by the project, and the
+// features recorded by the session are updated. Aircraft
+// which no longer pass the filter are lost after the sighting timeout.
+// The project is modified with projectMu locked, so code reading the
+// configuration of a project must hold projectMu.RLock. projectMu is
+// always taken before sightingMu.
+func (t *Tracker) UpdateProject(p *Project) error {
+	t.projectMu.Lock()
+	defer t.projectMu.Unlock()
+
+	var existing *Project
+	for _, other := range t.projects {
+		if other.Name == p.Name {
+			existing = other
+			break
+		}
+	}
+	if existing == nil {
+		return errors.Errorf("unknown project %s", p.Name)
+	}
+	if p.IsFeatureEnabled(GeocodeEndpoints) && t.opt.AirportGeocoder == nil {
+		return errors.Errorf("geocoder must be available for %s feature to work", GeocodeEndpoints)
+	}
+
+	// the session records which data is tracked
+	withSquawks := p.IsFeatureEnabled(TrackSquawks)
+	withTxTypes := p.IsFeatureEnabled(TrackTxTypes)
+	withCallSigns := p.IsFeatureEnabled(TrackCallSigns)
+	if withSquawks != existing.Session.WithSquawks ||
+		withTxTypes != existing.Session.WithTransmissionTypes ||
+		withCallSigns != existing.Session.WithCallSigns {
+		_, err := t.database.UpdateSessionFeatures(existing.Session, withSquawks, withTxTypes, withCallSigns)
+		if err != nil {
+			return errors.Wrapf(err, "updating session features")
+		}
+	}
+
+	// the map needs to add or remove the project
+	mapChanged := existing.ShouldMap != p.ShouldMap
+	numListeners := len(t.projectStatusListeners)
+	if mapChanged {
+		for i := 0; i < numListeners; i++ {
+			t.projectStatusListeners[i].Deactivated(existing)
+		}
+	}
+
+	existing.ShouldMap = p.ShouldMap
+	existing.Filter = p.Filter
+	existing.Program = p.Program
+	existing.Features = p.Features
+	existing.NotifyEmail = p.NotifyEmail
+	existing.EmailNotifications = p.EmailNotifications
+	existing.Webhooks = p.Webhooks
+	existing.Rules = p.Rules
+	existing.ReopenSightings = p.ReopenSightings
+	existing.ReopenSightingsInterval = p.ReopenSightingsInterval
+	existing.OnGroundUpdateThreshold = p.OnGroundUpdateThreshold
+	existing.HasLocationUpdateInterval = p.HasLocationUpdateInterval
+	existing.LocationUpdateInterval = p.LocationUpdateInterval
+	if !existing.HasLocationUpdateInterval {
+		existing.LocationUpdateInterval = t.opt.LocationUpdateInterval
+	}
+
+	if mapChanged {
+		for i := 0; i < numListeners; i++ {
+			t.projectStatusListeners[i].Activated(existing)
+		}
+	}
+	return nil
+}
+
+// RemoveProject removes the named project from the tracker. Its open
+// sightings and session are closed, as if the tracker was stopped.
+// Sends Deactivated event to ProjectStatusListeners
+func (t *Tracker) RemoveProject(name string) error {
+	t.projectMu.Lock()
+	idx := -1
+	for i, other := range t.projects {
+		if other.Name == name {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		t.projectMu.Unlock()
+		return errors.Errorf("unknown project %s", name)
+	}
+	p := t.projects[idx]
+	projects := make([]*Project, 0, len(t.projects)-1)
+	projects = append(projects, t.projects[:idx]...)
+	t.projects = append(projects, t.projects[idx+1:]...)
+	// Consumers no longer see p, and UpdateProject can't modify it,
+	// so the sightings are closed without blocking other projects.
+	t.projectMu.Unlock()
+
+	err := t.closeProjectSightings(p)
+	if err != nil {
+		return err
+	}
+	res, err := t.database.CloseSession(p.Session, t.now())
+	if err != nil {
+		return errors.Wrapf(err, "closing session")
+	} else if err = db.CheckRowsUpdated(res, 1); err != nil {
+		return errors.Wrap(err, "should have updated 1 session")
+	}
+
+	numListeners := len(t.projectStatusListeners)
+	for i := 0; i < numListeners; i++ {
+		t.projectStatusListeners[i].Deactivated(p)
+	}
+	log.Infof("removed project %s", p.Name)
+	return nil
+}
+
+// closeProjectSightings processes every aircraft observed by p as lost,
+// and closes the sightings. Other projects observing the same aircraft
+// are unaffected.
+func (t *Tracker) closeProjectSightings(p *Project) error {
+	t.sightingMu.Lock()
+	defer t.sightingMu.Unlock()
+
+	pSightings := make([]*db.Sighting, 0)
+	for _, sighting := range t.sighting {
+		sighting.mu.Lock()
+		observation, ok := sighting.observedBy[p.Session.ID]
+		if !ok {
+			sighting.mu.Unlock()
+			continue
+		}
+		err := t.handleLostAircraft(p, sighting)
+		if err != nil {
+			sighting.mu.Unlock()
+			return errors.Wrap(err, "processing lost aircraft")
+		}
+		if observation.sighting != nil {
+			pSightings = append(pSightings, observation.sighting)
+		}
+		p.obsMu.Lock()
+		delete(p.Observations, sighting.State.Icao)
+		p.obsMu.Unlock()
+		delete(sighting.observedBy, p.Session.ID)
+		sighting.mu.Unlock()
+	}
+
+	now := t.now()
+	limit := 100
+	for i := 0; i < len(pSightings); i += limit {
+		err := t.database.CloseSightingBatch(pSightings[i:min(i+limit, len(pSightings))], now)
+		if err != nil {
+			return errors.Wrapf(err, "closing batch of sightings")
+		}
+	}
+	return nil
+}
+
 // startDatabaseTask is a goroutine that periodically writes state
 // to disk, and stops if the stop signal is received from ctx.
 func (t *Tracker) startDatabaseTask(ctx context.Context) {
@@ -611,11 +1061,13 @@ func (t *Tracker) processDatabaseUpdates() error {
 	updatedSightings := 0
 	var csUpdates []callsignLog
 	var squawkUpdates []squawkLog
+	var phaseUpdates []phaseLog
 	var locationUpdates []locationLog
+	var receiverUpdates []receiverLog
 	for _, proj := range t.projects {
 		proj.obsMu.RLock()
 		for _, o := range proj.Observations {
-			createdSighting, csLogs, squawkLogs, locationLogs, err := t.updateSightingAndReturnLogs(o)
+			createdSighting, csLogs, squawkLogs, phaseLogs, locationLogs, receiverLogs, err := t.updateSightingAndReturnLogs(o)
 			if err != nil {
 				proj.obsMu.RUnlock()
 				return err
@@ -625,40 +1077,49 @@ func (t *Tracker) processDatabaseUpdates() error {
 			}
 			csUpdates = append(csUpdates, csLogs...)
 			squawkUpdates = append(squawkUpdates, squawkLogs...)
+			phaseUpdates = append(phaseUpdates, phaseLogs...)
 			locationUpdates = append(locationUpdates, locationLogs...)
+			receiverUpdates = append(receiverUpdates, receiverLogs...)
 		}
 		proj.obsMu.RUnlock()
 	}
 
-	err := t.writeUpdates(csUpdates, squawkUpdates, locationUpdates)
+	err := t.writeUpdates(csUpdates, squawkUpdates, phaseUpdates, locationUpdates, receiverUpdates)
 	if err != nil {
 		return errors.Wrapf(err, "write updates")
 	}
 	timeTaken := time.Since(begin)
 	numCsUpdates := len(csUpdates)
 	numSquawkUpdates := len(squawkUpdates)
+	numPhaseUpdates := len(phaseUpdates)
 	numLocationUpdates := len(locationUpdates)
-	log.Debugf("flushing updates (took %s)  sightings:%d  callsigns:%d  squawks:%d  locations:%d",
-		timeTaken, updatedSightings, numCsUpdates, numSquawkUpdates, numLocationUpdates)
+	numReceiverUpdates := len(receiverUpdates)
+	log.Debugf("flushing updates (took %s)  sightings:%d  callsigns:%d  squawks:%d  phases:%d  locations:%d  receivers:%d",
+		timeTaken, updatedSightings, numCsUpdates, numSquawkUpdates, numPhaseUpdates, numLocationUpdates, numReceiverUpdates)
 
 	return nil
 }
-func (t *Tracker) updateSightingAndReturnLogs(o *ProjectObservation) (bool, []callsignLog, []squawkLog, []locationLog, error) {
+func (t *Tracker) updateSightingAndReturnLogs(o *ProjectObservation) (bool, []callsignLog, []squawkLog, []phaseLog, []locationLog, []receiverLog, error) {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 	if o.sighting != nil && !o.dirty {
 		// not interesting, move on
-		return false, nil, nil, nil, nil
+		return false, nil, nil, nil, nil, nil, nil
 	}
 
 	hasNoSighting := o.sighting == nil
 	hasCsLogs := len(o.csLogs) > 0
 	hasSquawkLogs := len(o.squawkLogs) > 0
+	hasPhaseLogs := len(o.phaseLogs) > 0
 	hasLocations := len(o.locationLogs) > 0
+	hasOrigin := o.origin != nil && o.origin.ok && !o.originSaved
+	hasDestination := o.destination != nil && o.destination.ok && !o.destinationSaved
 	var csUpdates []callsignLog
 	var squawkUpdates []squawkLog
+	var phaseUpdates []phaseLog
 	var locationUpdates []locationLog
-	if hasNoSighting || hasCsLogs || hasSquawkLogs {
+	var receiverUpdates []receiverLog
+	if hasNoSighting || hasCsLogs || hasSquawkLogs || hasOrigin || hasDestination {
 		// Updates regarding the sighting record (also to gather build up inserts for batching)
 		err := t.database.Transaction(func(tx *sqlx.Tx) error {
 			var err error
@@ -697,16 +1158,42 @@ func (t *Tracker) updateSightingAndReturnLogs(o *ProjectObservation) (bool, []ca
 				squawkUpdates = o.squawkLogs
 				o.squawkLogs = nil
 			}
+			// a reopened sighting keeps the origin it was first seen with
+			if hasOrigin && o.sighting.OriginCode == nil {
+				_, err := t.database.UpdateSightingOriginTx(tx, o.sighting, o.origin.sightingAirport())
+				if err != nil {
+					return errors.Wrap(err, "updating sighting origin")
+				}
+			}
+			if hasDestination {
+				_, err := t.database.UpdateSightingDestinationTx(tx, o.sighting, o.destination.sightingAirport())
+				if err != nil {
+					return errors.Wrap(err, "updating sighting destination")
+				}
+			}
 
 			return nil
 		})
 		if err != nil {
-			return false, nil, nil, nil, err
+			return false, nil, nil, nil, nil, nil, err
+		}
+		if hasOrigin {
+			o.originSaved = true
+		}
+		if hasDestination {
+			o.destinationSaved = true
 		}
 	}
 
-	// Locations is processed separately - if we only have locations, we avoid
-	// the above transaction
+	// Phases and locations are processed separately - if we only have these,
+	// we avoid the above transaction
+	if hasPhaseLogs {
+		for i := 0; i < len(o.phaseLogs); i++ {
+			(&o.phaseLogs[i]).sighting = o.sighting
+		}
+		phaseUpdates = o.phaseLogs
+		o.phaseLogs = nil
+	}
 	if hasLocations {
 		for i := 0; i < len(o.locationLogs); i++ {
 			(&o.locationLogs[i]).sighting = o.sighting
@@ -714,18 +1201,40 @@ func (t *Tracker) updateSightingAndReturnLogs(o *ProjectObservation) (bool, []ca
 		locationUpdates = o.locationLogs
 		o.locationLogs = nil
 	}
+	for receiver, r := range o.receivers {
+		if !r.dirty {
+			continue
+		}
+		l := receiverLog{
+			receiver:  receiver,
+			firstSeen: r.firstSeen,
+			lastSeen:  r.lastSeen,
+			update:    r.saved,
+			sighting:  o.sighting,
+		}
+		if r.haveRssi {
+			rssi := r.bestRssi
+			l.bestRssi = &rssi
+		}
+		receiverUpdates = append(receiverUpdates, l)
+		r.saved = true
+		r.savedLastSeen = r.lastSeen
+		r.dirty = false
+	}
 
 	o.dirty = false
 
-	return hasNoSighting, csUpdates, squawkUpdates, locationUpdates, nil
+	return hasNoSighting, csUpdates, squawkUpdates, phaseUpdates, locationUpdates, receiverUpdates, nil
 }
-func (t *Tracker) writeUpdates(csUpdates []callsignLog, squawkUpdates []squawkLog, locationUpdates []locationLog) error {
+func (t *Tracker) writeUpdates(csUpdates []callsignLog, squawkUpdates []squawkLog, phaseUpdates []phaseLog, locationUpdates []locationLog, receiverUpdates []receiverLog) error {
 	csBatch := 100
 	numCsUpdates := len(csUpdates)
 	numSquawkUpdates := len(squawkUpdates)
+	numPhaseUpdates := len(phaseUpdates)
 	numLocationUpdates := len(locationUpdates)
+	numReceiverUpdates := len(receiverUpdates)
 
-	// Insert new callsigns, squawks, and location logs in batches
+	// Insert new callsigns, squawks, phases, location logs, and receivers in batches
 	for i := 0; i < numCsUpdates; i += csBatch {
 		err := t.database.Transaction(func(tx *sqlx.Tx) error {
 			var err error
@@ -758,12 +1267,28 @@ func (t *Tracker) writeUpdates(csUpdates []callsignLog, squawkUpdates []squawkLo
 			return errors.Wrapf(err, "in transaction")
 		}
 	}
+	for i := 0; i < numPhaseUpdates; i += csBatch {
+		err := t.database.Transaction(func(tx *sqlx.Tx) error {
+			var err error
+			last := min(numPhaseUpdates, i+csBatch)
+			for j := i; j < last; j++ {
+				_, err = t.database.CreateNewSightingPhaseTx(tx, phaseUpdates[j].sighting, string(phaseUpdates[j].phase), phaseUpdates[j].time)
+				if err != nil {
+					return errors.Wrap(err, "creating phase record")
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "in transaction")
+		}
+	}
 	for i := 0; i < numLocationUpdates; i += csBatch {
 		err := t.database.Transaction(func(tx *sqlx.Tx) error {
 			last := min(numLocationUpdates, i+csBatch)
 			for j := i; j < last; j++ {
 				_, err := t.database.CreateSightingLocationTx(tx, locationUpdates[j].sighting.ID, locationUpdates[j].time,
-					locationUpdates[j].alt, locationUpdates[j].lat, locationUpdates[j].lon)
+					locationUpdates[j].alt, locationUpdates[j].lat, locationUpdates[j].lon, locationProvenance(locationUpdates[j].provenance))
 				if err != nil {
 					return errors.Wrapf(err, "failed to insert sighting location")
 				}
@@ -774,13 +1299,34 @@ func (t *Tracker) writeUpdates(csUpdates []callsignLog, squawkUpdates []squawkLo
 			return errors.Wrapf(err, "in transaction")
 		}
 	}
+	for i := 0; i < numReceiverUpdates; i += csBatch {
+		err := t.database.Transaction(func(tx *sqlx.Tx) error {
+			var err error
+			last := min(numReceiverUpdates, i+csBatch)
+			for j := i; j < last; j++ {
+				r := receiverUpdates[j]
+				if r.update {
+					_, err = t.database.UpdateSightingReceiverTx(tx, r.sighting, r.receiver, r.lastSeen, r.bestRssi)
+				} else {
+					_, err = t.database.CreateSightingReceiverTx(tx, r.sighting, r.receiver, r.firstSeen, r.lastSeen, r.bestRssi)
+				}
+				if err != nil {
+					return errors.Wrap(err, "saving receiver record")
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "in transaction")
+		}
+	}
 	return nil
 }
 
 // checkForLostAircraft is a goroutine that periodically calls doLostAircraftCheck
 // and stops once the stop signal is received.
 func (t *Tracker) checkForLostAircraft(ctx context.Context) {
-	waitTime := time.Second * 5
+	waitTime := lostAircraftCheckInterval
 	for {
 		select {
 		case <-time.After(waitTime):
@@ -803,18 +1349,21 @@ func (t *Tracker) checkForLostAircraft(ctx context.Context) {
 //   - no projects are interested -> it's deleted from the map in this section.
 //   - at least one project is interested -> it remains locked and is included in results
 func (t *Tracker) doLostAircraftCheck() error {
+	t.projectMu.RLock()
+	defer t.projectMu.RUnlock()
 	t.sightingMu.Lock()
 	defer t.sightingMu.Unlock()
 
+	now := t.now()
 	lostSightings := make([]lostSighting, 0)
 	lostDbSightings := make([]*db.Sighting, 0)
 	for _, sighting := range t.sighting {
 		sighting.mu.Lock()
-		lostForAll := time.Since(sighting.lastSeen) > t.opt.SightingTimeout
+		lostForAll := now.Sub(sighting.lastSeen) > t.opt.SightingTimeout
 		if len(sighting.observedBy) > 0 {
 			lostForProject := false
 			for _, observation := range sighting.observedBy {
-				if lostForAll || time.Since(observation.lastSeen) > t.opt.SightingTimeout {
+				if lostForAll || now.Sub(observation.lastSeen) > t.opt.SightingTimeout {
 					lostSightings = append(lostSightings, lostSighting{
 						project: observation.project,
 						s:       sighting,
@@ -876,7 +1425,6 @@ func (t *Tracker) doLostAircraftCheck() error {
 	}()
 
 	// Do this in batches, ensure we don't get too many variables error from sqlite
-	now := time.Now()
 	limit := 100
 	for i := 0; i < len(lostDbSightings); i += limit {
 		err := t.database.CloseSightingBatch(lostDbSightings[i:min(i+limit, len(lostDbSightings))], now)
@@ -895,27 +1443,51 @@ func (t *Tracker) doLostAircraftCheck() error {
 	return nil
 }
 
+// sightingAirport converts the matched airport into a db.SightingAirport
+func (l *GeocodeLocation) sightingAirport() db.SightingAirport {
+	return db.SightingAirport{
+		Code:      l.airport.Code,
+		Name:      l.airport.Name,
+		Latitude:  l.airport.Latitude,
+		Longitude: l.airport.Longitude,
+		Distance:  l.distance,
+	}
+}
+
+// groundElevation returns the elevation (in ft) of the nearest airport
+// within NearestAirportMaxDistance, or zero if there isn't one.
+func (t *Tracker) groundElevation(lat float64, lon float64) int64 {
+	airport, distance := t.opt.AirportGeocoder.NearestAirport(lat, lon)
+	if airport == nil || distance > t.opt.NearestAirportMaxDistance {
+		return 0
+	}
+	return int64(airport.Elevation * feetPerMeter)
+}
+
 // reverseGeocode attempts to determine the nearest airport for the provided
 // latitude and longitude. The nearest airport is only accepted if we are
 // within 'NearestAirportMaxDistance' in range
 func (t *Tracker) reverseGeocode(lat float64, lon float64) (*GeocodeLocation, float64, error) {
 	location := &GeocodeLocation{}
-	addr, distance := t.opt.AirportGeocoder.ReverseGeocode(lat, lon)
-	if addr == "" || distance > t.opt.NearestAirportMaxDistance {
-		if addr != "" {
-			log.Debugf("nearest airport %s is too away (%f is over limit %f)", addr, distance, t.opt.NearestAirportMaxDistance)
+	airport, distance := t.opt.AirportGeocoder.NearestAirport(lat, lon)
+	if airport == nil || distance > t.opt.NearestAirportMaxDistance {
+		if airport != nil {
+			log.Debugf("nearest airport %s is too away (%f is over limit %f)", airport.Name, distance, t.opt.NearestAirportMaxDistance)
 		}
 		location.ok = false
 	} else {
 		location.ok = true
 		location.lat = lat
 		location.long = lon
-		location.address = addr
+		location.address = airport.Name
+		location.airport = *airport
+		location.distance = distance
 	}
 	return location, distance, nil
 }
 
-// needs to be called with t.sightingMu locked
+// needs to be called with t.sightingMu locked, and with t.projectMu
+// read locked unless project was already removed from the tracker
 func (t *Tracker) handleLostAircraft(project *Project, sighting *Sighting) error {
 	observation, ok := sighting.observedBy[project.Session.ID]
 	if !ok {
@@ -923,9 +1495,10 @@ func (t *Tracker) handleLostAircraft(project *Project, sighting *Sighting) error
 	}
 
 	log.Infof("[session %d] %s: lost aircraft (firstSeen: %s, duration: %s)",
-		project.Session.ID, sighting.State.Icao, observation.firstSeen.Format(time.RFC822), time.Since(observation.firstSeen))
+		project.Session.ID, sighting.State.Icao, observation.firstSeen.Format(time.RFC822), t.now().Sub(observation.firstSeen))
 
-	if project.IsFeatureEnabled(GeocodeEndpoints) && observation.HaveLocation() {
+	// if the aircraft landed, the destination was found at touchdown
+	if project.IsFeatureEnabled(GeocodeEndpoints) && observation.HaveLocation() && !observation.tags.HasLanded {
 		if observation.AltitudeBarometric() > t.opt.NearestAirportMaxAltitude {
 			// too high for an airport
 			log.Debugf("[session %d] %s: too high to determine destination location",
@@ -945,6 +1518,9 @@ func (t *Tracker) handleLostAircraft(project *Project, sighting *Sighting) error
 					project.Session.ID, sighting.State.Icao, observation.latitude, observation.longitude)
 			}
 			observation.destination = location
+			if location.ok {
+				observation.dirty = true
+			}
 		}
 	}
 
@@ -952,11 +1528,12 @@ func (t *Tracker) handleLostAircraft(project *Project, sighting *Sighting) error
 	// necessary for aircraft that go out of range, with a location in memory, but none
 	// in the table yet. when handling session close, we have already processed updates, so
 	// this shouldn't have any major cost
-	_, csLogs, squawkLogs, locationLogs, err := t.updateSightingAndReturnLogs(observation)
+	observation.flushReceivers()
+	_, csLogs, squawkLogs, phaseLogs, locationLogs, receiverLogs, err := t.updateSightingAndReturnLogs(observation)
 	if err != nil {
 		return errors.Wrapf(err, "updateSightingAndReturnLogs")
 	}
-	err = t.writeUpdates(csLogs, squawkLogs, locationLogs)
+	err = t.writeUpdates(csLogs, squawkLogs, phaseLogs, locationLogs, receiverLogs)
 	if err != nil {
 		return errors.Wrapf(err, "writeUpdates")
 	}
@@ -1018,9 +1595,38 @@ func (t *Tracker) processLostAircraftMap(sighting *Sighting, observation *Projec
 			return err
 		}
 	}
+	if project.IsWebhookNotificationEnabled(MapProduced) {
+		log.Debugf("[session %d] %s: sending %s webhook", project.Session.ID, sighting.State.Icao, MapProduced)
+		err = t.sendMapProducedWebhook(project, sighting, observation, &flightTime, mapUpdated, firstPos, lastPos)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// kmlTrack contains everything needed to build the KML file for an
+// observation, so it can be built without holding the sighting lock.
+type kmlTrack struct {
+	sessionID uint64
+	icao      string
+	options   kml.WriterOptions
+	// sighting - a copy of the db record, or nil if it's
+	// not been written yet
+	sighting *db.Sighting
+	// pending - locations not yet written to the database
+	pending []db.SightingLocation
+}
+
+// buildKml reads the location history of the observation and
+// returns the KML file, and the first and last position.
 func buildKml(database db.Database, project *Project, sighting *Sighting, observation *ProjectObservation, flightTime *FlightTime) ([]byte, *db.SightingLocation, *db.SightingLocation, error) {
+	return newKmlTrack(project, sighting, observation, flightTime).build(database)
+}
+
+// newKmlTrack copies the information required to build the KML
+// file for observation. The sighting must be locked by the caller.
+func newKmlTrack(project *Project, sighting *Sighting, observation *ProjectObservation, flightTime *FlightTime) *kmlTrack {
 	var ac string
 	var source = "Source"
 	var destination = "Destination"
@@ -1035,33 +1641,76 @@ func buildKml(database db.Database, project *Project, sighting *Sighting, observ
 	if observation.destination != nil && observation.destination.ok {
 		destination += fmt.Sprintf(": near %s", observation.destination.address)
 	}
-	w := kml.NewWriter(kml.WriterOptions{
-		RouteName:        fmt.Sprintf("%s flight", ac),
-		RouteDescription: fmt.Sprintf("Departure: %s<br />Arrival: %s<br />Flight duration: %s<br />", flightTime.StartTimeFmt, flightTime.EndTimeFmt, flightTime.SightingDuration),
+	track := &kmlTrack{
+		sessionID: project.Session.ID,
+		icao:      sighting.State.Icao,
+		options: kml.WriterOptions{
+			RouteName:        fmt.Sprintf("%s flight", ac),
+			RouteDescription: fmt.Sprintf("Departure: %s<br />Arrival: %s<br />Flight duration: %s<br />", flightTime.StartTimeFmt, flightTime.EndTimeFmt, flightTime.SightingDuration),
 
-		SourceName:        source,
-		SourceDescription: fmt.Sprintf("Departed at %s", flightTime.StartTimeFmt),
+			SourceName:        source,
+			SourceDescription: fmt.Sprintf("Departed at %s", flightTime.StartTimeFmt),
 
-		DestinationName:        destination,
-		DestinationDescription: fmt.Sprintf("Arrived at %s", flightTime.EndTimeFmt),
-	})
+			DestinationName:        destination,
+			DestinationDescription: fmt.Sprintf("Arrived at %s", flightTime.EndTimeFmt),
+		},
+	}
+	if observation.sighting != nil {
+		dbSighting := *observation.sighting
+		track.sighting = &dbSighting
+	}
+	if len(observation.locationLogs) > 0 {
+		track.pending = make([]db.SightingLocation, len(observation.locationLogs))
+		for i, l := range observation.locationLogs {
+			track.pending[i] = db.SightingLocation{
+				TimeStamp:  l.time,
+				Altitude:   l.alt,
+				Latitude:   l.lat,
+				Longitude:  l.lon,
+				Provenance: locationProvenance(l.provenance),
+			}
+		}
+	}
+	return track
+}
+
+// build reads the location history from the database and returns
+// the KML file, and the first and last position.
+func (k *kmlTrack) build(database db.Database) ([]byte, *db.SightingLocation, *db.SightingLocation, error) {
+	w := kml.NewWriter(k.options)
 
 	var numPoints int
 	var firstPos, lastPos *db.SightingLocation
-	err := database.WalkLocationHistoryBatch(observation.sighting, locationFetchBatchSize, func(location []db.SightingLocation) {
+	if k.sighting != nil {
+		err := database.WalkLocationHistoryBatch(k.sighting, locationFetchBatchSize, func(location []db.SightingLocation) {
+			if firstPos == nil {
+				firstPos = &location[0]
+			}
+			lastPos = &location[len(location)-1]
+			w.Write(location)
+			numPoints += len(location)
+		})
+		if err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "error walking location history")
+		}
+	}
+	// include locations which hadn't been written to the database. They
+	// may have been written since the track was copied.
+	pending := k.pending
+	for len(pending) > 0 && lastPos != nil && !pending[0].TimeStamp.After(lastPos.TimeStamp) {
+		pending = pending[1:]
+	}
+	if len(pending) > 0 {
 		if firstPos == nil {
-			firstPos = &location[0]
+			firstPos = &pending[0]
 		}
-		lastPos = &location[len(location)-1]
-		w.Write(location)
-		numPoints += len(location)
-	})
-	if err != nil {
-		return nil, nil, nil, errors.Wrapf(err, "error walking location history")
+		lastPos = &pending[len(pending)-1]
+		w.Write(pending)
+		numPoints += len(pending)
 	}
 
 	log.Debugf("[session %d] location history for %s had %d points",
-		project.Session.ID, sighting.State.Icao, numPoints)
+		k.sessionID, k.icao, numPoints)
 
 	kmlStr, err := w.Final()
 	if err != nil {
@@ -1072,28 +1721,35 @@ func buildKml(database db.Database, project *Project, sighting *Sighting, observ
 
 // startConsumer is a goroutine that reads from the messages channel
 // and updates our state for each aircraft. Then ProcessMessage is
-// called with the sighting and each project.
+// called with the sighting and each project. Frames already received
+// from another receiver within opt.DedupWindow are not processed again,
+// but their receiver is recorded.
 func (t *Tracker) startConsumer(ctx context.Context, msgs chan *pb.Message) {
 	defer t.consumerWG.Done()
 
 	for msg := range msgs {
 		inflightMsgVec.WithLabelValues().Inc()
 		t.projectMu.RLock()
-		now := time.Now()
+		now := t.messageTime(msg)
+		duplicate := t.isDuplicateMessage(msg, now)
 		s := t.getSighting(msg.Icao, now)
-		err := t.UpdateStateFromMessage(s, msg, now)
-		if err != nil {
-			s.mu.Unlock()
-			panic(err)
-		}
-
-		for _, proj := range t.projects {
-			err = t.ProcessMessage(proj, s, now, msg)
+		if !duplicate {
+			err := t.UpdateStateFromMessage(s, msg, now)
 			if err != nil {
 				s.mu.Unlock()
 				panic(err)
 			}
+
+			for _, proj := range t.projects {
+				err = t.ProcessMessage(proj, s, now, msg)
+				if err != nil {
+					s.mu.Unlock()
+					panic(err)
+				}
+			}
 		}
+		t.recordReceiver(s, msg, now)
+		t.recordCoverage(s, msg, now)
 		s.mu.Unlock()
 		t.projectMu.RUnlock()
 
@@ -1101,26 +1757,140 @@ func (t *Tracker) startConsumer(ctx context.Context, msgs chan *pb.Message) {
 		aircraftCountVec.WithLabelValues().Set(float64(len(t.sighting)))
 		t.sightingMu.Unlock()
 
+		if t.opt.Output != nil && !duplicate {
+			t.opt.Output.WriteMessage(msg, now)
+		}
+
 		inflightMsgVec.WithLabelValues().Dec()
-		msgsProcessed.Inc()
+		if duplicate {
+			msgsDuplicate.Inc()
+		} else {
+			msgsProcessed.Inc()
+		}
+
+		if t.opt.UseMessageTime && t.lostAircraftCheckDue(now) {
+			err := t.doLostAircraftCheck()
+			if err != nil {
+				panic(err)
+			}
+		}
 	}
 }
 
-// getSighting returns an existing Sighting if present,
-// and creates a new one if missing. It locks sightingMu
-// for this operation. The sighting will be returned
-// Locked, so must be unlocked by the caller when finished.
-func (t *Tracker) getSighting(icao string, msgTime time.Time) *Sighting {
-	// init sighting in map
-	t.sightingMu.Lock()
-	defer t.sightingMu.Unlock()
-	s, ok := t.sighting[icao]
-	if !ok {
-		s = NewSighting(icao, msgTime)
-		s.mu.Lock()
-		t.sighting[icao] = s
-	} else {
-		s.mu.Lock()
+// recordReceiver records the receiver which produced msg with the
+// observations of projects with the TrackReceivers feature enabled.
+// Duplicate messages are recorded too, so every receiver which heard
+// the aircraft is known. s must be locked by the caller.
+func (t *Tracker) recordReceiver(s *Sighting, msg *pb.Message, now time.Time) {
+	if msg.Source == nil || msg.Source.Name == "" {
+		return
+	}
+	for _, o := range s.observedBy {
+		if !o.project.IsFeatureEnabled(TrackReceivers) {
+			continue
+		}
+		o.mu.Lock()
+		o.RecordReceiver(msg.Source.Name, msg.Signal != nil, msg.Signal.GetRssi(), now)
+		o.mu.Unlock()
+	}
+}
+
+// recordCoverage records the position in msg in the coverage of the
+// receiver which produced it. Only positions accepted into the aircraft's
+// state are recorded. Duplicate messages are recorded too, as they
+// were heard by another receiver. MLAT, TIS-B and ADS-R positions
+// weren't received directly from the aircraft, so are ignored.
+// s must be locked by the caller.
+func (t *Tracker) recordCoverage(s *Sighting, msg *pb.Message, now time.Time) {
+	if t.opt.Coverage == nil || msg.Source == nil || msg.Latitude == "" || msg.Longitude == "" || !s.State.HaveLocation {
+		return
+	}
+	if msg.Provenance != pb.Provenance_ADSB && msg.Provenance != pb.Provenance_ModeS {
+		return
+	}
+	lat, err := strconv.ParseFloat(msg.Latitude, 64)
+	if err != nil {
+		return
+	}
+	lon, err := strconv.ParseFloat(msg.Longitude, 64)
+	if err != nil || lat != s.State.Latitude || lon != s.State.Longitude {
+		return
+	}
+	var alt *int64
+	if s.State.HaveAltitudeBarometric {
+		altitude := s.State.AltitudeBarometric
+		alt = &altitude
+	}
+	t.opt.Coverage.Record(msg.Source.Name, lat, lon, alt, now)
+}
+
+// localTime returns now in the configured TimeZone
+func (t *Tracker) localTime(now time.Time) time.Time {
+	if t.opt.TimeZone == nil {
+		return now.Local()
+	}
+	return now.In(t.opt.TimeZone)
+}
+
+// now returns the current time, or the latest message
+// time if opt.UseMessageTime is set.
+func (t *Tracker) now() time.Time {
+	if !t.opt.UseMessageTime {
+		return time.Now()
+	}
+	t.clockMu.Lock()
+	defer t.clockMu.Unlock()
+	return t.clock
+}
+
+// messageTime returns the time to use for msg. If opt.UseMessageTime
+// is set, msg.Time is used and the clock is advanced to it. Otherwise,
+// the current time is returned.
+func (t *Tracker) messageTime(msg *pb.Message) time.Time {
+	if !t.opt.UseMessageTime || msg.Time == 0 {
+		return time.Now()
+	}
+	msgTime := time.Unix(0, msg.Time*int64(time.Millisecond))
+	t.clockMu.Lock()
+	defer t.clockMu.Unlock()
+	if msgTime.After(t.clock) {
+		t.clock = msgTime
+	}
+	if t.lastLostCheck.IsZero() {
+		t.lastLostCheck = msgTime
+	}
+	return msgTime
+}
+
+// lostAircraftCheckDue returns true if the lost aircraft check
+// should run. It replaces the checkForLostAircraft goroutine when
+// opt.UseMessageTime is set, running at the same interval by message
+// time instead.
+func (t *Tracker) lostAircraftCheckDue(now time.Time) bool {
+	t.clockMu.Lock()
+	defer t.clockMu.Unlock()
+	if now.Sub(t.lastLostCheck) < lostAircraftCheckInterval {
+		return false
+	}
+	t.lastLostCheck = now
+	return true
+}
+
+// getSighting returns an existing Sighting if present,
+// and creates a new one if missing. It locks sightingMu
+// for this operation. The sighting will be returned
+// Locked, so must be unlocked by the caller when finished.
+func (t *Tracker) getSighting(icao string, msgTime time.Time) *Sighting {
+	// init sighting in map
+	t.sightingMu.Lock()
+	defer t.sightingMu.Unlock()
+	s, ok := t.sighting[icao]
+	if !ok {
+		s = NewSighting(icao, msgTime)
+		s.mu.Lock()
+		t.sighting[icao] = s
+	} else {
+		s.mu.Lock()
 	}
 
 	return s
@@ -1150,6 +1920,7 @@ func (t *Tracker) UpdateStateFromMessage(s *Sighting, msg *pb.Message, now time.
 
 	// Update Sighting state
 	var err error
+	var updatedLocation bool
 	if msg.AltitudeGeometric != "" {
 		var alt int64
 		alt, err = strconv.ParseInt(msg.AltitudeGeometric, 10, 64)
@@ -1178,9 +1949,29 @@ func (t *Tracker) UpdateStateFromMessage(s *Sighting, msg *pb.Message, now time.
 		if err != nil {
 			return errors.Wrapf(err, "parse msg longitude")
 		}
-		s.State.HaveLocation = true
-		s.State.Latitude = lat
-		s.State.Longitude = long
+		// NACp is broadcast in separate messages, so use the last
+		// value if this message doesn't carry it
+		fix := positionFix{time: now, lat: lat, lon: long, haveNACP: s.State.HaveNACP, nacp: s.State.NACP}
+		if msg.HaveNACP {
+			fix.haveNACP = true
+			fix.nacp = msg.NACP
+		}
+		if s.position.check(fix, t.opt.MaxPositionSpeed) {
+			updatedLocation = true
+			s.State.HaveLocation = true
+			s.State.Latitude = lat
+			s.State.Longitude = long
+			s.State.LocationProvenance = msg.Provenance
+			if t.opt.Zones != nil {
+				s.State.Zones = t.opt.Zones.Find(lat, long)
+			}
+		} else {
+			log.Debugf("%s: rejected position %f,%f implying %.0f knots", s.State.Icao, lat, long, s.position.rejectedSpeed)
+			positionsRejected.Inc()
+			// filters shouldn't see the rejected position either
+			msg.Latitude = ""
+			msg.Longitude = ""
+		}
 	}
 	if msg.CallSign != "" && msg.CallSign != s.State.CallSign {
 		s.State.HaveCallsign = true
@@ -1198,14 +1989,13 @@ func (t *Tracker) UpdateStateFromMessage(s *Sighting, msg *pb.Message, now time.
 		s.State.HaveSquawk = true
 		s.State.Squawk = msg.Squawk
 	}
+	if msg.HaveEmergency {
+		s.State.HaveEmergency = true
+		s.State.Emergency = msg.Emergency
+	}
 	if msg.HaveVerticalRateBarometric {
 		s.State.HaveVerticalRateBarometric = true
 		s.State.VerticalRateBarometric = msg.VerticalRateBarometric
-		if msg.VerticalRateBarometric == 0 && s.Tags.IsInTakeoff && (s.State.HaveAltitudeBarometric && s.State.AltitudeBarometric > 200) {
-			s.Tags.IsInTakeoff = false
-			log.Tracef("ac finished takeoff %s (Alt: %d, VerticalRate: %d)",
-				s.State.Icao, s.State.AltitudeBarometric, msg.VerticalRateBarometric)
-		}
 	}
 	if msg.HaveVerticalRateGeometric {
 		s.State.HaveVerticalRateGeometric = true
@@ -1293,12 +2083,6 @@ func (t *Tracker) UpdateStateFromMessage(s *Sighting, msg *pb.Message, now time.
 			if s.onGroundCounter > t.opt.OnGroundUpdateThreshold {
 				log.Tracef("%s: updated IsOnGround: %t -> %t", s.State.Icao, s.State.IsOnGround, msg.IsOnGround)
 				s.State.IsOnGround = msg.IsOnGround
-				if !s.State.IsOnGround && s.State.VerticalRateBarometric > 0 {
-					// todo: review best altitude for here
-					log.Tracef("%s: IsInTakeoff (Alt: %d, VerticalRate: %d)", s.State.Icao, s.State.AltitudeBarometric, s.State.VerticalRateBarometric)
-					s.Tags.IsInTakeoff = true
-					// takeoff_begin
-				}
 			}
 		} else {
 			log.Tracef("%s: new candidate IsOnGround %t", s.State.Icao, msg.IsOnGround)
@@ -1306,6 +2090,27 @@ func (t *Tracker) UpdateStateFromMessage(s *Sighting, msg *pb.Message, now time.
 			s.onGroundCounter = 0
 		}
 	}
+	if updatedLocation && t.opt.AirportGeocoder != nil &&
+		s.State.HaveAltitudeBarometric && s.State.AltitudeBarometric <= groundElevationMaxAltitude {
+		s.phase.setElevation(t.groundElevation(s.State.Latitude, s.State.Longitude))
+	}
+	if s.phase.update(&s.State) {
+		log.Tracef("%s: phase %s -> %s (Alt: %d, VerticalRate: %d, OnGround: %t)", s.State.Icao, s.State.Phase,
+			s.phase.phase, s.State.AltitudeBarometric, s.State.VerticalRateBarometric, s.State.IsOnGround)
+		s.State.Phase = string(s.phase.phase)
+		s.Tags.IsInTakeoff = s.phase.phase == PhaseTakeoff
+		s.Tags.IsOnApproach = s.phase.phase == PhaseApproach
+		if s.phase.phase == PhaseLanding {
+			s.Tags.HasLanded = true
+		} else if s.phase.phase.IsAirborne() {
+			s.Tags.HasLanded = false
+		}
+	}
+	if (s.State.IsOnGround || updatedLocation) && s.orbit.update(now, &s.State) {
+		log.Tracef("%s: orbiting %t -> %t", s.State.Icao, s.State.IsOrbiting, s.orbit.orbiting)
+		s.State.IsOrbiting = s.orbit.orbiting
+		s.Tags.IsOrbiting = s.orbit.orbiting
+	}
 
 	if !s.searchedCountry && t.opt.Allocations != nil {
 		s.searchedCountry = true
@@ -1334,6 +2139,14 @@ func (t *Tracker) UpdateStateFromMessage(s *Sighting, msg *pb.Message, now time.
 		s.searchedInfo = true
 	}
 
+	if t.opt.Watchlists != nil {
+		var registration string
+		if s.State.Info != nil {
+			registration = s.State.Info.Registration
+		}
+		s.State.Watchlist, _ = t.opt.Watchlists.Label(s.State.Icao, registration)
+	}
+
 	return nil
 }
 
@@ -1368,7 +2181,18 @@ func (t *Tracker) ProcessMessage(project *Project, s *Sighting, now time.Time, m
 
 	// Evaluate filter, see if we wish to continue
 	if project.Program != nil {
-		passed, err := checkIfPassesFilter(project.Program, msg, &s.State)
+		observation := s.observedBy[project.Session.ID]
+		if observation != nil {
+			observation.mu.RLock()
+		}
+		sighting, err := newFilterSighting(observation, now)
+		if observation != nil {
+			observation.mu.RUnlock()
+		}
+		if err != nil {
+			return err
+		}
+		passed, err := checkIfPassesFilter(project.Program, msg, &s.State, sighting, t.opt.Watchlists, t.localTime(now))
 		if err != nil {
 			return errors.Wrapf(err, "evaluating filter")
 		}
@@ -1398,6 +2222,10 @@ func (t *Tracker) ProcessMessage(project *Project, s *Sighting, now time.Time, m
 	if err != nil {
 		return err
 	}
+	err = t.evaluateRules(project, observation, s, msg, now)
+	if err != nil {
+		return err
+	}
 
 	numListeners := len(t.projectAcUpdateListeners)
 	for i := 0; i < numListeners; i++ {
@@ -1448,10 +2276,15 @@ func (t *Tracker) updateObservation(project *Project, observation *ProjectObserv
 		oldlat, oldlon := observation.Location()
 		updatedLocation = !observation.HaveLocation() || s.State.Latitude != oldlat || s.State.Longitude != oldlon
 		if updatedLocation {
+			observation.SetLocationProvenance(s.State.LocationProvenance)
 			err := observation.SetLocation(s.State.Latitude, s.State.Longitude, project.IsFeatureEnabled(TrackKmlLocation), now)
 			if err != nil {
 				return errors.Wrapf(err, "setting location")
 			}
+			err = t.updateZones(project, observation, s, now)
+			if err != nil {
+				return err
+			}
 		}
 	}
 	if s.State.HaveCallsign {
@@ -1473,6 +2306,10 @@ func (t *Tracker) updateObservation(project *Project, observation *ProjectObserv
 		}
 	}
 
+	if phase := FlightPhase(s.State.Phase); phase != observation.phase && phase != PhaseUnknown {
+		observation.SetPhase(phase, project.IsFeatureEnabled(TrackPhases), now)
+	}
+
 	if s.Tags.IsInTakeoff != observation.tags.IsInTakeoff {
 		observation.tags.IsInTakeoff = s.Tags.IsInTakeoff
 		if project.IsFeatureEnabled(TrackTakeoff) {
@@ -1495,6 +2332,19 @@ func (t *Tracker) updateObservation(project *Project, observation *ProjectObserv
 						return err
 					}
 				}
+				if geocodeOK && project.IsWebhookNotificationEnabled(TakeoffFromAirport) {
+					log.Debugf("[session %d] %s: sending %s webhook", project.Session.ID, s.State.Icao, TakeoffFromAirport)
+					err := t.sendTakeoffWebhook(project, s, TakeoffFromAirport, observation.origin.address)
+					if err != nil {
+						return err
+					}
+				} else if !geocodeOK && project.IsWebhookNotificationEnabled(TakeoffUnknownAirport) {
+					log.Debugf("[session %d] %s: sending %s webhook", project.Session.ID, s.State.Icao, TakeoffUnknownAirport)
+					err := t.sendTakeoffWebhook(project, s, TakeoffUnknownAirport, "")
+					if err != nil {
+						return err
+					}
+				}
 			} else {
 				log.Infof("[session %d] %s: has finished takeoff",
 					project.Session.ID, s.State.Icao)
@@ -1510,7 +2360,60 @@ func (t *Tracker) updateObservation(project *Project, observation *ProjectObserv
 						return err
 					}
 				}
+				if project.IsWebhookNotificationEnabled(TakeoffComplete) {
+					log.Debugf("[session %d] %s: sending %s webhook", project.Session.ID, s.State.Icao, TakeoffComplete)
+					var airport string
+					if geocodeOK {
+						airport = observation.origin.address
+					}
+					err := t.sendTakeoffWebhook(project, s, TakeoffComplete, airport)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	if code := emergencyCode(&s.State); code != observation.emergency {
+		observation.emergency = code
+		if code != "" {
+			err := t.handleEmergency(project, observation, s, now)
+			if err != nil {
+				return err
+			}
+		} else {
+			log.Infof("[session %d] %s: emergency is over", project.Session.ID, s.State.Icao)
+		}
+	}
+
+	if s.Tags.HasLanded != observation.tags.HasLanded {
+		observation.tags.HasLanded = s.Tags.HasLanded
+		if observation.tags.HasLanded {
+			err := t.handleLanding(project, observation, s, now)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.position.anomalies != observation.positionAnomalies {
+		observation.positionAnomalies = s.position.anomalies
+		err := t.handlePositionAnomaly(project, s, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.Tags.IsOrbiting != observation.tags.IsOrbiting {
+		observation.tags.IsOrbiting = s.Tags.IsOrbiting
+		if observation.tags.IsOrbiting {
+			err := t.handleOrbiting(project, s, now)
+			if err != nil {
+				return err
 			}
+		} else {
+			log.Infof("[session %d] %s: stopped orbiting", project.Session.ID, s.State.Icao)
 		}
 	}
 
@@ -1521,6 +2424,13 @@ func (t *Tracker) updateObservation(project *Project, observation *ProjectObserv
 			return err
 		}
 	}
+	if sightingOpened && project.IsWebhookNotificationEnabled(SpottedInFlight) {
+		log.Debugf("[session %d] %s: sending %s webhook", project.Session.ID, s.State.Icao, SpottedInFlight)
+		err := t.sendSpottedInFlightWebhook(project, s)
+		if err != nil {
+			return err
+		}
+	}
 
 	if project.IsFeatureEnabled(GeocodeEndpoints) && observation.origin == nil && observation.HaveLocation() {
 		if observation.altitudeBaro > t.opt.NearestAirportMaxAltitude {
@@ -1543,6 +2453,280 @@ func (t *Tracker) updateObservation(project *Project, observation *ProjectObserv
 					project.Session.ID, s.State.Icao, lat, lon)
 			}
 			observation.origin = location
+			if location.ok {
+				observation.dirty = true
+			}
+		}
+	}
+	return nil
+}
+
+// handleLanding determines the destination airport if GeocodeEndpoints
+// is enabled, and sends landing notifications if TrackLanding is enabled.
+func (t *Tracker) handleLanding(project *Project, observation *ProjectObservation, s *Sighting, now time.Time) error {
+	if project.IsFeatureEnabled(GeocodeEndpoints) && observation.HaveLocation() {
+		lat, lon := observation.Location()
+		location, distance, err := t.reverseGeocode(lat, lon)
+		if err != nil {
+			return errors.Wrap(err, "searching destination")
+		}
+		if location.ok {
+			log.Debugf("[session %d] %s: Landing reverse geocode result: (%f, %f): %s %.1f km",
+				project.Session.ID, s.State.Icao, lat, lon, location.address,
+				distance/1000)
+			observation.dirty = true
+		} else {
+			log.Debugf("[session %d] %s: Reverse geocode search for landing (%f, %f) yielded no results",
+				project.Session.ID, s.State.Icao, lat, lon)
+		}
+		observation.destination = location
+	}
+	if !project.IsFeatureEnabled(TrackLanding) {
+		return nil
+	}
+
+	log.Infof("[session %d] %s: has landed", project.Session.ID, s.State.Icao)
+	geocodeOK := observation.destination != nil && observation.destination.ok
+	if geocodeOK && project.IsEmailNotificationEnabled(LandingAtAirport) {
+		log.Debugf("[session %d] %s: sending %s notification", project.Session.ID, s.State.Icao, LandingAtAirport)
+		err := t.sendLandingAtAirportEmail(project, s, observation, now)
+		if err != nil {
+			return err
+		}
+	} else if !geocodeOK && project.IsEmailNotificationEnabled(LandingUnknownAirport) {
+		log.Debugf("[session %d] %s: sending %s notification", project.Session.ID, s.State.Icao, LandingUnknownAirport)
+		err := t.sendLandingUnknownAirportEmail(project, s, now)
+		if err != nil {
+			return err
+		}
+	}
+	if geocodeOK && project.IsWebhookNotificationEnabled(LandingAtAirport) {
+		log.Debugf("[session %d] %s: sending %s webhook", project.Session.ID, s.State.Icao, LandingAtAirport)
+		err := t.sendLandingWebhook(project, s, LandingAtAirport, observation.destination.address, now)
+		if err != nil {
+			return err
+		}
+	} else if !geocodeOK && project.IsWebhookNotificationEnabled(LandingUnknownAirport) {
+		log.Debugf("[session %d] %s: sending %s webhook", project.Session.ID, s.State.Icao, LandingUnknownAirport)
+		err := t.sendLandingWebhook(project, s, LandingUnknownAirport, "", now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluateRules evaluates the project's rules, and sends notifications
+// for those which trigger. A rule with no cooldown triggers once
+// per sighting, otherwise it can trigger again after the cooldown.
+func (t *Tracker) evaluateRules(project *Project, observation *ProjectObservation, s *Sighting, msg *pb.Message, now time.Time) error {
+	if len(project.Rules) == 0 {
+		return nil
+	}
+	sighting, err := newFilterSighting(observation, now)
+	if err != nil {
+		return err
+	}
+	for _, rule := range project.Rules {
+		last, triggered := observation.ruleTriggered[rule.Name]
+		if triggered && (rule.Cooldown == 0 || now.Sub(last) < rule.Cooldown) {
+			continue
+		}
+		passed, err := checkIfPassesFilter(rule.Program, msg, &s.State, sighting, t.opt.Watchlists, t.localTime(now))
+		if err != nil {
+			return errors.Wrapf(err, "evaluating rule %s", rule.Name)
+		} else if !passed {
+			continue
+		}
+		if observation.ruleTriggered == nil {
+			observation.ruleTriggered = make(map[EmailNotification]time.Time)
+		}
+		observation.ruleTriggered[rule.Name] = now
+		log.Infof("[session %d] %s: rule %s triggered", project.Session.ID, s.State.Icao, rule.Name)
+		if project.IsEmailNotificationEnabled(rule.Name) {
+			log.Debugf("[session %d] %s: sending %s notification", project.Session.ID, s.State.Icao, rule.Name)
+			err := t.sendRuleEmail(project, s, rule, now)
+			if err != nil {
+				return err
+			}
+		}
+		if project.IsWebhookNotificationEnabled(rule.Name) {
+			log.Debugf("[session %d] %s: sending %s webhook", project.Session.ID, s.State.Icao, rule.Name)
+			err := t.sendRuleWebhook(project, s, rule, now)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// containsZone returns true if zone is in zones
+func containsZone(zones []string, zone string) bool {
+	for _, z := range zones {
+		if z == zone {
+			return true
+		}
+	}
+	return false
+}
+
+// updateZones compares the zones the aircraft is inside with those
+// at the previous location update, and sends zone_entered and
+// zone_exited notifications for the difference.
+func (t *Tracker) updateZones(project *Project, observation *ProjectObservation, s *Sighting, now time.Time) error {
+	previous := observation.zones
+	observation.zones = s.State.Zones
+	for _, zone := range s.State.Zones {
+		if containsZone(previous, zone) {
+			continue
+		}
+		log.Infof("[session %d] %s: entered zone %s", project.Session.ID, s.State.Icao, zone)
+		err := t.sendZoneNotifications(project, s, ZoneEntered, zone, now)
+		if err != nil {
+			return err
+		}
+	}
+	for _, zone := range previous {
+		if containsZone(s.State.Zones, zone) {
+			continue
+		}
+		log.Infof("[session %d] %s: exited zone %s", project.Session.ID, s.State.Icao, zone)
+		err := t.sendZoneNotifications(project, s, ZoneExited, zone, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// positionAnomalyDescription returns a human readable
+// description of the sighting's position anomaly
+func positionAnomalyDescription(s *Sighting) string {
+	return fmt.Sprintf("%d implausible positions, the last implying %.0f knots",
+		s.position.rejections, s.position.rejectedSpeed)
+}
+
+// handlePositionAnomaly sends position_anomaly notifications when the
+// aircraft repeatedly reports implausible positions.
+func (t *Tracker) handlePositionAnomaly(project *Project, s *Sighting, now time.Time) error {
+	log.Infof("[session %d] %s: position anomaly: %s", project.Session.ID, s.State.Icao,
+		positionAnomalyDescription(s))
+	if project.IsEmailNotificationEnabled(PositionAnomaly) {
+		log.Debugf("[session %d] %s: sending %s notification", project.Session.ID, s.State.Icao, PositionAnomaly)
+		err := t.sendPositionAnomalyEmail(project, s, now)
+		if err != nil {
+			return err
+		}
+	}
+	if project.IsWebhookNotificationEnabled(PositionAnomaly) {
+		log.Debugf("[session %d] %s: sending %s webhook", project.Session.ID, s.State.Icao, PositionAnomaly)
+		err := t.sendPositionAnomalyWebhook(project, s, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleOrbiting sends orbiting notifications when
+// the aircraft begins circling or holding over an area.
+func (t *Tracker) handleOrbiting(project *Project, s *Sighting, now time.Time) error {
+	log.Infof("[session %d] %s: orbiting %f,%f (radius %.0fm)", project.Session.ID, s.State.Icao,
+		s.orbit.latitude, s.orbit.longitude, s.orbit.radius)
+	if project.IsEmailNotificationEnabled(Orbiting) {
+		log.Debugf("[session %d] %s: sending %s notification", project.Session.ID, s.State.Icao, Orbiting)
+		err := t.sendOrbitingEmail(project, s, now)
+		if err != nil {
+			return err
+		}
+	}
+	if project.IsWebhookNotificationEnabled(Orbiting) {
+		log.Debugf("[session %d] %s: sending %s webhook", project.Session.ID, s.State.Icao, Orbiting)
+		err := t.sendOrbitingWebhook(project, s, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendZoneNotifications sends the ZoneEntered or ZoneExited
+// notification n by email and webhook, if enabled.
+func (t *Tracker) sendZoneNotifications(project *Project, s *Sighting, n EmailNotification, zone string, now time.Time) error {
+	if project.IsEmailNotificationEnabled(n) {
+		log.Debugf("[session %d] %s: sending %s notification", project.Session.ID, s.State.Icao, n)
+		err := t.sendZoneEmail(project, s, n, zone, now)
+		if err != nil {
+			return err
+		}
+	}
+	if project.IsWebhookNotificationEnabled(n) {
+		log.Debugf("[session %d] %s: sending %s webhook", project.Session.ID, s.State.Icao, n)
+		err := t.sendZoneWebhook(project, s, n, zone, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emergencyCode returns the 7x00 squawk or the ADS-B emergency status
+// the aircraft is broadcasting. An empty string is returned if there's
+// no emergency. The squawk takes priority as it's usually set as well.
+func emergencyCode(state *pb.State) string {
+	if state.HaveSquawk {
+		switch state.Squawk {
+		case "7500", "7600", "7700":
+			return state.Squawk
+		}
+	}
+	if state.HaveEmergency && state.Emergency != "" && state.Emergency != "none" {
+		return state.Emergency
+	}
+	return ""
+}
+
+// emergencyDescription returns a human readable description
+// of an emergency code returned by emergencyCode
+func emergencyDescription(code string) string {
+	switch code {
+	case "7500", "unlawful":
+		return "unlawful interference"
+	case "7600", "nordo":
+		return "radio failure"
+	case "7700", "general":
+		return "general emergency"
+	case "lifeguard":
+		return "lifeguard/medical"
+	case "minfuel":
+		return "minimum fuel"
+	case "downed":
+		return "downed aircraft"
+	}
+	return code
+}
+
+// handleEmergency sends emergency notifications for a new emergency
+// episode, or when the emergency code changes. The track so far is
+// attached to the email if TrackKmlLocation is enabled.
+func (t *Tracker) handleEmergency(project *Project, observation *ProjectObservation, s *Sighting, now time.Time) error {
+	log.Infof("[session %d] %s: emergency: %s", project.Session.ID, s.State.Icao,
+		emergencyDescription(observation.emergency))
+	if project.IsEmailNotificationEnabled(Emergency) {
+		log.Debugf("[session %d] %s: sending %s notification", project.Session.ID, s.State.Icao, Emergency)
+		var track *kmlTrack
+		if project.IsFeatureEnabled(TrackKmlLocation) && observation.HaveLocation() {
+			flightTime := observation.GetFlightTime()
+			track = newKmlTrack(project, s, observation, &flightTime)
+		}
+		t.sendEmergencyEmail(project, s, observation, track, now)
+	}
+	if project.IsWebhookNotificationEnabled(Emergency) {
+		log.Debugf("[session %d] %s: sending %s webhook", project.Session.ID, s.State.Icao, Emergency)
+		err := t.sendEmergencyWebhook(project, s, observation, now)
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -1552,6 +2736,7 @@ func (t *Tracker) sendTakeoffFromAirportEmail(project *Project, s *Sighting, obs
 		Project:      project.Name,
 		Icao:         s.State.Icao,
 		CallSign:     s.State.CallSign,
+		Watchlist:    s.State.Watchlist,
 		AirportName:  observation.origin.address,
 		StartTimeFmt: s.firstSeen.Format(time.RFC1123Z),
 		StartLocation: email.Location{
@@ -1573,6 +2758,7 @@ func (t *Tracker) sendTakeoffUnknownAirportEmail(project *Project, s *Sighting,
 		Project:      project.Name,
 		Icao:         s.State.Icao,
 		CallSign:     s.State.CallSign,
+		Watchlist:    s.State.Watchlist,
 		StartTimeFmt: s.firstSeen.Format(time.RFC1123Z),
 		StartLocation: email.Location{
 			Latitude:  s.State.Latitude,
@@ -1593,6 +2779,7 @@ func (t *Tracker) sendTakeoffCompleteEmail(project *Project, s *Sighting, observ
 		Project:      project.Name,
 		Icao:         s.State.Icao,
 		CallSign:     s.State.CallSign,
+		Watchlist:    s.State.Watchlist,
 		StartTimeFmt: s.firstSeen.Format(time.RFC1123Z),
 		AirportName:  airport,
 		StartLocation: email.Location{
@@ -1609,11 +2796,210 @@ func (t *Tracker) sendTakeoffCompleteEmail(project *Project, s *Sighting, observ
 	}
 	return nil
 }
+func (t *Tracker) sendLandingAtAirportEmail(project *Project, s *Sighting, observation *ProjectObservation, landingTime time.Time) error {
+	msg, err := email.PrepareLandingAtAirport(t.mailTemplates, project.NotifyEmail, email.LandingParams{
+		Project:        project.Name,
+		Icao:           s.State.Icao,
+		CallSign:       s.State.CallSign,
+		Watchlist:      s.State.Watchlist,
+		AirportName:    observation.destination.address,
+		LandingTimeFmt: landingTime.Format(time.RFC1123Z),
+		LandingLocation: email.Location{
+			Latitude:  s.State.Latitude,
+			Longitude: s.State.Longitude,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "preparing LandingAtAirport email")
+	}
+	err = t.opt.Mailer.Queue(*msg)
+	if err != nil {
+		return errors.Wrapf(err, "queueing LandingAtAirport email")
+	}
+	return nil
+}
+func (t *Tracker) sendLandingUnknownAirportEmail(project *Project, s *Sighting, landingTime time.Time) error {
+	msg, err := email.PrepareLandingUnknownAirport(t.mailTemplates, project.NotifyEmail, email.LandingUnknownAirportParams{
+		Project:        project.Name,
+		Icao:           s.State.Icao,
+		CallSign:       s.State.CallSign,
+		Watchlist:      s.State.Watchlist,
+		LandingTimeFmt: landingTime.Format(time.RFC1123Z),
+		LandingLocation: email.Location{
+			Latitude:  s.State.Latitude,
+			Longitude: s.State.Longitude,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "preparing LandingUnknownAirport email")
+	}
+	err = t.opt.Mailer.Queue(*msg)
+	if err != nil {
+		return errors.Wrapf(err, "queueing LandingUnknownAirport email")
+	}
+	return nil
+}
+
+// sendEmergencyEmail queues the Emergency email. Reading the location
+// history for the KML attachment can be slow, so the email is prepared
+// in the background instead of blocking the consumer.
+func (t *Tracker) sendEmergencyEmail(project *Project, s *Sighting, observation *ProjectObservation, track *kmlTrack, now time.Time) {
+	params := email.EmergencyParams{
+		Project:     project.Name,
+		Icao:        s.State.Icao,
+		CallSign:    s.State.CallSign,
+		Watchlist:   s.State.Watchlist,
+		Description: emergencyDescription(observation.emergency),
+		TimeFmt:     now.Format(time.RFC1123Z),
+		Location: email.Location{
+			Latitude:  s.State.Latitude,
+			Longitude: s.State.Longitude,
+			Altitude:  s.State.AltitudeBarometric,
+		},
+	}
+	if s.State.HaveSquawk {
+		params.Squawk = s.State.Squawk
+	}
+	if s.State.HaveEmergency && s.State.Emergency != "none" {
+		params.Emergency = s.State.Emergency
+	}
+	if s.State.Operator != nil {
+		params.Operator = s.State.Operator.Name
+	}
+	notifyEmail := project.NotifyEmail
+	sessionID := project.Session.ID
+	t.notifyWG.Add(1)
+	go func() {
+		defer t.notifyWG.Done()
+		var plainTextKml []byte
+		if track != nil {
+			var err error
+			plainTextKml, _, _, err = track.build(t.database)
+			if err != nil {
+				log.Warnf("[session %d] %s: failed to build track for emergency: %s",
+					sessionID, params.Icao, err.Error())
+			}
+		}
+		msg, err := email.PrepareEmergency(t.mailTemplates, notifyEmail, plainTextKml, params)
+		if err != nil {
+			log.Warnf("[session %d] %s: preparing Emergency email: %s", sessionID, params.Icao, err.Error())
+			return
+		}
+		err = t.opt.Mailer.Queue(*msg)
+		if err != nil {
+			log.Warnf("[session %d] %s: queueing Emergency email: %s", sessionID, params.Icao, err.Error())
+		}
+	}()
+}
+func (t *Tracker) sendZoneEmail(project *Project, s *Sighting, n EmailNotification, zone string, now time.Time) error {
+	params := email.ZoneParams{
+		Project:   project.Name,
+		Icao:      s.State.Icao,
+		CallSign:  s.State.CallSign,
+		Watchlist: s.State.Watchlist,
+		Zone:      zone,
+		TimeFmt:   now.Format(time.RFC1123Z),
+		Location: email.Location{
+			Latitude:  s.State.Latitude,
+			Longitude: s.State.Longitude,
+			Altitude:  s.State.AltitudeBarometric,
+		},
+	}
+	prepare := email.PrepareZoneEntered
+	if n == ZoneExited {
+		prepare = email.PrepareZoneExited
+	}
+	msg, err := prepare(t.mailTemplates, project.NotifyEmail, params)
+	if err != nil {
+		return errors.Wrapf(err, "preparing %s email", n)
+	}
+	err = t.opt.Mailer.Queue(*msg)
+	if err != nil {
+		return errors.Wrapf(err, "queueing %s email", n)
+	}
+	return nil
+}
+func (t *Tracker) sendRuleEmail(project *Project, s *Sighting, rule *Rule, now time.Time) error {
+	params := email.RuleParams{
+		Project:   project.Name,
+		Icao:      s.State.Icao,
+		CallSign:  s.State.CallSign,
+		Watchlist: s.State.Watchlist,
+		Rule:      string(rule.Name),
+		Condition: rule.Condition,
+		TimeFmt:   now.Format(time.RFC1123Z),
+	}
+	if s.State.HaveLocation {
+		params.HaveLocation = true
+		params.Location = email.Location{
+			Latitude:  s.State.Latitude,
+			Longitude: s.State.Longitude,
+			Altitude:  s.State.AltitudeBarometric,
+		}
+	}
+	msg, err := email.PrepareRule(t.mailTemplates, project.NotifyEmail, params)
+	if err != nil {
+		return errors.Wrapf(err, "preparing %s email", rule.Name)
+	}
+	err = t.opt.Mailer.Queue(*msg)
+	if err != nil {
+		return errors.Wrapf(err, "queueing %s email", rule.Name)
+	}
+	return nil
+}
+func (t *Tracker) sendOrbitingEmail(project *Project, s *Sighting, now time.Time) error {
+	msg, err := email.PrepareOrbiting(t.mailTemplates, project.NotifyEmail, email.OrbitingParams{
+		Project:   project.Name,
+		Icao:      s.State.Icao,
+		CallSign:  s.State.CallSign,
+		Watchlist: s.State.Watchlist,
+		TimeFmt:   now.Format(time.RFC1123Z),
+		Centre: email.Location{
+			Latitude:  s.orbit.latitude,
+			Longitude: s.orbit.longitude,
+			Altitude:  s.State.AltitudeBarometric,
+		},
+		Radius: int64(math.Round(s.orbit.radius)),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "preparing Orbiting email")
+	}
+	err = t.opt.Mailer.Queue(*msg)
+	if err != nil {
+		return errors.Wrapf(err, "queueing Orbiting email")
+	}
+	return nil
+}
+func (t *Tracker) sendPositionAnomalyEmail(project *Project, s *Sighting, now time.Time) error {
+	msg, err := email.PreparePositionAnomaly(t.mailTemplates, project.NotifyEmail, email.PositionAnomalyParams{
+		Project:      project.Name,
+		Icao:         s.State.Icao,
+		CallSign:     s.State.CallSign,
+		Watchlist:    s.State.Watchlist,
+		Description:  positionAnomalyDescription(s),
+		TimeFmt:      now.Format(time.RFC1123Z),
+		HaveLocation: s.State.HaveLocation,
+		Location: email.Location{
+			Latitude:  s.State.Latitude,
+			Longitude: s.State.Longitude,
+			Altitude:  s.State.AltitudeBarometric,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "preparing PositionAnomaly email")
+	}
+	err = t.opt.Mailer.Queue(*msg)
+	if err != nil {
+		return errors.Wrapf(err, "queueing PositionAnomaly email")
+	}
+	return nil
+}
 func (t *Tracker) sendSpottedInFlightEmail(project *Project, s *Sighting, observation *ProjectObservation) error {
 	msg, err := email.PrepareSpottedInFlightEmail(t.mailTemplates, project.NotifyEmail, email.SpottedInFlightParameters{
 		Project:      project.Name,
 		Icao:         s.State.Icao,
 		CallSign:     s.State.CallSign,
+		Watchlist:    s.State.Watchlist,
 		StartTime:    s.firstSeen,
 		StartTimeFmt: s.firstSeen.Format(time.RFC1123Z),
 	})
@@ -1630,6 +3016,7 @@ func (t *Tracker) sendMapProducedEmail(project *Project, s *Sighting, observatio
 	sp := email.MapProducedParameters{
 		Project:      project.Name,
 		Icao:         s.State.Icao,
+		Watchlist:    s.State.Watchlist,
 		StartTimeFmt: ft.StartTimeFmt,
 		EndTimeFmt:   ft.EndTimeFmt,
 		DurationFmt:  ft.SightingDuration.String(),
@@ -1658,6 +3045,203 @@ func (t *Tracker) sendMapProducedEmail(project *Project, s *Sighting, observatio
 	}
 	return nil
 }
+func (t *Tracker) sendTakeoffWebhook(project *Project, s *Sighting, n EmailNotification, airport string) error {
+	startTime := s.firstSeen
+	return t.queueWebhooks(project, n, webhook.Event{
+		Icao:        s.State.Icao,
+		CallSign:    s.State.CallSign,
+		Watchlist:   s.State.Watchlist,
+		Time:        s.lastSeen,
+		AirportName: airport,
+		StartTime:   &startTime,
+		StartLocation: &webhook.Location{
+			Latitude:  s.State.Latitude,
+			Longitude: s.State.Longitude,
+			Altitude:  s.State.AltitudeBarometric,
+		},
+	})
+}
+func (t *Tracker) sendLandingWebhook(project *Project, s *Sighting, n EmailNotification, airport string, landingTime time.Time) error {
+	startTime := s.firstSeen
+	return t.queueWebhooks(project, n, webhook.Event{
+		Icao:        s.State.Icao,
+		CallSign:    s.State.CallSign,
+		Watchlist:   s.State.Watchlist,
+		Time:        landingTime,
+		AirportName: airport,
+		StartTime:   &startTime,
+		EndLocation: &webhook.Location{
+			Latitude:  s.State.Latitude,
+			Longitude: s.State.Longitude,
+			Altitude:  s.State.AltitudeBarometric,
+		},
+	})
+}
+func (t *Tracker) sendEmergencyWebhook(project *Project, s *Sighting, observation *ProjectObservation, now time.Time) error {
+	startTime := s.firstSeen
+	ev := webhook.Event{
+		Icao:        s.State.Icao,
+		CallSign:    s.State.CallSign,
+		Watchlist:   s.State.Watchlist,
+		Time:        now,
+		StartTime:   &startTime,
+		Description: emergencyDescription(observation.emergency),
+	}
+	if s.State.HaveSquawk {
+		ev.Squawk = s.State.Squawk
+	}
+	if s.State.HaveEmergency && s.State.Emergency != "none" {
+		ev.Emergency = s.State.Emergency
+	}
+	if s.State.Operator != nil {
+		ev.Operator = s.State.Operator.Name
+	}
+	if s.State.HaveLocation {
+		ev.Location = &webhook.Location{
+			Latitude:  s.State.Latitude,
+			Longitude: s.State.Longitude,
+			Altitude:  s.State.AltitudeBarometric,
+		}
+	}
+	return t.queueWebhooks(project, Emergency, ev)
+}
+func (t *Tracker) sendZoneWebhook(project *Project, s *Sighting, n EmailNotification, zone string, now time.Time) error {
+	startTime := s.firstSeen
+	return t.queueWebhooks(project, n, webhook.Event{
+		Icao:      s.State.Icao,
+		CallSign:  s.State.CallSign,
+		Watchlist: s.State.Watchlist,
+		Time:      now,
+		StartTime: &startTime,
+		Zone:      zone,
+		Location: &webhook.Location{
+			Latitude:  s.State.Latitude,
+			Longitude: s.State.Longitude,
+			Altitude:  s.State.AltitudeBarometric,
+		},
+	})
+}
+func (t *Tracker) sendRuleWebhook(project *Project, s *Sighting, rule *Rule, now time.Time) error {
+	startTime := s.firstSeen
+	ev := webhook.Event{
+		Icao:      s.State.Icao,
+		CallSign:  s.State.CallSign,
+		Watchlist: s.State.Watchlist,
+		Time:      now,
+		StartTime: &startTime,
+		Condition: rule.Condition,
+	}
+	if s.State.HaveLocation {
+		ev.Location = &webhook.Location{
+			Latitude:  s.State.Latitude,
+			Longitude: s.State.Longitude,
+			Altitude:  s.State.AltitudeBarometric,
+		}
+	}
+	return t.queueWebhooks(project, rule.Name, ev)
+}
+func (t *Tracker) sendOrbitingWebhook(project *Project, s *Sighting, now time.Time) error {
+	startTime := s.firstSeen
+	return t.queueWebhooks(project, Orbiting, webhook.Event{
+		Icao:      s.State.Icao,
+		CallSign:  s.State.CallSign,
+		Watchlist: s.State.Watchlist,
+		Time:      now,
+		StartTime: &startTime,
+		Location: &webhook.Location{
+			Latitude:  s.orbit.latitude,
+			Longitude: s.orbit.longitude,
+			Altitude:  s.State.AltitudeBarometric,
+		},
+		Radius: math.Round(s.orbit.radius),
+	})
+}
+func (t *Tracker) sendPositionAnomalyWebhook(project *Project, s *Sighting, now time.Time) error {
+	startTime := s.firstSeen
+	ev := webhook.Event{
+		Icao:        s.State.Icao,
+		CallSign:    s.State.CallSign,
+		Watchlist:   s.State.Watchlist,
+		Time:        now,
+		StartTime:   &startTime,
+		Description: positionAnomalyDescription(s),
+	}
+	if s.State.HaveLocation {
+		ev.Location = &webhook.Location{
+			Latitude:  s.State.Latitude,
+			Longitude: s.State.Longitude,
+			Altitude:  s.State.AltitudeBarometric,
+		}
+	}
+	return t.queueWebhooks(project, PositionAnomaly, ev)
+}
+func (t *Tracker) sendSpottedInFlightWebhook(project *Project, s *Sighting) error {
+	startTime := s.firstSeen
+	ev := webhook.Event{
+		Icao:      s.State.Icao,
+		CallSign:  s.State.CallSign,
+		Watchlist: s.State.Watchlist,
+		Time:      s.lastSeen,
+		StartTime: &startTime,
+	}
+	if s.State.HaveLocation {
+		ev.StartLocation = &webhook.Location{
+			Latitude:  s.State.Latitude,
+			Longitude: s.State.Longitude,
+			Altitude:  s.State.AltitudeBarometric,
+		}
+	}
+	return t.queueWebhooks(project, SpottedInFlight, ev)
+}
+func (t *Tracker) sendMapProducedWebhook(project *Project, s *Sighting, observation *ProjectObservation, ft *FlightTime, mapUpdated bool, firstPos, lastPos *db.SightingLocation) error {
+	ev := webhook.Event{
+		Icao:      s.State.Icao,
+		Watchlist: s.State.Watchlist,
+		Time:      ft.EndTime,
+		StartTime: &ft.StartTime,
+		EndTime:   &ft.EndTime,
+		Duration:  ft.SightingDuration.String(),
+		StartLocation: &webhook.Location{
+			Latitude:  firstPos.Latitude,
+			Longitude: firstPos.Longitude,
+			Altitude:  firstPos.Altitude,
+		},
+		EndLocation: &webhook.Location{
+			Latitude:  lastPos.Latitude,
+			Longitude: lastPos.Longitude,
+			Altitude:  lastPos.Altitude,
+		},
+		MapUpdated: mapUpdated,
+	}
+	if observation.HaveCallSign() {
+		ev.CallSign = observation.CallSign()
+	}
+	return t.queueWebhooks(project, MapProduced, ev)
+}
+
+// queueWebhooks prepares and queues a job for each of the project's
+// webhooks which are subscribed to notification n.
+func (t *Tracker) queueWebhooks(project *Project, n EmailNotification, ev webhook.Event) error {
+	if t.opt.Webhooks == nil {
+		return errors.Errorf("cannot send %s webhook, no webhook sender configured", n)
+	}
+	ev.Event = string(n)
+	ev.Project = project.Name
+	for i := range project.Webhooks {
+		if !project.Webhooks[i].IsNotificationEnabled(n) {
+			continue
+		}
+		job, err := webhook.PrepareJob(project.Webhooks[i].URL, project.Webhooks[i].Secret, ev)
+		if err != nil {
+			return errors.Wrapf(err, "preparing %s webhook", n)
+		}
+		err = t.opt.Webhooks.Queue(*job)
+		if err != nil {
+			return errors.Wrapf(err, "queueing %s webhook", n)
+		}
+	}
+	return nil
+}
 
 // loadAircraft finds or creates an aircraft record for the provided icao.
 func (t *Tracker) loadAircraft(icao string, seenTime time.Time) (*db.Aircraft, error) {
@@ -1690,7 +3274,7 @@ func (t *Tracker) initProjectSighting(tx *sqlx.Tx, p *Project, ac *db.Aircraft,
 		if s.ClosedAt == nil {
 			return nil, false, errors.Errorf("last session for %s (id=%d) is still open - possibly running multiple instances of this software", ac.Icao, s.ID)
 		}
-		timeSinceClosed := time.Since(*s.ClosedAt)
+		timeSinceClosed := t.now().Sub(*s.ClosedAt)
 		// reactivate session if it's within our interval
 		// todo: maybe other checks here, like, finished_on_ground or something to avoid rapid stops, so
 		// we break up legs of the journey
@@ -1721,19 +3305,38 @@ func (t *Tracker) initProjectSighting(tx *sqlx.Tx, p *Project, ac *db.Aircraft,
 }
 
 // checkIfPassesFilter evaluates the CEL program and passes it's inputs.
+// now should be in the configured time zone, as it's used for the hour variable.
 // The returned boolean result is only valid if no error is returned.
-func checkIfPassesFilter(prg cel.Program, msg *pb.Message, state *pb.State) (bool, error) {
+func checkIfPassesFilter(prg cel.Program, msg *pb.Message, state *pb.State, sighting *pb.Sighting, watchlists *watchlist.Watchlists, now time.Time) (bool, error) {
 	filterTimer := prometheus.NewTimer(prometheus.ObserverFunc(func(v float64) {
 		us := v * 1000000 // make microseconds
 		filterDurations.Observe(us)
 	}))
 	defer filterTimer.ObserveDuration()
 
+	ts, err := ptypes.TimestampProto(now)
+	if err != nil {
+		return false, errors.Wrapf(err, "converting filter time")
+	}
 	out, _, err := prg.Eval(map[string]interface{}{
 		"msg":                msg,
 		"state":              state,
+		"sighting":           sighting,
+		"now":                ts,
+		"hour":               now.Hour(),
+		"watchlists":         filterWatchlists{w: watchlists},
 		"AdsbExchangeSource": pb.Source_AdsbExchange,
 		"BeastSource":        pb.Source_BeastServer,
+		"SbsSource":          pb.Source_SbsServer,
+		"AvrSource":          pb.Source_AvrServer,
+		"AircraftJSONSource": pb.Source_AircraftJson,
+		"UatSource":          pb.Source_UatServer,
+		"UnknownProvenance":  pb.Provenance_Unknown,
+		"AdsbProvenance":     pb.Provenance_ADSB,
+		"MlatProvenance":     pb.Provenance_MLAT,
+		"TisbProvenance":     pb.Provenance_TISB,
+		"AdsrProvenance":     pb.Provenance_ADSR,
+		"ModeSProvenance":    pb.Provenance_ModeS,
 	})
 	if err != nil {
 		return false, err
@@ -2,12 +2,23 @@ package tracker
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"github.com/afk11/airtrack/pkg/config"
+	"github.com/afk11/airtrack/pkg/coverage"
 	"github.com/afk11/airtrack/pkg/db"
+	"github.com/afk11/airtrack/pkg/geo"
+	"github.com/afk11/airtrack/pkg/mailer"
 	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/afk11/airtrack/pkg/readsb/aircraftdb"
 	"github.com/afk11/airtrack/pkg/test"
+	"github.com/afk11/airtrack/pkg/watchlist"
+	"github.com/afk11/airtrack/pkg/webhook"
 	"github.com/pkg/errors"
 	assert "github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"sync"
 	"testing"
@@ -199,6 +210,122 @@ func TestTracker_AddProject(t *testing.T) {
 	})
 }
 
+func TestTracker_RemoveProject(t *testing.T) {
+	dbConn, dialect, _, closer := test.InitDBUp()
+	defer closer()
+	c := make(chan *pb.Message)
+	database := db.NewDatabase(dbConn, dialect)
+	tr := startTracker(database, c, Options{
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		AircraftDb:              aircraftdb.New(),
+	})
+	p1, err := InitProject(config.Project{Name: "project-one"})
+	assert.NoError(t, err)
+	p2, err := InitProject(config.Project{Name: "project-two"})
+	assert.NoError(t, err)
+	assert.NoError(t, tr.AddProject(p1))
+	assert.NoError(t, tr.AddProject(p2))
+
+	now := time.Now()
+	msg := &pb.Message{Source: beastSource, Icao: "444444"}
+	s := tr.getSighting(msg.Icao, now)
+	assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+	assert.NoError(t, tr.ProcessMessage(p1, s, now, msg))
+	assert.NoError(t, tr.ProcessMessage(p2, s, now, msg))
+	s.mu.Unlock()
+	assert.NoError(t, tr.processDatabaseUpdates())
+
+	assert.Error(t, tr.RemoveProject("unknown"))
+	assert.NoError(t, tr.RemoveProject("project-one"))
+	assert.Equal(t, []*Project{p2}, tr.projects)
+	assert.False(t, p1.IsObserving("444444"))
+	assert.True(t, p2.IsObserving("444444"))
+	_, ok := s.observedBy[p1.Session.ID]
+	assert.False(t, ok)
+	_, ok = s.observedBy[p2.Session.ID]
+	assert.True(t, ok)
+
+	// p1's sighting and session are closed
+	session, err := database.GetSessionByIdentifier(p1.Project, p1.Session.Identifier)
+	assert.NoError(t, err)
+	assert.NotNil(t, session.ClosedAt)
+	sighting, err := database.GetLastSighting(p1.Session, s.a)
+	assert.NoError(t, err)
+	assert.NotNil(t, sighting.ClosedAt)
+	sighting, err = database.GetLastSighting(p2.Session, s.a)
+	assert.NoError(t, err)
+	assert.Nil(t, sighting.ClosedAt)
+
+	// the name can be reused
+	p1, err = InitProject(config.Project{Name: "project-one"})
+	assert.NoError(t, err)
+	assert.NoError(t, tr.AddProject(p1))
+	assert.NoError(t, tr.Stop())
+}
+
+func TestTracker_UpdateProject(t *testing.T) {
+	dbConn, dialect, _, closer := test.InitDBUp()
+	defer closer()
+	c := make(chan *pb.Message)
+	database := db.NewDatabase(dbConn, dialect)
+	tr := startTracker(database, c, Options{
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		LocationUpdateInterval:  time.Second * 15,
+		AircraftDb:              aircraftdb.New(),
+	})
+	proj, err := InitProject(config.Project{Name: "testproj"})
+	assert.NoError(t, err)
+	assert.NoError(t, tr.AddProject(proj))
+
+	now := time.Now()
+	msg := &pb.Message{Source: beastSource, Icao: "444444", Squawk: "1234"}
+	s := tr.getSighting(msg.Icao, now)
+	assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+	assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+	s.mu.Unlock()
+	observation := proj.Observations["444444"]
+	assert.NotNil(t, observation)
+
+	updated, err := InitProject(config.Project{
+		Name:     "testproj",
+		Filter:   `state.Squawk == "7700"`,
+		Features: []string{string(TrackSquawks)},
+	})
+	assert.NoError(t, err)
+	assert.Error(t, tr.UpdateProject(&Project{Name: "unknown"}))
+	assert.NoError(t, tr.UpdateProject(updated))
+
+	// same project, with the new configuration
+	assert.Equal(t, []*Project{proj}, tr.projects)
+	assert.Equal(t, `state.Squawk == "7700"`, proj.Filter)
+	assert.NotNil(t, proj.Program)
+	assert.True(t, proj.IsFeatureEnabled(TrackSquawks))
+	assert.Equal(t, time.Second*15, proj.LocationUpdateInterval)
+
+	// the session records the new features
+	dbProject, err := database.GetProject(proj.Name)
+	assert.NoError(t, err)
+	session, err := database.GetSessionByIdentifier(dbProject, proj.Session.Identifier)
+	assert.NoError(t, err)
+	assert.Equal(t, proj.Session.ID, session.ID)
+	assert.True(t, session.WithSquawks)
+	assert.False(t, session.WithCallSigns)
+	assert.True(t, proj.Session.WithSquawks)
+
+	// the sighting is still open
+	assert.Equal(t, observation, proj.Observations["444444"])
+	msg = &pb.Message{Source: beastSource, Icao: "444444", Squawk: "7700"}
+	s = tr.getSighting(msg.Icao, now)
+	assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+	assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+	s.mu.Unlock()
+	assert.Equal(t, observation, proj.Observations["444444"])
+	assert.Equal(t, 1, len(proj.Observations))
+	assert.NoError(t, tr.Stop())
+}
+
 func TestProjectObservation(t *testing.T) {
 	projCfg := config.Project{
 		Name: "testproj",
@@ -475,3 +602,1099 @@ func TestProjectObservation_LocationUpdateInterval_NotSet(t *testing.T) {
 	assert.Equal(t, lat3, po1.latitude)
 	assert.Equal(t, lon3, po1.longitude)
 }
+
+type testWebhookSender struct {
+	jobs []webhook.Job
+}
+
+func (s *testWebhookSender) Queue(job webhook.Job) error {
+	s.jobs = append(s.jobs, job)
+	return nil
+}
+
+func TestTracker_Webhooks(t *testing.T) {
+	projCfg := config.Project{
+		Name: "testproj",
+		Notifications: &config.Notifications{
+			Enabled: []string{string(SpottedInFlight)},
+			Webhooks: []config.Webhook{
+				{URL: "http://127.0.0.1/hook1", Secret: "secret"},
+				{URL: "http://127.0.0.1/hook2", Enabled: []string{string(MapProduced)}},
+			},
+		},
+	}
+	proj, err := InitProject(projCfg)
+	assert.NoError(t, err)
+
+	sender := &testWebhookSender{}
+	err = doTest(Options{
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		Webhooks:                sender,
+	}, proj, func(tr *Tracker) error {
+		p := pb.Message{Source: beastSource, Icao: "444444"}
+		now := time.Now()
+		s := tr.getSighting(p.Icao, now)
+		defer s.mu.Unlock()
+		err := tr.ProcessMessage(proj, s, now, &p)
+		if err != nil {
+			return errors.Wrap(err, "process message")
+		}
+
+		assert.Equal(t, 1, len(sender.jobs))
+		job := sender.jobs[0]
+		assert.Equal(t, "http://127.0.0.1/hook1", job.URL)
+		assert.Equal(t, string(SpottedInFlight), job.Event)
+		assert.Equal(t, webhook.Sign("secret", job.Body), job.Signature)
+
+		ev := webhook.Event{}
+		assert.NoError(t, json.Unmarshal(job.Body, &ev))
+		assert.Equal(t, string(SpottedInFlight), ev.Event)
+		assert.Equal(t, "testproj", ev.Project)
+		assert.Equal(t, "444444", ev.Icao)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestTracker_Watchlist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watchlist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "gov.csv")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("icao,label\n444444,government\n"), 0644))
+	w := watchlist.NewWatchlists()
+	assert.NoError(t, w.Add("gov", file, ""))
+
+	projCfg := config.Project{
+		Name:   "testproj",
+		Filter: `state.Icao in watchlist("gov")`,
+		Notifications: &config.Notifications{
+			Enabled: []string{string(SpottedInFlight)},
+			Webhooks: []config.Webhook{
+				{URL: "http://127.0.0.1/hook1"},
+			},
+		},
+	}
+	proj, err := InitProject(projCfg)
+	assert.NoError(t, err)
+
+	sender := &testWebhookSender{}
+	err = doTest(Options{
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		Webhooks:                sender,
+		AircraftDb:              aircraftdb.New(),
+		Watchlists:              w,
+	}, proj, func(tr *Tracker) error {
+		process := func(icao string) *Sighting {
+			msg := &pb.Message{Source: beastSource, Icao: icao}
+			now := time.Now()
+			s := tr.getSighting(msg.Icao, now)
+			defer s.mu.Unlock()
+			assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+			assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+			return s
+		}
+
+		s := process("555555")
+		assert.Equal(t, "", s.State.Watchlist)
+		assert.Equal(t, 0, len(sender.jobs))
+
+		s = process("444444")
+		assert.Equal(t, "government", s.State.Watchlist)
+		assert.Equal(t, 1, len(sender.jobs))
+		ev := webhook.Event{}
+		assert.NoError(t, json.Unmarshal(sender.jobs[0].Body, &ev))
+		assert.Equal(t, "government", ev.Watchlist)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestTracker_UseMessageTime(t *testing.T) {
+	dbConn, dialect, _, closer := test.InitDBUp()
+	defer closer()
+	proj, err := InitProject(config.Project{Name: "testproj"})
+	assert.NoError(t, err)
+
+	c := make(chan *pb.Message)
+	database := db.NewDatabase(dbConn, dialect)
+	tr := startTracker(database, c, Options{
+		Workers:                 1,
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		UseMessageTime:          true,
+		AircraftDb:              aircraftdb.New(),
+	})
+	assert.NoError(t, tr.AddProject(proj))
+
+	t0 := time.Date(2020, 10, 18, 20, 0, 0, 0, time.UTC)
+	msgTime := func(d time.Duration) int64 {
+		return t0.Add(d).UnixNano() / int64(time.Millisecond)
+	}
+	c <- &pb.Message{Source: beastSource, Icao: "444444", Time: msgTime(0)}
+	c <- &pb.Message{Source: beastSource, Icao: "555555", Time: msgTime(10 * time.Second)}
+	c <- &pb.Message{Source: beastSource, Icao: "555555", Time: msgTime(20 * time.Second)}
+	// blocks until the previous message is processed
+	c <- &pb.Message{Source: beastSource, Icao: "555555", Time: msgTime(20 * time.Second)}
+
+	tr.sightingMu.Lock()
+	s, ok := tr.sighting["444444"]
+	assert.True(t, ok, "aircraft should not be lost yet")
+	assert.True(t, t0.Equal(s.firstSeen))
+	tr.sightingMu.Unlock()
+	assert.True(t, t0.Add(20*time.Second).Equal(tr.now()))
+
+	// 444444 is lost once message time passes the sighting timeout,
+	// no matter how much wall clock time passes
+	c <- &pb.Message{Source: beastSource, Icao: "555555", Time: msgTime(35 * time.Second)}
+	c <- &pb.Message{Source: beastSource, Icao: "555555", Time: msgTime(40 * time.Second)}
+
+	tr.sightingMu.Lock()
+	_, ok = tr.sighting["444444"]
+	assert.False(t, ok, "aircraft should be lost")
+	_, ok = tr.sighting["555555"]
+	assert.True(t, ok)
+	tr.sightingMu.Unlock()
+
+	close(c)
+	assert.NoError(t, tr.Stop())
+}
+
+// Reloading a project while the consumer processes lost aircraft
+// must not race (run with -race)
+func TestTracker_UpdateProject_UseMessageTime(t *testing.T) {
+	dbConn, dialect, _, closer := test.InitDBUp()
+	defer closer()
+	proj, err := InitProject(config.Project{Name: "testproj"})
+	assert.NoError(t, err)
+
+	c := make(chan *pb.Message)
+	database := db.NewDatabase(dbConn, dialect)
+	tr := startTracker(database, c, Options{
+		Workers:                 1,
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		UseMessageTime:          true,
+		AircraftDb:              aircraftdb.New(),
+	})
+	assert.NoError(t, tr.AddProject(proj))
+
+	done := make(chan struct{})
+	reloaded := make(chan error)
+	go func() {
+		for {
+			select {
+			case <-done:
+				close(reloaded)
+				return
+			default:
+			}
+			updated, err := InitProject(config.Project{
+				Name:     "testproj",
+				Features: []string{string(TrackSquawks)},
+			})
+			if err == nil {
+				err = tr.UpdateProject(updated)
+			}
+			if err != nil {
+				reloaded <- err
+				return
+			}
+		}
+	}()
+
+	t0 := time.Date(2020, 10, 18, 20, 0, 0, 0, time.UTC)
+	for i := 0; i < 20; i++ {
+		icao := fmt.Sprintf("4444%02d", i)
+		c <- &pb.Message{Source: beastSource, Icao: icao, Squawk: "1234",
+			Time: t0.Add(time.Duration(i*10)*time.Second).UnixNano() / int64(time.Millisecond)}
+	}
+	// blocks until the previous message is processed
+	c <- &pb.Message{Source: beastSource, Icao: "444419",
+		Time: t0.Add(190*time.Second).UnixNano() / int64(time.Millisecond)}
+	close(done)
+	for err := range reloaded {
+		assert.NoError(t, err)
+	}
+
+	// early aircraft were lost while the project was being reloaded
+	assert.False(t, proj.IsObserving("444400"))
+	assert.True(t, proj.IsObserving("444419"))
+	close(c)
+	assert.NoError(t, tr.Stop())
+}
+
+func TestTracker_Receivers(t *testing.T) {
+	dbConn, dialect, _, closer := test.InitDBUp()
+	defer closer()
+	proj, err := InitProject(config.Project{
+		Name:     "testproj",
+		Features: []string{string(TrackReceivers), string(TrackCallSigns)},
+	})
+	assert.NoError(t, err)
+
+	c := make(chan *pb.Message)
+	database := db.NewDatabase(dbConn, dialect)
+	tr := startTracker(database, c, Options{
+		Workers:                 1,
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		DedupWindow:             time.Minute,
+		AircraftDb:              aircraftdb.New(),
+	})
+	assert.NoError(t, tr.AddProject(proj))
+
+	home := &pb.Source{Name: "home", Type: pb.Source_BeastServer}
+	roof := &pb.Source{Name: "roof", Type: pb.Source_BeastServer}
+	raw := []byte{0x8d, 0x44, 0x44, 0x44, 0x20, 0x49, 0x64, 0xb2, 0xcb, 0x0c, 0x20, 0x00, 0x00, 0x00}
+	c <- &pb.Message{Source: home, Signal: &pb.Signal{Rssi: -20}, Icao: "444444", CallSign: "RYR2LE", Raw: raw}
+	c <- &pb.Message{Source: roof, Icao: "444444", CallSign: "RYR2LE", Raw: raw}
+	c <- &pb.Message{Source: home, Signal: &pb.Signal{Rssi: -10}, Icao: "444444", CallSign: "RYR2LE", Raw: raw}
+	close(c)
+	assert.NoError(t, tr.Stop())
+
+	ac, err := database.GetAircraftByIcao("444444")
+	assert.NoError(t, err)
+	sighting, err := database.GetLastSighting(proj.Session, ac)
+	assert.NoError(t, err)
+	// the duplicates were not processed
+	callsigns, err := database.GetSightingCallSigns(sighting)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(callsigns))
+
+	receivers, err := database.GetSightingReceivers(sighting)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(receivers))
+	byName := map[string]db.SightingReceiver{}
+	for _, r := range receivers {
+		byName[r.Receiver] = r
+	}
+	assert.NotNil(t, byName["home"].BestRssi)
+	assert.Equal(t, -10.0, *byName["home"].BestRssi)
+	assert.Nil(t, byName["roof"].BestRssi)
+}
+
+func TestTracker_Coverage(t *testing.T) {
+	dbConn, dialect, _, closer := test.InitDBUp()
+	defer closer()
+	proj, err := InitProject(config.Project{Name: "testproj"})
+	assert.NoError(t, err)
+
+	c := coverage.NewCoverage(coverage.DefaultMaxRange)
+	assert.NoError(t, c.AddReceiver("home", 53.35, -6.26, nil))
+	database := db.NewDatabase(dbConn, dialect)
+	msgs := make(chan *pb.Message)
+	tr := startTracker(database, msgs, Options{
+		Workers:                 1,
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		MaxPositionSpeed:        DefaultMaxPositionSpeed,
+		Coverage:                c,
+		AircraftDb:              aircraftdb.New(),
+	})
+	assert.NoError(t, tr.AddProject(proj))
+
+	home := &pb.Source{Name: "home", Type: pb.Source_BeastServer}
+	msgs <- &pb.Message{Source: home, Provenance: pb.Provenance_ADSB, Icao: "444444", AltitudeBarometric: "35000", Latitude: "54.35", Longitude: "-6.26"}
+	// rejected, too far from the previous position
+	msgs <- &pb.Message{Source: home, Provenance: pb.Provenance_ADSB, Icao: "444444", Latitude: "53.35", Longitude: "-5.00"}
+	// unknown receivers are ignored
+	msgs <- &pb.Message{Source: beastSource, Provenance: pb.Provenance_ADSB, Icao: "555555", Latitude: "53.35", Longitude: "-5.26"}
+	// positions which weren't received directly are ignored
+	msgs <- &pb.Message{Source: home, Provenance: pb.Provenance_MLAT, Icao: "666666", Latitude: "53.35", Longitude: "-5.26"}
+	msgs <- &pb.Message{Source: home, Provenance: pb.Provenance_TISB, Icao: "777777", Latitude: "52.35", Longitude: "-6.26"}
+	close(msgs)
+	assert.NoError(t, tr.Stop())
+
+	outline, ok := c.Outline("home")
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(outline.Points))
+	assert.Equal(t, 0, outline.Points[0].Bearing)
+	assert.Equal(t, int64(35000), *outline.Points[0].Altitude)
+}
+
+func TestTracker_LocationProvenance(t *testing.T) {
+	dbConn, dialect, _, closer := test.InitDBUp()
+	defer closer()
+	proj, err := InitProject(config.Project{
+		Name:     "testproj",
+		Features: []string{string(TrackKmlLocation)},
+	})
+	assert.NoError(t, err)
+
+	database := db.NewDatabase(dbConn, dialect)
+	msgs := make(chan *pb.Message)
+	tr := startTracker(database, msgs, Options{
+		Workers:                 1,
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		AircraftDb:              aircraftdb.New(),
+	})
+	assert.NoError(t, tr.AddProject(proj))
+
+	msgs <- &pb.Message{Source: beastSource, Provenance: pb.Provenance_ADSB, Icao: "444444", AltitudeBarometric: "35000", Latitude: "53.35", Longitude: "-6.26"}
+	msgs <- &pb.Message{Source: beastSource, Provenance: pb.Provenance_MLAT, Icao: "444444", Latitude: "53.36", Longitude: "-6.26"}
+	msgs <- &pb.Message{Source: beastSource, Icao: "444444", Latitude: "53.37", Longitude: "-6.26"}
+	close(msgs)
+	assert.NoError(t, tr.Stop())
+
+	ac, err := database.GetAircraftByIcao("444444")
+	assert.NoError(t, err)
+	sighting, err := database.GetLastSighting(proj.Session, ac)
+	assert.NoError(t, err)
+	history, err := database.GetFullLocationHistory(sighting, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(history))
+	assert.NotNil(t, history[0].Provenance)
+	assert.Equal(t, db.ProvenanceADSB, *history[0].Provenance)
+	assert.NotNil(t, history[1].Provenance)
+	assert.Equal(t, db.ProvenanceMLAT, *history[1].Provenance)
+	assert.Nil(t, history[2].Provenance)
+}
+
+func TestTracker_GeocodeEndpoints(t *testing.T) {
+	dbConn, dialect, _, closer := test.InitDBUp()
+	defer closer()
+	proj, err := InitProject(config.Project{
+		Name:     "testproj",
+		Features: []string{string(GeocodeEndpoints)},
+	})
+	assert.NoError(t, err)
+
+	airports := geo.NewNearestAirportGeocoder(DefaultGeoHashLength)
+	assert.NoError(t, airports.Register([]geo.AirportRecord{
+		{Name: "Dublin", Code: "EIDW", Latitude: 53.421333, Longitude: -6.270075},
+		{Name: "London Heathrow", Code: "EGLL", Latitude: 51.4775, Longitude: -0.461389},
+	}))
+
+	c := make(chan *pb.Message)
+	database := db.NewDatabase(dbConn, dialect)
+	tr := startTracker(database, c, Options{
+		Workers:                   1,
+		SightingTimeout:           time.Second * 30,
+		OnGroundUpdateThreshold:   1,
+		UseMessageTime:            true,
+		AircraftDb:                aircraftdb.New(),
+		AirportGeocoder:           airports,
+		NearestAirportMaxAltitude: DefaultNearestAirportMaxAltitude,
+		NearestAirportMaxDistance: DefaultNearestAirportMaxDistance,
+	})
+	assert.NoError(t, tr.AddProject(proj))
+
+	t0 := time.Date(2020, 10, 18, 20, 0, 0, 0, time.UTC)
+	msgTime := func(d time.Duration) int64 {
+		return t0.Add(d).UnixNano() / int64(time.Millisecond)
+	}
+	c <- &pb.Message{Source: beastSource, Icao: "444444", Time: msgTime(0),
+		AltitudeBarometric: "500", Latitude: "53.42500000", Longitude: "-6.26500000"}
+	c <- &pb.Message{Source: beastSource, Icao: "444444", Time: msgTime(10 * time.Second),
+		AltitudeBarometric: "500", Latitude: "51.47000000", Longitude: "-0.45000000"}
+	// 444444 is lost once message time passes the sighting timeout
+	c <- &pb.Message{Source: beastSource, Icao: "555555", Time: msgTime(45 * time.Second)}
+	c <- &pb.Message{Source: beastSource, Icao: "555555", Time: msgTime(50 * time.Second)}
+	close(c)
+	assert.NoError(t, tr.Stop())
+
+	ac, err := database.GetAircraftByIcao("444444")
+	assert.NoError(t, err)
+	sighting, err := database.GetLastSighting(proj.Session, ac)
+	assert.NoError(t, err)
+	assert.NotNil(t, sighting.OriginCode)
+	assert.Equal(t, "EIDW", *sighting.OriginCode)
+	assert.Equal(t, "Dublin", *sighting.OriginName)
+	assert.InDelta(t, 53.421333, *sighting.OriginLatitude, 0.000001)
+	assert.Less(t, *sighting.OriginDistance, DefaultNearestAirportMaxDistance)
+	assert.NotNil(t, sighting.DestinationCode)
+	assert.Equal(t, "EGLL", *sighting.DestinationCode)
+	assert.Equal(t, "London Heathrow", *sighting.DestinationName)
+	assert.Less(t, *sighting.DestinationDistance, DefaultNearestAirportMaxDistance)
+}
+
+func TestTracker_Landing(t *testing.T) {
+	projCfg := config.Project{
+		Name:     "testproj",
+		Features: []string{string(TrackLanding), string(GeocodeEndpoints)},
+		Notifications: &config.Notifications{
+			Enabled: []string{string(LandingAtAirport), string(LandingUnknownAirport)},
+			Webhooks: []config.Webhook{
+				{URL: "http://127.0.0.1/hook1"},
+			},
+		},
+	}
+	proj, err := InitProject(projCfg)
+	assert.NoError(t, err)
+
+	airports := geo.NewNearestAirportGeocoder(DefaultGeoHashLength)
+	assert.NoError(t, airports.Register([]geo.AirportRecord{
+		{Name: "Dublin", Code: "EIDW", Latitude: 53.421333, Longitude: -6.270075},
+	}))
+	sender := &testWebhookSender{}
+	err = doTest(Options{
+		SightingTimeout:           time.Second * 30,
+		OnGroundUpdateThreshold:   1,
+		Webhooks:                  sender,
+		AircraftDb:                aircraftdb.New(),
+		AirportGeocoder:           airports,
+		NearestAirportMaxAltitude: DefaultNearestAirportMaxAltitude,
+		NearestAirportMaxDistance: DefaultNearestAirportMaxDistance,
+	}, proj, func(tr *Tracker) error {
+		process := func(msg *pb.Message) *Sighting {
+			now := time.Now()
+			s := tr.getSighting(msg.Icao, now)
+			defer s.mu.Unlock()
+			assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+			assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+			return s
+		}
+		descending := func(icao string, altitude string, rate int64) *pb.Message {
+			return &pb.Message{Source: beastSource, Icao: icao, AltitudeBarometric: altitude,
+				HaveVerticalRateBarometric: true, VerticalRateBarometric: rate,
+				Latitude: "53.42500000", Longitude: "-6.26500000"}
+		}
+		onGround := func(icao string) *pb.Message {
+			return &pb.Message{Source: beastSource, Icao: icao, IsOnGround: true}
+		}
+
+		// too high to be on approach
+		s := process(descending("444444", "9000", -1200))
+		assert.False(t, s.Tags.IsOnApproach)
+		s = process(descending("444444", "1200", -640))
+		assert.True(t, s.Tags.IsOnApproach)
+		assert.False(t, s.Tags.HasLanded)
+		for i := 0; i < 3; i++ {
+			s = process(onGround("444444"))
+		}
+		assert.True(t, s.State.IsOnGround)
+		assert.False(t, s.Tags.IsOnApproach)
+		assert.True(t, s.Tags.HasLanded)
+
+		assert.Equal(t, 1, len(sender.jobs))
+		assert.Equal(t, string(LandingAtAirport), sender.jobs[0].Event)
+		ev := webhook.Event{}
+		assert.NoError(t, json.Unmarshal(sender.jobs[0].Body, &ev))
+		assert.Equal(t, "444444", ev.Icao)
+		assert.Equal(t, "Dublin", ev.AirportName)
+		assert.NotNil(t, ev.EndLocation)
+		observation := proj.Observations["444444"]
+		assert.True(t, observation.destination.ok)
+		assert.Equal(t, "EIDW", observation.destination.airport.Code)
+
+		// a go-around cancels the approach
+		s = process(descending("555555", "1000", -640))
+		assert.True(t, s.Tags.IsOnApproach)
+		s = process(descending("555555", "1100", 1500))
+		assert.False(t, s.Tags.IsOnApproach)
+		for i := 0; i < 3; i++ {
+			s = process(onGround("555555"))
+		}
+		assert.True(t, s.State.IsOnGround)
+		assert.False(t, s.Tags.HasLanded)
+		assert.Equal(t, 1, len(sender.jobs))
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestTracker_Landing_HighElevation(t *testing.T) {
+	projCfg := config.Project{
+		Name:     "testproj",
+		Features: []string{string(TrackLanding), string(GeocodeEndpoints)},
+		Notifications: &config.Notifications{
+			Enabled: []string{string(LandingAtAirport), string(LandingUnknownAirport)},
+			Webhooks: []config.Webhook{
+				{URL: "http://127.0.0.1/hook1"},
+			},
+		},
+	}
+	proj, err := InitProject(projCfg)
+	assert.NoError(t, err)
+
+	airports := geo.NewNearestAirportGeocoder(DefaultGeoHashLength)
+	assert.NoError(t, airports.Register([]geo.AirportRecord{
+		{Name: "Denver", Code: "KDEN", Latitude: 39.861656, Longitude: -104.673178, Elevation: 1655},
+	}))
+	sender := &testWebhookSender{}
+	err = doTest(Options{
+		SightingTimeout:           time.Second * 30,
+		OnGroundUpdateThreshold:   1,
+		Webhooks:                  sender,
+		AircraftDb:                aircraftdb.New(),
+		AirportGeocoder:           airports,
+		NearestAirportMaxAltitude: DefaultNearestAirportMaxAltitude,
+		NearestAirportMaxDistance: DefaultNearestAirportMaxDistance,
+	}, proj, func(tr *Tracker) error {
+		process := func(msg *pb.Message) *Sighting {
+			now := time.Now()
+			s := tr.getSighting(msg.Icao, now)
+			defer s.mu.Unlock()
+			assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+			assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+			return s
+		}
+		descending := func(altitude string, rate int64) *pb.Message {
+			return &pb.Message{Source: beastSource, Icao: "444444", AltitudeBarometric: altitude,
+				HaveVerticalRateBarometric: true, VerticalRateBarometric: rate,
+				Latitude: "39.85000000", Longitude: "-104.67000000"}
+		}
+
+		// the approach is measured from the airport elevation (5430ft)
+		s := process(descending("9000", -1200))
+		assert.False(t, s.Tags.IsOnApproach)
+		s = process(descending("7200", -700))
+		assert.True(t, s.Tags.IsOnApproach)
+		for i := 0; i < 3; i++ {
+			s = process(&pb.Message{Source: beastSource, Icao: "444444", IsOnGround: true})
+		}
+		assert.True(t, s.State.IsOnGround)
+		assert.True(t, s.Tags.HasLanded)
+
+		assert.Equal(t, 1, len(sender.jobs))
+		assert.Equal(t, string(LandingAtAirport), sender.jobs[0].Event)
+		ev := webhook.Event{}
+		assert.NoError(t, json.Unmarshal(sender.jobs[0].Body, &ev))
+		assert.Equal(t, "Denver", ev.AirportName)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestTracker_Emergency(t *testing.T) {
+	projCfg := config.Project{
+		Name: "testproj",
+		Notifications: &config.Notifications{
+			Enabled: []string{string(Emergency)},
+			Webhooks: []config.Webhook{
+				{URL: "http://127.0.0.1/hook1"},
+			},
+		},
+	}
+	proj, err := InitProject(projCfg)
+	assert.NoError(t, err)
+
+	sender := &testWebhookSender{}
+	err = doTest(Options{
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		Webhooks:                sender,
+		AircraftDb:              aircraftdb.New(),
+	}, proj, func(tr *Tracker) error {
+		process := func(msg *pb.Message) {
+			now := time.Now()
+			s := tr.getSighting(msg.Icao, now)
+			defer s.mu.Unlock()
+			assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+			assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+		}
+		squawk := func(code string) *pb.Message {
+			return &pb.Message{Source: beastSource, Icao: "444444", Squawk: code,
+				CallSign: "EIN123", Latitude: "53.42500000", Longitude: "-6.26500000"}
+		}
+		lastEvent := func() webhook.Event {
+			ev := webhook.Event{}
+			assert.NoError(t, json.Unmarshal(sender.jobs[len(sender.jobs)-1].Body, &ev))
+			return ev
+		}
+
+		process(squawk("1234"))
+		assert.Equal(t, 0, len(sender.jobs))
+
+		// fires once per episode
+		process(squawk("7700"))
+		process(squawk("7700"))
+		assert.Equal(t, 1, len(sender.jobs))
+		ev := lastEvent()
+		assert.Equal(t, string(Emergency), ev.Event)
+		assert.Equal(t, "EIN123", ev.CallSign)
+		assert.Equal(t, "7700", ev.Squawk)
+		assert.Equal(t, "general emergency", ev.Description)
+		assert.NotNil(t, ev.Location)
+		assert.Equal(t, 53.425, ev.Location.Latitude)
+
+		// fires again when the code changes
+		process(squawk("7600"))
+		assert.Equal(t, 2, len(sender.jobs))
+		assert.Equal(t, "radio failure", lastEvent().Description)
+
+		// and for a new episode
+		process(squawk("1234"))
+		assert.Equal(t, 2, len(sender.jobs))
+		process(squawk("7700"))
+		assert.Equal(t, 3, len(sender.jobs))
+
+		// ADS-B emergency status without a squawk
+		process(&pb.Message{Source: beastSource, Icao: "555555", HaveEmergency: true, Emergency: "none"})
+		assert.Equal(t, 3, len(sender.jobs))
+		process(&pb.Message{Source: beastSource, Icao: "555555", HaveEmergency: true, Emergency: "minfuel"})
+		assert.Equal(t, 4, len(sender.jobs))
+		ev = lastEvent()
+		assert.Equal(t, "minfuel", ev.Emergency)
+		assert.Equal(t, "", ev.Squawk)
+		assert.Equal(t, "minimum fuel", ev.Description)
+		assert.Nil(t, ev.Location)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+type testMailSender struct {
+	jobs chan mailer.EmailJob
+}
+
+func (s *testMailSender) Queue(job mailer.EmailJob) error {
+	s.jobs <- job
+	return nil
+}
+
+func TestTracker_EmergencyEmail(t *testing.T) {
+	projCfg := config.Project{
+		Name:     "testproj",
+		Features: []string{string(TrackKmlLocation)},
+		Notifications: &config.Notifications{
+			Email:   "test@example.com",
+			Enabled: []string{string(Emergency)},
+		},
+	}
+	proj, err := InitProject(projCfg)
+	assert.NoError(t, err)
+
+	sender := &testMailSender{jobs: make(chan mailer.EmailJob, 1)}
+	err = doTest(Options{
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		Mailer:                  sender,
+		AircraftDb:              aircraftdb.New(),
+	}, proj, func(tr *Tracker) error {
+		process := func(msg *pb.Message) {
+			now := time.Now()
+			s := tr.getSighting(msg.Icao, now)
+			defer s.mu.Unlock()
+			assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+			assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+		}
+		process(&pb.Message{Source: beastSource, Icao: "444444", Squawk: "1234",
+			AltitudeBarometric: "3000", Latitude: "53.42500000", Longitude: "-6.26500000"})
+		process(&pb.Message{Source: beastSource, Icao: "444444", Squawk: "7700",
+			AltitudeBarometric: "2900", Latitude: "53.43000000", Longitude: "-6.27000000"})
+
+		// the email is prepared in the background
+		select {
+		case job := <-sender.jobs:
+			assert.Equal(t, "test@example.com", job.To)
+			assert.Equal(t, 1, len(job.Attachments))
+			assert.Contains(t, string(job.Attachments[0].Contents), "-6.27")
+		case <-time.After(time.Second * 5):
+			return errors.New("timed out waiting for emergency email")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestTracker_Zones(t *testing.T) {
+	projCfg := config.Project{
+		Name:   "testproj",
+		Filter: `"home" in state.Zones || "box" in state.Zones`,
+		Notifications: &config.Notifications{
+			Enabled: []string{string(ZoneEntered), string(ZoneExited)},
+			Webhooks: []config.Webhook{
+				{URL: "http://127.0.0.1/hook1"},
+			},
+		},
+	}
+	proj, err := InitProject(projCfg)
+	assert.NoError(t, err)
+
+	zones, err := geo.NewZones([]geo.Zone{
+		geo.NewCircleZone("home", 53.421333, -6.270075, 5000),
+		geo.NewPolygonZone("box", []geo.Point{
+			{Latitude: 53.4, Longitude: -6.3}, {Latitude: 53.6, Longitude: -6.3},
+			{Latitude: 53.6, Longitude: -6.0}, {Latitude: 53.4, Longitude: -6.0},
+		}),
+	})
+	assert.NoError(t, err)
+	sender := &testWebhookSender{}
+	err = doTest(Options{
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		Webhooks:                sender,
+		AircraftDb:              aircraftdb.New(),
+		Zones:                   zones,
+	}, proj, func(tr *Tracker) error {
+		process := func(lat, lon string) *Sighting {
+			msg := &pb.Message{Source: beastSource, Icao: "444444", Latitude: lat, Longitude: lon}
+			now := time.Now()
+			s := tr.getSighting(msg.Icao, now)
+			defer s.mu.Unlock()
+			assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+			assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+			return s
+		}
+		event := func(i int) webhook.Event {
+			ev := webhook.Event{}
+			assert.NoError(t, json.Unmarshal(sender.jobs[i].Body, &ev))
+			return ev
+		}
+
+		// outside all zones, filtered out
+		s := process("53.00000000", "-6.27000000")
+		assert.Equal(t, 0, len(s.State.Zones))
+		assert.Equal(t, 0, len(sender.jobs))
+
+		// inside both
+		s = process("53.42000000", "-6.26000000")
+		assert.Equal(t, []string{"home", "box"}, s.State.Zones)
+		assert.Equal(t, 2, len(sender.jobs))
+		assert.Equal(t, string(ZoneEntered), sender.jobs[0].Event)
+		assert.Equal(t, "home", event(0).Zone)
+		assert.Equal(t, "box", event(1).Zone)
+		assert.NotNil(t, event(1).Location)
+
+		// still inside both
+		process("53.42100000", "-6.26100000")
+		assert.Equal(t, 2, len(sender.jobs))
+
+		// leaves home, still in box
+		s = process("53.55000000", "-6.10000000")
+		assert.Equal(t, []string{"box"}, s.State.Zones)
+		assert.Equal(t, 3, len(sender.jobs))
+		assert.Equal(t, string(ZoneExited), sender.jobs[2].Event)
+		assert.Equal(t, "home", event(2).Zone)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestTracker_Orbiting(t *testing.T) {
+	projCfg := config.Project{
+		Name:   "testproj",
+		Filter: `state.IsOrbiting`,
+		Notifications: &config.Notifications{
+			Enabled: []string{string(Orbiting)},
+			Webhooks: []config.Webhook{
+				{URL: "http://127.0.0.1/hook1"},
+			},
+		},
+	}
+	proj, err := InitProject(projCfg)
+	assert.NoError(t, err)
+
+	sender := &testWebhookSender{}
+	err = doTest(Options{
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		Webhooks:                sender,
+		AircraftDb:              aircraftdb.New(),
+	}, proj, func(tr *Tracker) error {
+		now := time.Now()
+		process := func(lat, lon, track float64) *Sighting {
+			msg := &pb.Message{Source: beastSource, Icao: "4CA123", CallSign: "GARDA1", AltitudeBarometric: "1500",
+				Latitude: fmt.Sprintf("%f", lat), Longitude: fmt.Sprintf("%f", lon), Track: fmt.Sprintf("%f", track)}
+			now = now.Add(time.Second * 5)
+			s := tr.getSighting(msg.Icao, now)
+			defer s.mu.Unlock()
+			assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+			assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+			return s
+		}
+
+		// half a circle isn't an orbit
+		var s *Sighting
+		for angle := 0.0; angle <= 180; angle += 10 {
+			s = process(circlePosition(53.35, -6.26, 1000, angle))
+		}
+		assert.False(t, s.State.IsOrbiting)
+		assert.Equal(t, 0, len(sender.jobs))
+
+		for angle := 190.0; angle <= 720; angle += 10 {
+			s = process(circlePosition(53.35, -6.26, 1000, angle))
+		}
+		assert.True(t, s.State.IsOrbiting)
+		assert.True(t, s.Tags.IsOrbiting)
+		assert.Equal(t, 1, len(sender.jobs))
+		assert.Equal(t, string(Orbiting), sender.jobs[0].Event)
+		ev := webhook.Event{}
+		assert.NoError(t, json.Unmarshal(sender.jobs[0].Body, &ev))
+		assert.Equal(t, "GARDA1", ev.CallSign)
+		assert.NotNil(t, ev.Location)
+		assert.InDelta(t, 0, geo.Distance(53.35, -6.26, ev.Location.Latitude, ev.Location.Longitude), 100)
+		assert.Equal(t, int64(1500), ev.Location.Altitude)
+		assert.InDelta(t, 1000, ev.Radius, 50)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestTracker_PositionAnomaly(t *testing.T) {
+	projCfg := config.Project{
+		Name: "testproj",
+		Notifications: &config.Notifications{
+			Enabled: []string{string(PositionAnomaly)},
+			Webhooks: []config.Webhook{
+				{URL: "http://127.0.0.1/hook1"},
+			},
+		},
+	}
+	proj, err := InitProject(projCfg)
+	assert.NoError(t, err)
+
+	sender := &testWebhookSender{}
+	err = doTest(Options{
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		MaxPositionSpeed:        DefaultMaxPositionSpeed,
+		Webhooks:                sender,
+		AircraftDb:              aircraftdb.New(),
+	}, proj, func(tr *Tracker) error {
+		now := time.Now()
+		process := func(lat, lon string) (*Sighting, *pb.Message) {
+			msg := &pb.Message{Source: beastSource, Icao: "444444", Latitude: lat, Longitude: lon}
+			now = now.Add(time.Second)
+			s := tr.getSighting(msg.Icao, now)
+			defer s.mu.Unlock()
+			assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+			assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+			return s, msg
+		}
+
+		s, _ := process("53.42000000", "-6.26000000")
+		assert.Equal(t, 53.42, s.State.Latitude)
+
+		// the jump is rejected
+		s, msg := process("40.10000000", "-73.40000000")
+		assert.Equal(t, 53.42, s.State.Latitude)
+		assert.Equal(t, -6.26, s.State.Longitude)
+		assert.Equal(t, "", msg.Latitude)
+		assert.Equal(t, 0, len(sender.jobs))
+
+		s, _ = process("53.42100000", "-6.26100000")
+		assert.Equal(t, 53.421, s.State.Latitude)
+
+		process("10.00000000", "10.00000000")
+		process("-33.90000000", "151.20000000")
+		assert.Equal(t, 1, len(sender.jobs))
+		assert.Equal(t, string(PositionAnomaly), sender.jobs[0].Event)
+		ev := webhook.Event{}
+		assert.NoError(t, json.Unmarshal(sender.jobs[0].Body, &ev))
+		assert.Contains(t, ev.Description, "3 implausible positions")
+		assert.NotNil(t, ev.Location)
+		assert.Equal(t, 53.421, ev.Location.Latitude)
+
+		// only once per anomaly
+		process("-33.90000000", "151.20000000")
+		assert.Equal(t, 1, len(sender.jobs))
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestTracker_PositionAnomaly_BeforeObserved(t *testing.T) {
+	projCfg := config.Project{
+		Name:   "testproj",
+		Filter: `state.Squawk == "7700"`,
+		Notifications: &config.Notifications{
+			Enabled: []string{string(PositionAnomaly)},
+			Webhooks: []config.Webhook{
+				{URL: "http://127.0.0.1/hook1"},
+			},
+		},
+	}
+	proj, err := InitProject(projCfg)
+	assert.NoError(t, err)
+
+	sender := &testWebhookSender{}
+	err = doTest(Options{
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		MaxPositionSpeed:        DefaultMaxPositionSpeed,
+		Webhooks:                sender,
+		AircraftDb:              aircraftdb.New(),
+	}, proj, func(tr *Tracker) error {
+		now := time.Now()
+		process := func(squawk, lat, lon string) {
+			msg := &pb.Message{Source: beastSource, Icao: "444444", Squawk: squawk, Latitude: lat, Longitude: lon}
+			now = now.Add(time.Second)
+			s := tr.getSighting(msg.Icao, now)
+			defer s.mu.Unlock()
+			assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+			assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+		}
+
+		process("1234", "53.42000000", "-6.26000000")
+		process("1234", "40.10000000", "-73.40000000")
+		process("1234", "10.00000000", "10.00000000")
+		process("1234", "-33.90000000", "151.20000000")
+		assert.False(t, tr.IsObserving(proj.Name, "444444"))
+
+		// the anomaly happened before the project observed the aircraft
+		process("7700", "53.42100000", "-6.26100000")
+		assert.True(t, tr.IsObserving(proj.Name, "444444"))
+		assert.Equal(t, 0, len(sender.jobs))
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestTracker_PositionNACP(t *testing.T) {
+	proj, err := InitProject(config.Project{Name: "testproj"})
+	assert.NoError(t, err)
+
+	err = doTest(Options{
+		SightingTimeout:         time.Second * 30,
+		OnGroundUpdateThreshold: 1,
+		MaxPositionSpeed:        DefaultMaxPositionSpeed,
+		AircraftDb:              aircraftdb.New(),
+	}, proj, func(tr *Tracker) error {
+		now := time.Now()
+		process := func(msg *pb.Message) *Sighting {
+			now = now.Add(time.Second)
+			s := tr.getSighting(msg.Icao, now)
+			defer s.mu.Unlock()
+			assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+			assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+			return s
+		}
+		// NACp 1 is received before the positions
+		process(&pb.Message{Source: beastSource, Icao: "444444", HaveNACP: true, NACP: 1})
+		process(&pb.Message{Source: beastSource, Icao: "444444", Latitude: "53.40000000", Longitude: "-6.20000000"})
+		// 19km in one second is within the uncertainty of NACp 1
+		s := process(&pb.Message{Source: beastSource, Icao: "444444", Latitude: "53.57000000", Longitude: "-6.20000000"})
+		assert.Equal(t, 53.57, s.State.Latitude)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestTracker_Rules(t *testing.T) {
+	projCfg := config.Project{
+		Name: "testproj",
+		Rules: []config.Rule{
+			{Name: "low_rch", Condition: `state.CallSign.startsWith("RCH") && state.AltitudeBarometric < 5000`},
+			{Name: "low", Condition: `state.AltitudeBarometric < 1000`, Trigger: RuleTriggerCooldown, Cooldown: 60},
+		},
+		Notifications: &config.Notifications{
+			Enabled: []string{"low_rch", "low"},
+			Webhooks: []config.Webhook{
+				{URL: "http://127.0.0.1/hook1"},
+			},
+		},
+	}
+	proj, err := InitProject(projCfg)
+	assert.NoError(t, err)
+
+	sender := &testWebhookSender{}
+	err = doTest(Options{
+		SightingTimeout:         time.Hour,
+		OnGroundUpdateThreshold: 1,
+		Webhooks:                sender,
+		AircraftDb:              aircraftdb.New(),
+	}, proj, func(tr *Tracker) error {
+		start := time.Now()
+		process := func(icao, callsign, altitude string, now time.Time) {
+			msg := &pb.Message{Source: beastSource, Icao: icao, CallSign: callsign, AltitudeBarometric: altitude}
+			s := tr.getSighting(msg.Icao, now)
+			defer s.mu.Unlock()
+			assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+			assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+		}
+
+		process("AE1234", "RCH123", "8000", start)
+		assert.Equal(t, 0, len(sender.jobs))
+		process("AE1234", "RCH123", "4000", start)
+		assert.Equal(t, 1, len(sender.jobs))
+		assert.Equal(t, "low_rch", sender.jobs[0].Event)
+		ev := webhook.Event{}
+		assert.NoError(t, json.Unmarshal(sender.jobs[0].Body, &ev))
+		assert.Equal(t, "AE1234", ev.Icao)
+		assert.Equal(t, "RCH123", ev.CallSign)
+		assert.Equal(t, projCfg.Rules[0].Condition, ev.Condition)
+
+		// once per sighting
+		process("AE1234", "RCH123", "8000", start)
+		process("AE1234", "RCH123", "3000", start)
+		assert.Equal(t, 1, len(sender.jobs))
+
+		// cooldown rule re-arms
+		process("AE1234", "RCH123", "900", start)
+		assert.Equal(t, 2, len(sender.jobs))
+		assert.Equal(t, "low", sender.jobs[1].Event)
+		process("AE1234", "RCH123", "800", start.Add(time.Second*59))
+		assert.Equal(t, 2, len(sender.jobs))
+		process("AE1234", "RCH123", "700", start.Add(time.Second*61))
+		assert.Equal(t, 3, len(sender.jobs))
+		assert.Equal(t, "low", sender.jobs[2].Event)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestTracker_Phases(t *testing.T) {
+	projCfg := config.Project{
+		Name:     "testproj",
+		Features: []string{string(TrackPhases), string(TrackTakeoff)},
+		Notifications: &config.Notifications{
+			Enabled: []string{string(TakeoffUnknownAirport), string(TakeoffComplete)},
+			Webhooks: []config.Webhook{
+				{URL: "http://127.0.0.1/hook1"},
+			},
+		},
+	}
+	proj, err := InitProject(projCfg)
+	assert.NoError(t, err)
+
+	sender := &testWebhookSender{}
+	err = doTest(Options{
+		SightingTimeout:         time.Hour,
+		OnGroundUpdateThreshold: 1,
+		Webhooks:                sender,
+		AircraftDb:              aircraftdb.New(),
+	}, proj, func(tr *Tracker) error {
+		now := time.Now()
+		process := func(msg *pb.Message) *Sighting {
+			now = now.Add(time.Second)
+			s := tr.getSighting(msg.Icao, now)
+			defer s.mu.Unlock()
+			assert.NoError(t, tr.UpdateStateFromMessage(s, msg, now))
+			assert.NoError(t, tr.ProcessMessage(proj, s, now, msg))
+			return s
+		}
+		airborne := func(altitude string, rate int64) *pb.Message {
+			return &pb.Message{Source: beastSource, Icao: "444444", AltitudeBarometric: altitude,
+				HaveVerticalRateBarometric: true, VerticalRateBarometric: rate}
+		}
+
+		var s *Sighting
+		for i := 0; i < 3; i++ {
+			s = process(&pb.Message{Source: beastSource, Icao: "444444", IsOnGround: true})
+		}
+		assert.Equal(t, string(PhaseGround), s.State.Phase)
+		for i := 0; i < 3; i++ {
+			s = process(airborne("300", 2000))
+		}
+		assert.Equal(t, string(PhaseTakeoff), s.State.Phase)
+		assert.True(t, s.Tags.IsInTakeoff)
+		assert.Equal(t, 1, len(sender.jobs))
+		assert.Equal(t, string(TakeoffUnknownAirport), sender.jobs[0].Event)
+
+		s = process(airborne("2000", 2000))
+		assert.Equal(t, string(PhaseClimb), s.State.Phase)
+		assert.False(t, s.Tags.IsInTakeoff)
+		assert.Equal(t, 2, len(sender.jobs))
+		assert.Equal(t, string(TakeoffComplete), sender.jobs[1].Event)
+		s = process(airborne("9000", 0))
+		assert.Equal(t, string(PhaseCruise), s.State.Phase)
+
+		observation := proj.Observations["444444"]
+		assert.Equal(t, PhaseCruise, observation.phase)
+		assert.NoError(t, tr.processDatabaseUpdates())
+		phases, err := tr.database.GetSightingPhases(observation.sighting)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, len(phases))
+		for i, phase := range []FlightPhase{PhaseGround, PhaseTakeoff, PhaseClimb, PhaseCruise} {
+			assert.Equal(t, string(phase), phases[i].Phase)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,48 @@
+package tracker
+
+// UatJSONMessage - structure of a message written to the
+// json port of dump978-fa. Optional fields are pointers so
+// a missing value can be distinguished from zero.
+//easyjson:json
+type UatJSONMessage struct {
+	Address                    string           `json:"address"`
+	AddressQualifier           string           `json:"address_qualifier"`
+	AirGroundState             string           `json:"airground_state"`
+	Position                   *UatJSONPosition `json:"position"`
+	PressureAltitude           *int64           `json:"pressure_altitude"`
+	GeometricAltitude          *int64           `json:"geometric_altitude"`
+	GroundSpeed                *float64         `json:"ground_speed"`
+	TrueTrack                  *float64         `json:"true_track"`
+	MagneticHeading            *float64         `json:"magnetic_heading"`
+	TrueHeading                *float64         `json:"true_heading"`
+	VerticalVelocityBarometric *int64           `json:"vertical_velocity_barometric"`
+	VerticalVelocityGeometric  *int64           `json:"vertical_velocity_geometric"`
+	Callsign                   string           `json:"callsign"`
+	FlightPlanID               string           `json:"flightplan_id"`
+	EmitterCategory            string           `json:"emitter_category"`
+	Emergency                  string           `json:"emergency"`
+	NacP                       *uint32          `json:"nac_p"`
+	NacV                       *uint32          `json:"nac_v"`
+	NicBaro                    *uint32          `json:"nic_baro"`
+	Sil                        *uint32          `json:"sil"`
+	SilSupplement              string           `json:"sil_supplement"`
+	SelectedHeading            *float64         `json:"selected_heading"`
+	BarometricPressureSetting  *float64         `json:"barometric_pressure_setting"`
+	Metadata                   *UatJSONMetadata `json:"metadata"`
+}
+
+// UatJSONPosition - position of the aircraft in a UatJSONMessage
+//easyjson:json
+type UatJSONPosition struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// UatJSONMetadata - information about how dump978-fa
+// received the message
+//easyjson:json
+type UatJSONMetadata struct {
+	Errors     int64    `json:"errors"`
+	ReceivedAt float64  `json:"received_at"`
+	Rssi       *float64 `json:"rssi"`
+}
@@ -0,0 +1,442 @@
+package tracker
+
+import (
+	"context"
+	"encoding/hex"
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/afk11/airtrack/pkg/readsb"
+	"github.com/mailru/easyjson"
+	"github.com/pkg/errors"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// UatFormatJSON - dump978-fa's json output (port 30979)
+	UatFormatJSON = "json"
+	// UatFormatRaw - dump978's raw frame output (port 30978)
+	UatFormatRaw = "raw"
+
+	// uatBasicFrameLength - length of a basic UAT ADS-B frame in bytes
+	uatBasicFrameLength = 18
+	// uatLongFrameLength - length of a long UAT ADS-B frame in bytes
+	uatLongFrameLength = 34
+
+	// Address qualifiers of UAT messages with an ICAO address.
+	// Other qualifiers are self-assigned or TIS-B track file
+	// addresses, and are ignored.
+	uatQualifierAdsbIcao = 0
+	uatQualifierTisbIcao = 2
+
+	// Air/ground states in the UAT state vector
+	uatAirborneSubsonic   = 0
+	uatAirborneSupersonic = 1
+	uatOnGround           = 2
+)
+
+var (
+	// uatBase40 - alphabet used for UAT callsigns
+	uatBase40 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ  .."
+	// uatEmergencies - names of UAT emergency/priority codes,
+	// which match readsb's names for ADS-B emergencies
+	uatEmergencies = []string{"none", "general", "lifeguard", "minfuel", "nordo", "unlawful", "downed", "reserved"}
+)
+
+// UatProducer - implements Producer.
+// This type represents a connection to a dump978 server, which
+// decodes UAT (978MHz) messages. dump978-fa writes JSON messages
+// to its json port, and raw frames to its raw port.
+type UatProducer struct {
+	name      string
+	reader    *lineReader
+	wg        sync.WaitGroup
+	canceller func()
+}
+
+// NewUatProducer initializes a new UatProducer. format is
+// either UatFormatJSON or UatFormatRaw.
+func NewUatProducer(msgs chan *pb.Message, host string, port uint16, name string, format string) (*UatProducer, error) {
+	var parse func(line string) (*pb.Message, error)
+	switch format {
+	case UatFormatJSON:
+		parse = ParseUatJSONMessage
+	case UatFormatRaw:
+		parse = ParseUatRawMessage
+	default:
+		return nil, errors.Errorf("unknown uat format '%s'", format)
+	}
+	source := &pb.Source{
+		Type: pb.Source_UatServer,
+		Name: name,
+	}
+	return &UatProducer{
+		name: name,
+		reader: newLineReader(msgs, "uat", source, host, port, func(line string) ([]*pb.Message, error) {
+			msg, err := parse(line)
+			if err != nil || msg == nil {
+				return nil, err
+			}
+			return []*pb.Message{msg}, nil
+		}),
+	}, nil
+}
+
+// Name - see Producer.Name()
+func (p *UatProducer) Name() string {
+	return p.name
+}
+
+// Start - see Producer.Start()
+// This function starts the producer goroutine.
+func (p *UatProducer) Start() {
+	p.wg.Add(1)
+	ctx, canceller := context.WithCancel(context.Background())
+	p.canceller = canceller
+	go func() {
+		defer p.wg.Done()
+		p.reader.run(ctx)
+	}()
+}
+
+// Stop sends the cancel signal to the producer goroutine
+// and blocks until it finishes processing
+func (p *UatProducer) Stop() {
+	p.canceller()
+	p.wg.Wait()
+}
+
+// ParseUatJSONMessage parses a JSON message written by dump978-fa
+// and converts it into a pb.Message without a Source. Messages
+// without an ICAO address return a nil message.
+func ParseUatJSONMessage(line string) (*pb.Message, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil, nil
+	}
+	m := &UatJSONMessage{}
+	err := easyjson.Unmarshal([]byte(line), m)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding uat json")
+	}
+	var provenance pb.Provenance
+	switch m.AddressQualifier {
+	case "adsb_icao":
+		provenance = pb.Provenance_ADSB
+	case "tisb_icao":
+		provenance = pb.Provenance_TISB
+	default:
+		return nil, nil
+	}
+	icao := strings.ToUpper(m.Address)
+	if len(icao) != 6 {
+		return nil, errors.Errorf("invalid icao: %s", m.Address)
+	} else if _, err := strconv.ParseUint(icao, 16, 32); err != nil {
+		return nil, errors.Errorf("invalid icao: %s", m.Address)
+	}
+
+	msg := &pb.Message{
+		Icao:       icao,
+		Provenance: provenance,
+		CallSign:   strings.TrimSpace(m.Callsign),
+		Squawk:     m.FlightPlanID,
+		IsOnGround: m.AirGroundState == "ground",
+	}
+	if m.Position != nil {
+		msg.Latitude = strconv.FormatFloat(m.Position.Lat, 'f', 8, 64)
+		msg.Longitude = strconv.FormatFloat(m.Position.Lon, 'f', 8, 64)
+	}
+	if m.PressureAltitude != nil {
+		msg.AltitudeBarometric = strconv.FormatInt(*m.PressureAltitude, 10)
+	}
+	if m.GeometricAltitude != nil {
+		msg.AltitudeGeometric = strconv.FormatInt(*m.GeometricAltitude, 10)
+	}
+	if m.GroundSpeed != nil {
+		msg.GroundSpeed = strconv.FormatFloat(*m.GroundSpeed, 'f', 1, 64)
+	}
+	if m.TrueTrack != nil {
+		msg.Track = strconv.FormatFloat(*m.TrueTrack, 'f', 6, 64)
+	}
+	if m.MagneticHeading != nil {
+		msg.HaveMagneticHeading = true
+		msg.MagneticHeading = *m.MagneticHeading
+	}
+	if m.TrueHeading != nil {
+		msg.HaveTrueHeading = true
+		msg.TrueHeading = *m.TrueHeading
+	}
+	if m.VerticalVelocityBarometric != nil {
+		msg.HaveVerticalRateBarometric = true
+		msg.VerticalRateBarometric = *m.VerticalVelocityBarometric
+	}
+	if m.VerticalVelocityGeometric != nil {
+		msg.HaveVerticalRateGeometric = true
+		msg.VerticalRateGeometric = *m.VerticalVelocityGeometric
+	}
+	if m.EmitterCategory != "" {
+		msg.HaveCategory = true
+		msg.Category = m.EmitterCategory
+	}
+	if m.Emergency != "" {
+		msg.HaveEmergency = true
+		msg.Emergency = m.Emergency
+	}
+	if m.NacP != nil {
+		msg.HaveNACP = true
+		msg.NACP = *m.NacP
+	}
+	if m.NacV != nil {
+		msg.HaveNACV = true
+		msg.NACV = *m.NacV
+	}
+	if m.NicBaro != nil {
+		msg.HaveNICBaro = true
+		msg.NICBaro = *m.NicBaro
+	}
+	if m.Sil != nil {
+		msg.HaveSIL = true
+		msg.SIL = *m.Sil
+		switch m.SilSupplement {
+		case "per_hour":
+			msg.SILType = uint32(readsb.SILPerHour)
+		case "per_sample":
+			msg.SILType = uint32(readsb.SILPerSample)
+		default:
+			msg.SILType = uint32(readsb.SILUnknown)
+		}
+	}
+	if m.SelectedHeading != nil {
+		msg.HaveNavHeading = true
+		msg.NavHeading = *m.SelectedHeading
+	}
+	if m.BarometricPressureSetting != nil {
+		msg.HaveNavQNH = true
+		msg.NavQNH = *m.BarometricPressureSetting
+	}
+	if m.Metadata != nil && m.Metadata.Rssi != nil {
+		msg.Signal = &pb.Signal{Rssi: *m.Metadata.Rssi}
+	}
+	return msg, nil
+}
+
+// ParseUatRawMessage parses a raw frame written by dump978, and decodes it
+// into a pb.Message without a Source. Downlink frames begin with '-', and
+// are followed by optional fields: -<hex>;rs=1;rssi=-20.1;
+// Uplink frames (beginning with '+') carry weather and other FIS-B data,
+// and return a nil message, as do messages without an ICAO address.
+func ParseUatRawMessage(line string) (*pb.Message, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || line[0] == '+' {
+		return nil, nil
+	} else if line[0] != '-' {
+		return nil, errors.Errorf("invalid uat frame: %s", line)
+	}
+	fields := strings.Split(strings.TrimSuffix(line[1:], ";"), ";")
+	frame, err := hex.DecodeString(fields[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding uat frame")
+	}
+	msg, err := decodeUatFrame(frame)
+	if err != nil || msg == nil {
+		return nil, err
+	}
+	for _, field := range fields[1:] {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 || kv[0] != "rssi" {
+			continue
+		}
+		rssi, err := strconv.ParseFloat(kv[1], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing rssi")
+		}
+		msg.Signal = &pb.Signal{Rssi: rssi}
+	}
+	return msg, nil
+}
+
+// decodeUatFrame decodes a UAT ADS-B frame. All payload types
+// begin with the header and state vector. Types 1 and 3 carry
+// the mode status (callsign, category and accuracy), and types
+// 1, 2, 5 and 6 carry the secondary altitude.
+func decodeUatFrame(frame []byte) (*pb.Message, error) {
+	if len(frame) != uatBasicFrameLength && len(frame) != uatLongFrameLength {
+		return nil, errors.Errorf("invalid uat frame length %d", len(frame))
+	}
+	payloadType := frame[0] >> 3
+	if (payloadType == 0) != (len(frame) == uatBasicFrameLength) {
+		return nil, errors.Errorf("invalid length %d for uat payload type %d", len(frame), payloadType)
+	}
+	msg := &pb.Message{Raw: frame}
+	switch frame[0] & 0x07 {
+	case uatQualifierAdsbIcao:
+		msg.Provenance = pb.Provenance_ADSB
+	case uatQualifierTisbIcao:
+		msg.Provenance = pb.Provenance_TISB
+	default:
+		return nil, nil
+	}
+	msg.Icao = strings.ToUpper(hex.EncodeToString(frame[1:4]))
+
+	geometric := decodeUatStateVector(frame, msg)
+	switch payloadType {
+	case 1, 3:
+		decodeUatModeStatus(frame, msg)
+	}
+	switch payloadType {
+	case 1, 2, 5, 6:
+		// the secondary altitude is the other type of altitude
+		if alt, ok := decodeUatAltitude(uint32(frame[29])<<4 | uint32(frame[30])>>4); ok {
+			if geometric {
+				msg.AltitudeBarometric = strconv.FormatInt(alt, 10)
+			} else {
+				msg.AltitudeGeometric = strconv.FormatInt(alt, 10)
+			}
+		}
+	}
+	return msg, nil
+}
+
+// decodeUatAltitude decodes a 12 bit altitude in 25ft increments.
+// False is returned if the altitude is unavailable.
+func decodeUatAltitude(raw uint32) (int64, bool) {
+	if raw == 0 {
+		return 0, false
+	}
+	return int64(raw-1)*25 - 1000, true
+}
+
+// decodeUatStateVector decodes the position, altitude and velocity
+// into msg. It returns true if the primary altitude is geometric.
+func decodeUatStateVector(frame []byte, msg *pb.Message) bool {
+	rawLat := uint32(frame[4])<<15 | uint32(frame[5])<<7 | uint32(frame[6])>>1
+	rawLon := uint32(frame[6]&0x01)<<23 | uint32(frame[7])<<15 | uint32(frame[8])<<7 | uint32(frame[9])>>1
+	nic := frame[11] & 0x0f
+	if rawLat != 0 || rawLon != 0 || nic != 0 {
+		lat := float64(rawLat) * 360.0 / 16777216.0
+		if lat > 90 {
+			lat -= 180
+		}
+		lon := float64(rawLon) * 360.0 / 16777216.0
+		if lon > 180 {
+			lon -= 360
+		}
+		msg.Latitude = strconv.FormatFloat(lat, 'f', 8, 64)
+		msg.Longitude = strconv.FormatFloat(lon, 'f', 8, 64)
+	}
+
+	geometric := frame[9]&0x01 == 1
+	if alt, ok := decodeUatAltitude(uint32(frame[10])<<4 | uint32(frame[11])>>4); ok {
+		if geometric {
+			msg.AltitudeGeometric = strconv.FormatInt(alt, 10)
+		} else {
+			msg.AltitudeBarometric = strconv.FormatInt(alt, 10)
+		}
+	}
+
+	airGround := frame[12] >> 6
+	rawNS := uint32(frame[12]&0x1f)<<6 | uint32(frame[13])>>2
+	rawEW := uint32(frame[13]&0x03)<<9 | uint32(frame[14])<<1 | uint32(frame[15])>>7
+	switch airGround {
+	case uatAirborneSubsonic, uatAirborneSupersonic:
+		ns, nsOk := decodeUatVelocity(rawNS, airGround == uatAirborneSupersonic)
+		ew, ewOk := decodeUatVelocity(rawEW, airGround == uatAirborneSupersonic)
+		if nsOk && ewOk {
+			msg.GroundSpeed = strconv.FormatFloat(math.Sqrt(ns*ns+ew*ew), 'f', 1, 64)
+			if ns != 0 || ew != 0 {
+				track := math.Mod(360+math.Atan2(ew, ns)*180/math.Pi, 360)
+				msg.Track = strconv.FormatFloat(track, 'f', 6, 64)
+			}
+		}
+		rawVV := uint32(frame[15]&0x7f)<<4 | uint32(frame[16])>>4
+		if rawVV&0x1ff != 0 {
+			rate := int64(rawVV&0x1ff-1) * 64
+			if rawVV&0x200 != 0 {
+				rate = -rate
+			}
+			if rawVV&0x400 != 0 {
+				msg.HaveVerticalRateBarometric = true
+				msg.VerticalRateBarometric = rate
+			} else {
+				msg.HaveVerticalRateGeometric = true
+				msg.VerticalRateGeometric = rate
+			}
+		}
+	case uatOnGround:
+		msg.IsOnGround = true
+		if rawNS&0x3ff != 0 {
+			msg.GroundSpeed = strconv.FormatFloat(float64(rawNS&0x3ff-1), 'f', 1, 64)
+		}
+		// the type of angle: 1 is the track, 2 the magnetic
+		// heading, and 3 the true heading
+		angle := float64(rawEW&0x1ff) * 360 / 512
+		switch (rawEW & 0x600) >> 9 {
+		case 1:
+			msg.Track = strconv.FormatFloat(angle, 'f', 6, 64)
+		case 2:
+			msg.HaveMagneticHeading = true
+			msg.MagneticHeading = angle
+		case 3:
+			msg.HaveTrueHeading = true
+			msg.TrueHeading = angle
+		}
+	}
+	return geometric
+}
+
+// decodeUatVelocity decodes an 11 bit north/south or east/west
+// velocity in knots. The top bit is set for south or west. False
+// is returned if the velocity is unavailable.
+func decodeUatVelocity(raw uint32, supersonic bool) (float64, bool) {
+	if raw&0x3ff == 0 {
+		return 0, false
+	}
+	v := float64(raw&0x3ff - 1)
+	if raw&0x400 != 0 {
+		v = -v
+	}
+	if supersonic {
+		v *= 4
+	}
+	return v, true
+}
+
+// decodeUatModeStatus decodes the callsign or squawk, emitter
+// category, emergency status and accuracy into msg.
+func decodeUatModeStatus(frame []byte, msg *pb.Message) {
+	v1 := uint32(frame[17])<<8 | uint32(frame[18])
+	v2 := uint32(frame[19])<<8 | uint32(frame[20])
+	v3 := uint32(frame[21])<<8 | uint32(frame[22])
+
+	category := (v1 / 1600) % 40
+	if category < 32 {
+		msg.HaveCategory = true
+		msg.Category = string(rune('A'+category/8)) + strconv.Itoa(int(category%8))
+	}
+	id := []byte{
+		uatBase40[(v1/40)%40], uatBase40[v1%40],
+		uatBase40[(v2/1600)%40], uatBase40[(v2/40)%40], uatBase40[v2%40],
+		uatBase40[(v3/1600)%40], uatBase40[(v3/40)%40], uatBase40[v3%40],
+	}
+	if ident := strings.TrimSpace(string(id)); ident != "" {
+		// the CSID bit indicates whether it's a callsign or a squawk
+		if frame[26]&0x02 != 0 {
+			msg.CallSign = ident
+		} else {
+			msg.Squawk = ident
+		}
+	}
+
+	msg.HaveEmergency = true
+	msg.Emergency = uatEmergencies[frame[23]>>5]
+	msg.HaveSIL = true
+	msg.SIL = uint32(frame[23] & 0x03)
+	msg.SILType = uint32(readsb.SILUnknown)
+	msg.HaveNACP = true
+	msg.NACP = uint32(frame[25] >> 4)
+	msg.HaveNACV = true
+	msg.NACV = uint32(frame[25]>>1) & 0x07
+	msg.HaveNICBaro = true
+	msg.NICBaro = uint32(frame[25] & 0x01)
+}
@@ -0,0 +1,220 @@
+package tracker
+
+import (
+	"github.com/afk11/airtrack/pkg/pb"
+	"github.com/afk11/airtrack/pkg/readsb"
+	assert "github.com/stretchr/testify/require"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captured from a dump978-fa json port
+var uatJSONTestLines = []string{
+	`{"address":"a1b2c3","address_qualifier":"adsb_icao","airground_state":"airborne","barometric_pressure_setting":1013.6,"callsign":"N123AB","capability_codes":{"es_in":false,"tcas_operational":false,"uat_in":true},"east_velocity":110,"emergency":"none","emitter_category":"A1","geometric_altitude":4575,"ground_speed":111,"gva":2,"metadata":{"errors":0,"received_at":1603000000.123,"rssi":-20.1},"nac_p":9,"nac_v":1,"nic":8,"nic_baro":1,"north_velocity":-12,"position":{"lat":37.61918,"lon":-122.37472},"pressure_altitude":4400,"sda":2,"selected_heading":95.0,"sil":3,"sil_supplement":"per_hour","true_track":96.2,"uat_version":2,"vertical_velocity_barometric":-640}`,
+	`{"address":"a1b2c4","address_qualifier":"tisb_icao","airground_state":"ground","flightplan_id":"1200","metadata":{"errors":0,"received_at":1603000000.523},"position":{"lat":37.6,"lon":-122.38},"tisb_site_id":5}`,
+	`{"address":"123456","address_qualifier":"adsb_other","airground_state":"airborne","metadata":{"errors":0,"received_at":1603000001.012,"rssi":-30.5},"pressure_altitude":1000}`,
+}
+
+// captured from a dump978-fa raw port
+var uatRawTestLines = []string{
+	"-08a1b2c33580bb51f4ba0d98103437e0b009d90d024a840b00930200000e00000000;rs=2;rssi=-20.1;",
+	"-02a1b2c43579bf51f2ce0007000000000000;rs=0;",
+	"-011234563579bf51f2ce0517002c05800000;",
+	"-00a1b2c5357f3551ef2a0299803540000000;rssi=-25.0;",
+	// uplink frames are 432 bytes
+	"+3514c4d3a5b8c6be2f40e0" + strings.Repeat("0", 842) + ";rs=0;",
+}
+
+func TestParseUatJSONMessage(t *testing.T) {
+	msg, err := ParseUatJSONMessage(uatJSONTestLines[0])
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, "A1B2C3", msg.Icao)
+	assert.Equal(t, pb.Provenance_ADSB, msg.Provenance)
+	assert.Equal(t, "N123AB", msg.CallSign)
+	assert.False(t, msg.IsOnGround)
+	assert.Equal(t, "37.61918000", msg.Latitude)
+	assert.Equal(t, "-122.37472000", msg.Longitude)
+	assert.Equal(t, "4400", msg.AltitudeBarometric)
+	assert.Equal(t, "4575", msg.AltitudeGeometric)
+	assert.Equal(t, "111.0", msg.GroundSpeed)
+	assert.Equal(t, "96.200000", msg.Track)
+	assert.True(t, msg.HaveVerticalRateBarometric)
+	assert.Equal(t, int64(-640), msg.VerticalRateBarometric)
+	assert.True(t, msg.HaveCategory)
+	assert.Equal(t, "A1", msg.Category)
+	assert.Equal(t, "none", msg.Emergency)
+	assert.Equal(t, uint32(9), msg.NACP)
+	assert.True(t, msg.HaveSIL)
+	assert.Equal(t, uint32(readsb.SILPerHour), msg.SILType)
+	assert.Equal(t, 95.0, msg.NavHeading)
+	assert.Equal(t, 1013.6, msg.NavQNH)
+	assert.Equal(t, -20.1, msg.Signal.Rssi)
+
+	msg, err = ParseUatJSONMessage(uatJSONTestLines[1])
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, "A1B2C4", msg.Icao)
+	assert.Equal(t, pb.Provenance_TISB, msg.Provenance)
+	assert.True(t, msg.IsOnGround)
+	assert.Equal(t, "1200", msg.Squawk)
+	assert.Equal(t, "", msg.AltitudeBarometric)
+	assert.False(t, msg.HaveCategory)
+	assert.Nil(t, msg.Signal)
+
+	t.Run("ignored", func(t *testing.T) {
+		for _, line := range []string{uatJSONTestLines[2], ""} {
+			msg, err := ParseUatJSONMessage(line)
+			assert.NoError(t, err)
+			assert.Nil(t, msg)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, line := range []string{
+			`{"address":`,
+			`{"address":"xyz123","address_qualifier":"adsb_icao"}`,
+			`{"address":"a1b2","address_qualifier":"adsb_icao"}`,
+		} {
+			msg, err := ParseUatJSONMessage(line)
+			assert.Error(t, err, line)
+			assert.Nil(t, msg)
+		}
+	})
+}
+
+func TestParseUatRawMessage(t *testing.T) {
+	msg, err := ParseUatRawMessage(uatRawTestLines[0])
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, "A1B2C3", msg.Icao)
+	assert.Equal(t, pb.Provenance_ADSB, msg.Provenance)
+	assert.Equal(t, "N123AB", msg.CallSign)
+	assert.Equal(t, "", msg.Squawk)
+	assert.False(t, msg.IsOnGround)
+	lat, err := strconv.ParseFloat(msg.Latitude, 64)
+	assert.NoError(t, err)
+	assert.InDelta(t, 37.61918, lat, 0.0001)
+	lon, err := strconv.ParseFloat(msg.Longitude, 64)
+	assert.NoError(t, err)
+	assert.InDelta(t, -122.37472, lon, 0.0001)
+	assert.Equal(t, "4400", msg.AltitudeBarometric)
+	assert.Equal(t, "4575", msg.AltitudeGeometric)
+	assert.Equal(t, "110.7", msg.GroundSpeed)
+	assert.Equal(t, "96.225829", msg.Track)
+	assert.True(t, msg.HaveVerticalRateBarometric)
+	assert.Equal(t, int64(-640), msg.VerticalRateBarometric)
+	assert.False(t, msg.HaveVerticalRateGeometric)
+	assert.True(t, msg.HaveCategory)
+	assert.Equal(t, "A1", msg.Category)
+	assert.True(t, msg.HaveEmergency)
+	assert.Equal(t, "none", msg.Emergency)
+	assert.Equal(t, uint32(3), msg.SIL)
+	assert.Equal(t, uint32(9), msg.NACP)
+	assert.Equal(t, uint32(1), msg.NACV)
+	assert.Equal(t, uint32(1), msg.NICBaro)
+	assert.Equal(t, -20.1, msg.Signal.Rssi)
+
+	// basic frame: no mode status, and no altitude
+	msg, err = ParseUatRawMessage(uatRawTestLines[1])
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, "A1B2C4", msg.Icao)
+	assert.Equal(t, pb.Provenance_TISB, msg.Provenance)
+	assert.NotEqual(t, "", msg.Latitude)
+	assert.Equal(t, "", msg.AltitudeBarometric)
+	assert.Equal(t, "", msg.CallSign)
+	assert.False(t, msg.HaveCategory)
+	assert.Equal(t, "", msg.GroundSpeed)
+	assert.Nil(t, msg.Signal)
+
+	// on ground, with the track
+	msg, err = ParseUatRawMessage(uatRawTestLines[3])
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, "A1B2C5", msg.Icao)
+	assert.True(t, msg.IsOnGround)
+	assert.Equal(t, "0", msg.AltitudeBarometric)
+	assert.Equal(t, "12.0", msg.GroundSpeed)
+	assert.Equal(t, "90.000000", msg.Track)
+	assert.Equal(t, -25.0, msg.Signal.Rssi)
+
+	t.Run("ignored", func(t *testing.T) {
+		// non-ICAO address, uplink frame, and empty line
+		for _, line := range []string{uatRawTestLines[2], uatRawTestLines[4], ""} {
+			msg, err := ParseUatRawMessage(line)
+			assert.NoError(t, err)
+			assert.Nil(t, msg)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, line := range []string{
+			"*8D4840D6202CC371C32CE0576098;",
+			"-zz;",
+			"-08a1b2c33580bb51f4ba0d98103437e0b009;",
+			"-00a1b2c5357f3551ef2a0299803540000000aa;",
+			"-08a1b2c33580bb51f4ba0d98103437e0b009d90d024a840b00930200000e00000000;rssi=loud;",
+		} {
+			msg, err := ParseUatRawMessage(line)
+			assert.Error(t, err, line)
+			assert.Nil(t, msg)
+		}
+	})
+}
+
+func TestUatProducer(t *testing.T) {
+	_, err := NewUatProducer(make(chan *pb.Message), "127.0.0.1", 30978, "standin", "beast")
+	assert.Error(t, err)
+
+	for _, tc := range []struct {
+		format string
+		lines  []string
+		icaos  []string
+	}{
+		{UatFormatJSON, uatJSONTestLines, []string{"A1B2C3", "A1B2C4"}},
+		{UatFormatRaw, uatRawTestLines, []string{"A1B2C3", "A1B2C4", "A1B2C5"}},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			assert.NoError(t, err)
+			defer l.Close()
+
+			// replays the captured lines, then drops the connection
+			// so the producer has to reconnect
+			go func() {
+				for {
+					conn, err := l.Accept()
+					if err != nil {
+						return
+					}
+					for _, line := range tc.lines {
+						_, _ = conn.Write([]byte(line + "\n"))
+					}
+					_ = conn.Close()
+				}
+			}()
+
+			msgs := make(chan *pb.Message)
+			addr := l.Addr().(*net.TCPAddr)
+			p, err := NewUatProducer(msgs, "127.0.0.1", uint16(addr.Port), "standin", tc.format)
+			assert.NoError(t, err)
+			p.reader.minReconnectDelay = time.Millisecond
+			p.Start()
+			defer p.Stop()
+
+			// messages are received again after reconnecting
+			for i := 0; i < len(tc.icaos)*2; i++ {
+				select {
+				case msg := <-msgs:
+					assert.Equal(t, tc.icaos[i%len(tc.icaos)], msg.Icao)
+					assert.Equal(t, pb.Source_UatServer, msg.Source.Type)
+					assert.Equal(t, "standin", msg.Source.Name)
+				case <-time.After(time.Second * 5):
+					t.Fatalf("timeout waiting for message %d", i)
+				}
+			}
+		})
+	}
+}
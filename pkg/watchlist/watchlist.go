@@ -0,0 +1,291 @@
+// Package watchlist loads named lists of interesting aircraft, identified
+// by their ICAO hex or registration, from CSV and JSON files.
+package watchlist
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultReloadInterval - how often watchlist
+	// files are checked for changes
+	DefaultReloadInterval = time.Second * 30
+)
+
+type (
+	// Entry is an aircraft on a watchlist. Either Icao or
+	// Registration must be set.
+	Entry struct {
+		Icao         string `json:"icao"`
+		Registration string `json:"registration"`
+		Label        string `json:"label"`
+	}
+	// list is a named watchlist loaded from a file. entries
+	// maps upper case ICAO hexes and registrations to their
+	// label, and is replaced rather than modified on reload.
+	list struct {
+		name    string
+		file    string
+		label   string
+		modTime time.Time
+		entries map[string]string
+	}
+	// Watchlists contains the configured watchlists, in the order
+	// they were added, and reloads them when their files change.
+	Watchlists struct {
+		lists     []*list
+		byName    map[string]*list
+		mu        sync.RWMutex
+		canceller func()
+		wg        sync.WaitGroup
+	}
+)
+
+// ReadCSV reads entries from CSV. The first row is a header naming the
+// icao, registration and label columns. Only one of icao or registration
+// is required, and columns with other names are ignored.
+func ReadCSV(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "reading CSV header")
+	}
+	icaoCol, regCol, labelCol := -1, -1, -1
+	for i, h := range header {
+		switch strings.ToLower(strings.TrimSpace(h)) {
+		case "icao":
+			icaoCol = i
+		case "registration":
+			regCol = i
+		case "label":
+			labelCol = i
+		}
+	}
+	if icaoCol == -1 && regCol == -1 {
+		return nil, errors.New("CSV header must have an icao or registration column")
+	}
+	column := func(record []string, col int) string {
+		if col == -1 || col >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[col])
+	}
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "reading CSV")
+		}
+		entries = append(entries, Entry{
+			Icao:         column(record, icaoCol),
+			Registration: column(record, regCol),
+			Label:        column(record, labelCol),
+		})
+	}
+	return entries, nil
+}
+
+// ReadJSON reads entries from a JSON array of objects
+// with icao, registration and label keys.
+func ReadJSON(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	err := json.NewDecoder(r).Decode(&entries)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding JSON")
+	}
+	return entries, nil
+}
+
+// ReadFile reads entries from a CSV (.csv) or JSON (.json) file
+func ReadFile(file string) ([]Entry, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening watchlist file")
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".csv":
+		return ReadCSV(f)
+	case ".json":
+		return ReadJSON(f)
+	default:
+		return nil, errors.Errorf("unsupported watchlist file extension: %s", filepath.Ext(file))
+	}
+}
+
+// NewWatchlists creates an empty Watchlists
+func NewWatchlists() *Watchlists {
+	return &Watchlists{
+		byName: make(map[string]*list),
+	}
+}
+
+// Add loads the watchlist named name from file. Entries without
+// a label are given label, or name if label is empty.
+func (w *Watchlists) Add(name, file, label string) error {
+	if name == "" {
+		return errors.New("watchlist missing name")
+	} else if file == "" {
+		return errors.Errorf("watchlist %s missing file", name)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.byName[name]; ok {
+		return errors.Errorf("duplicate watchlist name: %s", name)
+	}
+	if label == "" {
+		label = name
+	}
+	l := &list{name: name, file: file, label: label}
+	err := l.load()
+	if err != nil {
+		return errors.Wrapf(err, "loading watchlist %s", name)
+	}
+	w.lists = append(w.lists, l)
+	w.byName[name] = l
+	return nil
+}
+
+// load reads the list's file and replaces its entries
+func (l *list) load() error {
+	info, err := os.Stat(l.file)
+	if err != nil {
+		return errors.Wrapf(err, "checking watchlist file")
+	}
+	entries, err := ReadFile(l.file)
+	if err != nil {
+		return err
+	}
+	m := make(map[string]string, len(entries))
+	for i, e := range entries {
+		if e.Icao == "" && e.Registration == "" {
+			return errors.Errorf("entry %d missing icao or registration", i)
+		}
+		label := e.Label
+		if label == "" {
+			label = l.label
+		}
+		if e.Icao != "" {
+			m[strings.ToUpper(e.Icao)] = label
+		}
+		if e.Registration != "" {
+			m[strings.ToUpper(e.Registration)] = label
+		}
+	}
+	l.modTime = info.ModTime()
+	l.entries = m
+	return nil
+}
+
+// Len returns the number of watchlists
+func (w *Watchlists) Len() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.lists)
+}
+
+// Get returns the entries of the named watchlist, mapping upper case
+// ICAO hexes and registrations to their label. The map must not be
+// modified. An empty map is returned if the watchlist doesn't exist.
+func (w *Watchlists) Get(name string) map[string]string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	l, ok := w.byName[name]
+	if !ok {
+		return map[string]string{}
+	}
+	return l.entries
+}
+
+// Label searches the watchlists in order for any of the ICAO hexes
+// or registrations in keys, and returns the label of the first match.
+func (w *Watchlists) Label(keys ...string) (string, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for _, l := range w.lists {
+		for _, key := range keys {
+			if key == "" {
+				continue
+			}
+			if label, ok := l.entries[strings.ToUpper(key)]; ok {
+				return label, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Reload reloads the watchlists whose files were modified since they
+// were last loaded. If a file can't be loaded, the previous entries
+// are kept and an error is returned after trying the remaining files.
+func (w *Watchlists) Reload() error {
+	w.mu.RLock()
+	var changed []*list
+	for _, l := range w.lists {
+		info, err := os.Stat(l.file)
+		if err == nil && !info.ModTime().Equal(l.modTime) {
+			changed = append(changed, l)
+		}
+	}
+	w.mu.RUnlock()
+
+	var lastErr error
+	for _, l := range changed {
+		reloaded := &list{name: l.name, file: l.file, label: l.label}
+		err := reloaded.load()
+		if err != nil {
+			lastErr = errors.Wrapf(err, "reloading watchlist %s", l.name)
+			continue
+		}
+		w.mu.Lock()
+		l.modTime = reloaded.modTime
+		l.entries = reloaded.entries
+		w.mu.Unlock()
+		log.Infof("reloaded watchlist %s with %d entries", l.name, len(reloaded.entries))
+	}
+	return lastErr
+}
+
+// Start invokes a goroutine which calls Reload every interval
+func (w *Watchlists) Start(interval time.Duration) {
+	ctx, canceller := context.WithCancel(context.Background())
+	w.canceller = canceller
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		for {
+			select {
+			case <-time.After(interval):
+				err := w.Reload()
+				if err != nil {
+					log.Warnf("watchlist: %s", err.Error())
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the reload goroutine
+func (w *Watchlists) Stop() {
+	w.canceller()
+	w.wg.Wait()
+}
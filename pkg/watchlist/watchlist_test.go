@@ -0,0 +1,105 @@
+package watchlist
+
+import (
+	assert "github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile writes contents to name in dir, and returns the path
+func writeFile(t *testing.T, dir, name, contents string) string {
+	file := filepath.Join(dir, name)
+	assert.NoError(t, ioutil.WriteFile(file, []byte(contents), 0644))
+	return file
+}
+
+func TestReadCSV(t *testing.T) {
+	t.Run("columns", func(t *testing.T) {
+		entries, err := ReadCSV(strings.NewReader("registration, icao, notes, label\nEI-ABC, 4ca123, ignored, test airframe\n, ae1234\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, []Entry{
+			{Icao: "4ca123", Registration: "EI-ABC", Label: "test airframe"},
+			{Icao: "ae1234"},
+		}, entries)
+	})
+	t.Run("empty", func(t *testing.T) {
+		entries, err := ReadCSV(strings.NewReader(""))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(entries))
+	})
+	t.Run("missing columns", func(t *testing.T) {
+		_, err := ReadCSV(strings.NewReader("label\ngovernment\n"))
+		assert.EqualError(t, err, "CSV header must have an icao or registration column")
+	})
+}
+
+func TestReadJSON(t *testing.T) {
+	entries, err := ReadJSON(strings.NewReader(`[{"icao": "4CA123", "label": "government"}, {"registration": "N12345"}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Icao: "4CA123", Label: "government"},
+		{Registration: "N12345"},
+	}, entries)
+
+	_, err = ReadJSON(strings.NewReader(`{}`))
+	assert.Error(t, err)
+}
+
+func TestWatchlists(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watchlist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	gov := writeFile(t, dir, "gov.csv", "icao,label\n4ca123,\nae1234,military\n")
+	medical := writeFile(t, dir, "medical.json", `[{"icao": "4CA123"}, {"registration": "ei-med"}]`)
+
+	w := NewWatchlists()
+	assert.NoError(t, w.Add("gov", gov, "government"))
+	assert.NoError(t, w.Add("medical", medical, "air ambulance"))
+	assert.Equal(t, 2, w.Len())
+
+	assert.Equal(t, map[string]string{"4CA123": "government", "AE1234": "military"}, w.Get("gov"))
+	assert.Equal(t, 0, len(w.Get("unknown")))
+
+	// the first watchlist wins
+	label, ok := w.Label("4CA123")
+	assert.True(t, ok)
+	assert.Equal(t, "government", label)
+	label, ok = w.Label("4CA999", "EI-MED")
+	assert.True(t, ok)
+	assert.Equal(t, "air ambulance", label)
+	_, ok = w.Label("4CA999", "")
+	assert.False(t, ok)
+
+	t.Run("errors", func(t *testing.T) {
+		assert.EqualError(t, w.Add("gov", gov, ""), "duplicate watchlist name: gov")
+		assert.EqualError(t, w.Add("", gov, ""), "watchlist missing name")
+		assert.EqualError(t, w.Add("nofile", "", ""), "watchlist nofile missing file")
+		txt := writeFile(t, dir, "list.txt", "4CA123")
+		assert.EqualError(t, w.Add("txt", txt, ""), "loading watchlist txt: unsupported watchlist file extension: .txt")
+		blank := writeFile(t, dir, "blank.json", `[{"label": "nothing"}]`)
+		assert.EqualError(t, w.Add("blank", blank, ""), "loading watchlist blank: entry 0 missing icao or registration")
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		assert.NoError(t, w.Reload())
+		assert.Equal(t, 2, len(w.Get("gov")))
+
+		writeFile(t, dir, "gov.csv", "icao,label\n4ca123,test airframe\n")
+		future := time.Now().Add(time.Minute)
+		assert.NoError(t, os.Chtimes(gov, future, future))
+		assert.NoError(t, w.Reload())
+		assert.Equal(t, map[string]string{"4CA123": "test airframe"}, w.Get("gov"))
+
+		// bad files keep the previous entries
+		writeFile(t, dir, "medical.json", `[`)
+		future = future.Add(time.Minute)
+		assert.NoError(t, os.Chtimes(medical, future, future))
+		assert.Error(t, w.Reload())
+		assert.Equal(t, 2, len(w.Get("medical")))
+	})
+}
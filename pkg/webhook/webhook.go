@@ -0,0 +1,362 @@
+package webhook
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/afk11/airtrack/pkg/db"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// EventHeader - HTTP header containing the event name
+	EventHeader = "X-Airtrack-Event"
+	// SignatureHeader - HTTP header containing the HMAC-SHA256
+	// signature of the request body, if the webhook has a secret
+	SignatureHeader = "X-Airtrack-Signature"
+	// SignaturePrefix - prefix for the hex encoded signature
+	SignaturePrefix = "sha256="
+
+	// DefaultMaxRetries - number of retries before a job is marked failed
+	DefaultMaxRetries = 4
+	// DefaultRetryInterval - delay before the first retry. Doubles with
+	// each subsequent retry.
+	DefaultRetryInterval = time.Minute
+	// DefaultTimeout - timeout for each HTTP request
+	DefaultTimeout = time.Second * 10
+)
+
+type (
+	// Location - a structure containing an aircraft's location
+	Location struct {
+		// Latitude - decimal latitude
+		Latitude float64 `json:"latitude"`
+		// Longitude - decimal longitude
+		Longitude float64 `json:"longitude"`
+		// Altitude - height in ft
+		Altitude int64 `json:"altitude"`
+	}
+	// Event is the JSON payload POSTed to a webhook. Fields which
+	// are not relevant to a particular event are omitted.
+	Event struct {
+		Event         string     `json:"event"`
+		Project       string     `json:"project"`
+		Icao          string     `json:"icao"`
+		CallSign      string     `json:"callsign,omitempty"`
+		Watchlist     string     `json:"watchlist,omitempty"`
+		Time          time.Time  `json:"time"`
+		AirportName   string     `json:"airport_name,omitempty"`
+		StartTime     *time.Time `json:"start_time,omitempty"`
+		EndTime       *time.Time `json:"end_time,omitempty"`
+		Duration      string     `json:"duration,omitempty"`
+		StartLocation *Location  `json:"start_location,omitempty"`
+		EndLocation   *Location  `json:"end_location,omitempty"`
+		MapUpdated    bool       `json:"map_updated,omitempty"`
+		Operator      string     `json:"operator,omitempty"`
+		Squawk        string     `json:"squawk,omitempty"`
+		Emergency     string     `json:"emergency,omitempty"`
+		Description   string     `json:"description,omitempty"`
+		Location      *Location  `json:"location,omitempty"`
+		Zone          string     `json:"zone,omitempty"`
+		Radius        float64    `json:"radius,omitempty"`
+		Condition     string     `json:"condition,omitempty"`
+	}
+	// Job - the JSON structure for db.Webhook Job field. The body
+	// is signed when the job is prepared so the secret is never persisted.
+	Job struct {
+		URL       string          `json:"url"`
+		Event     string          `json:"event"`
+		Signature string          `json:"signature,omitempty"`
+		Body      json.RawMessage `json:"body"`
+	}
+	// Sender - public interface for queuing webhooks to be sent.
+	Sender interface {
+		Queue(job Job) error
+	}
+	// Dispatcher manages background services for delivering webhooks.
+	// New jobs are queued in queued until the processing coroutine
+	// saves them to the database in a batch. The processing routine
+	// also searches for pending jobs to deliver.
+	// Implements Sender
+	Dispatcher struct {
+		database      db.Database
+		client        *http.Client
+		maxRetries    int32
+		retryInterval time.Duration
+		queued        []Job
+		canceller     func()
+		mu            sync.RWMutex
+		wg            sync.WaitGroup
+	}
+)
+
+// PrepareJob encodes event and produces a Job for url. If secret is
+// not empty the body is signed using HMAC-SHA256.
+func PrepareJob(url string, secret string, event Event) (*Job, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, errors.Wrapf(err, "encoding %s event", event.Event)
+	}
+	job := &Job{
+		URL:   url,
+		Event: event.Event,
+		Body:  body,
+	}
+	if secret != "" {
+		job.Signature = Sign(secret, body)
+	}
+	return job, nil
+}
+
+// Sign returns the value of the SignatureHeader for body
+// using secret as the HMAC key.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewDispatcher creates a new Dispatcher
+func NewDispatcher(database db.Database, client *http.Client) *Dispatcher {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &Dispatcher{
+		database:      database,
+		client:        client,
+		maxRetries:    DefaultMaxRetries,
+		retryInterval: DefaultRetryInterval,
+		queued:        make([]Job, 0),
+	}
+}
+
+// Queue adds job to the queue so it can be persisted later.
+// See Sender.Queue
+func (d *Dispatcher) Queue(job Job) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queued = append(d.queued, job)
+	return nil
+}
+
+// Start invokes the processing goroutine
+func (d *Dispatcher) Start() {
+	ctx, canceller := context.WithCancel(context.Background())
+	d.canceller = canceller
+	d.wg.Add(1)
+	go d.periodicallyProcessWebhooks(ctx)
+}
+
+// periodicallyProcessWebhooks runs in a loop until the shutdown
+// signal is received. In each iteration it calls processWebhooks.
+// If errors arise, an extra delay is used.
+func (d *Dispatcher) periodicallyProcessWebhooks(ctx context.Context) {
+	defer d.wg.Done()
+	normalDelay := time.Second * 5
+	delay := normalDelay
+	for {
+		select {
+		case <-time.After(delay):
+			err := d.processWebhooks(ctx)
+			if err != nil {
+				log.Warnf("webhook: %s", err.Error())
+				delay = time.Minute
+			} else {
+				delay = normalDelay
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// processWebhooks is called periodically to persist new jobs
+// and to deliver jobs which are pending.
+func (d *Dispatcher) processWebhooks(ctx context.Context) error {
+	d.mu.Lock()
+	queued := d.queued
+	d.queued = nil
+	d.mu.Unlock()
+
+	if len(queued) > 0 {
+		err := d.addWebhooksToDb(time.Now(), queued)
+		if err != nil {
+			return errors.Wrapf(err, "add queued webhooks to database")
+		}
+	}
+
+	records, err := d.database.GetPendingWebhookJobs(time.Now())
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return err
+	} else if len(records) == 0 {
+		return nil
+	}
+	log.Debugf("webhook: processing %d jobs", len(records))
+
+	failed := make([]db.Webhook, 0)
+	finished := make([]db.Webhook, 0)
+	for i := range records {
+		job, err := decodeJob(records[i].Job)
+		if err != nil {
+			return errors.Wrapf(err, "decoding job")
+		}
+		err = d.deliver(ctx, &job)
+		if err != nil {
+			log.Warnf("failed to deliver %s webhook to %s: %s", job.Event, job.URL, err.Error())
+			failed = append(failed, records[i])
+		} else {
+			finished = append(finished, records[i])
+		}
+	}
+
+	if len(finished) > 0 {
+		err = d.database.Transaction(func(tx *sqlx.Tx) error {
+			for i := range finished {
+				_, err := d.database.DeleteCompletedWebhookTx(tx, finished[i])
+				if err != nil {
+					return errors.Wrapf(err, "deleting completed webhook %d", finished[i].ID)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(failed) > 0 {
+		err = d.database.Transaction(func(tx *sqlx.Tx) error {
+			for i := range failed {
+				if failed[i].Retries >= d.maxRetries {
+					_, err := d.database.MarkWebhookFailedTx(tx, &failed[i])
+					if err != nil {
+						return errors.Wrapf(err, "marking webhook failed %d", failed[i].ID)
+					}
+				} else {
+					retryAfter := time.Now().Add(d.retryInterval << uint(failed[i].Retries))
+					_, err := d.database.RetryWebhookAfterTx(tx, &failed[i], retryAfter)
+					if err != nil {
+						return errors.Wrapf(err, "updating webhook retry information %d", failed[i].ID)
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver POSTs the job to its URL. An error is returned if
+// the request fails, or if a non-2xx status code is received.
+func (d *Dispatcher) deliver(ctx context.Context, job *Job) error {
+	req, err := http.NewRequest("POST", job.URL, bytes.NewReader(job.Body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, job.Event)
+	if job.Signature != "" {
+		req.Header.Set(SignatureHeader, job.Signature)
+	}
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+// addWebhooksToDb encodes and persist queued jobs.
+func (d *Dispatcher) addWebhooksToDb(now time.Time, queued []Job) error {
+	return d.database.Transaction(func(tx *sqlx.Tx) error {
+		for idx := range queued {
+			encoded, err := encodeJob(&queued[idx])
+			if err != nil {
+				return err
+			}
+			_, err = d.database.CreateWebhookJobTx(tx, now, encoded)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Stop cancels the processing goroutine and waits for it to finish.
+// Any queued jobs are persisted so they are delivered after a restart.
+func (d *Dispatcher) Stop() {
+	d.canceller()
+	d.wg.Wait()
+
+	d.mu.Lock()
+	queued := d.queued
+	d.queued = nil
+	d.mu.Unlock()
+	if len(queued) > 0 {
+		err := d.addWebhooksToDb(time.Now(), queued)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// encodeJob takes a job and encodes it into a compressed payload
+func encodeJob(job *Job) ([]byte, error) {
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	if _, err := w.Write(raw); err != nil {
+		return nil, err
+	} else if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return compressed.Bytes(), nil
+}
+
+// decodeJob takes a compressed job and decodes it into a Job.
+func decodeJob(compressed []byte) (Job, error) {
+	r, err := gzip.NewReader(bytes.NewBuffer(compressed))
+	if err != nil {
+		return Job{}, errors.Wrapf(err, "creating gzip reader for job")
+	}
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Job{}, errors.Wrapf(err, "decompressing job")
+	}
+
+	job := Job{}
+	err = json.Unmarshal(raw, &job)
+	if err != nil {
+		return Job{}, err
+	}
+	return job, nil
+}
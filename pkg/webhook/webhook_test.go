@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/afk11/airtrack/pkg/db"
+	"github.com/afk11/airtrack/pkg/test"
+	assert "github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPrepareJob(t *testing.T) {
+	event := Event{
+		Event:    "spotted_in_flight",
+		Project:  "unittest",
+		Icao:     "424242",
+		CallSign: "THIC4F",
+		Time:     time.Unix(1600000000, 0).UTC(),
+	}
+	job, err := PrepareJob("http://127.0.0.1/hook", "", event)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1/hook", job.URL)
+	assert.Equal(t, "spotted_in_flight", job.Event)
+	assert.Equal(t, "", job.Signature)
+	assert.Equal(t, `{"event":"spotted_in_flight","project":"unittest","icao":"424242","callsign":"THIC4F","time":"2020-09-13T12:26:40Z"}`, string(job.Body))
+
+	job, err = PrepareJob("http://127.0.0.1/hook", "secret", event)
+	assert.NoError(t, err)
+	assert.Equal(t, Sign("secret", job.Body), job.Signature)
+	assert.Equal(t, SignaturePrefix, job.Signature[:len(SignaturePrefix)])
+	assert.NotEqual(t, Sign("other", job.Body), job.Signature)
+}
+
+func TestEncodeJob(t *testing.T) {
+	job, err := PrepareJob("http://127.0.0.1/hook", "secret", Event{Event: "takeoff_complete", Icao: "424242"})
+	assert.NoError(t, err)
+	encoded, err := encodeJob(job)
+	assert.NoError(t, err)
+	decoded, err := decodeJob(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, *job, decoded)
+}
+
+func TestDispatcher(t *testing.T) {
+	dbConn, dialect, _, closer := test.InitDBUp()
+	defer closer()
+	database := db.NewDatabase(dbConn, dialect)
+
+	var status = http.StatusOK
+	var received []*http.Request
+	var bodies [][]byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		received = append(received, r)
+		bodies = append(bodies, body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	d := NewDispatcher(database, srv.Client())
+	job, err := PrepareJob(srv.URL, "secret", Event{Event: "spotted_in_flight", Icao: "424242"})
+	assert.NoError(t, err)
+	assert.NoError(t, d.Queue(*job))
+
+	t.Run("delivered", func(t *testing.T) {
+		assert.NoError(t, d.processWebhooks(context.Background()))
+		assert.Equal(t, 1, len(received))
+		assert.Equal(t, "POST", received[0].Method)
+		assert.Equal(t, "application/json", received[0].Header.Get("Content-Type"))
+		assert.Equal(t, "spotted_in_flight", received[0].Header.Get(EventHeader))
+		assert.Equal(t, Sign("secret", bodies[0]), received[0].Header.Get(SignatureHeader))
+
+		ev := Event{}
+		assert.NoError(t, json.Unmarshal(bodies[0], &ev))
+		assert.Equal(t, "424242", ev.Icao)
+
+		pending, err := database.GetPendingWebhookJobs(time.Now())
+		assert.NoError(t, err)
+		assert.Nil(t, pending)
+	})
+
+	t.Run("retried", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		d.retryInterval = 0
+		d.maxRetries = 1
+		assert.NoError(t, d.Queue(*job))
+		assert.NoError(t, d.processWebhooks(context.Background()))
+		assert.Equal(t, 2, len(received))
+
+		pending, err := database.GetPendingWebhookJobs(time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(pending))
+		assert.Equal(t, int32(1), pending[0].Retries)
+
+		// second failure exceeds maxRetries
+		assert.NoError(t, d.processWebhooks(context.Background()))
+		assert.Equal(t, 3, len(received))
+		pending, err = database.GetPendingWebhookJobs(time.Now())
+		assert.NoError(t, err)
+		assert.Nil(t, pending)
+	})
+}
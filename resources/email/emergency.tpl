@@ -0,0 +1,28 @@
+Project: {{.Project}}<br />
+
+{{.Icao }}
+{{if .CallSign}}
+ {{.CallSign}}
+{{end}}
+{{if .Watchlist}}
+ [{{.Watchlist}}]
+{{end}}
+{{if .Operator}}
+ ({{.Operator}})
+{{end}}
+is reporting an emergency: {{.Description}}
+<br />
+<br />
+<ul>
+    <li>Time: {{ .TimeFmt }}</li>
+{{if .Squawk}}
+    <li>Squawk: {{ .Squawk }}</li>
+{{end}}
+{{if .Emergency}}
+    <li>Emergency status: {{ .Emergency }}</li>
+{{end}}
+    <li>Place: <a href="https://www.openstreetmap.org/#map=13/{{ .Location.Latitude }}/{{ .Location.Longitude }}">{{ .Location.Latitude }}, {{ .Location.Longitude }}</a> @ {{ .Location.Altitude }} ft</li>
+</ul>
+{{if .HaveTrack}}
+The track so far is attached.
+{{end}}
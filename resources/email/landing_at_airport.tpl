@@ -0,0 +1,16 @@
+Project: {{.Project}}<br />
+
+{{.Icao }}
+{{if .CallSign}}
+ {{.CallSign}}
+{{end}}
+{{if .Watchlist}}
+ [{{.Watchlist}}]
+{{end}}
+has landed at {{.AirportName}}
+<br />
+<br />
+<ul>
+    <li>Time: {{ .LandingTimeFmt }}</li>
+    <li>Place: <a href="https://www.openstreetmap.org/#map=13/{{ .LandingLocation.Latitude }}/{{ .LandingLocation.Longitude }}">{{ .LandingLocation.Latitude }}, {{ .LandingLocation.Longitude }}</a></li>
+</ul>
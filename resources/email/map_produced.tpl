@@ -5,6 +5,10 @@ Map produced for {{.Icao }} {{.CallSign}}.
 {{else}}
 Map produced for {{.Icao }} flight.
 {{end}}
+{{if .Watchlist}}
+<br />
+Watchlist: {{.Watchlist}}
+{{end}}
 <br />
 Duration: {{.DurationFmt}}
 
@@ -0,0 +1,17 @@
+Project: {{.Project}}<br />
+
+{{.Icao }}
+{{if .CallSign}}
+ {{.CallSign}}
+{{end}}
+{{if .Watchlist}}
+ [{{.Watchlist}}]
+{{end}}
+is orbiting
+<br />
+<br />
+<ul>
+    <li>Time: {{ .TimeFmt }}</li>
+    <li>Centre: <a href="https://www.openstreetmap.org/#map=13/{{ .Centre.Latitude }}/{{ .Centre.Longitude }}">{{ .Centre.Latitude }}, {{ .Centre.Longitude }}</a> @ {{ .Centre.Altitude }} ft</li>
+    <li>Radius: {{ .Radius }} m</li>
+</ul>
@@ -0,0 +1,18 @@
+Project: {{.Project}}<br />
+
+{{.Icao }}
+{{if .CallSign}}
+ {{.CallSign}}
+{{end}}
+{{if .Watchlist}}
+ [{{.Watchlist}}]
+{{end}}
+is reporting implausible positions: {{.Description}}
+<br />
+<br />
+<ul>
+    <li>Time: {{ .TimeFmt }}</li>
+{{if .HaveLocation}}
+    <li>Last accepted position: <a href="https://www.openstreetmap.org/#map=13/{{ .Location.Latitude }}/{{ .Location.Longitude }}">{{ .Location.Latitude }}, {{ .Location.Longitude }}</a> @ {{ .Location.Altitude }} ft</li>
+{{end}}
+</ul>
@@ -0,0 +1,19 @@
+Project: {{.Project}}<br />
+
+{{.Icao }}
+{{if .CallSign}}
+ {{.CallSign}}
+{{end}}
+{{if .Watchlist}}
+ [{{.Watchlist}}]
+{{end}}
+triggered rule {{.Rule}}
+<br />
+<br />
+<ul>
+    <li>Time: {{ .TimeFmt }}</li>
+    <li>Condition: {{ .Condition }}</li>
+{{if .HaveLocation}}
+    <li>Place: <a href="https://www.openstreetmap.org/#map=13/{{ .Location.Latitude }}/{{ .Location.Longitude }}">{{ .Location.Latitude }}, {{ .Location.Longitude }}</a> @ {{ .Location.Altitude }} ft</li>
+{{end}}
+</ul>
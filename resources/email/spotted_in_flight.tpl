@@ -4,6 +4,9 @@ Project: {{.Project}}<br />
 {{if .CallSign}}
  {{.CallSign}}
 {{end}}
+{{if .Watchlist}}
+ [{{.Watchlist}}]
+{{end}}
 spotted in flight.
 <br />
 <br />
@@ -4,6 +4,9 @@ Project: {{.Project}}<br />
 {{if .CallSign}}
  {{.CallSign}}
 {{end}}
+{{if .Watchlist}}
+ [{{.Watchlist}}]
+{{end}}
 has completed takeoff {{if .HaveAirport}} from {{.AirportName}} {{end}}
 <br />
 <br />
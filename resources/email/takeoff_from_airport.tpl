@@ -4,6 +4,9 @@ Project: {{.Project}}<br />
 {{if .CallSign}}
  {{.CallSign}}
 {{end}}
+{{if .Watchlist}}
+ [{{.Watchlist}}]
+{{end}}
 has started it's takeoff from {{.AirportName}}
 <br />
 <br />
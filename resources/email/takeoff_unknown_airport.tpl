@@ -4,6 +4,9 @@ Project: {{.Project}}<br />
 {{if .CallSign}}
  {{.CallSign}}
 {{end}}
+{{if .Watchlist}}
+ [{{.Watchlist}}]
+{{end}}
 departed an unknown airport.
 <br />
 <br />
@@ -0,0 +1,16 @@
+Project: {{.Project}}<br />
+
+{{.Icao }}
+{{if .CallSign}}
+ {{.CallSign}}
+{{end}}
+{{if .Watchlist}}
+ [{{.Watchlist}}]
+{{end}}
+has exited {{.Zone}}
+<br />
+<br />
+<ul>
+    <li>Time: {{ .TimeFmt }}</li>
+    <li>Place: <a href="https://www.openstreetmap.org/#map=13/{{ .Location.Latitude }}/{{ .Location.Longitude }}">{{ .Location.Latitude }}, {{ .Location.Longitude }}</a> @ {{ .Location.Altitude }} ft</li>
+</ul>
@@ -0,0 +1,13 @@
+alter table `sighting`
+    drop index `sighting_project_id_origin_code_index`,
+    drop index `sighting_project_id_destination_code_index`,
+    drop `origin_code`,
+    drop `origin_name`,
+    drop `origin_latitude`,
+    drop `origin_longitude`,
+    drop `origin_distance`,
+    drop `destination_code`,
+    drop `destination_name`,
+    drop `destination_latitude`,
+    drop `destination_longitude`,
+    drop `destination_distance`;
\ No newline at end of file
@@ -0,0 +1,13 @@
+alter table `sighting`
+    add `origin_code` varchar(10) null,
+    add `origin_name` varchar(255) null,
+    add `origin_latitude` double(12, 8) null,
+    add `origin_longitude` double(12, 8) null,
+    add `origin_distance` double null,
+    add `destination_code` varchar(10) null,
+    add `destination_name` varchar(255) null,
+    add `destination_latitude` double(12, 8) null,
+    add `destination_longitude` double(12, 8) null,
+    add `destination_distance` double null;
+alter table `sighting` add index `sighting_project_id_origin_code_index`(`project_id`, `origin_code`);
+alter table `sighting` add index `sighting_project_id_destination_code_index`(`project_id`, `destination_code`);
@@ -0,0 +1,2 @@
+create table `sighting_phase` (`id` int unsigned not null auto_increment primary key, `sighting_id` int not null, `phase` varchar(20) not null, `observed_at` timestamp not null) default character set utf8mb4 collate 'utf8mb4_unicode_ci';
+alter table `sighting_phase` add index `sighting_phase_sighting_id_index`(`sighting_id`);
@@ -0,0 +1,2 @@
+create table `sighting_receiver` (`id` int unsigned not null auto_increment primary key, `sighting_id` int not null, `receiver` varchar(100) not null, `first_seen` timestamp not null, `last_seen` timestamp not null, `best_rssi` double null) default character set utf8mb4 collate 'utf8mb4_unicode_ci';
+alter table `sighting_receiver` add unique index `sighting_receiver_sighting_id_receiver_unique`(`sighting_id`, `receiver`);
@@ -0,0 +1 @@
+alter table `sighting_location` drop `provenance`;
\ No newline at end of file
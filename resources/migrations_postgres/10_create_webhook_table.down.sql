@@ -0,0 +1 @@
+drop table webhook;
\ No newline at end of file
@@ -0,0 +1,10 @@
+create table webhook (
+                       id serial not null primary key,
+                       created_at timestamp NOT NULL,
+                       updated_at timestamp NOT NULL,
+                       retry_after timestamp null,
+                       status int not null,
+                       retries int not null,
+                       job bytea not null
+    );
+create index webhook_status on webhook(status);
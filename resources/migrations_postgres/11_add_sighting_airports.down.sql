@@ -0,0 +1,13 @@
+drop index sighting_project_id_origin_code_index;
+drop index sighting_project_id_destination_code_index;
+alter table sighting
+    drop column origin_code,
+    drop column origin_name,
+    drop column origin_latitude,
+    drop column origin_longitude,
+    drop column origin_distance,
+    drop column destination_code,
+    drop column destination_name,
+    drop column destination_latitude,
+    drop column destination_longitude,
+    drop column destination_distance;
\ No newline at end of file
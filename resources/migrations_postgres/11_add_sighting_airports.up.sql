@@ -0,0 +1,13 @@
+alter table sighting
+    add column origin_code varchar(10) null,
+    add column origin_name varchar(255) null,
+    add column origin_latitude numeric(12, 8) null,
+    add column origin_longitude numeric(12, 8) null,
+    add column origin_distance double precision null,
+    add column destination_code varchar(10) null,
+    add column destination_name varchar(255) null,
+    add column destination_latitude numeric(12, 8) null,
+    add column destination_longitude numeric(12, 8) null,
+    add column destination_distance double precision null;
+create index sighting_project_id_origin_code_index on sighting(project_id, origin_code);
+create index sighting_project_id_destination_code_index on sighting(project_id, destination_code);
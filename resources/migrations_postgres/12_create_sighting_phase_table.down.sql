@@ -0,0 +1 @@
+drop table sighting_phase;
\ No newline at end of file
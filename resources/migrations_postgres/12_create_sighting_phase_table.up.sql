@@ -0,0 +1,6 @@
+create table sighting_phase (
+    id serial not null primary key,
+    sighting_id int not null,
+    phase varchar(20) not null,
+    observed_at timestamp not null);
+create index sighting_phase_sighting_id_index on sighting_phase(sighting_id);
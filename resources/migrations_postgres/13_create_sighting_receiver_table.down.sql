@@ -0,0 +1 @@
+drop table sighting_receiver;
\ No newline at end of file
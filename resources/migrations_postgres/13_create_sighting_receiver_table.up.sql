@@ -0,0 +1,8 @@
+create table sighting_receiver (
+    id serial not null primary key,
+    sighting_id int not null,
+    receiver varchar(100) not null,
+    first_seen timestamp not null,
+    last_seen timestamp not null,
+    best_rssi double precision null);
+create unique index sighting_receiver_sighting_id_receiver_unique on sighting_receiver(sighting_id, receiver);
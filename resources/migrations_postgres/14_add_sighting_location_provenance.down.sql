@@ -0,0 +1 @@
+alter table sighting_location drop column provenance;
\ No newline at end of file
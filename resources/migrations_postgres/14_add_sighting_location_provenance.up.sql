@@ -0,0 +1 @@
+alter table sighting_location add column provenance varchar(10) null;
\ No newline at end of file
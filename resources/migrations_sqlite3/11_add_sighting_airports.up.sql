@@ -0,0 +1,12 @@
+alter table `sighting` add column `origin_code` varchar(10) null;
+alter table `sighting` add column `origin_name` varchar(255) null;
+alter table `sighting` add column `origin_latitude` double(12, 8) null;
+alter table `sighting` add column `origin_longitude` double(12, 8) null;
+alter table `sighting` add column `origin_distance` double null;
+alter table `sighting` add column `destination_code` varchar(10) null;
+alter table `sighting` add column `destination_name` varchar(255) null;
+alter table `sighting` add column `destination_latitude` double(12, 8) null;
+alter table `sighting` add column `destination_longitude` double(12, 8) null;
+alter table `sighting` add column `destination_distance` double null;
+create index `sighting_project_id_origin_code_index` on `sighting`(`project_id`, `origin_code`);
+create index `sighting_project_id_destination_code_index` on `sighting`(`project_id`, `destination_code`);
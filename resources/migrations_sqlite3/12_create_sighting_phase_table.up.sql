@@ -0,0 +1,7 @@
+create table `sighting_phase` (
+    `id` integer not null primary key autoincrement,
+    `sighting_id` int not null,
+    `phase` varchar(20) not null,
+    `observed_at` timestamp not null
+                               );
+create index sighting_phase_sighting_id_index on sighting_phase(`sighting_id`);
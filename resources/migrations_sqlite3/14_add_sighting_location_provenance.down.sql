@@ -0,0 +1,12 @@
+-- sqlite can't drop columns, so the table is rebuilt
+create table `sighting_location_old` (
+    `id` integer not null primary key autoincrement,
+    `sighting_id` int not null,
+    `timestamp` timestamp not null,
+    `altitude` mediumint not null,
+    `latitude` double(12, 8) not null,
+    `longitude` double(12, 8) not null);
+insert into `sighting_location_old` select `id`, `sighting_id`, `timestamp`, `altitude`, `latitude`, `longitude` from `sighting_location`;
+drop table `sighting_location`;
+alter table `sighting_location_old` rename to `sighting_location`;
+create index `sighting_location_sighting_id_index` on `sighting_location`(`sighting_id`);
\ No newline at end of file